@@ -0,0 +1,185 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Built-in argument encodings for [ToolCall.ArgsEncoding].
+const (
+	// ArgsEncodingJSON is the default: Input.ArgsJSON already holds JSON.
+	ArgsEncodingJSON = "json"
+	// ArgsEncodingForm is a flat urlencoded form; "key[]=a&key[]=b" produces an array.
+	ArgsEncodingForm = "form"
+)
+
+// ArgsCodec converts a non-JSON argument payload into JSON before validation.
+// The registry runs it when [ToolCall.ArgsEncoding] names the codec; everything after decoding
+// (hooks, policy, validators, schema validation) sees plain JSON.
+type ArgsCodec interface {
+	Decode(raw []byte) ([]byte, error)
+}
+
+// SchemaArgsCodec is an optional [ArgsCodec] extension that receives the tool's Parameters schema,
+// so untyped encodings such as forms can coerce "3" to 3 without duplicating schema knowledge.
+type SchemaArgsCodec interface {
+	ArgsCodec
+	DecodeWithSchema(raw []byte, schema map[string]any) ([]byte, error)
+}
+
+// ArgsCodecFunc adapts a function to [ArgsCodec].
+type ArgsCodecFunc func(raw []byte) ([]byte, error)
+
+// Decode calls f(raw).
+func (f ArgsCodecFunc) Decode(raw []byte) ([]byte, error) {
+	return f(raw)
+}
+
+// WithArgsCodec registers codec under name for [ToolCall.ArgsEncoding]. Registering "json" or "form"
+// replaces the built-in codec of that name. A nil codec removes a previous registration.
+func WithArgsCodec(name string, codec ArgsCodec) RegistryOption {
+	return func(o *registryOptions) {
+		codecs := maps.Clone(o.argsCodecs)
+		if codecs == nil {
+			codecs = make(map[string]ArgsCodec, 1)
+		}
+		if codec == nil {
+			delete(codecs, name)
+		} else {
+			codecs[name] = codec
+		}
+		o.argsCodecs = codecs
+	}
+}
+
+func (o *registryOptions) argsCodec(name string) (ArgsCodec, bool) {
+	if codec, ok := o.argsCodecs[name]; ok {
+		return codec, true
+	}
+	switch name {
+	case ArgsEncodingJSON:
+		return jsonArgsCodec{}, true
+	case ArgsEncodingForm:
+		return formArgsCodec{}, true
+	default:
+		return nil, false
+	}
+}
+
+// decodeCallArgs rewrites call.Input.ArgsJSON to JSON using the codec named by call.ArgsEncoding.
+func (r *Registry) decodeCallArgs(call *ToolCall, tool Tool) error {
+	encoding := call.ArgsEncoding
+	if encoding == "" {
+		return nil
+	}
+	if _, custom := r.opts.argsCodecs[encoding]; !custom && encoding == ArgsEncodingJSON {
+		call.ArgsEncoding = ""
+		return nil
+	}
+	codec, ok := r.opts.argsCodec(encoding)
+	if !ok {
+		return NewSchemaError(fmt.Sprintf("unsupported argument encoding %q", encoding))
+	}
+	var decoded []byte
+	var err error
+	if sc, isSchemaAware := codec.(SchemaArgsCodec); isSchemaAware {
+		decoded, err = sc.DecodeWithSchema(call.Input.ArgsJSON, tool.Manifest().Parameters)
+	} else {
+		decoded, err = codec.Decode(call.Input.ArgsJSON)
+	}
+	if err != nil {
+		return &ToolError{ //nolint:exhaustruct // optional envelope fields omitted by design
+			Code:      CodeSchemaInvalid,
+			Reason:    fmt.Sprintf("invalid %s arguments", encoding),
+			Retryable: false,
+			Err:       err,
+		}
+	}
+	call.Input.ArgsJSON = decoded
+	call.ArgsEncoding = ""
+	return nil
+}
+
+type jsonArgsCodec struct{}
+
+func (jsonArgsCodec) Decode(raw []byte) ([]byte, error) {
+	return raw, nil
+}
+
+type formArgsCodec struct{}
+
+func (c formArgsCodec) Decode(raw []byte) ([]byte, error) {
+	return c.DecodeWithSchema(raw, nil)
+}
+
+// DecodeWithSchema parses a flat form. Values stay strings unless the matching property
+// (or array items) declares integer, number, or boolean.
+func (formArgsCodec) DecodeWithSchema(raw []byte, schema map[string]any) ([]byte, error) {
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	props, _ := schema["properties"].(map[string]any)
+	out := make(map[string]any, len(values))
+	for key, vals := range values {
+		name, isArray := strings.CutSuffix(key, "[]")
+		prop, _ := props[name].(map[string]any)
+		if isArray || len(vals) > 1 || schemaTypeOf(prop) == "array" {
+			items, _ := prop["items"].(map[string]any)
+			list := make([]any, 0, len(vals))
+			if prev, ok := out[name].([]any); ok {
+				list = prev
+			}
+			for _, v := range vals {
+				list = append(list, coerceFormValue(v, items))
+			}
+			out[name] = list
+			continue
+		}
+		out[name] = coerceFormValue(vals[0], prop)
+	}
+	return json.Marshal(out)
+}
+
+func coerceFormValue(v string, prop map[string]any) any {
+	switch schemaTypeOf(prop) {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// schemaTypeOf returns the first non-null JSON Schema type of prop ("" when absent).
+func schemaTypeOf(prop map[string]any) string {
+	switch t := prop["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	case []string:
+		for _, s := range t {
+			if s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
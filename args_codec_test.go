@@ -0,0 +1,114 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherArgs struct {
+	City   string   `json:"city"`
+	Days   int      `json:"days"`
+	Metric bool     `json:"metric"`
+	Fields []string `json:"fields"`
+}
+
+func weatherTool(t *testing.T, seen *weatherArgs) Tool {
+	t.Helper()
+	tool, err := NewTool("weather", "Weather forecast", func(_ context.Context, _ *RunEnv, a weatherArgs) (string, error) {
+		*seen = a
+		return "sunny in " + a.City, nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestRegistry_ArgsEncodingForm_EndToEnd(t *testing.T) {
+	var seen weatherArgs
+	var hookArgs string
+	reg := mustBuildRegistry(t, []Tool{weatherTool(t, &seen)},
+		WithOnBeforeExecute(func(_ context.Context, call ToolCall) { hookArgs = string(call.Input.ArgsJSON) }),
+	)
+
+	var out string
+	err := reg.Execute(context.Background(), ToolCall{
+		ToolName:     "weather",
+		Input:        ToolInput{CallID: "1", ArgsJSON: []byte("city=Paris&days=3&metric=true&fields[]=temp&fields[]=wind")},
+		ArgsEncoding: ArgsEncodingForm,
+	}, func(c Chunk) error { return json.Unmarshal(c.Data, &out) })
+	require.NoError(t, err)
+	assert.Equal(t, "sunny in Paris", out)
+	assert.Equal(t, weatherArgs{City: "Paris", Days: 3, Metric: true, Fields: []string{"temp", "wind"}}, seen)
+	assert.JSONEq(t, `{"city":"Paris","days":3,"metric":true,"fields":["temp","wind"]}`, hookArgs)
+}
+
+func TestRegistry_ArgsEncodingForm_UncoercibleValueFailsValidation(t *testing.T) {
+	var seen weatherArgs
+	reg := mustBuildRegistry(t, []Tool{weatherTool(t, &seen)})
+	err := reg.Execute(context.Background(), ToolCall{
+		ToolName:     "weather",
+		Input:        ToolInput{ArgsJSON: []byte("city=Paris&days=soon&metric=false&fields[]=temp")},
+		ArgsEncoding: ArgsEncodingForm,
+	}, func(Chunk) error { return nil })
+	require.Error(t, err)
+	assert.True(t, clientCorrectable(err))
+}
+
+func TestRegistry_ArgsEncodingUnknown(t *testing.T) {
+	var seen weatherArgs
+	reg := mustBuildRegistry(t, []Tool{weatherTool(t, &seen)})
+	err := reg.Execute(context.Background(), ToolCall{
+		ToolName:     "weather",
+		Input:        ToolInput{ArgsJSON: []byte{0xa1}},
+		ArgsEncoding: "cbor",
+	}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeSchemaInvalid, te.Code)
+	assert.Contains(t, te.Reason, `"cbor"`)
+	assert.True(t, clientCorrectable(err))
+}
+
+func TestRegistry_WithArgsCodec_Custom(t *testing.T) {
+	var seen weatherArgs
+	upper := ArgsCodecFunc(func(raw []byte) ([]byte, error) {
+		parts := strings.Split(string(raw), "|")
+		if len(parts) != 2 {
+			return nil, errors.New("want city|days")
+		}
+		return json.Marshal(map[string]any{"city": parts[0], "days": len(parts[1]), "metric": true, "fields": []string{}})
+	})
+	reg := mustBuildRegistry(t, []Tool{weatherTool(t, &seen)}, WithArgsCodec("pipe", upper))
+
+	err := reg.Execute(context.Background(), ToolCall{
+		ToolName:     "weather",
+		Input:        ToolInput{ArgsJSON: []byte("Oslo|xx")},
+		ArgsEncoding: "pipe",
+	}, func(Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "Oslo", seen.City)
+	assert.Equal(t, 2, seen.Days)
+
+	err = reg.Execute(context.Background(), ToolCall{
+		ToolName:     "weather",
+		Input:        ToolInput{ArgsJSON: []byte("broken")},
+		ArgsEncoding: "pipe",
+	}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeSchemaInvalid, te.Code)
+	assert.EqualError(t, te.Err, "want city|days")
+}
+
+func TestFormArgsCodec_WithoutSchemaKeepsStrings(t *testing.T) {
+	codec, ok := (&registryOptions{}).argsCodec(ArgsEncodingForm)
+	require.True(t, ok)
+	out, err := codec.Decode([]byte("a=1&b[]=x&b[]=y"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":"1","b":["x","y"]}`, string(out))
+}
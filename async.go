@@ -124,7 +124,7 @@ func (r *asyncRuntime) trackBackground() func() {
 	}
 	r.backgroundStarted.Store(true)
 	return func() {
-		r.registry.state.running.Done()
+		r.registry.state.finishExecution()
 	}
 }
 
@@ -218,7 +218,7 @@ func (t *asyncTool) runBackground(
 	var executionErr error
 	defer func() {
 		if r := recover(); r != nil {
-			executionErr = NewInternalError(&panicError{p: r})
+			executionErr = NewInternalError(newPanicError(r, true))
 		}
 		t.invokeOnComplete(baseCtx, taskID, collected, executionErr)
 	}()
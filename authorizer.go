@@ -1,6 +1,9 @@
 package toolsy
 
-import "context"
+import (
+	"context"
+	"slices"
+)
 
 // Authorizer performs runtime authorization before tool execution.
 type Authorizer interface {
@@ -18,6 +21,67 @@ func (f AuthorizerFunc) Authorize(ctx context.Context, req AuthorizationRequest)
 	return f(ctx, req)
 }
 
+// AuthorizationDeniedMessage is the [ToolError.SafeMessage] of a call denied by an [Authorizer] that
+// returned a plain error; the error itself stays in Reason and the Unwrap chain for server logs.
+const AuthorizationDeniedMessage = "you are not allowed to use this tool"
+
+// ToolInfo is the tool metadata a [ToolAuthorizerFunc] decides on.
+type ToolInfo struct {
+	Name                 string
+	Version              string
+	Tags                 []string
+	Dangerous            bool
+	ReadOnly             bool
+	RequiresConfirmation bool
+}
+
+func toolInfoFromManifest(m ToolManifest) ToolInfo {
+	return ToolInfo{
+		Name:                 m.Name,
+		Version:              m.Version,
+		Tags:                 slices.Clone(m.Tags),
+		Dangerous:            m.Dangerous,
+		ReadOnly:             m.ReadOnly,
+		RequiresConfirmation: m.RequiresConfirmation,
+	}
+}
+
+// HasTag reports whether the tool carries tag.
+func (i ToolInfo) HasTag(tag string) bool {
+	return slices.Contains(i.Tags, tag)
+}
+
+// ToolAuthorizerFunc is an [Authorizer] deciding from the tool metadata and the call, typically on a
+// principal read from ctx or [ToolCall.CallContext]. Use it with [WithAuthorizer] or [WithAuthorization];
+// both run before argument validation, so a denied caller cannot probe the schema through validation
+// errors. A plain error is reported as [CodePolicyDenied] with [AuthorizationDeniedMessage] for the
+// model; a [ToolError] is returned unchanged.
+type ToolAuthorizerFunc func(ctx context.Context, tool ToolInfo, call ToolCall) error
+
+// Authorize implements Authorizer.
+func (f ToolAuthorizerFunc) Authorize(ctx context.Context, req AuthorizationRequest) error {
+	if f == nil {
+		return NewPolicyDeniedError("authorizer function is nil")
+	}
+	call := ToolCall{
+		ToolName:     req.Manifest.Name,
+		Input:        req.Input,
+		ArgsEncoding: "",
+		Env:          nil,
+		CallContext:  req.CallContext,
+		Metadata:     nil,
+	}
+	return f(ctx, toolInfoFromManifest(req.Manifest), call)
+}
+
+// authorizationDenied converts an [Authorizer] error into the error the call fails with.
+func authorizationDenied(err error) error {
+	if _, ok := AsToolError(err); ok {
+		return err
+	}
+	return WithSafeMessage(NewPolicyDeniedErrorFrom(err), AuthorizationDeniedMessage)
+}
+
 // WithAuthorizer configures registry-level authorization executed before tools run.
 func WithAuthorizer(a Authorizer) RegistryOption {
 	return func(o *registryOptions) {
@@ -51,16 +115,13 @@ func (t *authorizationTool) Execute(
 		return t.next.Execute(ctx, run, input, yield)
 	}
 	req := AuthorizationRequest{
-		Manifest:    cloneManifestForPolicy(t.next.Manifest()),
+		Manifest:    cloneManifestForPolicy(toolManifest(t.next)),
 		Input:       input.Clone(),
 		CallContext: run.CallContext(),
 		View:        run.RegistryViewSnapshot(),
 	}
 	if err := t.auth.Authorize(ctx, req); err != nil {
-		if _, ok := AsToolError(err); ok {
-			return err
-		}
-		return NewPolicyDeniedErrorFrom(err)
+		return authorizationDenied(err)
 	}
 	return t.next.Execute(ctx, run, input, yield)
 }
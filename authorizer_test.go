@@ -136,3 +136,44 @@ func TestWithAuthorizationMiddleware_TrustsOnlyRegistryBoundView(t *testing.T) {
 	assert.Equal(t, view.Snapshot().ID, viewViewID)
 	assert.True(t, handlerRan)
 }
+
+func TestToolAuthorizerFunc_DeniesBeforeValidation(t *testing.T) {
+	type args struct {
+		ID int `json:"id"`
+	}
+	ran := false
+	drop, err := NewTool("drop_table", "Drop", func(context.Context, *RunEnv, args) (string, error) {
+		ran = true
+		return "dropped", nil
+	}, WithDangerous(), WithTags("db"), WithVersion("v1"))
+	require.NoError(t, err)
+	var seen ToolInfo
+	denyErr := errors.New("role viewer may not run dangerous tools")
+	authz := ToolAuthorizerFunc(func(_ context.Context, tool ToolInfo, call ToolCall) error {
+		seen = tool
+		assert.Equal(t, "drop_table", call.ToolName)
+		if tool.Dangerous && call.CallContext.Subject != "admin" {
+			return denyErr
+		}
+		return nil
+	})
+	reg := mustBuildRegistry(t, []Tool{drop}, WithAuthorizer(authz))
+
+	call := ToolCall{ToolName: "drop_table", Input: ToolInput{ArgsJSON: []byte(`{"id":"not-a-number"}`)}}
+	err = reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, denyErr)
+	require.ErrorIs(t, err, ErrPolicyDenied)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodePolicyDenied, te.Code, "denial wins over the invalid arguments")
+	assert.Equal(t, AuthorizationDeniedMessage, te.SafeMessage)
+	assert.Equal(t, FinishClientError, FinishReasonOf(err))
+	assert.Equal(t, ToolInfo{Name: "drop_table", Version: "v1", Tags: []string{"db"}, Dangerous: true}, seen)
+	assert.True(t, seen.HasTag("db"))
+	assert.False(t, ran)
+
+	call.Input.ArgsJSON = []byte(`{"id":1}`)
+	call.CallContext.Subject = "admin"
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.True(t, ran)
+}
@@ -0,0 +1,158 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type boundOptions struct {
+	rejectConflicts bool
+}
+
+// BoundOption configures [NewBoundTool].
+type BoundOption func(*boundOptions)
+
+// WithRejectBoundConflicts makes a bound tool fail with a validation error when the caller supplies a
+// bound argument. By default such values are silently replaced by the bound ones.
+func WithRejectBoundConflicts() BoundOption {
+	return func(o *boundOptions) {
+		o.rejectConflicts = true
+	}
+}
+
+// NewBoundTool registers base under name with some arguments fixed (for example a "search" tool bound to
+// {"site": "docs.internal"} for a docs agent). The derived manifest removes bound keys from the
+// schema's properties and required lists, so the model never sees them, and records them in
+// [ToolManifest.BoundArgs]. Execute merges boundArgs over the incoming object before delegating,
+// so base still validates against its full schema. Every bound key must be a top-level property of base.
+func NewBoundTool(name string, base Tool, boundArgs map[string]any, opts ...BoundOption) (Tool, error) {
+	if base == nil {
+		return nil, errors.New("toolsy: bound tool requires a base tool")
+	}
+	if name == "" {
+		return nil, errors.New("toolsy: bound tool name is required")
+	}
+	if len(boundArgs) == 0 {
+		return nil, errors.New("toolsy: bound tool requires at least one bound argument")
+	}
+	props, _ := base.Manifest().Parameters["properties"].(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(boundArgs)) {
+		if _, ok := props[key]; !ok {
+			return nil, fmt.Errorf("toolsy: bound argument %q is not a property of tool %q", key, base.Manifest().Name)
+		}
+	}
+	var o boundOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &boundTool{
+		toolBase: toolBase{next: base},
+		name:     name,
+		bound:    deepCopySchema(boundArgs),
+		params:   withoutBoundProperties(base.Manifest().Parameters, boundArgs),
+		opts:     o,
+	}, nil
+}
+
+type boundTool struct {
+	toolBase
+
+	name   string
+	bound  map[string]any
+	params map[string]any // base parameters without the bound keys
+	opts   boundOptions
+}
+
+func (t *boundTool) Manifest() ToolManifest {
+	manifest := t.next.Manifest()
+	manifest.Name = t.name
+	manifest.Parameters = deepCopySchema(t.params)
+	manifest.BoundArgs = deepCopySchema(t.bound)
+	return manifest
+}
+
+func (t *boundTool) manifestView() ToolManifest {
+	manifest := toolManifest(t.next)
+	manifest.Name = t.name
+	manifest.Parameters = t.params
+	manifest.BoundArgs = t.bound
+	return manifest
+}
+
+// withoutBoundProperties returns a deep copy of schema without the bound keys in properties/required.
+func withoutBoundProperties(schema, bound map[string]any) map[string]any {
+	out := deepCopySchema(schema)
+	if props, ok := out["properties"].(map[string]any); ok {
+		for key := range bound {
+			delete(props, key)
+		}
+	}
+	switch required := out["required"].(type) {
+	case []string:
+		out["required"] = slices.DeleteFunc(required, func(k string) bool { _, ok := bound[k]; return ok })
+	case []any:
+		out["required"] = slices.DeleteFunc(required, func(k any) bool {
+			s, isString := k.(string)
+			_, ok := bound[s]
+			return isString && ok
+		})
+	}
+	return out
+}
+
+func (t *boundTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	merged, err := t.mergeArgs(input.ArgsJSON)
+	if err != nil {
+		return err
+	}
+	input.ArgsJSON = merged
+	return t.next.Execute(ctx, run, input, func(c Chunk) error {
+		c.ToolName = t.name
+		return yield(c)
+	})
+}
+
+func (t *boundTool) mergeArgs(raw []byte) ([]byte, error) {
+	args := map[string]json.RawMessage{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, NewJSONParseError(err)
+		}
+		if args == nil {
+			args = map[string]json.RawMessage{}
+		}
+	}
+	var conflicts []string
+	for key, value := range t.bound {
+		if _, ok := args[key]; ok {
+			conflicts = append(conflicts, key)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, NewInternalError(fmt.Errorf("toolsy: marshal bound argument %q: %w", key, err))
+		}
+		args[key] = encoded
+	}
+	if t.opts.rejectConflicts && len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		return nil, NewValidationError(fmt.Sprintf("arguments %v are fixed by the tool and must not be set", conflicts),
+			conflicts...)
+	}
+	merged, err := json.Marshal(args)
+	if err != nil {
+		return nil, NewInternalError(fmt.Errorf("toolsy: marshal merged arguments: %w", err))
+	}
+	return merged, nil
+}
+
+func (t *boundTool) validateArgs(argsJSON []byte) error {
+	merged, err := t.mergeArgs(argsJSON)
+	if err != nil {
+		return err
+	}
+	return validateToolArgs(t.next, merged)
+}
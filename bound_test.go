@@ -0,0 +1,99 @@
+package toolsy_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type searchArgs struct {
+	Query string `json:"query"`
+	Site  string `json:"site"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+func newSearchTool(t *testing.T, got *searchArgs) toolsy.Tool {
+	t.Helper()
+	tool, err := toolsy.NewTool("search", "Search the web", func(_ context.Context, _ *toolsy.RunEnv, a searchArgs) (string, error) {
+		*got = a
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestNewBoundTool_HidesBoundProperties(t *testing.T) {
+	var got searchArgs
+	base := newSearchTool(t, &got)
+	bound, err := toolsy.NewBoundTool("docs_search", base, map[string]any{"site": "docs.internal"})
+	require.NoError(t, err)
+
+	m := bound.Manifest()
+	assert.Equal(t, "docs_search", m.Name)
+	props := m.Parameters["properties"].(map[string]any)
+	assert.NotContains(t, props, "site")
+	assert.Contains(t, props, "query")
+	raw, err := json.Marshal(m.Parameters["required"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `["query"]`, string(raw))
+	assert.Equal(t, map[string]any{"site": "docs.internal"}, m.BoundArgs)
+
+	baseProps := base.Manifest().Parameters["properties"].(map[string]any)
+	assert.Contains(t, baseProps, "site", "base schema must stay intact")
+}
+
+func TestNewBoundTool_MergesBoundArgsThroughRegistry(t *testing.T) {
+	var got searchArgs
+	bound, err := toolsy.NewBoundTool("docs_search", newSearchTool(t, &got), map[string]any{"site": "docs.internal"})
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistry(bound)
+	require.NoError(t, err)
+
+	var toolName string
+	err = reg.Execute(context.Background(), toolsy.ToolCall{
+		ToolName: "docs_search",
+		Input:    toolsy.ToolInput{ArgsJSON: []byte(`{"query":"retries","limit":3}`)},
+	}, func(c toolsy.Chunk) error {
+		toolName = c.ToolName
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, searchArgs{Query: "retries", Site: "docs.internal", Limit: 3}, got)
+	assert.Equal(t, "docs_search", toolName)
+
+	err = reg.Execute(context.Background(), toolsy.ToolCall{
+		ToolName: "docs_search",
+		Input:    toolsy.ToolInput{ArgsJSON: []byte(`{"query":"q","site":"evil.example"}`)},
+	}, func(toolsy.Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "docs.internal", got.Site, "bound values win by default")
+}
+
+func TestNewBoundTool_RejectConflicts(t *testing.T) {
+	var got searchArgs
+	bound, err := toolsy.NewBoundTool("docs_search", newSearchTool(t, &got), map[string]any{"site": "docs.internal"},
+		toolsy.WithRejectBoundConflicts())
+	require.NoError(t, err)
+
+	err = bound.Execute(context.Background(), toolsy.NewRunEnv(nil),
+		toolsy.ToolInput{ArgsJSON: []byte(`{"query":"q","site":"evil.example"}`)},
+		func(toolsy.Chunk) error { return nil })
+	require.ErrorIs(t, err, toolsy.ErrValidation)
+	te, ok := toolsy.AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"site"}, te.FixableArgs)
+	assert.Empty(t, got.Query, "base handler must not run")
+}
+
+func TestNewBoundTool_RejectsUnknownKeys(t *testing.T) {
+	var got searchArgs
+	_, err := toolsy.NewBoundTool("docs_search", newSearchTool(t, &got), map[string]any{"region": "eu"})
+	require.ErrorContains(t, err, `"region"`)
+	_, err = toolsy.NewBoundTool("docs_search", newSearchTool(t, &got), nil)
+	require.Error(t, err)
+}
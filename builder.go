@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"maps"
+	"slices"
 
 	"github.com/skosovsky/toolsy/textprocessor"
 )
@@ -14,6 +14,9 @@ import (
 type tool struct {
 	manifest ToolManifest
 	execute  func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error
+	// validate runs the argument validation of execute without the handler ([Registry.ValidateCall]);
+	// nil when validation needs the run context (for example an [ArgsBinder]).
+	validate func(argsJSON []byte) error
 }
 
 // NewTool builds a low-level Tool from a typed function that also receives [*RunEnv].
@@ -35,6 +38,53 @@ func NewTool[T any, R any](
 	if err != nil {
 		return nil, err
 	}
+	return newToolFromExtractor(ext, name, description, fn, cfg)
+}
+
+// ExtractorTool builds the same Tool as [NewTool] around an existing [Extractor], reusing its
+// generated schema and compiled validator instead of regenerating them. Schema options
+// ([WithStrict], [WithSchemaRegistry]) are rejected because they were fixed when the extractor was built;
+// manifest options (tags, version, policy flags) apply as usual.
+func ExtractorTool[T any, R any](
+	ext *Extractor[T],
+	name, description string,
+	fn func(ctx context.Context, env *RunEnv, args T) (R, error),
+	opts ...ToolOption,
+) (Tool, error) {
+	cfg, err := extractorToolConfig(ext, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newToolFromExtractor(ext, name, description, fn, cfg)
+}
+
+// extractorToolConfig applies opts for [ExtractorTool] / [ExtractorStreamTool] and inherits the
+// extractor's schema configuration.
+func extractorToolConfig[T any](ext *Extractor[T], opts []ToolOption) (ToolConfig, error) {
+	if ext == nil {
+		return ToolConfig{}, errors.New("toolsy: extractor is required")
+	}
+	var cfg ToolConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Schema.Strict || cfg.Schema.Registry != nil || cfg.Schema.AllValidationErrors ||
+		len(cfg.Schema.Transforms) > 0 || cfg.Schema.Nullable != NullableTypeArray || cfg.Schema.OptionalAsNullable {
+		return ToolConfig{}, errors.New("toolsy: schema options (WithStrict, WithSchemaRegistry, " +
+			"WithAllValidationErrors, WithSchemaTransform, WithNullableStyle, WithStrictOptionalAsNullable) " +
+			"are fixed by the extractor; set them in NewExtractorWithConfig")
+	}
+	cfg.Schema = ext.cfg
+	cfg.Manifest.Strict = ext.cfg.Strict
+	return cfg, nil
+}
+
+func newToolFromExtractor[T any, R any](
+	ext *Extractor[T],
+	name, description string,
+	fn func(ctx context.Context, env *RunEnv, args T) (R, error),
+	cfg ToolConfig,
+) (Tool, error) {
 	if len(cfg.Manifest.OutputSchema) == 0 {
 		outSchema, genErr := generateOutputSchema[R](cfg.Schema)
 		if genErr != nil {
@@ -42,6 +92,16 @@ func NewTool[T any, R any](
 		}
 		cfg.Manifest.OutputSchema = outSchema
 	}
+	if err := checkToolIdentity(cfg, name, description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, ext.Validate); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
 	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
 		args, err := ext.ParseAndValidate(input.ArgsJSON)
 		if err != nil {
@@ -65,14 +125,18 @@ func NewTool[T any, R any](
 		if err != nil {
 			return err
 		}
+		if err := output.check(prepared); err != nil {
+			return err
+		}
 		if err := yield(prepared); err != nil {
 			return wrapYieldError(err)
 		}
 		return nil
 	}
 	return &tool{
-		manifest: buildToolManifest(name, description, ext.Schema(), cfg.Manifest),
+		manifest: ext.toolManifest(name, description, cfg.Manifest),
 		execute:  execute,
+		validate: ext.parseOnly,
 	}, nil
 }
 
@@ -119,14 +183,13 @@ func deepCopySchemaFromMap(schemaMap map[string]any) (map[string]any, error) {
 //nolint:gocognit
 func rawArgsValidatedExecute(
 	compiled schemaValidator,
+	schema map[string]any,
+	allErrors bool,
+	output *outputValidator,
 	handler func(ctx context.Context, env *RunEnv, argsJSON []byte, yield func(Chunk) error) error,
 ) func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error {
 	return func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
-		var v any
-		if err := json.Unmarshal(input.ArgsJSON, &v); err != nil {
-			return wrapJSONParseError(err)
-		}
-		if err := validateAgainstSchema(compiled, v); err != nil {
+		if _, err := decodeAndValidateRaw(compiled, schema, allErrors, input.ArgsJSON); err != nil {
 			return err
 		}
 		yieldWrapped := func(c Chunk) error {
@@ -134,6 +197,9 @@ func rawArgsValidatedExecute(
 			if err != nil {
 				return err
 			}
+			if err := output.check(prepared); err != nil {
+				return err
+			}
 			if err := yield(prepared); err != nil {
 				return wrapYieldError(err)
 			}
@@ -159,8 +225,8 @@ func rawArgsValidatedExecute(
 //
 // Unlike [NewTool], stream tools do not have a single typed result type R, so
 // [ToolManifest.OutputSchema] is not generated automatically. Set it with
-// [WithOutputSchema] when the LLM should know the shape of final JSON results,
-// or document progress/result chunks in the tool description.
+// [WithOutputSchema] when the LLM should know the shape of final JSON results
+// (and [WithOutputValidation] to enforce it), or document progress/result chunks in the tool description.
 //
 //nolint:gocognit
 func NewStreamTool[T any](
@@ -177,12 +243,49 @@ func NewStreamTool[T any](
 	if err != nil {
 		return nil, err
 	}
+	return newStreamToolFromExtractor(ext, name, description, fn, cfg)
+}
+
+// ExtractorStreamTool is the streaming counterpart of [ExtractorTool]: it builds the same Tool as
+// [NewStreamTool] around an existing [Extractor] without regenerating its schema.
+func ExtractorStreamTool[T any](
+	ext *Extractor[T],
+	name, description string,
+	fn func(ctx context.Context, env *RunEnv, args T, yield func(Chunk) error) error,
+	opts ...ToolOption,
+) (Tool, error) {
+	cfg, err := extractorToolConfig(ext, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamToolFromExtractor(ext, name, description, fn, cfg)
+}
+
+func newStreamToolFromExtractor[T any](
+	ext *Extractor[T],
+	name, description string,
+	fn func(ctx context.Context, env *RunEnv, args T, yield func(Chunk) error) error,
+	cfg ToolConfig,
+) (Tool, error) {
+	if err := checkToolIdentity(cfg, name, description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, ext.Validate); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
 	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
 		yieldWrapped := func(c Chunk) error {
 			prepared, err := prepareChunk(c)
 			if err != nil {
 				return err
 			}
+			if err := output.check(prepared); err != nil {
+				return err
+			}
 			if err := yield(prepared); err != nil {
 				return wrapYieldError(err)
 			}
@@ -207,8 +310,9 @@ func NewStreamTool[T any](
 		return nil
 	}
 	return &tool{
-		manifest: buildToolManifest(name, description, ext.Schema(), cfg.Manifest),
+		manifest: ext.toolManifest(name, description, cfg.Manifest),
 		execute:  execute,
+		validate: ext.parseOnly,
 	}, nil
 }
 
@@ -231,61 +335,115 @@ func NewProxyTool(
 	if handler == nil {
 		return nil, errors.New("proxy tool handler must not be nil")
 	}
-	var parsed map[string]any
-	if err := json.Unmarshal(rawJSONSchema, &parsed); err != nil {
+	// Unmarshaling the caller's bytes already yields a private map, so no defensive copy is needed.
+	var schemaCopy map[string]any
+	if err := json.Unmarshal(rawJSONSchema, &schemaCopy); err != nil {
 		return nil, fmt.Errorf("failed to parse proxy schema: %w", err)
 	}
-	schemaCopy, err := deepCopySchemaFromMap(parsed)
-	if err != nil {
-		return nil, fmt.Errorf("failed to copy proxy schema: %w", err)
-	}
 	if cfg.Schema.Strict {
-		applyStrictMode(schemaCopy)
+		applyStrictMode(schemaCopy, cfg.Schema)
+	}
+	schemaCopy, _, err := transformSchema(schemaCopy, schemaTransforms(cfg.Schema))
+	if err != nil {
+		return nil, err
 	}
 	stripSchemaIDs(schemaCopy)
 	compiled, err := compileRawSchema(schemaCopy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile proxy schema: %w", err)
 	}
-	execute := rawArgsValidatedExecute(compiled, handler)
+	if err := checkToolIdentity(cfg, name, description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, nil); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	execute := rawArgsValidatedExecute(compiled, schemaCopy, cfg.Schema.AllValidationErrors, output, handler)
 	return &tool{
 		manifest: buildToolManifest(name, description, schemaCopy, cfg.Manifest),
 		execute:  execute,
+		validate: func(argsJSON []byte) error {
+			_, err := decodeAndValidateRaw(compiled, schemaCopy, cfg.Schema.AllValidationErrors, argsJSON)
+			return err
+		},
 	}, nil
 }
 
+// decodeAndValidateRaw decodes argsJSON and validates it against a raw (proxy or dynamic) schema.
+func decodeAndValidateRaw(
+	compiled schemaValidator,
+	schema map[string]any,
+	allErrors bool,
+	argsJSON []byte,
+) (any, error) {
+	var v any
+	if err := json.Unmarshal(argsJSON, &v); err != nil {
+		return nil, wrapJSONParseError(err)
+	}
+	if err := validateAgainstSchema(compiled, schema, v, allErrors); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
 func buildToolManifest(name, description string, schema map[string]any, cfg ToolManifest) ToolManifest {
 	tags := append([]string(nil), cfg.Tags...)
 	return ToolManifest{
 		Name:                 name,
 		Description:          description,
-		Parameters:           maps.Clone(schema),
-		OutputSchema:         maps.Clone(cfg.OutputSchema),
+		Parameters:           withSchemaExamples(deepCopySchema(schema), cfg.Examples),
+		OutputSchema:         deepCopySchema(cfg.OutputSchema),
 		Tags:                 tags,
 		Version:              cfg.Version,
 		Requirements:         cloneRequirements(cfg.Requirements),
+		Strict:               cfg.Strict,
+		BoundArgs:            deepCopySchema(cfg.BoundArgs),
+		SensitiveArgs:        slices.Clone(cfg.SensitiveArgs),
 		CompletionPolicy:     cfg.CompletionPolicy,
 		ReadOnly:             cfg.ReadOnly,
 		RequiresConfirmation: cfg.RequiresConfirmation,
 		Dangerous:            cfg.Dangerous,
 		Idempotent:           cfg.Idempotent,
+		Deprecated:           cfg.Deprecated,
+		DeprecationMessage:   cfg.DeprecationMessage,
+		Hidden:               cfg.Hidden,
+		Examples:             cloneExamples(cfg.Examples),
 	}
 }
 
 func (t *tool) Manifest() ToolManifest {
 	m := t.manifest
 	m.Tags = append([]string(nil), t.manifest.Tags...)
-	m.Parameters = maps.Clone(t.manifest.Parameters)
-	m.OutputSchema = maps.Clone(t.manifest.OutputSchema)
+	m.Parameters = deepCopySchema(t.manifest.Parameters)
+	m.OutputSchema = deepCopySchema(t.manifest.OutputSchema)
 	m.Requirements = cloneRequirements(t.manifest.Requirements)
+	m.BoundArgs = deepCopySchema(t.manifest.BoundArgs)
+	m.SensitiveArgs = slices.Clone(t.manifest.SensitiveArgs)
 	m.CompletionPolicy = t.manifest.CompletionPolicy
 	m.ReadOnly = t.manifest.ReadOnly
 	m.RequiresConfirmation = t.manifest.RequiresConfirmation
 	m.Dangerous = t.manifest.Dangerous
 	m.Idempotent = t.manifest.Idempotent
+	m.Deprecated = t.manifest.Deprecated
+	m.DeprecationMessage = t.manifest.DeprecationMessage
+	m.Hidden = t.manifest.Hidden
+	m.Examples = cloneExamples(t.manifest.Examples)
 	return m
 }
 
+func (t *tool) manifestView() ToolManifest { return t.manifest }
+
+func (t *tool) validateArgs(argsJSON []byte) error {
+	if t.validate == nil {
+		return nil
+	}
+	return t.validate(argsJSON)
+}
+
 func (t *tool) Execute(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
 	if env == nil {
 		env = NewRunEnv(nil)
@@ -179,31 +179,84 @@ func TestTool_ManifestParameters_ReturnsCopy(t *testing.T) {
 	require.False(t, ok)
 }
 
-func TestTool_ManifestParameters_ShallowCopyNested(t *testing.T) {
+// mutateSchemaEverywhere changes every map and slice reachable from schema.
+func mutateSchemaEverywhere(schema map[string]any) {
+	for k, v := range schema {
+		switch x := v.(type) {
+		case map[string]any:
+			mutateSchemaEverywhere(x)
+		case []any:
+			for i, item := range x {
+				if m, ok := item.(map[string]any); ok {
+					mutateSchemaEverywhere(m)
+				} else {
+					x[i] = "mutated"
+				}
+			}
+		}
+		if k != "properties" {
+			schema[k+"_mutated"] = true
+		}
+	}
+	schema["mutated"] = true
+}
+
+func TestTool_ManifestParameters_DeepCopy(t *testing.T) {
+	type Item struct {
+		SKU  string   `json:"sku"`
+		Tags []string `json:"tags" enum:"a,b"`
+	}
 	type Args struct {
-		X int `json:"x"`
+		X     int    `json:"x"`
+		Items []Item `json:"items"`
 	}
 	type R struct {
 		Y int `json:"y"`
 	}
-
 	tool, err := NewTool("t", "d", func(_ context.Context, _ *RunEnv, a Args) (R, error) {
 		return R{Y: a.X}, nil
-	})
+	}, WithStrict())
+	require.NoError(t, err)
+	ext, err := NewExtractor[Args](true)
 	require.NoError(t, err)
 
-	m1 := tool.Manifest()
-	obj := findSchemaObject(m1.Parameters)
-	require.NotNil(t, obj, "expected properties in schema")
-	props, ok := obj["properties"].(map[string]any)
-	require.True(t, ok)
-	props["x"] = "mutated_nested"
+	snapshot := func() string {
+		m := tool.Manifest()
+		data, err := json.Marshal([]any{m.Parameters, m.OutputSchema, ext.Schema()})
+		require.NoError(t, err)
+		return string(data)
+	}
+	want := snapshot()
+	m := tool.Manifest()
+	mutateSchemaEverywhere(m.Parameters)
+	mutateSchemaEverywhere(m.OutputSchema)
+	mutateSchemaEverywhere(ext.Schema())
+	assert.JSONEq(t, want, snapshot())
+	assert.NotContains(t, tool.Manifest().Parameters, "mutated")
+}
 
-	m2 := tool.Manifest()
-	obj2 := findSchemaObject(m2.Parameters)
-	require.NotNil(t, obj2)
-	props2 := obj2["properties"].(map[string]any)
-	assert.Equal(t, "mutated_nested", props2["x"], "nested maps are shared")
+func TestToolManifest_MatchesManifestWithoutCopying(t *testing.T) {
+	type Args struct {
+		Query string `json:"query"`
+		Site  string `json:"site"`
+	}
+	base, err := NewTool("search", "Search", func(_ context.Context, _ *RunEnv, a Args) (string, error) {
+		return a.Query, nil
+	}, WithTags("web"))
+	require.NoError(t, err)
+	bound, err := NewBoundTool("docs_search", base, map[string]any{"site": "docs"})
+	require.NoError(t, err)
+	tools := map[string]Tool{
+		"base":     base,
+		"bound":    bound,
+		"override": OverrideTool(bound, WithNewDescription("Docs"), WithNewName("docs")),
+		"wrapped":  WithRetry(RetryPolicy{MaxAttempts: 2})(OverrideTool(base, WithNewName("web"))),
+	}
+	for name, tool := range tools {
+		assert.Equal(t, tool.Manifest(), toolManifest(tool), name)
+		allocs := testing.AllocsPerRun(10, func() { _ = toolManifest(tool) })
+		assert.Zero(t, allocs, name)
+	}
 }
 
 func BenchmarkExecute(b *testing.B) {
@@ -510,3 +563,21 @@ func TestWrapHandlerError_SandboxReadLimitSubject(t *testing.T) {
 	require.Equal(t, CodeValidationFailed, te.Code)
 	require.Contains(t, te.Reason, "stdout exceeds 4096 byte limit")
 }
+
+// BenchmarkRegistryExecute covers the registry path, which reads the tool manifest on every call.
+func BenchmarkRegistryExecute(b *testing.B) {
+	tool, err := NewTool("order", "Place an order", func(_ context.Context, _ *RunEnv, a benchOrderArgs) (int, error) {
+		return len(a.Items), nil
+	})
+	require.NoError(b, err)
+	reg, err := NewRegistryBuilder().Add(tool).Build()
+	require.NoError(b, err)
+	call := ToolCall{ToolName: "order", Input: ToolInput{ArgsJSON: benchOrderJSON(1)}}
+	yield := func(Chunk) error { return nil }
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := reg.Execute(context.Background(), call, yield); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WithChunkBuffer decouples tool handlers from slow consumers with a bounded queue of n chunks.
+// Chunks are validated and copied as without the buffer, then queued; a registry-side forwarder
+// delivers them to the caller's yield in order, so the handler's yield returns as soon as the queue
+// has room and blocks only when it is full. When the consumer's yield fails, the forwarder stops,
+// the handler context is cancelled with that error as its cause, further yields return it, and
+// Execute reports it wrapped in [ErrStreamAborted]. [ExecutionSummary] counts only chunks the
+// consumer accepted. n <= 0 disables buffering (the default: yield delivers inline).
+func WithChunkBuffer(n int) RegistryOption {
+	return func(o *registryOptions) {
+		o.chunkBuffer = n
+	}
+}
+
+type bufferedChunk struct {
+	chunk         Chunk
+	metadataBytes int64
+}
+
+// chunkBuffer is a single-producer queue drained by one forwarder goroutine.
+type chunkBuffer struct {
+	queue chan bufferedChunk
+	done  chan struct{} // closed when the forwarder exits; err and panicked are final afterwards
+	err   error
+	// A panic in the consumer's yield is re-raised by finish on the handler goroutine, where
+	// [WithRecoverPanics] and callers observe it exactly as they would without the buffer.
+	panicked   bool
+	panicValue any
+}
+
+var errChunkConsumerPanic = errors.New("toolsy: chunk consumer panicked")
+
+func startChunkBuffer(
+	size int,
+	deliver func(bufferedChunk) error,
+	cancel context.CancelCauseFunc,
+) *chunkBuffer {
+	b := &chunkBuffer{
+		queue:      make(chan bufferedChunk, size),
+		done:       make(chan struct{}),
+		err:        nil,
+		panicked:   false,
+		panicValue: nil,
+	}
+	go func() {
+		defer close(b.done)
+		defer func() {
+			if p := recover(); p != nil {
+				b.panicked, b.panicValue = true, p
+				b.err = errChunkConsumerPanic
+				cancel(errChunkConsumerPanic)
+			}
+		}()
+		for item := range b.queue {
+			if err := deliver(item); err != nil {
+				b.err = err
+				cancel(err)
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// push queues item, blocking while the queue is full. It returns the consumer error once the
+// forwarder has stopped, without queueing.
+func (b *chunkBuffer) push(item bufferedChunk) error {
+	select {
+	case <-b.done:
+		return b.err
+	default:
+	}
+	select {
+	case b.queue <- item:
+		return nil
+	case <-b.done:
+		return b.err
+	}
+}
+
+// finish closes the queue, waits until every queued chunk was delivered or the forwarder failed,
+// and returns the consumer error. It re-panics with the consumer's panic value.
+func (b *chunkBuffer) finish() error {
+	close(b.queue)
+	<-b.done
+	if b.panicked {
+		panic(b.panicValue)
+	}
+	return b.err
+}
+
+// runToolBuffered runs the tool like runToolWithValidationAndExecute with yields routed through a
+// [WithChunkBuffer] queue. The handler gets a context that is cancelled when the consumer fails.
+func (r *Registry) runToolBuffered(
+	ctx context.Context,
+	call ToolCall,
+	env *RunEnv,
+	tool Tool,
+	summary *ExecutionSummary,
+	start time.Time,
+	yield func(Chunk) error,
+) {
+	execCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	buffer := startChunkBuffer(r.opts.chunkBuffer, func(item bufferedChunk) error {
+		return r.deliverChunk(ctx, item.chunk, item.metadataBytes, summary, start, yield)
+	}, cancel)
+	defer func() {
+		consumerErr := buffer.finish()
+		if consumerErr == nil {
+			return
+		}
+		if errors.Is(consumerErr, ErrStreamAborted) {
+			summary.Error = consumerErr
+		} else if !errors.Is(summary.Error, ErrStreamAborted) {
+			summary.Error = wrapYieldError(consumerErr)
+		}
+	}()
+	toolYield := r.wrapYieldWithCallMeta(ctx, call, summary, start, yield, buffer)
+	r.runToolWithValidationAndExecute(execCtx, call, env, tool, toolYield, summary)
+}
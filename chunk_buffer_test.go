@@ -0,0 +1,117 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingStreamTool(name string, total int, handlerDone chan<- struct{}) Tool {
+	return newMiddlewareMinTool(name, func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+		defer close(handlerDone)
+		for i := range total {
+			if err := yield(Chunk{Event: EventResult, Data: []byte(strconv.Itoa(i)), MimeType: MimeTypeText}); err != nil {
+				return wrapYieldError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func TestWithChunkBuffer_ProducerFinishesBeforeSlowConsumer(t *testing.T) {
+	const buffered = 4
+	handlerDone := make(chan struct{})
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(
+		t,
+		[]Tool{newCountingStreamTool("fast", buffered+1, handlerDone)},
+		WithChunkBuffer(buffered),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	)
+
+	var got []string
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "fast", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(c Chunk) error {
+			if len(got) == 0 {
+				select {
+				case <-handlerDone:
+				case <-time.After(5 * time.Second):
+					return errors.New("handler blocked behind slow consumer")
+				}
+			}
+			got = append(got, string(c.Data))
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, got)
+	assert.Equal(t, buffered+1, summary.ChunksDelivered)
+	assert.Equal(t, int64(buffered+1), summary.TotalBytes)
+}
+
+func TestWithChunkBuffer_ConsumerAbortStopsProducer(t *testing.T) {
+	handlerDone := make(chan struct{})
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(
+		t,
+		[]Tool{newCountingStreamTool("endless", 1_000_000, handlerDone)},
+		WithChunkBuffer(8),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	)
+	clientGone := errors.New("client gone")
+	delivered := 0
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "endless", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error {
+			if delivered == 2 {
+				return clientGone
+			}
+			delivered++
+			return nil
+		})
+	require.ErrorIs(t, err, ErrStreamAborted)
+	require.ErrorIs(t, err, clientGone)
+	<-handlerDone
+	assert.Equal(t, 2, summary.ChunksDelivered, "only chunks accepted by the consumer are counted")
+}
+
+func TestWithChunkBuffer_ConsumerFailureCancelsHandlerContext(t *testing.T) {
+	clientGone := errors.New("client gone")
+	var cause error
+	ignoreYield := func(ctx context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				cause = context.Cause(ctx)
+				return ctx.Err()
+			default:
+				_ = yield(Chunk{Event: EventResult, Data: []byte("x"), MimeType: MimeTypeText})
+			}
+		}
+	}
+	reg := mustBuildRegistry(t, []Tool{newMiddlewareMinTool("ignores_yield", ignoreYield)}, WithChunkBuffer(2))
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "ignores_yield", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error { return clientGone })
+	require.ErrorIs(t, err, ErrStreamAborted)
+	require.ErrorIs(t, err, clientGone)
+	require.ErrorIs(t, cause, clientGone)
+}
+
+func TestWithChunkBuffer_ConsumerPanicRecovered(t *testing.T) {
+	handlerDone := make(chan struct{})
+	reg := mustBuildRegistry(
+		t,
+		[]Tool{newCountingStreamTool("panicky", 3, handlerDone)},
+		WithChunkBuffer(1),
+		WithRecoverPanics(true),
+	)
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "panicky", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error { panic("consumer exploded") })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	<-handlerDone
+}
@@ -0,0 +1,52 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithMaxMetadataBytes caps the JSON-encoded size of the metadata a tool attaches to each delivered
+// chunk: Envelope.Metadata plus the keys the tool set in [Chunk.Metadata]. Call metadata the registry
+// merges into [Chunk.Metadata] is not counted. A tool that exceeds the cap fails with a non-retryable
+// [CodeInternal] error wrapping [ErrMetadataTooLarge]: oversized metadata is a programming bug, not
+// something the model can fix. n <= 0 disables the cap. Metadata size is always reported in
+// [ExecutionSummary.MetadataBytes].
+func WithMaxMetadataBytes(n int) RegistryOption {
+	return func(o *registryOptions) {
+		o.maxMetadataSize = n
+	}
+}
+
+// checkChunkMetadata returns the encoded metadata size of c, or an error when it exceeds the cap.
+// toolMetadata is [Chunk.Metadata] as the tool yielded it, before call metadata was merged in.
+func (r *Registry) checkChunkMetadata(c Chunk, toolMetadata map[string]any) (int64, error) {
+	size := chunkMetadataBytes(c, toolMetadata)
+	if limit := r.opts.maxMetadataSize; limit > 0 && size > int64(limit) {
+		return 0, NewInternalError(fmt.Errorf("%w: %d bytes, limit %d (tool %q)", ErrMetadataTooLarge, size, limit,
+			c.ToolName))
+	}
+	return size, nil
+}
+
+// chunkMetadataBytes measures Envelope.Metadata and the tool-set chunk metadata as they would be
+// persisted.
+func chunkMetadataBytes(c Chunk, toolMetadata map[string]any) int64 {
+	var size int64
+	if c.Envelope != nil {
+		size += encodedMetadataSize(c.Envelope.Metadata)
+	}
+	return size + encodedMetadataSize(toolMetadata)
+}
+
+// encodedMetadataSize is the JSON-encoded size of m. Values that do not encode as JSON fall back to the
+// in-memory estimate used by [Registry.MemoryFootprint].
+func encodedMetadataSize(m map[string]any) int64 {
+	if len(m) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return valueFootprint(m)
+	}
+	return int64(len(data))
+}
@@ -0,0 +1,122 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metadataTool(name string, metadata map[string]any) Tool {
+	return &minTool{
+		manifest: ToolManifest{Name: name, Description: "d", Parameters: map[string]any{"type": "object"}},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			data := []byte(`{"ok":true}`)
+			return yield(Chunk{
+				Event:    EventResult,
+				Data:     data,
+				MimeType: MimeTypeJSON,
+				Envelope: NewResultEnvelope(nil, data, MimeTypeJSON, "", "", metadata),
+			})
+		},
+	}
+}
+
+func TestRegistry_MetadataBytesAccounted(t *testing.T) {
+	t.Parallel()
+	metadata := map[string]any{"source": "crm"}
+	encoded, err := json.Marshal(metadata)
+	require.NoError(t, err)
+
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{metadataTool("meta", metadata)},
+		WithMaxMetadataBytes(1024),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	)
+	require.NoError(t, reg.Execute(context.Background(), ToolCall{ToolName: "meta"}, func(Chunk) error { return nil }))
+	assert.Equal(t, int64(len(encoded)), summary.MetadataBytes)
+	assert.Equal(t, int64(len(`{"ok":true}`)), summary.TotalBytes)
+}
+
+func TestRegistry_MaxMetadataBytes_RejectsOversizedChunk(t *testing.T) {
+	t.Parallel()
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{metadataTool("meta", map[string]any{"doc": strings.Repeat("x", 4096)})},
+		WithMaxMetadataBytes(1024),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	)
+	delivered := 0
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "meta"}, func(Chunk) error {
+		delivered++
+		return nil
+	})
+	require.ErrorIs(t, err, ErrMetadataTooLarge)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.False(t, te.Retryable)
+	assert.False(t, clientCorrectable(err))
+	assert.Zero(t, delivered)
+	assert.Zero(t, summary.MetadataBytes)
+	assert.Zero(t, summary.ChunksDelivered)
+}
+
+func TestRegistry_MaxMetadataBytes_BatchReportsPerCall(t *testing.T) {
+	t.Parallel()
+	reg := mustBuildRegistry(t, []Tool{
+		metadataTool("big", map[string]any{"doc": strings.Repeat("x", 4096)}),
+		metadataTool("small", map[string]any{"k": "v"}),
+	}, WithMaxMetadataBytes(1024))
+
+	var mu sync.Mutex
+	results := map[string]Chunk{}
+	err := reg.ExecuteBatchStream(context.Background(), []ToolCall{
+		{ToolName: "big", Input: ToolInput{CallID: "1"}},
+		{ToolName: "small", Input: ToolInput{CallID: "2"}},
+	}, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		results[c.CallID] = c
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, results["1"].IsError)
+	te := executionErrorFromChunk(results["1"])
+	require.NotNil(t, te)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.False(t, results["2"].IsError)
+}
+
+func TestRegistry_MaxMetadataBytes_CountsToolChunkMetadata(t *testing.T) {
+	t.Parallel()
+	doc := strings.Repeat("x", 4096)
+	tool := &minTool{
+		manifest: ToolManifest{Name: "meta", Description: "d", Parameters: map[string]any{"type": "object"}},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			return yield(Chunk{
+				Event:    EventResult,
+				Data:     []byte("ok"),
+				MimeType: MimeTypeText,
+				Metadata: map[string]any{"doc": doc},
+			})
+		},
+	}
+	reg := mustBuildRegistry(t, []Tool{tool}, WithMaxMetadataBytes(1024))
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "meta"}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrMetadataTooLarge)
+
+	var summary ExecutionSummary
+	reg = mustBuildRegistry(t, []Tool{metadataTool("meta", nil)},
+		WithMaxMetadataBytes(16),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	)
+	call := ToolCall{ToolName: "meta", Metadata: map[string]any{"trace": doc}}
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }),
+		"call metadata merged by the registry is not counted")
+	assert.Zero(t, summary.MetadataBytes)
+}
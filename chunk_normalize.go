@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -51,10 +52,16 @@ func validateErrorChunk(c Chunk) error {
 }
 
 // normalizeErrorChunk wraps legacy text (or other) error chunks in a structured ToolError envelope.
+// Chunks whose envelope still holds the tool-error wire ([WithBatchLLMErrors]) get it back as Data.
 func normalizeErrorChunk(c Chunk) Chunk {
 	if !c.IsError || c.MimeType == MimeTypeToolErrorJSON {
 		return c
 	}
+	if env := c.Envelope; env != nil && env.Kind == ToolEnvelopeKindError && env.MimeType == MimeTypeToolErrorJSON &&
+		json.Valid(env.Raw) {
+		c.Data, c.MimeType = slices.Clone(env.Raw), MimeTypeToolErrorJSON
+		return c
+	}
 	reason := "tool returned malformed error chunk: expected " + MimeTypeToolErrorJSON
 	if detail := malformedErrorChunkDetail(c); detail != "" {
 		reason += "; " + detail
@@ -81,6 +88,7 @@ func malformedErrorChunkDetail(c Chunk) string {
 }
 
 // prepareChunk normalizes error chunks and validates the wire contract before delivery.
+// Progress and Envelope (including Metadata) are copied so later mutation by the tool cannot leak into consumers.
 func prepareChunk(c Chunk) (Chunk, error) {
 	if c.IsError {
 		c = normalizeErrorChunk(c)
@@ -88,6 +96,9 @@ func prepareChunk(c Chunk) (Chunk, error) {
 	if err := validateChunk(c); err != nil {
 		return Chunk{}, err
 	}
+	if c.Progress != nil {
+		c.Progress = cloneProgressInfo(c.Progress)
+	}
 	if c.Envelope != nil {
 		c.Envelope = cloneToolEnvelope(c.Envelope)
 	} else if c.Event == EventResult {
@@ -96,3 +107,16 @@ func prepareChunk(c Chunk) (Chunk, error) {
 	}
 	return c, nil
 }
+
+func cloneProgressInfo(in *ProgressInfo) *ProgressInfo {
+	out := *in
+	if in.Percent != nil {
+		percent := *in.Percent
+		out.Percent = &percent
+	}
+	if in.Total != nil {
+		total := *in.Total
+		out.Total = &total
+	}
+	return &out
+}
@@ -0,0 +1,79 @@
+package toolsy
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// CloneMetadata returns a deep copy of a metadata map (nested maps, slices, and byte slices included).
+// Use it when a tool wants to keep mutating a map after passing it to [NewResultEnvelope] or a yield.
+// It returns nil for an empty map.
+func CloneMetadata(m map[string]any) map[string]any {
+	return deepCloneMap(m)
+}
+
+// WithStrictChunkOwnership enables a debug check that warns when one execution yields the same
+// Data buffer or Effects slice more than once. Progress and Envelope are copied by the registry, so
+// reusing them is allowed. Reuse means the tool still owns memory that consumers may have stored; see
+// [Chunk] for the ownership rules. A nil logger uses [slog.Default]. The check keeps every yielded
+// Data and Effects slice reachable until the call ends, so the addresses it compares cannot be
+// recycled by the garbage collector mid-call; keep it out of production.
+func WithStrictChunkOwnership(logger *slog.Logger) RegistryOption {
+	return func(o *registryOptions) {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		o.ownershipLogger = logger
+	}
+}
+
+// chunkOwnershipTracker remembers buffer identities yielded during one execution.
+type chunkOwnershipTracker struct {
+	logger   *slog.Logger
+	toolName string
+	seen     map[uintptr]ownedBuffer
+}
+
+// ownedBuffer holds the yielded slice itself, keeping its backing array alive so that no later
+// allocation in the same call can reuse the address recorded for it.
+type ownedBuffer struct {
+	field string
+	ref   any
+}
+
+func newChunkOwnershipTracker(logger *slog.Logger, toolName string) *chunkOwnershipTracker {
+	if logger == nil {
+		return nil
+	}
+	return &chunkOwnershipTracker{
+		logger:   logger,
+		toolName: toolName,
+		seen:     make(map[uintptr]ownedBuffer),
+	}
+}
+
+// observe records the identities of the fields the registry forwards as is and warns on reuse.
+func (t *chunkOwnershipTracker) observe(c Chunk) {
+	if t == nil {
+		return
+	}
+	if len(c.Data) > 0 {
+		t.check("Data", c.Data)
+	}
+	if len(c.Effects) > 0 {
+		t.check("Effects", c.Effects)
+	}
+}
+
+func (t *chunkOwnershipTracker) check(field string, slice any) {
+	ptr := reflect.ValueOf(slice).Pointer()
+	if prev, dup := t.seen[ptr]; dup {
+		t.logger.Warn("toolsy: chunk memory reused across yields",
+			"tool", t.toolName,
+			"field", field,
+			"first_seen", prev.field,
+		)
+		return
+	}
+	t.seen[ptr] = ownedBuffer{field: field, ref: slice}
+}
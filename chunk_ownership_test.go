@@ -0,0 +1,122 @@
+package toolsy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reusingBufferTool(name string) Tool {
+	return minTool{
+		manifest: ToolManifest{Name: name},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			buf := []byte("a")
+			for range 2 {
+				buf[0]++
+				if err := yield(Chunk{Event: EventResult, Data: buf, MimeType: MimeTypeText}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestWithStrictChunkOwnership_WarnsOnReusedData(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	reg := mustBuildRegistry(t, []Tool{reusingBufferTool("reuser")}, WithStrictChunkOwnership(logger))
+
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "reuser"}, func(Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "chunk memory reused across yields")
+	assert.Contains(t, logs.String(), "tool=reuser")
+	assert.Contains(t, logs.String(), "field=Data")
+}
+
+func TestWithStrictChunkOwnership_SilentForFreshBuffers(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	stream := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		for _, s := range []string{"a", "b"} {
+			if err := yield(Chunk{Event: EventResult, Data: []byte(s), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tool, err := NewStreamTool("fresh", "fresh", stream)
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool}, WithStrictChunkOwnership(logger))
+
+	call := ToolCall{ToolName: "fresh", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	err = reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Empty(t, logs.String())
+}
+
+func TestWithStrictChunkOwnership_AllowsReusedProgressAndEnvelope(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	tool := minTool{
+		manifest: ToolManifest{Name: "progress"},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			progress := &ProgressInfo{Message: "step"}
+			envelope := NewResultEnvelope(nil, nil, "", "", "", map[string]any{"step": 1})
+			for range 2 {
+				if err := yield(Chunk{Event: EventProgress, Progress: progress, Envelope: envelope}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	reg := mustBuildRegistry(t, []Tool{tool}, WithStrictChunkOwnership(logger))
+
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "progress"}, func(Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Empty(t, logs.String(), "the registry copies Progress and Envelope, so reuse is fine")
+}
+
+func TestRegistry_ProgressAndMetadataCopiedPerChunk(t *testing.T) {
+	tool := minTool{
+		manifest: ToolManifest{Name: "mutator"},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			progress := &ProgressInfo{Message: "first"}
+			meta := map[string]any{"step": 1}
+			envelope := NewResultEnvelope(nil, nil, "", "", "", nil)
+			envelope.Metadata = meta
+			if err := yield(Chunk{Event: EventProgress, Progress: progress, Envelope: envelope}); err != nil {
+				return err
+			}
+			progress.Message = "second"
+			meta["step"] = 2
+			return nil
+		},
+	}
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	var stored []Chunk
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "mutator"}, func(c Chunk) error {
+		stored = append(stored, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "first", stored[0].Progress.Message)
+	assert.Equal(t, 1, stored[0].Envelope.Metadata["step"])
+}
+
+func TestCloneMetadata_DeepCopy(t *testing.T) {
+	in := map[string]any{"nested": map[string]any{"k": "v"}, "list": []any{"a"}}
+	out := CloneMetadata(in)
+	out["nested"].(map[string]any)["k"] = "changed"
+	out["list"].([]any)[0] = "b"
+	assert.Equal(t, "v", in["nested"].(map[string]any)["k"])
+	assert.Equal(t, "a", in["list"].([]any)[0])
+	assert.Nil(t, CloneMetadata(nil))
+}
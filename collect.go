@@ -0,0 +1,170 @@
+package toolsy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CollectOption configures [Registry.ExecuteCollect], [CollectTool], and [ExecuteInto].
+type CollectOption func(*collectOptions)
+
+type collectMode int
+
+const (
+	collectDefault collectMode = iota
+	collectJoin
+	collectSingle
+	collectLast
+	collectArray
+)
+
+type collectOptions struct {
+	mode collectMode
+}
+
+// WithCollectJSONArray returns multi-chunk streams as a JSON array with one element per
+// [EventResult] chunk instead of joining their bytes. Chunks that are not valid JSON become
+// JSON strings. A call that yields no result chunk collects to [].
+func WithCollectJSONArray() CollectOption {
+	return func(o *collectOptions) {
+		o.mode = collectArray
+	}
+}
+
+// WithCollectLastChunk keeps only the data of the last [EventResult] chunk, matching
+// [CallResult.Result] in [Registry.ExecuteBatch].
+func WithCollectLastChunk() CollectOption {
+	return func(o *collectOptions) {
+		o.mode = collectLast
+	}
+}
+
+// ExecuteCollect runs call through [Registry.Execute] and returns the collected [EventResult] data.
+// By default the data of all result chunks is joined in order, so single-chunk tools return their
+// payload unchanged and text streams return the whole text; use [WithCollectJSONArray] to keep chunk
+// boundaries. Progress and [EventDone] chunks are dropped. Errors from Execute are returned unchanged;
+// a soft error chunk is returned as its [*ToolError]. All registry options (timeouts, hooks, load
+// shedding) apply because the call goes through Execute.
+func (r *Registry) ExecuteCollect(
+	ctx context.Context,
+	call ToolCall,
+	opts ...CollectOption,
+) (json.RawMessage, error) {
+	c := newChunkCollector(collectJoin, opts)
+	if err := r.Execute(ctx, call, c.add); err != nil {
+		return nil, err
+	}
+	return c.result()
+}
+
+// CollectTool runs t directly with argsJSON and collects its result like [Registry.ExecuteCollect].
+// No registry options apply: there is no timeout, hook, or load shedding.
+func CollectTool(ctx context.Context, t Tool, argsJSON []byte, opts ...CollectOption) (json.RawMessage, error) {
+	c := newChunkCollector(collectJoin, opts)
+	input := ToolInput{ArgsJSON: argsJSON} //nolint:exhaustruct // direct calls carry no call ID or attachments
+	if err := t.Execute(ctx, NewRunEnv(nil), input, c.add); err != nil {
+		return nil, err
+	}
+	return c.result()
+}
+
+type chunkCollector struct {
+	opts   collectOptions
+	chunks [][]byte
+	err    error
+}
+
+func newChunkCollector(fallback collectMode, opts []CollectOption) *chunkCollector {
+	c := &chunkCollector{opts: collectOptions{mode: collectDefault}, chunks: nil, err: nil}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	if c.opts.mode == collectDefault {
+		c.opts.mode = fallback
+	}
+	return c
+}
+
+func (c *chunkCollector) add(chunk Chunk) error {
+	if chunk.IsError {
+		if c.err == nil {
+			c.err = executionErrorFromChunk(chunk)
+		}
+		return nil
+	}
+	if chunk.Event != EventResult {
+		return nil
+	}
+	c.chunks = append(c.chunks, chunk.Data)
+	return nil
+}
+
+func (c *chunkCollector) result() (json.RawMessage, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	switch c.opts.mode {
+	case collectArray:
+		return collectJSONArray(c.chunks)
+	case collectLast:
+		if len(c.chunks) == 0 {
+			return nil, nil
+		}
+		return c.chunks[len(c.chunks)-1], nil
+	case collectSingle:
+		if len(c.chunks) != 1 {
+			return nil, NewInternalError(fmt.Errorf(
+				"toolsy: tool yielded %d result chunks; ExecuteInto only supports single-result tools "+
+					"unless WithCollectLastChunk or WithCollectJSONArray is set", len(c.chunks)))
+		}
+		return c.chunks[0], nil
+	case collectDefault, collectJoin:
+	}
+	if len(c.chunks) == 1 {
+		return c.chunks[0], nil
+	}
+	return bytes.Join(c.chunks, nil), nil
+}
+
+func collectJSONArray(chunks [][]byte) (json.RawMessage, error) {
+	items := make([]json.RawMessage, 0, len(chunks))
+	for _, data := range chunks {
+		if json.Valid(data) {
+			items = append(items, data)
+			continue
+		}
+		s, err := json.Marshal(string(data))
+		if err != nil {
+			return nil, NewInternalError(fmt.Errorf("toolsy: collect chunk: %w", err))
+		}
+		items = append(items, s)
+	}
+	out, err := json.Marshal(items)
+	if err != nil {
+		return nil, NewInternalError(fmt.Errorf("toolsy: collect chunks: %w", err))
+	}
+	return out, nil
+}
+
+// ExecuteInto runs call through [Registry.Execute] and unmarshals the result into R. By default
+// the tool must yield exactly one [EventResult] chunk, which fits tools built with [NewTool]; streaming
+// tools need [WithCollectLastChunk] or [WithCollectJSONArray]. Output that does not unmarshal into R is
+// the tool's fault, so it is reported as a [CodeInternal] error rather than a client-correctable one.
+func ExecuteInto[R any](ctx context.Context, reg *Registry, call ToolCall, opts ...CollectOption) (R, error) {
+	var zero R
+	c := newChunkCollector(collectSingle, opts)
+	if err := reg.Execute(ctx, call, c.add); err != nil {
+		return zero, err
+	}
+	data, err := c.result()
+	if err != nil {
+		return zero, err
+	}
+	var out R
+	if err := json.Unmarshal(data, &out); err != nil {
+		return zero, NewInternalError(fmt.Errorf("toolsy: decode %s result: %w", call.ToolName, err))
+	}
+	return out, nil
+}
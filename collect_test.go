@@ -0,0 +1,152 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collectArgs struct {
+	Name string `json:"name"`
+}
+
+type collectOut struct {
+	Greeting string `json:"greeting"`
+}
+
+func collectRegistry(t *testing.T, opts ...RegistryOption) *Registry {
+	t.Helper()
+	greet, err := NewTool("greet", "Greets", func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: "hi " + a.Name}, nil
+	})
+	require.NoError(t, err)
+	stream := func(ctx context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		ReportProgress(ctx, 10, "starting")
+		for _, s := range []string{`{"n":1}`, "plain"} {
+			if err := yield(Chunk{Event: EventResult, Data: []byte(s), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	parts, err := NewStreamTool("parts", "Two chunks", stream)
+	require.NoError(t, err)
+	fail, err := NewTool("fail", "Fails", func(context.Context, *RunEnv, struct{}) (collectOut, error) {
+		return collectOut{}, NewValidationError("bad input")
+	})
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder(opts...).Add(greet, parts, fail).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func TestExecuteCollect_SingleChunk(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t, WithFinalChunk())
+	out, err := reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "greet", Input: ToolInput{ArgsJSON: []byte(`{"name":"ann"}`)}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"hi ann"}`, string(out))
+}
+
+func TestExecuteCollect_MultiChunkRules(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t)
+	call := ToolCall{ToolName: "parts", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	joined, err := reg.ExecuteCollect(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":1}plain`, string(joined))
+
+	arr, err := reg.ExecuteCollect(context.Background(), call, WithCollectJSONArray())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"n":1},"plain"]`, string(arr))
+}
+
+func TestExecuteCollect_ReturnsErrorsUnchanged(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t)
+	_, err := reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "fail", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "missing", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	require.ErrorIs(t, err, ErrToolNotFound)
+}
+
+func TestCollectTool_ErrorChunk(t *testing.T) {
+	t.Parallel()
+	soft := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		return yield(NewErrorChunkFromErr(NewValidationError("quota exceeded")))
+	}
+	tool, err := NewStreamTool("soft", "Soft error", soft)
+	require.NoError(t, err)
+	_, err = CollectTool(context.Background(), tool, []byte(`{}`))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, "quota exceeded", te.Reason)
+}
+
+func TestCollectTool_Direct(t *testing.T) {
+	t.Parallel()
+	tool, err := NewTool("greet", "Greets", func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: "hi " + a.Name}, nil
+	})
+	require.NoError(t, err)
+	out, err := CollectTool(context.Background(), tool, []byte(`{"name":"bo"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"hi bo"}`, string(out))
+
+	silent, err := NewStreamTool("silent", "Nothing", func(context.Context, *RunEnv, struct{}, func(Chunk) error) error {
+		return nil
+	})
+	require.NoError(t, err)
+	empty, err := CollectTool(context.Background(), silent, []byte(`{}`), WithCollectJSONArray())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(empty))
+}
+
+func TestExecuteInto_DecodesSingleResult(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t, WithFinalChunk())
+	out, err := ExecuteInto[collectOut](context.Background(), reg,
+		ToolCall{ToolName: "greet", Input: ToolInput{ArgsJSON: []byte(`{"name":"ann"}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, "hi ann", out.Greeting)
+}
+
+func TestExecuteInto_StreamingNeedsOption(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t)
+	call := ToolCall{ToolName: "parts", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	_, err := ExecuteInto[map[string]int](context.Background(), reg, call)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.ErrorContains(t, te.Err, "single-result")
+
+	arr, err := ExecuteInto[[]any](context.Background(), reg, call, WithCollectJSONArray())
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"n": float64(1)}, "plain"}, arr)
+
+	_, err = ExecuteInto[map[string]int](context.Background(), reg, call, WithCollectLastChunk())
+	te, ok = AsToolError(err)
+	require.True(t, ok, "malformed output is a system error")
+	assert.Equal(t, CodeInternal, te.Code)
+}
+
+func TestExecuteInto_ReturnsToolErrors(t *testing.T) {
+	t.Parallel()
+	reg := collectRegistry(t)
+	_, err := ExecuteInto[collectOut](context.Background(), reg,
+		ToolCall{ToolName: "fail", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/skosovsky/toolsy"
 	"github.com/skosovsky/toolsy/textprocessor"
@@ -18,14 +19,23 @@ import (
 
 const truncationSuffix = textprocessor.ContractsTruncationSuffix
 
-// execute runs the HTTP request for one operation: path params in path, query params in query string, body params in body only.
+// operation is one spec operation prepared for execution, with its argument names split by location.
+type operation struct {
+	toolName     string
+	method       string
+	pathTemplate string
+	pathParams   []string
+	queryParams  []string
+	headerParams []string
+	bodyParams   []string
+}
+
+// execute runs the HTTP request for one operation: path params in path, query params in query string,
+// header params as request headers, body params in body only.
 func execute(
 	ctx context.Context,
 	run *toolsy.RunEnv,
-	toolName string,
-	method, pathTemplate string,
-	pathParamNames, queryParamNames []string,
-	bodyParamNames []string,
+	op *operation,
 	argsJSON []byte,
 	opts *Options,
 	yield func(toolsy.Chunk) error,
@@ -41,42 +51,52 @@ func execute(
 		return err
 	}
 
-	pathParamsSet := paramNameSet(pathParamNames)
-	queryParamsSet := paramNameSet(queryParamNames)
+	pathParamsSet := paramNameSet(op.pathParams)
+	queryParamsSet := paramNameSet(op.queryParams)
 
-	substitutedPath := substitutePathParams(pathTemplate, args, pathParamsSet)
+	substitutedPath := substitutePathParams(op.pathTemplate, args, pathParamsSet)
 	u, err := buildRequestURL(baseURL, substitutedPath, args, pathParamsSet, queryParamsSet)
 	if err != nil {
 		return err
 	}
 
 	var body io.Reader
-	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
-		b, marshalErr := marshalRequestBodyJSON(bodyParamNames, args)
+	if op.method == http.MethodPost || op.method == http.MethodPut || op.method == http.MethodPatch {
+		b, marshalErr := marshalRequestBodyJSON(op.bodyParams, args)
 		if marshalErr != nil {
 			return marshalErr
 		}
-		if len(bodyParamNames) > 0 {
+		if len(op.bodyParams) > 0 {
 			body = bytes.NewReader(b)
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, op.method, u.String(), body)
 	if err != nil {
 		return fmt.Errorf("openapi: request: %w", err)
 	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for _, name := range op.headerParams {
+		if v, ok := args[name]; ok {
+			req.Header.Set(name, fmt.Sprint(v))
+		}
+	}
 	if run.Credentials != nil {
-		authHeader, authErr := run.Credentials.GetAuth(ctx, toolName)
+		authHeader, authErr := run.Credentials.GetAuth(ctx, op.toolName)
 		if authErr != nil {
-			return fmt.Errorf("openapi: credentials for %s: %w", toolName, authErr)
+			return fmt.Errorf("openapi: credentials for %s: %w", op.toolName, authErr)
 		}
 		if authHeader != "" {
 			req.Header.Set("Authorization", authHeader)
 		}
 	}
+	if opts.PrepareRequest != nil {
+		if prepErr := opts.PrepareRequest(ctx, op.toolName, req); prepErr != nil {
+			return fmt.Errorf("openapi: prepare request for %s: %w", op.toolName, prepErr)
+		}
+	}
 
 	// #nosec G704 -- URL from Options/spec, not user input
 	resp, err := client.Do(req) //nolint:bodyclose // closed via httptool.CloseResponseBody
@@ -88,6 +108,9 @@ func execute(
 		return fmt.Errorf("openapi: response status %d", resp.StatusCode)
 	}
 
+	if opts.StreamChunkBytes > 0 {
+		return streamResponse(ctx, resp.Body, opts.StreamChunkBytes, opts.maxResponseBytes(), yield)
+	}
 	text, err := textprocessor.ReadAndTruncate(ctx, resp.Body, opts.maxResponseBytes(), truncationSuffix)
 	if err != nil {
 		return fmt.Errorf("openapi: read response: %w", err)
@@ -164,3 +187,58 @@ func marshalRequestBodyJSON(bodyParamNames []string, args map[string]any) ([]byt
 	}
 	return bodyBytes, nil
 }
+
+// streamResponse yields the body as result chunks of at most chunkBytes, cut on UTF-8 rune boundaries.
+// Once maxBytes have been read the last chunk gets the truncation suffix and the rest is not read.
+func streamResponse(
+	ctx context.Context,
+	body io.Reader,
+	chunkBytes, maxBytes int,
+	yield func(toolsy.Chunk) error,
+) error {
+	r := textprocessor.ReaderWithContext(ctx, io.LimitReader(body, int64(maxBytes)+1))
+	buf := make([]byte, max(chunkBytes, utf8.UTFMax))
+	carried, total := 0, 0
+	for {
+		n, err := io.ReadFull(r, buf[carried:])
+		final := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !final {
+			return fmt.Errorf("openapi: read response: %w", err)
+		}
+		total += n
+		end := carried + n
+		truncated := total > maxBytes
+		if truncated {
+			end -= total - maxBytes
+			final = true
+		}
+		cut := completeRunesLen(buf[:end])
+		data := bytes.Clone(buf[:cut])
+		carried = copy(buf, buf[cut:end])
+		if truncated {
+			data = append(data, truncationSuffix...)
+		}
+		if len(data) > 0 {
+			chunk := toolsy.Chunk{Event: toolsy.EventResult, Data: data, MimeType: toolsy.MimeTypeText}
+			if yieldErr := yield(chunk); yieldErr != nil {
+				return yieldErr
+			}
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// completeRunesLen returns the length of b without a trailing incomplete UTF-8 sequence.
+func completeRunesLen(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
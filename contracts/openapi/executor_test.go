@@ -60,12 +60,7 @@ func TestExecuteTruncatesOversizedResponse(t *testing.T) {
 	err := execute(
 		context.Background(),
 		toolsy.NewRunEnv(nil),
-		"list_items",
-		http.MethodGet,
-		"/items",
-		nil,
-		nil,
-		nil,
+		&operation{toolName: "list_items", method: http.MethodGet, pathTemplate: "/items"},
 		[]byte(`{}`),
 		&Options{
 			BaseURL:          server.URL,
@@ -102,12 +97,7 @@ func TestExecuteTruncatesOversizedResponseUTF8Safely(t *testing.T) {
 	err := execute(
 		context.Background(),
 		toolsy.NewRunEnv(nil),
-		"list_items",
-		http.MethodGet,
-		"/items",
-		nil,
-		nil,
-		nil,
+		&operation{toolName: "list_items", method: http.MethodGet, pathTemplate: "/items"},
 		[]byte(`{}`),
 		&Options{
 			BaseURL:          server.URL,
@@ -148,12 +138,7 @@ func TestExecuteReadsAtMostMaxBytesPlusOne(t *testing.T) {
 	err := execute(
 		context.Background(),
 		toolsy.NewRunEnv(nil),
-		"list_items",
-		http.MethodGet,
-		"/items",
-		nil,
-		nil,
-		nil,
+		&operation{toolName: "list_items", method: http.MethodGet, pathTemplate: "/items"},
 		[]byte(`{}`),
 		&Options{
 			BaseURL:          server.URL,
@@ -185,12 +170,7 @@ func TestExecute_Non2xxStatus(t *testing.T) {
 	err := execute(
 		context.Background(),
 		toolsy.NewRunEnv(nil),
-		"list_items",
-		http.MethodGet,
-		"/items",
-		nil,
-		nil,
-		nil,
+		&operation{toolName: "list_items", method: http.MethodGet, pathTemplate: "/items"},
 		[]byte(`{}`),
 		&Options{
 			BaseURL:         server.URL,
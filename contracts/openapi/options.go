@@ -1,6 +1,11 @@
 package openapi
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+
+	"github.com/skosovsky/toolsy"
+)
 
 const defaultMaxResponseBytes = 512 * 1024
 
@@ -19,6 +24,33 @@ type Options struct {
 	MaxResponseBytes int
 	// AllowPrivateIPs relaxes SSRF IP blocking for tests and private networks (e.g. httptest on 127.0.0.1).
 	AllowPrivateIPs bool
+	// MaxTools aborts the import once the spec yields more tools than this (0 means unlimited).
+	MaxTools int
+	// OnTool is called for every tool as soon as it is built; a non-nil error aborts the import.
+	// Pair it with [toolsy.RegistryBuilder.Remaining] to stop before the registry limit is reached.
+	OnTool func(tool toolsy.Tool) error
+	// StreamChunkBytes streams the response as result chunks of at most this many bytes instead of one
+	// chunk (0 means a single chunk). MaxResponseBytes still caps the total.
+	StreamChunkBytes int
+	// PrepareRequest is called with every outgoing request after [toolsy.RunEnv] credentials are applied;
+	// use it to inject auth headers (see [BearerToken] and [StaticHeader]). An error aborts the call.
+	PrepareRequest RequestHook
+}
+
+// RequestHook edits an outgoing request for the named tool before it is sent.
+type RequestHook func(ctx context.Context, toolName string, req *http.Request) error
+
+// BearerToken returns a [RequestHook] that sets "Authorization: Bearer <token>".
+func BearerToken(token string) RequestHook {
+	return StaticHeader("Authorization", "Bearer "+token)
+}
+
+// StaticHeader returns a [RequestHook] that sets header name to value on every request (e.g. an API key).
+func StaticHeader(name, value string) RequestHook {
+	return func(_ context.Context, _ string, req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	}
 }
 
 func (o *Options) httpClient() HTTPClient {
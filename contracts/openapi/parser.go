@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -13,6 +14,9 @@ import (
 	"github.com/skosovsky/toolsy/toolkits/httptool"
 )
 
+// ErrTooManyTools is returned when a spec yields more operations than [Options.MaxTools].
+var ErrTooManyTools = errors.New("openapi: spec exceeds tool limit")
+
 // ParseURL fetches the OpenAPI spec from specURL, parses it, filters by opts, and returns one toolsy.Tool per operation.
 func ParseURL(ctx context.Context, specURL string, opts Options) ([]toolsy.Tool, error) {
 	client := opts.httpClient()
@@ -42,8 +46,14 @@ func ParseURL(ctx context.Context, specURL string, opts Options) ([]toolsy.Tool,
 		return nil, fmt.Errorf("openapi: read spec: %w", err)
 	}
 
+	return ToolsFromSpec(data, opts)
+}
+
+// ToolsFromSpec parses an OpenAPI 3.x document (JSON or YAML), filters by opts, and returns one
+// toolsy.Tool per operation. Use it for specs embedded in the binary or read from disk.
+func ToolsFromSpec(spec []byte, opts Options) ([]toolsy.Tool, error) {
 	loader := openapi3.NewLoader()
-	doc, err := loader.LoadFromData(data)
+	doc, err := loader.LoadFromData(spec)
 	if err != nil {
 		return nil, fmt.Errorf("openapi: parse spec: %w", err)
 	}
@@ -88,7 +98,17 @@ func docToTools(doc *openapi3.T, opts *Options) ([]toolsy.Tool, error) {
 		if err != nil {
 			return nil, err
 		}
-		tools = append(tools, forPath...)
+		for _, tool := range forPath {
+			if opts.MaxTools > 0 && len(tools) >= opts.MaxTools {
+				return nil, fmt.Errorf("%w: limit %d", ErrTooManyTools, opts.MaxTools)
+			}
+			if opts.OnTool != nil {
+				if cbErr := opts.OnTool(tool); cbErr != nil {
+					return nil, fmt.Errorf("openapi: import aborted: %w", cbErr)
+				}
+			}
+			tools = append(tools, tool)
+		}
 	}
 	return tools, nil
 }
@@ -117,29 +137,29 @@ func toolsForPath(
 		if err != nil {
 			return nil, fmt.Errorf("openapi: schema %s %s: %w", method, path, err)
 		}
-		pathNames, queryNames, bodyNames := operationParamSets(op, pathItem, path)
-		pathTemplate := path
-		methodCopy := method
+		pathNames, queryNames, headerNames, bodyNames := operationParamSets(op, pathItem, path)
+		prepared := &operation{
+			toolName:     name,
+			method:       method,
+			pathTemplate: path,
+			pathParams:   pathNames,
+			queryParams:  queryNames,
+			headerParams: headerNames,
+			bodyParams:   bodyNames,
+		}
 		optsCopy := *opts
+		var toolOpts []toolsy.ToolOption
+		if operationDangerous(op, method) {
+			toolOpts = append(toolOpts, toolsy.WithDangerous())
+		}
 		tool, err := toolsy.NewProxyTool(
 			name,
 			desc,
 			schemaBytes,
 			func(ctx context.Context, run *toolsy.RunEnv, argsJSON []byte, yield func(toolsy.Chunk) error) error {
-				return execute(
-					ctx,
-					run,
-					name,
-					methodCopy,
-					pathTemplate,
-					pathNames,
-					queryNames,
-					bodyNames,
-					argsJSON,
-					&optsCopy,
-					yield,
-				)
+				return execute(ctx, run, prepared, argsJSON, &optsCopy, yield)
 			},
+			toolOpts...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("openapi: tool %s: %w", name, err)
@@ -149,6 +169,18 @@ func toolsForPath(
 	return tools, nil
 }
 
+// dangerousExtension marks an operation as dangerous (true) or safe (false), overriding the method default.
+const dangerousExtension = "x-dangerous"
+
+// operationDangerous reports whether the tool for op should be [toolsy.WithDangerous]: the x-dangerous
+// extension when it is a boolean, otherwise every method except GET.
+func operationDangerous(op *openapi3.Operation, method string) bool {
+	if v, ok := op.Extensions[dangerousExtension].(bool); ok {
+		return v
+	}
+	return method != http.MethodGet
+}
+
 func operationForMethod(pathItem *openapi3.PathItem, method string) *openapi3.Operation {
 	switch method {
 	case http.MethodGet:
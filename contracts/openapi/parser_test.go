@@ -122,3 +122,63 @@ func TestParseURL_Non2xxStatus(t *testing.T) {
 		t.Fatalf("expected 404 in error, got: %v", err)
 	}
 }
+
+func manyPathsSpec(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"openapi":"3.0.0","info":{"title":"t","version":"1"},"servers":[{"url":"http://127.0.0.1"}],"paths":{`)
+	for i := range n {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"/item%d":{"get":{"operationId":"get_item_%d","responses":{"200":{"description":"ok"}}}}`, i, i)
+	}
+	b.WriteString(`}}`)
+	return b.String()
+}
+
+func TestParseURL_MaxToolsAbortsImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, manyPathsSpec(20))
+	}))
+	defer server.Close()
+
+	_, err := ParseURL(context.Background(), server.URL, Options{
+		HTTPClient:      server.Client(),
+		AllowPrivateIPs: true,
+		MaxTools:        5,
+	})
+	if !errors.Is(err, ErrTooManyTools) {
+		t.Fatalf("expected ErrTooManyTools, got: %v", err)
+	}
+}
+
+func TestParseURL_OnToolStreamsIntoBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, manyPathsSpec(20))
+	}))
+	defer server.Close()
+
+	errFull := errors.New("registry full")
+	builder := toolsy.NewRegistryBuilder(toolsy.WithMaxTools(3))
+	_, err := ParseURL(context.Background(), server.URL, Options{
+		HTTPClient:      server.Client(),
+		AllowPrivateIPs: true,
+		OnTool: func(tool toolsy.Tool) error {
+			if builder.Remaining() == 0 {
+				return errFull
+			}
+			builder.Add(tool)
+			return nil
+		},
+	})
+	if !errors.Is(err, errFull) {
+		t.Fatalf("expected callback error, got: %v", err)
+	}
+	reg, err := builder.Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if got := len(reg.ToolNames()); got != 3 {
+		t.Fatalf("expected 3 streamed tools, got %d", got)
+	}
+}
@@ -8,36 +8,36 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
-// operationParamSets returns path, query, and body parameter name sets for an operation.
-// pathNames: from pathTemplate placeholders {name}. queryNames: from parameters with In=="query".
+// operationParamSets returns path, query, header, and body parameter name sets for an operation.
+// pathNames: from pathTemplate placeholders {name}. queryNames/headerNames: from parameters with In=="query"/"header".
 // bodyNames: top-level keys from requestBody application/json schema (resolved via Schema.Value); nil if no body.
 func operationParamSets(
 	op *openapi3.Operation,
 	pathItem *openapi3.PathItem,
 	pathTemplate string,
-) ([]string, []string, []string) {
+) ([]string, []string, []string, []string) {
 	pathNames := pathParamNamesFromTemplate(pathTemplate)
-	querySet := make(map[string]bool)
-	mergeQueryParamsFromRefs(op.Parameters, querySet)
-	mergeQueryParamsFromRefs(pathItem.Parameters, querySet)
-	var queryNames []string
-	for k := range querySet {
-		queryNames = append(queryNames, k)
-	}
+	queryNames := paramNamesIn(openapi3.ParameterInQuery, op.Parameters, pathItem.Parameters)
+	headerNames := paramNamesIn(openapi3.ParameterInHeader, op.Parameters, pathItem.Parameters)
 	bodyNames := bodyParamNamesFromRequestBody(op)
-	return pathNames, queryNames, bodyNames
+	return pathNames, queryNames, headerNames, bodyNames
 }
 
-func mergeQueryParamsFromRefs(refs []*openapi3.ParameterRef, set map[string]bool) {
-	for _, pRef := range refs {
-		if pRef == nil || pRef.Value == nil {
-			continue
-		}
-		p := pRef.Value
-		if p.In == "query" && p.Name != "" {
-			set[p.Name] = true
+// paramNamesIn returns the distinct names of parameters located in in (e.g. "query"), in order of appearance.
+func paramNamesIn(in string, refLists ...[]*openapi3.ParameterRef) []string {
+	var names []string
+	for _, refs := range refLists {
+		for _, pRef := range refs {
+			if pRef == nil || pRef.Value == nil {
+				continue
+			}
+			p := pRef.Value
+			if p.In == in && p.Name != "" && !slices.Contains(names, p.Name) {
+				names = append(names, p.Name)
+			}
 		}
 	}
+	return names
 }
 
 func bodyParamNamesFromRequestBody(op *openapi3.Operation) []string {
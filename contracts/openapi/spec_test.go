@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/skosovsky/toolsy"
+)
+
+func petstoreTools(t *testing.T, opts Options) map[string]toolsy.Tool {
+	t.Helper()
+	spec, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	tools, err := ToolsFromSpec(spec, opts)
+	if err != nil {
+		t.Fatalf("ToolsFromSpec: %v", err)
+	}
+	byName := make(map[string]toolsy.Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Manifest().Name] = tool
+	}
+	return byName
+}
+
+func TestToolsFromSpec_Manifests(t *testing.T) {
+	tools := petstoreTools(t, Options{})
+	var names []string
+	for name := range tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	want := []string{"createpet", "deletepet", "export_all-pets", "getpet", "listpets", "updatepet"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("tool names: got %v want %v", names, want)
+	}
+	if got := tools["createpet"].Manifest().Description; got != "Create a pet" {
+		t.Fatalf("description falls back to the operation description, got %q", got)
+	}
+
+	dangerous := map[string]bool{
+		"listpets": false, "getpet": false, "createpet": true,
+		"deletepet": true, "updatepet": false, "export_all-pets": true,
+	}
+	for name, want := range dangerous {
+		if got := tools[name].Manifest().Dangerous; got != want {
+			t.Errorf("%s: Dangerous = %v, want %v", name, got, want)
+		}
+	}
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+		Required   []string       `json:"required"`
+	}
+	raw, err := json.Marshal(tools["createpet"].Manifest().Parameters)
+	if err != nil {
+		t.Fatalf("marshal parameters: %v", err)
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("unmarshal parameters: %v", err)
+	}
+	slices.Sort(schema.Required)
+	if !slices.Equal(schema.Required, []string{"Idempotency-Key", "name"}) {
+		t.Fatalf("required header and body fields are merged, got %v", schema.Required)
+	}
+	if _, ok := schema.Properties["tag"]; !ok {
+		t.Fatalf("body properties are merged, got %v", schema.Properties)
+	}
+}
+
+func TestToolsFromSpec_ExecuteRoutesArguments(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got, gotBody = r, string(b)
+		_, _ = io.WriteString(w, `{"id":7}`)
+	}))
+	defer server.Close()
+
+	tools := petstoreTools(t, Options{
+		BaseURL:         server.URL + "/v1",
+		HTTPClient:      server.Client(),
+		AllowPrivateIPs: true,
+		PrepareRequest:  BearerToken("secret"),
+	})
+	reg, err := toolsy.NewRegistryBuilder().Add(tools["createpet"], tools["getpet"]).Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	var result []byte
+	call := toolsy.ToolCall{ToolName: "createpet", Input: toolsy.ToolInput{
+		ArgsJSON: []byte(`{"name":"Rex","tag":"dog","Idempotency-Key":"k-1"}`),
+	}}
+	err = reg.Execute(context.Background(), call, func(c toolsy.Chunk) error {
+		result = append(result, c.Data...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/v1/pets" {
+		t.Fatalf("unexpected request %s %s", got.Method, got.URL.Path)
+	}
+	if h := got.Header.Get("Idempotency-Key"); h != "k-1" {
+		t.Fatalf("header parameter not sent, got %q", h)
+	}
+	if h := got.Header.Get("Authorization"); h != "Bearer secret" {
+		t.Fatalf("PrepareRequest not applied, got %q", h)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(gotBody), &body); err != nil {
+		t.Fatalf("body: %v", err)
+	}
+	if len(body) != 2 || body["name"] != "Rex" || body["tag"] != "dog" {
+		t.Fatalf("only body properties are sent, got %v", body)
+	}
+	if string(result) != `{"id":7}` {
+		t.Fatalf("unexpected result %q", result)
+	}
+
+	call = toolsy.ToolCall{ToolName: "getpet", Input: toolsy.ToolInput{ArgsJSON: []byte(`{"petId":42}`)}}
+	if err := reg.Execute(context.Background(), call, func(toolsy.Chunk) error { return nil }); err != nil {
+		t.Fatalf("execute getpet: %v", err)
+	}
+	if got.URL.Path != "/v1/pets/42" || got.URL.RawQuery != "" {
+		t.Fatalf("unexpected URL %s", got.URL)
+	}
+
+	call = toolsy.ToolCall{ToolName: "createpet", Input: toolsy.ToolInput{ArgsJSON: []byte(`{"tag":"dog"}`)}}
+	if err := reg.Execute(context.Background(), call, func(toolsy.Chunk) error { return nil }); err == nil {
+		t.Fatal("expected validation error for missing required fields")
+	}
+}
+
+func TestExecute_StreamsResponseChunks(t *testing.T) {
+	body := strings.Repeat("héllo wörld ", 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	run := func(maxBytes int) []toolsy.Chunk {
+		var chunks []toolsy.Chunk
+		err := execute(
+			context.Background(),
+			toolsy.NewRunEnv(nil),
+			&operation{toolName: "list_items", method: http.MethodGet, pathTemplate: "/items"},
+			[]byte(`{}`),
+			&Options{
+				BaseURL:          server.URL,
+				HTTPClient:       server.Client(),
+				AllowPrivateIPs:  true,
+				StreamChunkBytes: 7,
+				MaxResponseBytes: maxBytes,
+			},
+			func(c toolsy.Chunk) error {
+				chunks = append(chunks, c)
+				return nil
+			},
+		)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return chunks
+	}
+
+	var joined strings.Builder
+	chunks := run(0)
+	for _, c := range chunks {
+		if len(c.Data) > 7 || !utf8.Valid(c.Data) {
+			t.Fatalf("chunk must be at most 7 bytes of valid UTF-8: %q", c.Data)
+		}
+		joined.Write(c.Data)
+	}
+	if len(chunks) < 2 || joined.String() != body {
+		t.Fatalf("chunks do not reassemble the body (%d chunks): %q", len(chunks), joined.String())
+	}
+
+	chunks = run(10)
+	joined.Reset()
+	for _, c := range chunks {
+		joined.Write(c.Data)
+	}
+	if want := "héllo wö" + truncationSuffix; joined.String() != want {
+		t.Fatalf("truncated stream: got %q want %q", joined.String(), want)
+	}
+}
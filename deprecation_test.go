@@ -0,0 +1,70 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeprecated_HookSummaryAndDescription(t *testing.T) {
+	t.Parallel()
+	var (
+		mu       sync.Mutex
+		messages []string
+		summary  ExecutionSummary
+	)
+	onDeprecated := func(_ context.Context, call ToolCall, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, call.ToolName+": "+message)
+	}
+	onAfter := func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		summary = s
+	}
+	handler := func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: "hi " + a.Name}, nil
+	}
+	old, err := NewTool("greet_v1", "Greets", handler, WithDeprecated("use greet_v2"))
+	require.NoError(t, err)
+	current, err := NewTool("greet_v2", "Greets", handler)
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder(WithOnDeprecatedCall(onDeprecated), WithOnAfterExecute(onAfter)).
+		Add(old, current).Build()
+	require.NoError(t, err)
+
+	out, err := reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "greet_v1", Input: ToolInput{ArgsJSON: []byte(`{"name":"ann"}`)}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"hi ann"}`, string(out))
+	assert.Equal(t, []string{"greet_v1: use greet_v2"}, messages)
+	assert.True(t, summary.Deprecated)
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "greet_v2", Input: ToolInput{ArgsJSON: []byte(`{"name":"bo"}`)}})
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.False(t, summary.Deprecated)
+
+	descriptors := reg.EffectiveDescriptors(context.Background())
+	require.Len(t, descriptors, 2)
+	assert.Equal(t, "Greets (deprecated: use greet_v2)", descriptors[0].Manifest.Description)
+	assert.Equal(t, "Greets", descriptors[1].Manifest.Description)
+	assert.Equal(t, "Greets", old.Manifest().Description, "the raw manifest stays unchanged")
+}
+
+func TestModelDescription(t *testing.T) {
+	t.Parallel()
+	m := ToolManifest{Name: "x", Description: "Does x", Deprecated: true}
+	assert.Equal(t, "Does x (deprecated)", ModelDescription(m))
+	m.DeprecationMessage = "use y"
+	assert.Equal(t, "Does x (deprecated: use y)", ModelDescription(m))
+	m.Description = ModelDescription(m)
+	assert.Equal(t, "Does x (deprecated: use y)", ModelDescription(m))
+	assert.Equal(t, "Plain", ModelDescription(ToolManifest{Name: "p", Description: "Plain"}))
+}
@@ -2,7 +2,6 @@ package toolsy
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
@@ -28,9 +27,12 @@ func (m MapSchemaProvider) ParametersSchema() map[string]any {
 type DynamicToolSpec struct {
 	Name, Description string
 	Schema            SchemaProvider
-	ValidateArgs      func(ctx context.Context, decoded map[string]any) error
-	Handler           func(ctx context.Context, env *RunEnv, decoded map[string]any, yield func(Chunk) error) error
-	Options           []ToolOption
+	// OutputSchema is the JSON Schema of the tool's results; when set it replaces [WithOutputSchema]
+	// in Options. Combine it with [WithOutputValidation] to check results before they are yielded.
+	OutputSchema map[string]any
+	ValidateArgs func(ctx context.Context, decoded map[string]any) error
+	Handler      func(ctx context.Context, env *RunEnv, decoded map[string]any, yield func(Chunk) error) error
+	Options      []ToolOption
 }
 
 // NewDynamicToolFromSpec creates a [Tool] from [DynamicToolSpec].
@@ -53,13 +55,29 @@ func NewDynamicToolFromSpec(spec DynamicToolSpec) (Tool, error) {
 		opt(&cfg)
 	}
 	cfg.Schema = ensureSchemaConfig(cfg.Schema)
+	if len(spec.OutputSchema) > 0 {
+		cfg.Manifest.OutputSchema = deepCloneMap(spec.OutputSchema)
+	}
+	if err := checkToolIdentity(cfg, spec.Name, spec.Description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, nil); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	schemaCopy, err := deepCopySchemaFromMap(schemaMap)
 	if err != nil {
 		return nil, err
 	}
 	if cfg.Schema.Strict {
-		applyStrictMode(schemaCopy)
+		applyStrictMode(schemaCopy, cfg.Schema)
+	}
+	if schemaCopy, _, err = transformSchema(schemaCopy, schemaTransforms(cfg.Schema)); err != nil {
+		return nil, err
 	}
 	stripSchemaIDs(schemaCopy)
 	compiled, err := compileRawSchema(schemaCopy)
@@ -70,28 +88,36 @@ func NewDynamicToolFromSpec(spec DynamicToolSpec) (Tool, error) {
 	validateArgs := spec.ValidateArgs
 	handler := spec.Handler
 
-	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
-		var v any
-		if err := json.Unmarshal(input.ArgsJSON, &v); err != nil {
-			return wrapJSONParseError(err)
-		}
-		if err := validateAgainstSchema(compiled, v); err != nil {
-			return err
+	decodeArgs := func(ctx context.Context, argsJSON []byte) (map[string]any, error) {
+		v, err := decodeAndValidateRaw(compiled, schemaCopy, cfg.Schema.AllValidationErrors, argsJSON)
+		if err != nil {
+			return nil, err
 		}
 		decoded, ok := v.(map[string]any)
 		if !ok {
-			return NewSchemaError("dynamic tool arguments must be a JSON object")
+			return nil, NewSchemaError("dynamic tool arguments must be a JSON object")
 		}
 		if validateArgs != nil {
 			if vErr := runDynamicValidateArgs(ctx, validateArgs, decoded); vErr != nil {
-				return vErr
+				return nil, vErr
 			}
 		}
+		return decoded, nil
+	}
+
+	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
+		decoded, err := decodeArgs(ctx, input.ArgsJSON)
+		if err != nil {
+			return err
+		}
 		yieldWrapped := func(c Chunk) error {
 			prepared, err := prepareChunk(c)
 			if err != nil {
 				return err
 			}
+			if err := output.check(prepared); err != nil {
+				return err
+			}
 			if err := yield(prepared); err != nil {
 				return wrapYieldError(err)
 			}
@@ -115,6 +141,10 @@ func NewDynamicToolFromSpec(spec DynamicToolSpec) (Tool, error) {
 	return &tool{
 		manifest: buildToolManifest(spec.Name, spec.Description, schemaCopy, cfg.Manifest),
 		execute:  execute,
+		validate: func(argsJSON []byte) error {
+			_, err := decodeArgs(context.Background(), argsJSON)
+			return err
+		},
 	}, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/skosovsky/toolsy/textprocessor"
 )
@@ -21,6 +22,22 @@ var (
 	// ErrAsyncCollectedLimitExceeded is returned when background chunk collection exceeds WithMaxCollectedChunks.
 	ErrAsyncCollectedLimitExceeded = errors.New("toolsy: async collected chunks limit exceeded")
 	ErrBudgetExceeded              = errors.New("budget exceeded")
+	// ErrDuplicateTool is returned by [RegistryBuilder.Build] when two tools share a name.
+	ErrDuplicateTool = errors.New("toolsy: duplicate tool name")
+	// ErrTooManyTools is returned by [RegistryBuilder.Build] and [Registry.Reload] when [WithMaxTools] is exceeded.
+	ErrTooManyTools = errors.New("toolsy: registry tool limit exceeded")
+	// ErrOverloaded is returned when [WithLoadShedding] rejects a call; see [OverloadedError].
+	ErrOverloaded = errors.New("toolsy: registry overloaded")
+	// ErrRateLimited is returned when [WithRateLimit] or [WithGlobalRateLimit] rejects a call; see [RateLimitedError].
+	ErrRateLimited = errors.New("toolsy: rate limit exceeded")
+	// ErrMetadataTooLarge is wrapped in the internal error returned when a chunk exceeds [WithMaxMetadataBytes].
+	ErrMetadataTooLarge = errors.New("toolsy: chunk metadata exceeds limit")
+	// ErrLeaseHeld is returned by a [LeaseProvider] when another worker holds the lease.
+	ErrLeaseHeld = errors.New("toolsy: execution lease held by another worker")
+	// ErrLeaseLost is wrapped in the internal error returned when [WithLeasing] cannot extend a lease.
+	ErrLeaseLost = errors.New("toolsy: execution lease lost")
+	// ErrConfirmationDenied is returned when the [WithConfirmationHandler] handler declines a call.
+	ErrConfirmationDenied = errors.New("toolsy: user declined the tool call")
 )
 
 // ErrorCode is a machine-readable tool execution error category.
@@ -41,6 +58,10 @@ const (
 	CodeStateCodecMissing    ErrorCode = "STATE_CODEC_MISSING"
 	CodePolicyDenied         ErrorCode = "POLICY_DENIED"
 	CodeCapabilityDenied     ErrorCode = "CAPABILITY_DENIED"
+	CodeOverloaded           ErrorCode = "OVERLOADED"
+	CodeLeaseHeld            ErrorCode = "LEASE_HELD"
+	CodeRateLimited          ErrorCode = "RATE_LIMITED"
+	CodeConfirmationDenied   ErrorCode = "CONFIRMATION_DENIED"
 )
 
 // ToolError is the structured execution error envelope for orchestrator routing.
@@ -51,7 +72,9 @@ type ToolError struct {
 	Reason      string
 	FixableArgs []string
 	SafeMessage string
-	Err         error
+	// Violations lists structured schema violations for [CodeValidationFailed] argument errors.
+	Violations []FieldViolation
+	Err        error
 }
 
 // NewValidationError builds a non-retryable validation [ToolError].
@@ -315,6 +338,90 @@ func NewBudgetExceededError(reason string) *ToolError {
 	}
 }
 
+// OverloadedError describes a call rejected by [WithLoadShedding]. It unwraps to [ErrOverloaded].
+// RetryAfter is a hint derived from recent execution durations; zero when no execution has finished yet.
+type OverloadedError struct {
+	InFlight   int
+	Max        int
+	RetryAfter time.Duration
+}
+
+func (e *OverloadedError) Error() string {
+	return fmt.Sprintf("%s: %d/%d executions in flight", ErrOverloaded, e.InFlight, e.Max)
+}
+
+func (e *OverloadedError) Unwrap() error { return ErrOverloaded }
+
+// NewOverloadedError reports a retryable load-shedding rejection; Err is an [*OverloadedError].
+func NewOverloadedError(inFlight, maxInFlight int, retryAfter time.Duration) *ToolError {
+	reason := ErrOverloaded.Error()
+	if retryAfter > 0 {
+		reason += "; retry after " + retryAfter.Round(time.Millisecond).String()
+	}
+	return &ToolError{ //nolint:exhaustruct // optional envelope fields omitted by design
+		Code:      CodeOverloaded,
+		Reason:    reason,
+		Retryable: true,
+		Err:       &OverloadedError{InFlight: inFlight, Max: maxInFlight, RetryAfter: retryAfter},
+	}
+}
+
+// RateLimitedError describes a call rejected by [WithRateLimit] or [WithGlobalRateLimit]. It unwraps to
+// [ErrRateLimited]. RetryAfter is when the limiter expects a token to be available again.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s; retry after %s", ErrRateLimited, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitedError) Unwrap() error { return ErrRateLimited }
+
+// NewRateLimitedError reports a retryable rate-limit rejection; Err is a [*RateLimitedError].
+// The Reason carries the retry-after hint so a model can back off on its own.
+func NewRateLimitedError(retryAfter time.Duration) *ToolError {
+	rl := &RateLimitedError{RetryAfter: retryAfter}
+	return &ToolError{ //nolint:exhaustruct // optional envelope fields omitted by design
+		Code:      CodeRateLimited,
+		Reason:    rl.Error(),
+		Retryable: true,
+		Err:       rl,
+	}
+}
+
+// NewConfirmationDeniedError reports a call the user declined ([WithConfirmationHandler]). It is not
+// retryable: the model should change its approach instead of asking again with the same call.
+func NewConfirmationDeniedError() *ToolError {
+	return &ToolError{ //nolint:exhaustruct // optional envelope fields omitted by design
+		Code:        CodeConfirmationDenied,
+		Reason:      "the user declined this tool call",
+		SafeMessage: "the user declined this tool call",
+		Err:         ErrConfirmationDenied,
+	}
+}
+
+// NewLeaseHeldError reports that another worker is executing the same destructive call ([WithLeasing]).
+func NewLeaseHeldError(key string) *ToolError {
+	return &ToolError{ //nolint:exhaustruct // optional envelope fields omitted by design
+		Code:      CodeLeaseHeld,
+		Reason:    fmt.Sprintf("%s (lease %q); retry later", ErrLeaseHeld, key),
+		Retryable: true,
+		Err:       ErrLeaseHeld,
+	}
+}
+
+// NewUnknownToolError reports an unknown tool name with the registered names the caller may have meant,
+// for example `unknown tool "get_wether"; did you mean: get_weather?`. Without suggestions it is the
+// plain [NewToolNotFoundError].
+func NewUnknownToolError(name string, suggestions ...string) *ToolError {
+	te := NewToolNotFoundError()
+	if len(suggestions) > 0 {
+		te.Reason = fmt.Sprintf("unknown tool %q; did you mean: %s?", name, strings.Join(suggestions, ", "))
+	}
+	return te
+}
+
 // NewToolNotFoundInSubsetError reports an unknown tool name when building a registry subset.
 func NewToolNotFoundInSubsetError(name string) *ToolError {
 	te := NewToolNotFoundError()
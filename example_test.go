@@ -96,3 +96,169 @@ func ExampleRegistryBuilder_Use() {
 	// Output:
 	// result: {"double":42}
 }
+
+func ExampleRegistry_ExecuteCollect() {
+	type Args struct {
+		N int `json:"n"`
+	}
+	type Out struct {
+		Double int `json:"double"`
+	}
+	tool, err := NewTool("double", "Double the number", func(_ context.Context, _ *RunEnv, a Args) (Out, error) {
+		return Out{Double: a.N * 2}, nil
+	})
+	if err != nil {
+		return
+	}
+	reg, err := NewRegistryBuilder().Add(tool).Build()
+	if err != nil {
+		return
+	}
+	out, err := reg.ExecuteCollect(context.Background(), ToolCall{
+		ToolName: "double",
+		Input:    ToolInput{CallID: "1", ArgsJSON: []byte(`{"n": 21}`)},
+	})
+	if err != nil {
+		return
+	}
+	fmt.Printf("result: %s", out)
+	// Output:
+	// result: {"double":42}
+}
+
+type exampleRoleKey struct{}
+
+func ExampleToolAuthorizerFunc() {
+	type Args struct {
+		Table string `json:"table"`
+	}
+	drop, err := NewTool("drop_table", "Drop a table", func(_ context.Context, _ *RunEnv, a Args) (string, error) {
+		return "dropped " + a.Table, nil
+	}, WithDangerous())
+	if err != nil {
+		return
+	}
+	denyDangerous := ToolAuthorizerFunc(func(ctx context.Context, tool ToolInfo, _ ToolCall) error {
+		role, _ := ctx.Value(exampleRoleKey{}).(string)
+		if tool.Dangerous && role != "admin" {
+			return fmt.Errorf("role %q may not call %s", role, tool.Name)
+		}
+		return nil
+	})
+	reg, err := NewRegistryBuilder(WithAuthorizer(denyDangerous)).Add(drop).Build()
+	if err != nil {
+		return
+	}
+	call := ToolCall{ToolName: "drop_table", Input: ToolInput{ArgsJSON: []byte(`{"table":"users"}`)}}
+	for _, role := range []string{"viewer", "admin"} {
+		ctx := context.WithValue(context.Background(), exampleRoleKey{}, role)
+		err := reg.Execute(ctx, call, func(c Chunk) error {
+			fmt.Println(role+":", string(c.Data))
+			return nil
+		})
+		if te, ok := AsToolError(err); ok {
+			fmt.Println(role+":", te.SafeMessage, "("+te.Reason+")")
+		}
+	}
+	// Output:
+	// viewer: you are not allowed to use this tool (role "viewer" may not call drop_table)
+	// admin: "dropped users"
+}
+
+func ExampleReportProgress() {
+	rebuild := func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		ReportProgress(ctx, 0, "scanning documents")
+		ReportProgress(ctx, 50, "writing index")
+		ReportProgress(ctx, 100, "done")
+		return "indexed", nil
+	}
+	reindex, err := NewTool("reindex", "Rebuild the search index", rebuild)
+	if err != nil {
+		return
+	}
+	reg, err := NewRegistryBuilder().Add(reindex).Build()
+	if err != nil {
+		return
+	}
+	call := ToolCall{ToolName: "reindex", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	_ = reg.Execute(context.Background(), call, func(c Chunk) error {
+		if c.Event == EventProgress {
+			fmt.Printf("%d%% %s\n", *c.Progress.Percent, c.Progress.Message)
+			return nil
+		}
+		fmt.Println("result:", string(c.Data))
+		return nil
+	})
+	// Output:
+	// 0% scanning documents
+	// 50% writing index
+	// 100% done
+	// result: "indexed"
+}
+
+// Priority is an int-backed enum that travels as its name.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityUrgent
+)
+
+var priorityNames = []string{"low", "normal", "urgent"}
+
+func (p Priority) SchemaEnum() []string { return priorityNames }
+
+func (p Priority) MarshalJSON() ([]byte, error) { return json.Marshal(priorityNames[p]) }
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for i, n := range priorityNames {
+		if n == name {
+			*p = Priority(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown priority %q", name)
+}
+
+func ExampleSchemaEnumer() {
+	type Args struct {
+		Title    string   `json:"title"`
+		Priority Priority `json:"priority"`
+	}
+	file := func(_ context.Context, _ *RunEnv, a Args) (string, error) {
+		if a.Priority >= PriorityUrgent {
+			return "paged on-call for " + a.Title, nil
+		}
+		return "queued " + a.Title, nil
+	}
+	tool, err := NewTool("file_ticket", "File a ticket", file)
+	if err != nil {
+		return
+	}
+	prop := tool.Manifest().Parameters["properties"].(map[string]any)["priority"]
+	schema, _ := json.Marshal(prop)
+	fmt.Println(string(schema))
+
+	for _, args := range []string{
+		`{"title":"db down","priority":"urgent"}`,
+		`{"title":"typo","priority":"low"}`,
+		`{"title":"typo","priority":"asap"}`,
+	} {
+		out, err := CollectTool(context.Background(), tool, []byte(args))
+		if te, ok := AsToolError(err); ok {
+			fmt.Println("rejected:", te.Code, te.Violations[0].Path, te.Violations[0].Keyword)
+			continue
+		}
+		fmt.Println(string(out))
+	}
+	// Output:
+	// {"enum":["low","normal","urgent"],"type":"string"}
+	// "paged on-call for db down"
+	// "queued typo"
+	// rejected: VALIDATION_FAILED /priority enum
+}
@@ -0,0 +1,338 @@
+package exectool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/textprocessor"
+)
+
+const (
+	defaultCommandMaxOutputBytes = 1 << 20
+	commandReadBytes             = 32 << 10
+	commandStderrTailBytes       = 2 << 10
+	commandWaitDelay             = 2 * time.Second
+)
+
+//nolint:gochecknoglobals // compiled once; placeholder syntax is fixed
+var commandPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// CommandConfig describes a host binary exposed as a tool by [NewCommandTool].
+//
+// The command never runs through a shell: Args is an argv template whose {field}
+// placeholders are replaced with the JSON values of the typed arguments.
+type CommandConfig struct {
+	// Path is the binary to run; bare names are resolved with [exec.LookPath] at build time.
+	Path string
+	// Args is the argv template. Elements whose placeholders resolve to absent or empty
+	// fields are dropped. An element that is exactly "{field}" expands to one argv entry
+	// per item for array fields; placeholders embedded in a larger element
+	// (e.g. "--limit={limit}") accept scalars only.
+	Args []string
+	// Dir is the working directory; empty keeps the current process directory.
+	Dir string
+	// Env lists host environment variable names passed through to the command.
+	// Nothing else is inherited, so include PATH explicitly when the binary needs it.
+	Env []string
+	// MaxOutputBytes caps streamed output; exceeding it kills the command.
+	// Zero selects 1 MiB.
+	MaxOutputBytes int
+	// MergeStderr streams stderr interleaved with stdout instead of keeping it for error reports.
+	MergeStderr bool
+	// Safe opts out of the default [toolsy.WithDangerous] policy for read-only commands.
+	Safe bool
+}
+
+// NewCommandTool exposes a single host binary as a streaming tool.
+// Stdout is yielded as text chunks as it is produced. A non-zero exit status is reported
+// as a client-correctable error carrying the tail of stderr; context cancellation kills
+// the whole process group. Tools are marked dangerous unless cfg.Safe is set.
+func NewCommandTool[T any](
+	name, description string,
+	cfg CommandConfig,
+	opts ...toolsy.ToolOption,
+) (toolsy.Tool, error) {
+	path, err := resolveCommandPath(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxOutputBytes < 0 {
+		return nil, errors.New("exectool: command MaxOutputBytes must not be negative")
+	}
+	if cfg.MaxOutputBytes == 0 {
+		cfg.MaxOutputBytes = defaultCommandMaxOutputBytes
+	}
+	cfg.Path = path
+	cfg.Args = append([]string(nil), cfg.Args...)
+	cfg.Env = append([]string(nil), cfg.Env...)
+
+	if !cfg.Safe {
+		opts = append([]toolsy.ToolOption{toolsy.WithDangerous()}, opts...)
+	}
+	tool, err := toolsy.NewStreamTool(name, description,
+		func(ctx context.Context, _ *toolsy.RunEnv, args T, yield func(toolsy.Chunk) error) error {
+			argv, err := renderCommandArgs(cfg.Args, args)
+			if err != nil {
+				return err
+			}
+			return runCommand(ctx, &cfg, argv, yield)
+		}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCommandPlaceholders(cfg.Args, tool.Manifest().Parameters); err != nil {
+		return nil, err
+	}
+	return tool, nil
+}
+
+func resolveCommandPath(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("exectool: command Path is required")
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return "", fmt.Errorf("exectool: resolve command %q: %w", path, err)
+	}
+	return resolved, nil
+}
+
+// checkCommandPlaceholders rejects templates referencing fields the argument schema does not declare.
+func checkCommandPlaceholders(template []string, params map[string]any) error {
+	props, _ := params["properties"].(map[string]any)
+	for _, elem := range template {
+		for _, m := range commandPlaceholder.FindAllStringSubmatch(elem, -1) {
+			if _, ok := props[m[1]]; !ok {
+				return fmt.Errorf("exectool: command argument %q references unknown field %q", elem, m[1])
+			}
+		}
+	}
+	return nil
+}
+
+func renderCommandArgs[T any](template []string, args T) ([]string, error) {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("exectool: marshal command arguments: %w", err))
+	}
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("exectool: decode command arguments: %w", err))
+	}
+
+	argv := make([]string, 0, len(template))
+	for _, elem := range template {
+		if m := commandPlaceholder.FindStringSubmatch(elem); m != nil && m[0] == elem {
+			values, err := placeholderValues(m[1], fields[m[1]])
+			if err != nil {
+				return nil, err
+			}
+			argv = append(argv, values...)
+			continue
+		}
+		var renderErr error
+		missing := false
+		first := ""
+		rendered := commandPlaceholder.ReplaceAllStringFunc(elem, func(p string) string {
+			field := p[1 : len(p)-1]
+			if first == "" {
+				first = field
+			}
+			s, err := scalarArg(field, fields[field])
+			if err != nil && renderErr == nil {
+				renderErr = err
+			}
+			missing = missing || s == ""
+			return s
+		})
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		// A template such as "{name}.txt" must not turn into a flag either; only elements the
+		// template itself spells with a leading "-" (e.g. "--max={limit}") may render as one.
+		if first != "" && strings.HasPrefix(rendered, "-") && !strings.HasPrefix(elem, "-") {
+			return nil, toolsy.NewValidationError(
+				fmt.Sprintf("%s must not start with '-'", first), first)
+		}
+		if !missing {
+			argv = append(argv, rendered)
+		}
+	}
+	return argv, nil
+}
+
+// placeholderValues renders a whole-element placeholder. Values starting with "-" are
+// rejected so model-supplied input cannot smuggle extra flags into the command.
+func placeholderValues(field string, v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		items = []any{v}
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, err := scalarArg(field, item)
+		if err != nil {
+			return nil, err
+		}
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, "-") {
+			return nil, toolsy.NewValidationError(
+				fmt.Sprintf("%s must not start with '-'", field), field)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func scalarArg(field string, v any) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	case json.Number:
+		return x.String(), nil
+	case bool:
+		if x {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		return "", toolsy.NewValidationError(
+			fmt.Sprintf("%s cannot be passed as a command argument", field), field)
+	}
+}
+
+func commandEnv(names []string) []string {
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+func runCommand(ctx context.Context, cfg *CommandConfig, argv []string, yield func(toolsy.Chunk) error) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	//nolint:gosec // binary is fixed at build time; argv never passes through a shell
+	cmd := exec.CommandContext(runCtx, cfg.Path, argv...)
+	prepareCommand(cmd)
+	cmd.Dir = cfg.Dir
+	cmd.Env = commandEnv(cfg.Env)
+	cmd.WaitDelay = commandWaitDelay
+
+	stdout, stdoutW := io.Pipe()
+	stderr := &tailBuffer{buf: nil, max: commandStderrTailBytes}
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderr
+	if cfg.MergeStderr {
+		cmd.Stderr = stdoutW
+	}
+	if err := cmd.Start(); err != nil {
+		return toolsy.NewInternalError(fmt.Errorf("exectool: start command: %w", err))
+	}
+	waitCh := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		_ = stdoutW.Close()
+		waitCh <- err
+	}()
+
+	streamErr := streamCommandOutput(stdout, cfg, stderr, yield)
+	if streamErr != nil {
+		cancel()
+		_ = stdout.CloseWithError(streamErr)
+	}
+	waitErr := <-waitCh
+
+	if ie := toolsy.ToolkitContextError(ctx, "exectool: run command"); ie != nil {
+		return ie
+	}
+	if streamErr != nil {
+		if textprocessor.IsReadLimitExceeded(streamErr) {
+			return toolsy.MapReadLimitErrorFor(streamErr, cfg.MaxOutputBytes, "command output",
+				"narrow the arguments to produce less output")
+		}
+		return streamErr
+	}
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		msg := fmt.Sprintf("command exited with status %d", exitErr.ExitCode())
+		if tail := strings.TrimSpace(strings.ToValidUTF8(stderr.String(), "")); tail != "" {
+			msg += ": " + tail
+		}
+		return toolsy.NewValidationError(msg)
+	}
+	if waitErr != nil {
+		return toolsy.NewInternalError(fmt.Errorf("exectool: wait command: %w", waitErr))
+	}
+	return nil
+}
+
+// streamCommandOutput yields output reads as chunks until EOF or the byte budget is spent.
+// Merged output is mirrored into tail so a failing exit can still report the last lines.
+func streamCommandOutput(r io.Reader, cfg *CommandConfig, tail *tailBuffer, yield func(toolsy.Chunk) error) error {
+	buf := make([]byte, commandReadBytes)
+	total := 0
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			total += n
+			if total > cfg.MaxOutputBytes {
+				return fmt.Errorf("%w: command output", textprocessor.ErrReadLimitExceeded)
+			}
+			if cfg.MergeStderr {
+				_, _ = tail.Write(buf[:n])
+			}
+			chunk := toolsy.Chunk{
+				Event:    toolsy.EventResult,
+				Data:     bytes.Clone(buf[:n]),
+				MimeType: toolsy.MimeTypeText,
+			}
+			if yieldErr := yield(chunk); yieldErr != nil {
+				return yieldErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return toolsy.NewInternalError(fmt.Errorf("exectool: read command output: %w", err))
+		}
+	}
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
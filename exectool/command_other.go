@@ -0,0 +1,7 @@
+//go:build !unix
+
+package exectool
+
+import "os/exec"
+
+func prepareCommand(cmd *exec.Cmd) {}
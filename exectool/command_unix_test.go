@@ -0,0 +1,144 @@
+//go:build unix
+
+package exectool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type grepArgs struct {
+	Pattern string   `json:"pattern"`
+	Files   []string `json:"files,omitempty"`
+	Limit   int      `json:"limit,omitempty"`
+}
+
+type shellArgs struct {
+	Script string `json:"script"`
+}
+
+func runCommandTool(ctx context.Context, tool toolsy.Tool, args string) (string, []toolsy.Chunk, error) {
+	var out strings.Builder
+	var chunks []toolsy.Chunk
+	err := tool.Execute(ctx, toolsy.NewRunEnv(nil), toolsy.ToolInput{ArgsJSON: []byte(args)},
+		func(c toolsy.Chunk) error {
+			chunks = append(chunks, c)
+			out.Write(c.Data)
+			return nil
+		})
+	return out.String(), chunks, err
+}
+
+func TestNewCommandTool_RendersArgv(t *testing.T) {
+	tool, err := NewCommandTool[grepArgs]("echo_args", "Echo arguments", CommandConfig{
+		Path: "echo",
+		Args: []string{"--max={limit}", "{pattern}", "{files}"},
+	})
+	require.NoError(t, err)
+	require.True(t, tool.Manifest().Dangerous, "commands are dangerous by default")
+
+	out, chunks, err := runCommandTool(context.Background(), tool,
+		`{"pattern":"a b; rm -rf /","files":["x","y"],"limit":3}`)
+	require.NoError(t, err)
+	require.Equal(t, "--max=3 a b; rm -rf / x y\n", out)
+	require.Equal(t, toolsy.MimeTypeText, chunks[0].MimeType)
+
+	out, _, err = runCommandTool(context.Background(), tool, `{"pattern":"p"}`)
+	require.NoError(t, err)
+	require.Equal(t, "p\n", out, "elements with absent fields are dropped")
+
+	_, _, err = runCommandTool(context.Background(), tool, `{"pattern":"--version"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation, "flag injection is rejected")
+}
+
+func TestNewCommandTool_RejectsFlagInEmbeddedPlaceholder(t *testing.T) {
+	tool, err := NewCommandTool[grepArgs]("echo_args", "Echo arguments", CommandConfig{
+		Path: "echo",
+		Args: []string{"--max={limit}", "{pattern}.txt"},
+	})
+	require.NoError(t, err)
+
+	_, _, err = runCommandTool(context.Background(), tool, `{"pattern":"--version"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation, "embedded placeholders cannot render a flag")
+
+	out, _, err := runCommandTool(context.Background(), tool, `{"pattern":"notes","limit":-1}`)
+	require.NoError(t, err)
+	require.Equal(t, "--max=-1 notes.txt\n", out, "template-spelled flags keep their values")
+}
+
+func TestNewCommandTool_Config(t *testing.T) {
+	tool, err := NewCommandTool[grepArgs]("echo_args", "Echo", CommandConfig{Path: "echo", Safe: true})
+	require.NoError(t, err)
+	require.False(t, tool.Manifest().Dangerous)
+
+	_, err = NewCommandTool[grepArgs]("echo_args", "Echo", CommandConfig{Path: "echo", Args: []string{"{nope}"}})
+	require.ErrorContains(t, err, "unknown field")
+
+	_, err = NewCommandTool[grepArgs]("missing", "Missing", CommandConfig{Path: "toolsy-no-such-binary"})
+	require.Error(t, err)
+}
+
+func TestNewCommandTool_EnvAndDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TOOLSY_VISIBLE", "yes")
+	t.Setenv("TOOLSY_HIDDEN", "no")
+	tool, err := NewCommandTool[shellArgs]("sh", "Shell", CommandConfig{
+		Path: "/bin/sh",
+		Args: []string{"-c", "{script}"},
+		Dir:  dir,
+		Env:  []string{"TOOLSY_VISIBLE"},
+	})
+	require.NoError(t, err)
+	out, _, err := runCommandTool(context.Background(), tool,
+		`{"script":"echo $TOOLSY_VISIBLE:$TOOLSY_HIDDEN; pwd"}`)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Equal(t, "yes:", lines[0])
+	require.True(t, strings.HasSuffix(lines[1], dir[strings.LastIndex(dir, "/"):]))
+}
+
+func TestNewCommandTool_ExitStatus(t *testing.T) {
+	tool, err := NewCommandTool[shellArgs]("sh", "Shell", CommandConfig{Path: "/bin/sh", Args: []string{"-c", "{script}"}})
+	require.NoError(t, err)
+	_, _, err = runCommandTool(context.Background(), tool, `{"script":"echo partial; echo bad flag >&2; exit 3"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation)
+	require.Equal(t, toolsy.FinishClientError, toolsy.FinishReasonOf(err))
+	require.ErrorContains(t, err, "status 3: bad flag")
+
+	tool, err = NewCommandTool[shellArgs]("sh", "Shell", CommandConfig{
+		Path: "/bin/sh", Args: []string{"-c", "{script}"}, MergeStderr: true,
+	})
+	require.NoError(t, err)
+	out, _, err := runCommandTool(context.Background(), tool, `{"script":"echo out; echo err >&2"}`)
+	require.NoError(t, err)
+	require.Equal(t, "out\nerr\n", out)
+}
+
+func TestNewCommandTool_OutputLimit(t *testing.T) {
+	tool, err := NewCommandTool[shellArgs]("sh", "Shell", CommandConfig{
+		Path: "/bin/sh", Args: []string{"-c", "{script}"}, MaxOutputBytes: 1024,
+	})
+	require.NoError(t, err)
+	start := time.Now()
+	_, _, err = runCommandTool(context.Background(), tool, `{"script":"yes"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation)
+	require.ErrorContains(t, err, "command output exceeds 1024 byte limit")
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestNewCommandTool_CancelKillsProcessGroup(t *testing.T) {
+	tool, err := NewCommandTool[shellArgs]("sh", "Shell", CommandConfig{Path: "/bin/sh", Args: []string{"-c", "{script}"}})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err = runCommandTool(ctx, tool, `{"script":"sleep 30 & wait"}`)
+	require.Equal(t, toolsy.FinishTimeout, toolsy.FinishReasonOf(err))
+	require.Less(t, time.Since(start), commandWaitDelay, "grandchildren holding stdout are killed too")
+}
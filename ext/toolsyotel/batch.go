@@ -0,0 +1,39 @@
+package toolsyotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// BatchSpanName is the name of the parent span created by [WithBatchTracing].
+const BatchSpanName = "toolsy.execute_batch"
+
+// WithBatchTracing returns a registry option that wraps every [toolsy.Registry.ExecuteBatchStream] in
+// a [BatchSpanName] span with the batch size and call IDs. Calls run under that span's context, so
+// the per-call spans of [WithTracing] are its children and parallel fan-out shows in the trace
+// waterfall. The span is marked as an error when the batch itself fails; per-call failures delivered
+// as soft error chunks live on the child spans. It installs a [toolsy.WithOnBatch] hook.
+func WithBatchTracing(opts ...Option) toolsy.RegistryOption {
+	_, tracer := newTracer(opts)
+	return toolsy.WithOnBatch(func(ctx context.Context, calls []toolsy.ToolCall) (context.Context, func(error)) {
+		ids := make([]string, 0, len(calls))
+		for _, c := range calls {
+			if c.Input.CallID != "" {
+				ids = append(ids, c.Input.CallID)
+			}
+		}
+		ctx, span := tracer.Start(ctx, BatchSpanName, trace.WithAttributes(
+			attribute.String("gen_ai.operation.name", "execute_tool_batch"),
+			attribute.Int("toolsy.batch.size", len(calls)),
+			attribute.StringSlice("toolsy.batch.call_ids", ids),
+		))
+		return ctx, func(err error) {
+			applySpanStatusFromExec(span, err, false, "")
+			span.End()
+		}
+	})
+}
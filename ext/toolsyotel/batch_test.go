@@ -0,0 +1,145 @@
+package toolsyotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/skosovsky/toolsy"
+)
+
+func TestWithTracing_RecordsDeliveryAndPropagatesContext(t *testing.T) {
+	tp, rec := newSpanRecorder()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	var handlerSpan trace.SpanContext
+	tool := &stubTool{
+		manifest: toolsy.ToolManifest{Name: "fetch"},
+		execute: func(ctx context.Context, _ *toolsy.RunEnv, _ toolsy.ToolInput, yield func(toolsy.Chunk) error) error {
+			handlerSpan = trace.SpanContextFromContext(ctx)
+			if err := yield(toolsy.Chunk{Event: toolsy.EventResult, Data: []byte("abc")}); err != nil {
+				return err
+			}
+			return yield(toolsy.Chunk{Event: toolsy.EventResult, Data: []byte("de")})
+		},
+	}
+	wrapped := WithTracing(WithTracer(tp.Tracer("test")))(tool)
+	err := wrapped.Execute(context.Background(), toolsy.NewRunEnv(nil),
+		toolsy.ToolInput{CallID: "c1", ArgsJSON: []byte(`{"url":"x"}`)}, func(toolsy.Chunk) error { return nil })
+	require.NoError(t, err)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID(), "handler ctx carries the tool span")
+	for key, want := range map[string]int64{
+		"toolsy.args.bytes":       int64(len(`{"url":"x"}`)),
+		"toolsy.chunks.delivered": 2,
+		"toolsy.bytes.delivered":  5,
+	} {
+		v, ok := attrValue(span, key)
+		require.True(t, ok, key)
+		assert.Equal(t, want, v.AsInt64(), key)
+	}
+	outcome, ok := attrValue(span, "toolsy.outcome")
+	require.True(t, ok)
+	assert.Equal(t, string(toolsy.FinishSuccess), outcome.AsString())
+}
+
+func TestWithTracing_PanicAndTimeoutMarkSpan(t *testing.T) {
+	tp, rec := newSpanRecorder()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	panicking := &stubTool{
+		manifest: toolsy.ToolManifest{Name: "boom"},
+		execute: func(context.Context, *toolsy.RunEnv, toolsy.ToolInput, func(toolsy.Chunk) error) error {
+			panic("boom")
+		},
+	}
+	assert.Panics(t, func() {
+		_ = WithTracing(WithTracerProvider(tp))(panicking).Execute(context.Background(), toolsy.NewRunEnv(nil),
+			toolsy.ToolInput{}, func(toolsy.Chunk) error { return nil })
+	})
+	slow := &stubTool{
+		manifest: toolsy.ToolManifest{Name: "slow"},
+		execute: func(ctx context.Context, _ *toolsy.RunEnv, _ toolsy.ToolInput, _ func(toolsy.Chunk) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	err := WithTracing(WithTracerProvider(tp))(slow).Execute(ctx, toolsy.NewRunEnv(nil), toolsy.ToolInput{},
+		func(toolsy.Chunk) error { return nil })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	for i, want := range []toolsy.FinishReason{toolsy.FinishPanic, toolsy.FinishTimeout} {
+		assert.Equal(t, codes.Error, spans[i].Status().Code)
+		outcome, ok := attrValue(spans[i], "toolsy.outcome")
+		require.True(t, ok)
+		assert.Equal(t, string(want), outcome.AsString())
+	}
+}
+
+func TestWithBatchTracing_ParentSpanPerBatch(t *testing.T) {
+	tp, rec := newSpanRecorder()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ok := &stubTool{manifest: toolsy.ToolManifest{Name: "ok", Parameters: map[string]any{"type": "object"}}}
+	reg, err := toolsy.NewRegistryBuilder(WithBatchTracing(WithTracerProvider(tp))).
+		Use(WithTracing(WithTracerProvider(tp))).
+		Add(ok).
+		Build()
+	require.NoError(t, err)
+
+	calls := []toolsy.ToolCall{
+		{ToolName: "ok", Input: toolsy.ToolInput{CallID: "a", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "ok", Input: toolsy.ToolInput{CallID: "b", ArgsJSON: []byte(`{}`)}},
+	}
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(toolsy.Chunk) error { return nil }))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 3)
+	var parent sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		if s.Name() == BatchSpanName {
+			parent = s
+		}
+	}
+	require.NotNil(t, parent)
+	size, found := attrValue(parent, "toolsy.batch.size")
+	require.True(t, found)
+	assert.Equal(t, int64(2), size.AsInt64())
+	for _, s := range spans {
+		if s.Name() == "toolsy.execute ok" {
+			assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID())
+			assert.Equal(t, parent.SpanContext().TraceID(), s.SpanContext().TraceID())
+		}
+	}
+}
+
+func TestWithBatchTracing_FailedBatchMarksSpan(t *testing.T) {
+	tp, rec := newSpanRecorder()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ok := &stubTool{manifest: toolsy.ToolManifest{Name: "ok", Parameters: map[string]any{"type": "object"}}}
+	reg, err := toolsy.NewRegistryBuilder(WithBatchTracing(WithTracerProvider(tp))).Add(ok).Build()
+	require.NoError(t, err)
+	calls := []toolsy.ToolCall{{ToolName: "ok", Input: toolsy.ToolInput{ArgsJSON: []byte(`{}`)}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = reg.ExecuteBatchStream(ctx, calls, func(toolsy.Chunk) error { return errors.New("unreachable") })
+	require.ErrorIs(t, err, context.Canceled)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -16,15 +17,14 @@ import (
 
 const instrumentationName = "github.com/skosovsky/toolsy/ext/toolsyotel"
 
-// WithTracing returns middleware that emits one span per tool execution.
-// Span status is left neutral for control-plane errors and toolsy.ErrStreamAborted.
+// WithTracing returns middleware that emits one span per tool execution, named "toolsy.execute <tool>".
+// The span context flows into the tool via ctx, so spans of nested calls made by the handler (for
+// example instrumented HTTP clients) become its children. When the span ends it carries the argument
+// size, the chunks and bytes the consumer accepted, and the [toolsy.FinishReason] as toolsy.outcome;
+// panics and timeouts mark it as an error. Span status is left neutral for control-plane errors and
+// toolsy.ErrStreamAborted. Use [WithBatchTracing] to group the calls of one batch under a parent span.
 func WithTracing(opts ...Option) toolsy.Middleware {
-	cfg := defaultConfig()
-	cfg.tracerProvider = otel.GetTracerProvider()
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	cfg, tracer := newTracer(opts)
 
 	return func(next toolsy.Tool) toolsy.Tool {
 		return &tracingTool{
@@ -35,6 +35,20 @@ func WithTracing(opts ...Option) toolsy.Middleware {
 	}
 }
 
+// newTracer applies opts over the defaults and resolves the tracer: [WithTracer], else the
+// configured or global tracer provider.
+func newTracer(opts []Option) (config, trace.Tracer) {
+	cfg := defaultConfig()
+	cfg.tracerProvider = otel.GetTracerProvider()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.tracer != nil {
+		return cfg, cfg.tracer
+	}
+	return cfg, cfg.tracerProvider.Tracer(instrumentationName)
+}
+
 type tracingTool struct {
 	next   toolsy.Tool
 	tracer trace.Tracer
@@ -51,6 +65,20 @@ func (t *tracingTool) UnwrapNext() toolsy.Tool {
 
 var _ toolsy.ChainUnwrapper = (*tracingTool)(nil)
 
+// deliveryStats counts chunks the consumer accepted; tools may yield from several goroutines.
+type deliveryStats struct {
+	chunks atomic.Int64
+	bytes  atomic.Int64
+}
+
+func (d *deliveryStats) attributes(outcome toolsy.FinishReason) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("toolsy.chunks.delivered", d.chunks.Load()),
+		attribute.Int64("toolsy.bytes.delivered", d.bytes.Load()),
+		attribute.String("toolsy.outcome", string(outcome)),
+	}
+}
+
 type softErrorState struct {
 	mu   sync.Mutex
 	flag bool
@@ -92,6 +120,7 @@ func (t *tracingTool) spanStartAttributes(
 		attribute.String("gen_ai.tool.name", toolName),
 		attribute.String("gen_ai.operation.name", "execute_tool"),
 		attribute.String("langfuse.observation.type", "tool"),
+		attribute.Int("toolsy.args.bytes", len(input.ArgsJSON)),
 	}
 	if input.CallID != "" {
 		attrs = append(attrs, attribute.String("gen_ai.tool.call_id", input.CallID))
@@ -144,11 +173,14 @@ func (t *tracingTool) wrapYield(
 	yield func(toolsy.Chunk) error,
 	soft *softErrorState,
 	outAcc *payloadAccumulator,
+	stats *deliveryStats,
 ) func(toolsy.Chunk) error {
 	return func(c toolsy.Chunk) error {
 		if err := yield(c); err != nil {
 			return err
 		}
+		stats.chunks.Add(1)
+		stats.bytes.Add(int64(len(c.Data)))
 		soft.recordFromChunk(c)
 		if outAcc != nil {
 			if c.IsError {
@@ -175,10 +207,11 @@ func (t *tracingTool) Execute(
 
 	toolName := t.toolName()
 	var soft softErrorState
+	var stats deliveryStats
 
 	ctx, span := t.tracer.Start(
 		ctx,
-		"tool.execute."+toolName,
+		"toolsy.execute "+toolName,
 		trace.WithAttributes(t.spanStartAttributes(toolName, input, maxPayload)...),
 	)
 	defer span.End()
@@ -187,14 +220,16 @@ func (t *tracingTool) Execute(
 	defer func() {
 		if p := recover(); p != nil {
 			panicErr := fmt.Errorf("panic: %v", p)
+			span.SetAttributes(stats.attributes(toolsy.FinishPanic)...)
 			span.RecordError(panicErr)
 			span.SetStatus(codes.Error, panicErr.Error())
 			panic(p)
 		}
+		span.SetAttributes(stats.attributes(toolsy.FinishReasonOf(execErr))...)
 		t.finalizeExecuteSpan(span, execErr, outAcc, maxPayload, &soft)
 	}()
 
-	execErr = t.next.Execute(ctx, run, input, t.wrapYield(yield, &soft, outAcc))
+	execErr = t.next.Execute(ctx, run, input, t.wrapYield(yield, &soft, outAcc, &stats))
 	return execErr
 }
 
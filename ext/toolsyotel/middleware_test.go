@@ -86,7 +86,7 @@ func TestWithTracing_SetsSpanNameAndAttributes(t *testing.T) {
 	spans := rec.Ended()
 	require.Len(t, spans, 1)
 	span := spans[0]
-	assert.Equal(t, "tool.execute.weather", span.Name())
+	assert.Equal(t, "toolsy.execute weather", span.Name())
 	assert.Equal(t, codes.Unset, span.Status().Code)
 
 	toolName, ok := attrValue(span, "gen_ai.tool.name")
@@ -564,7 +564,7 @@ func TestWithTracing_AsyncToolViaRegistry_SpanEndsAfterBackground(t *testing.T)
 	require.Eventually(t, func() bool { return len(rec.Ended()) == 1 }, time.Second, 10*time.Millisecond)
 
 	span := rec.Ended()[0]
-	assert.Equal(t, "tool.execute.async_traced", span.Name())
+	assert.Equal(t, "toolsy.execute async_traced", span.Name())
 }
 
 func TestWithTracing_NestedAsyncTool_FailsRegistryBuild(t *testing.T) {
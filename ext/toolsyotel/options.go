@@ -5,6 +5,7 @@ import "go.opentelemetry.io/otel/trace"
 const defaultMaxPayloadSize = 4096
 
 type config struct {
+	tracer         trace.Tracer
 	tracerProvider trace.TracerProvider
 	contentCapture bool
 	maxPayloadSize int
@@ -15,6 +16,7 @@ type Option func(*config)
 
 func defaultConfig() config {
 	return config{
+		tracer:         nil,
 		tracerProvider: nil,
 		contentCapture: false,
 		maxPayloadSize: defaultMaxPayloadSize,
@@ -37,6 +39,15 @@ func WithTracerProvider(tp trace.TracerProvider) Option {
 	}
 }
 
+// WithTracer sets the tracer used by middleware directly; it takes precedence over [WithTracerProvider].
+func WithTracer(tracer trace.Tracer) Option {
+	return func(c *config) {
+		if tracer != nil {
+			c.tracer = tracer
+		}
+	}
+}
+
 // WithContentCapture enables capture of tool input/output payloads in span attributes.
 // Disabled by default because payloads may contain PII or be very large.
 func WithContentCapture(enabled bool) Option {
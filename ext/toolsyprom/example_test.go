@@ -0,0 +1,36 @@
+package toolsyprom_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/ext/toolsyprom"
+)
+
+func ExampleWithMetrics() {
+	reg := prometheus.NewRegistry()
+	metrics, err := toolsyprom.WithMetrics(reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ping, err := toolsy.NewTool("ping", "Ping", func(context.Context, *toolsy.RunEnv, struct{}) (string, error) {
+		return "pong", nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	registry, err := toolsy.NewRegistryBuilder().Use(metrics).Add(ping).Build()
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = registry // serve tool calls with registry.Execute
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	_ = mux // http.ListenAndServe(":9090", mux)
+}
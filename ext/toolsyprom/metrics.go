@@ -0,0 +1,195 @@
+// Package toolsyprom exports Prometheus metrics for toolsy tool executions.
+package toolsyprom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/skosovsky/toolsy"
+)
+
+const defaultNamespace = "toolsy"
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// Option configures [WithMetrics].
+type Option func(*config)
+
+// WithNamespace sets the metric namespace (default "toolsy").
+func WithNamespace(ns string) Option {
+	return func(c *config) {
+		c.namespace = ns
+	}
+}
+
+// WithBuckets sets the duration histogram buckets in seconds (default [prometheus.DefBuckets]).
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		if len(buckets) > 0 {
+			c.buckets = buckets
+		}
+	}
+}
+
+// Metrics holds the collectors shared by every tool [WithMetrics] wraps.
+type Metrics struct {
+	executions *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	chunks     *prometheus.CounterVec
+	bytes      *prometheus.CounterVec
+	inFlight   *prometheus.GaugeVec
+}
+
+// WithMetrics registers the toolsy collectors with reg and returns middleware that feeds them:
+//
+//   - <ns>_tool_executions_total{tool,outcome}: finished executions; outcome is a [toolsy.FinishReason]
+//   - <ns>_tool_execution_duration_seconds{tool,outcome}: time spent in the wrapped tool
+//   - <ns>_tool_chunks_delivered_total{tool} and <ns>_tool_bytes_delivered_total{tool}: chunks the
+//     consumer accepted and their Data bytes
+//   - <ns>_tool_in_flight{tool}: executions currently running
+//
+// Labels are limited to the tool name and the outcome enum; call IDs and arguments are never labels.
+// The registry never queues calls, so there is no queue-wait metric. Collectors that are already
+// registered with reg (for example by a second registry) are reused. As middleware it composes with
+// any execution hooks the registry has; add it with [toolsy.RegistryBuilder.Use].
+func WithMetrics(reg prometheus.Registerer, opts ...Option) (toolsy.Middleware, error) {
+	m, err := NewMetrics(reg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return m.Middleware(), nil
+}
+
+// NewMetrics builds and registers the collectors; see [WithMetrics].
+func NewMetrics(reg prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	cfg := config{namespace: defaultNamespace, buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	m := &Metrics{
+		executions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "tool_executions_total",
+			Help:      "Tool executions by tool and outcome.",
+		}, []string{"tool", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "tool_execution_duration_seconds",
+			Help:      "Time spent executing tools.",
+			Buckets:   cfg.buckets,
+		}, []string{"tool", "outcome"}),
+		chunks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "tool_chunks_delivered_total",
+			Help:      "Chunks accepted by the consumer.",
+		}, []string{"tool"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "tool_bytes_delivered_total",
+			Help:      "Chunk data bytes accepted by the consumer.",
+		}, []string{"tool"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: cfg.namespace,
+			Name:      "tool_in_flight",
+			Help:      "Tool executions currently running.",
+		}, []string{"tool"}),
+	}
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	var err error
+	if m.executions, err = register(reg, m.executions); err != nil {
+		return nil, err
+	}
+	if m.duration, err = register(reg, m.duration); err != nil {
+		return nil, err
+	}
+	if m.chunks, err = register(reg, m.chunks); err != nil {
+		return nil, err
+	}
+	if m.bytes, err = register(reg, m.bytes); err != nil {
+		return nil, err
+	}
+	if m.inFlight, err = register(reg, m.inFlight); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register adds c to reg, returning the collector registered earlier under the same description.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+// Middleware returns tool middleware recording into m.
+func (m *Metrics) Middleware() toolsy.Middleware {
+	return func(next toolsy.Tool) toolsy.Tool {
+		return &metricsTool{next: next, metrics: m}
+	}
+}
+
+type metricsTool struct {
+	next    toolsy.Tool
+	metrics *Metrics
+}
+
+func (t *metricsTool) Manifest() toolsy.ToolManifest {
+	return t.next.Manifest()
+}
+
+func (t *metricsTool) UnwrapNext() toolsy.Tool {
+	return t.next
+}
+
+var _ toolsy.ChainUnwrapper = (*metricsTool)(nil)
+
+func (t *metricsTool) Execute(
+	ctx context.Context,
+	run *toolsy.RunEnv,
+	input toolsy.ToolInput,
+	yield func(toolsy.Chunk) error,
+) error {
+	tool := t.next.Manifest().Name
+	if tool == "" {
+		tool = "unknown"
+	}
+	m := t.metrics
+	inFlight := m.inFlight.WithLabelValues(tool)
+	inFlight.Inc()
+	start := time.Now()
+	outcome := toolsy.FinishPanic
+	defer func() {
+		inFlight.Dec()
+		m.executions.WithLabelValues(tool, string(outcome)).Inc()
+		m.duration.WithLabelValues(tool, string(outcome)).Observe(time.Since(start).Seconds())
+	}()
+
+	chunks, bytes := m.chunks.WithLabelValues(tool), m.bytes.WithLabelValues(tool)
+	err := t.next.Execute(ctx, run, input, func(c toolsy.Chunk) error {
+		if yieldErr := yield(c); yieldErr != nil {
+			return yieldErr
+		}
+		chunks.Inc()
+		bytes.Add(float64(len(c.Data)))
+		return nil
+	})
+	outcome = toolsy.FinishReasonOf(err)
+	return err
+}
+
+var _ toolsy.Tool = (*metricsTool)(nil)
@@ -0,0 +1,74 @@
+package toolsyprom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type lookupArgs struct {
+	ID int `json:"id"`
+}
+
+type lookupResult struct {
+	Name string `json:"name"`
+}
+
+func newInstrumentedRegistry(t *testing.T, reg *prometheus.Registry) *toolsy.Registry {
+	t.Helper()
+	lookup, err := toolsy.NewTool("lookup", "Lookup", func(_ context.Context, _ *toolsy.RunEnv, a lookupArgs) (
+		lookupResult, error,
+	) {
+		if a.ID < 0 {
+			panic("negative id")
+		}
+		return lookupResult{Name: "ada"}, nil
+	})
+	require.NoError(t, err)
+	mw, err := WithMetrics(reg)
+	require.NoError(t, err)
+	r, err := toolsy.NewRegistryBuilder(toolsy.WithRecoverPanics(true)).Use(mw).Add(lookup).Build()
+	require.NoError(t, err)
+	return r
+}
+
+func execute(r *toolsy.Registry, args string) error {
+	call := toolsy.ToolCall{ToolName: "lookup", Input: toolsy.ToolInput{CallID: "c", ArgsJSON: []byte(args)}}
+	return r.Execute(context.Background(), call, func(toolsy.Chunk) error { return nil })
+}
+
+func TestWithMetrics_CountsOutcomes(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewMetrics(reg)
+	require.NoError(t, err)
+	r := newInstrumentedRegistry(t, reg)
+
+	require.NoError(t, execute(r, `{"id":1}`))
+	require.Error(t, execute(r, `{"id":"x"}`))
+	require.Error(t, execute(r, `{"id":-1}`))
+
+	success := string(toolsy.FinishSuccess)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.executions.WithLabelValues("lookup", success)), 0)
+	assert.InDelta(t, 1,
+		testutil.ToFloat64(m.executions.WithLabelValues("lookup", string(toolsy.FinishClientError))), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.executions.WithLabelValues("lookup", string(toolsy.FinishPanic))), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.chunks.WithLabelValues("lookup")), 0)
+	assert.InDelta(t, len(`{"name":"ada"}`), testutil.ToFloat64(m.bytes.WithLabelValues("lookup")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(m.inFlight.WithLabelValues("lookup")), 0)
+	assert.Equal(t, 3, testutil.CollectAndCount(m.duration))
+}
+
+func TestNewMetrics_ReusesRegisteredCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first, err := NewMetrics(reg)
+	require.NoError(t, err)
+	second, err := NewMetrics(reg)
+	require.NoError(t, err)
+	assert.Same(t, first.executions, second.executions)
+}
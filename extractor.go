@@ -2,7 +2,6 @@ package toolsy
 
 import (
 	"encoding/json"
-	"maps"
 	"reflect"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -14,50 +13,112 @@ import (
 type Extractor[T any] struct {
 	schemaMap map[string]any
 	resolved  *jsonschema.Resolved
+	cfg       SchemaConfig
+	// strings is nil unless T contains a type with a [SchemaStringCodec].
+	stringCodecs *stringDecodeNode
+	byteLimits   *byteLimitNode
+	// sensitiveArgs are the JSON pointers of fields tagged `sensitive:"true"`.
+	sensitiveArgs []string
 }
 
 // NewExtractor creates an Extractor for type T. When strict is true, the generated schema
 // has additionalProperties: false for all objects and all properties required (OpenAI Structured Outputs).
 func NewExtractor[T any](strict bool) (*Extractor[T], error) {
 	return NewExtractorWithConfig[T](SchemaConfig{
-		Strict:   strict,
-		Registry: nil,
+		Strict:              strict,
+		Registry:            nil,
+		AllValidationErrors: false,
+		Transforms:          nil,
+		Nullable:            NullableTypeArray,
+		OptionalAsNullable:  false,
+		PreserveUnions:      false,
+		CloseFreeform:       false,
 	})
 }
 
 // NewExtractorWithConfig creates an Extractor for type T using the provided schema configuration.
 func NewExtractorWithConfig[T any](cfg SchemaConfig) (*Extractor[T], error) {
+	cfg = ensureSchemaConfig(cfg)
 	schemaMap, resolved, err := generateSchema[T](cfg)
 	if err != nil {
 		return nil, err
 	}
+	schemaMap, resolved, err = transformCompiledSchema(schemaMap, resolved, schemaTransforms(cfg))
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := sensitiveArgPointers(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
 	return &Extractor[T]{
 		schemaMap: schemaMap,
 		resolved:  resolved,
+		cfg:       cfg,
+		stringCodecs: buildStringDecodePlan(reflect.TypeFor[T](), cfg.Registry.buildStringCodecs(),
+			make(map[reflect.Type]bool)),
+		byteLimits:    buildByteLimitPlan(reflect.TypeFor[T](), make(map[reflect.Type]bool)),
+		sensitiveArgs: sensitive,
 	}, nil
 }
 
-// Schema returns a shallow copy of the JSON Schema (top-level keys only).
-// Nested maps are shared; callers must not mutate them.
+// Schema returns a deep copy of the JSON Schema: nested maps and slices are cloned, so callers may
+// mutate the result freely.
 func (e *Extractor[T]) Schema() map[string]any {
-	return maps.Clone(e.schemaMap)
+	return deepCopySchema(e.schemaMap)
 }
 
-// ParseAndValidate deserializes argsJSON into T, runs Layer 1 (schema validation) and
-// Layer 2 (Validatable.Validate() if T implements it). Returns [ToolError] for invalid
+// toolManifest builds the manifest of a tool taking arguments of type T, merging the extractor's
+// sensitive fields with those set by [WithSensitiveArgs].
+func (e *Extractor[T]) toolManifest(name, description string, cfg ToolManifest) ToolManifest {
+	cfg.SensitiveArgs = mergeSensitiveArgs(e.sensitiveArgs, cfg.SensitiveArgs)
+	return buildToolManifest(name, description, e.schemaMap, cfg)
+}
+
+// Validate runs Layer 1 only: argsJSON must be valid JSON matching the schema. It neither decodes
+// into T nor calls [Validatable], so it suits cheap yes/no checks such as streamed partial
+// arguments; use [Extractor.ParseAndValidate] for both layers. Failures are the same [ToolError]
+// values ParseAndValidate returns.
+func (e *Extractor[T]) Validate(argsJSON []byte) error {
+	var v any
+	if err := json.Unmarshal(argsJSON, &v); err != nil {
+		return wrapJSONParseError(err)
+	}
+	return e.ValidateValue(v)
+}
+
+// ValidateValue runs Layer 1 only on an already decoded JSON value (as produced by [json.Unmarshal]
+// into any: map[string]any, []any, string, float64, bool or nil). See [Extractor.Validate].
+// Besides the schema it checks the decoded size of []byte fields tagged `maxBytes`.
+func (e *Extractor[T]) ValidateValue(v any) error {
+	if err := validateAgainstSchema(e.resolved, e.schemaMap, v, e.cfg.AllValidationErrors); err != nil {
+		return err
+	}
+	return checkByteLimits(e.byteLimits, v, e.cfg.AllValidationErrors)
+}
+
+// parseOnly runs both validation layers and discards the decoded value.
+func (e *Extractor[T]) parseOnly(argsJSON []byte) error {
+	_, err := e.ParseAndValidate(argsJSON)
+	return err
+}
+
+// ParseAndValidate deserializes argsJSON into T, runs Layer 1 ([Extractor.Validate]) and
+// Layer 2 (Validatable.Validate() if T implements it). Strings of types with a [SchemaStringCodec]
+// (such as time.Duration) are decoded through the codec between the two. Returns [ToolError] for invalid
 // JSON or validation failures so the caller can pass the message to the LLM for self-correction.
 func (e *Extractor[T]) ParseAndValidate(argsJSON []byte) (T, error) {
 	var zero T
-	var v any
-	if err := json.Unmarshal(argsJSON, &v); err != nil {
+	var tree any
+	if err := json.Unmarshal(argsJSON, &tree); err != nil {
 		return zero, wrapJSONParseError(err)
 	}
-	if err := validateAgainstSchema(e.resolved, v); err != nil {
+	if err := e.ValidateValue(tree); err != nil {
 		return zero, err
 	}
-	var args T
-	if err := json.Unmarshal(argsJSON, &args); err != nil {
-		return zero, wrapJSONParseError(err)
+	args, err := e.decode(argsJSON, tree)
+	if err != nil {
+		return zero, err
 	}
 	// Layer 2: Validatable. Try args first (value receiver or T is *SomeType), then &args only
 	// for value type T when args does not implement Validatable (pointer receiver).
@@ -70,6 +131,54 @@ func (e *Extractor[T]) ParseAndValidate(argsJSON []byte) (T, error) {
 	return args, nil
 }
 
+// decode builds T from argsJSON after Layer 1. When T is a generic tree type (map[string]any or
+// []any) and no string codecs apply, tree is reused as is. Struct types are decoded a second time:
+// deriving the validator tree from T would lose unknown and missing fields, and building T from
+// tree would accept integer literals such as 1e2 and merge duplicate keys differently than
+// [json.Unmarshal]. The second decode is about a tenth of ParseAndValidate for structs, against
+// roughly a third for the generic decode and the rest for schema validation.
+func (e *Extractor[T]) decode(argsJSON []byte, tree any) (T, error) {
+	var args T
+	if e.stringCodecs == nil && reuseJSONTree(&args, tree) {
+		return args, nil
+	}
+	if e.stringCodecs != nil {
+		decoded, err := decodeCodecStrings(e.stringCodecs, argsJSON)
+		if err != nil {
+			return args, err
+		}
+		argsJSON = decoded
+	}
+	if err := json.Unmarshal(argsJSON, &args); err != nil {
+		var zero T
+		return zero, wrapJSONParseError(err)
+	}
+	return args, nil
+}
+
+// reuseJSONTree stores tree (a value decoded into any) in *dst when that is exactly what decoding
+// the same JSON into *dst would produce. It reports false when dst needs a real decode.
+func reuseJSONTree(dst, tree any) bool {
+	switch p := dst.(type) {
+	case *map[string]any:
+		m, ok := tree.(map[string]any)
+		if !ok && tree != nil {
+			return false
+		}
+		*p = m
+		return true
+	case *[]any:
+		a, ok := tree.([]any)
+		if !ok && tree != nil {
+			return false
+		}
+		*p = a
+		return true
+	default:
+		return false
+	}
+}
+
 // runLayer2Validation runs Validatable.Validate() on args; if args does not implement Validatable,
 // it tries &args for value types (pointer receiver). Never calls Validate twice for the same receiver.
 func runLayer2Validation[T any](args T) error {
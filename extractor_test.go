@@ -1,6 +1,11 @@
 package toolsy
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -212,6 +217,27 @@ func TestExtractor_ParseAndValidate_ValidatableNotCalledTwice(t *testing.T) {
 	assert.Equal(t, int32(1), layer2ValidateCallCount.Load(), "Validate() must be called exactly once")
 }
 
+// TestExtractor_Validate_SchemaOnly ensures Validate and ValidateValue run Layer 1 without
+// decoding into T or calling Validatable.
+func TestExtractor_Validate_SchemaOnly(t *testing.T) {
+	layer2ValidateCallCount.Store(0)
+	defer func() { layer2ValidateCallCount.Store(0) }()
+	ext, err := NewExtractor[countValidatable](false)
+	require.NoError(t, err)
+	require.NoError(t, ext.Validate([]byte(`{"x": 1}`)))
+	require.NoError(t, ext.ValidateValue(map[string]any{"x": 1.0}))
+	assert.Zero(t, layer2ValidateCallCount.Load(), "Validate must not run Layer 2")
+
+	require.ErrorIs(t, ext.Validate([]byte(`{"x": "one"}`)), ErrValidation)
+	require.ErrorIs(t, ext.ValidateValue(map[string]any{"x": "one"}), ErrValidation)
+	te, ok := AsToolError(ext.Validate([]byte(`{"x":`)))
+	require.True(t, ok)
+	assert.Equal(t, CodeSchemaInvalid, te.Code)
+
+	_, parseErr := ext.ParseAndValidate([]byte(`{"x": "one"}`))
+	assert.Equal(t, ext.Validate([]byte(`{"x": "one"}`)).Error(), parseErr.Error())
+}
+
 // TestExtractor_ParseAndValidate_MapStringAny_Null_NoPanic ensures ParseAndValidate with
 // map[string]any and JSON "null" does not panic (runLayer2Validation guards nil map).
 func TestExtractor_ParseAndValidate_MapStringAny_Null_NoPanic(t *testing.T) {
@@ -232,3 +258,181 @@ func TestExtractor_ParseAndValidate_MapStringAny_Object_NoPanic(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestExtractor_ParseAndValidate_GenericTreeMatchesUnmarshal ensures reusing the schema tree for
+// generic T yields exactly what json.Unmarshal would.
+func TestExtractor_ParseAndValidate_GenericTreeMatchesUnmarshal(t *testing.T) {
+	payload := []byte(`{"a":[1,2.5,{"b":null}],"c":"d","e":true}`)
+	var want map[string]any
+	require.NoError(t, json.Unmarshal(payload, &want))
+
+	mapExt, err := NewExtractor[map[string]any](false)
+	require.NoError(t, err)
+	got, err := mapExt.ParseAndValidate(payload)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	listExt, err := NewExtractor[[]any](false)
+	require.NoError(t, err)
+	gotList, err := listExt.ParseAndValidate([]byte(`[1,"x",null]`))
+	require.NoError(t, err)
+	assert.Equal(t, []any{1.0, "x", nil}, gotList)
+
+	var dst []any
+	assert.False(t, reuseJSONTree(&dst, want), "mismatched trees fall back to a real decode")
+}
+
+type extractorToolArgs struct {
+	City string `json:"city"`
+	Days int    `json:"days"`
+}
+
+func (a extractorToolArgs) Validate() error {
+	if a.Days > 14 {
+		return NewValidationError("days must be at most 14", "days")
+	}
+	return nil
+}
+
+func TestExtractorTool_ReusesSchemaAndValidation(t *testing.T) {
+	t.Parallel()
+	ext, err := NewExtractor[extractorToolArgs](true)
+	require.NoError(t, err)
+	tool, err := ExtractorTool(ext, "forecast", "Forecast",
+		func(_ context.Context, _ *RunEnv, a extractorToolArgs) (string, error) { return a.City, nil },
+		WithTags("weather"))
+	require.NoError(t, err)
+
+	m := tool.Manifest()
+	assert.True(t, m.Strict)
+	assert.Equal(t, []string{"weather"}, m.Tags)
+	assert.Equal(t, ext.schemaMap, m.Parameters, "the tool exposes the extractor's schema")
+
+	for _, args := range []string{`{"city":"Oslo","days":3}`, `{"city":"Oslo"}`, `{"city":"Oslo","days":30}`, `{`,
+		`{"city":"Oslo","days":3,"extra":1}`} {
+		_, extErr := ext.ParseAndValidate([]byte(args))
+		toolErr := tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(args)},
+			func(Chunk) error { return nil })
+		if extErr == nil {
+			require.NoError(t, toolErr, args)
+			continue
+		}
+		extTE, ok := AsToolError(extErr)
+		require.True(t, ok)
+		toolTE, ok := AsToolError(toolErr)
+		require.True(t, ok, args)
+		assert.Equal(t, extTE.Code, toolTE.Code, args)
+		assert.Equal(t, extTE.Reason, toolTE.Reason, args)
+	}
+}
+
+func TestExtractorStreamTool_Streams(t *testing.T) {
+	t.Parallel()
+	ext, err := NewExtractor[extractorToolArgs](false)
+	require.NoError(t, err)
+	tool, err := ExtractorStreamTool(ext, "stream", "Stream",
+		func(_ context.Context, _ *RunEnv, a extractorToolArgs, yield func(Chunk) error) error {
+			for range a.Days {
+				if err := yield(Chunk{Event: EventResult, Data: []byte(a.City), MimeType: MimeTypeText}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	assert.False(t, tool.Manifest().Strict)
+
+	var chunks int
+	require.NoError(t, tool.Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{"city":"Oslo","days":3}`)}, func(Chunk) error {
+			chunks++
+			return nil
+		}))
+	assert.Equal(t, 3, chunks)
+}
+
+func TestExtractorTool_RejectsSchemaOptions(t *testing.T) {
+	t.Parallel()
+	ext, err := NewExtractor[extractorToolArgs](false)
+	require.NoError(t, err)
+	fn := func(_ context.Context, _ *RunEnv, _ extractorToolArgs) (string, error) { return "", nil }
+
+	_, err = ExtractorTool(ext, "x", "x", fn, WithStrict())
+	require.ErrorContains(t, err, "fixed by the extractor")
+	_, err = ExtractorTool(ext, "x", "x", fn, WithSchemaRegistry(NewSchemaRegistry()))
+	require.ErrorContains(t, err, "fixed by the extractor")
+	_, err = ExtractorTool[extractorToolArgs, string](nil, "x", "x", fn)
+	require.Error(t, err)
+}
+
+type benchLineItem struct {
+	SKU      string   `json:"sku"`
+	Quantity int      `json:"quantity" minimum:"1"`
+	Price    float64  `json:"price"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type benchOrderArgs struct {
+	Customer string          `json:"customer"`
+	Notes    string          `json:"notes,omitempty"`
+	Items    []benchLineItem `json:"items"`
+}
+
+func benchOrderJSON(items int) []byte {
+	var buf strings.Builder
+	buf.WriteString(`{"customer":"acme","notes":"leave at the front desk","items":[`)
+	for i := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"sku":"SKU-%04d","quantity":%d,"price":%d.99,"tags":["a","b"]}`, i, i+1, i)
+	}
+	buf.WriteString(`]}`)
+	return []byte(buf.String())
+}
+
+func BenchmarkParseAndValidate(b *testing.B) {
+	payload := benchOrderJSON(50)
+	b.Run("struct", func(b *testing.B) {
+		ext, err := NewExtractor[benchOrderArgs](false)
+		require.NoError(b, err)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for b.Loop() {
+			if _, err := ext.ParseAndValidate(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		ext, err := NewExtractor[map[string]any](false)
+		require.NoError(b, err)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for b.Loop() {
+			if _, err := ext.ParseAndValidate(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNewExtractor(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewExtractor[benchOrderArgs](true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBuildSchema measures uncached schema generation (what the first NewExtractor[T] pays).
+func BenchmarkBuildSchema(b *testing.B) {
+	typeSchemas, _ := NewSchemaRegistry().buildTypeSchemas()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := buildSchema(reflect.TypeFor[benchOrderArgs](), SchemaConfig{Strict: true}, typeSchemas); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package toolsy
+
+import "time"
+
+// EventDone marks the terminal chunk the registry emits after a successful call when [WithFinalChunk]
+// is set. Tools cannot yield it themselves.
+const EventDone EventType = "done"
+
+// Metadata keys of the [EventDone] chunk.
+const (
+	// FinalChunksDeliveredKey holds [ExecutionSummary.ChunksDelivered] as an int.
+	FinalChunksDeliveredKey = "toolsy.chunks_delivered"
+	// FinalDurationKey holds the call duration as a [time.Duration].
+	FinalDurationKey = "toolsy.duration"
+)
+
+// WithFinalChunk makes every successful call end with one [EventDone] chunk carrying CallID, ToolName,
+// no Data, and [FinalChunksDeliveredKey] / [FinalDurationKey] merged into the call metadata, so stream
+// consumers (notably of [Registry.ExecuteBatchStream]) know the call completed. Failed calls get no
+// final chunk: Execute returns the error, and in batch errors-as-chunks mode the IsError chunk is the
+// terminal one. The marker bypasses chunk hooks and [ExecutionSummary] counters. Off by default
+// because existing consumers may count chunks.
+func WithFinalChunk() RegistryOption {
+	return func(o *registryOptions) {
+		o.finalChunk = true
+	}
+}
+
+// IsFinalChunk reports whether c is the terminal chunk added by [WithFinalChunk].
+func IsFinalChunk(c Chunk) bool {
+	return c.Event == EventDone
+}
+
+func newFinalChunk(call ToolCall, summary *ExecutionSummary, elapsed time.Duration) Chunk {
+	return Chunk{ //nolint:exhaustruct // the terminal marker carries identity and metadata only
+		CallID:   call.Input.CallID,
+		ToolName: call.ToolName,
+		Event:    EventDone,
+		Metadata: mergeChunkMetadata(call.Metadata, map[string]any{
+			FinalChunksDeliveredKey: summary.ChunksDelivered,
+			FinalDurationKey:        elapsed,
+		}),
+	}
+}
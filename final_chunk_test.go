@@ -0,0 +1,106 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func finalChunkRegistry(t *testing.T, opts ...RegistryOption) *Registry {
+	t.Helper()
+	stream := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		for _, s := range []string{"a", "b", "c"} {
+			if err := yield(Chunk{Event: EventResult, Data: []byte(s), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	three, err := NewStreamTool("three", "Three chunks", stream)
+	require.NoError(t, err)
+	silent, err := NewStreamTool("silent", "No chunks", func(context.Context, *RunEnv, struct{}, func(Chunk) error) error {
+		return nil
+	})
+	require.NoError(t, err)
+	broken, err := NewStreamTool("broken", "Fails", func(context.Context, *RunEnv, struct{}, func(Chunk) error) error {
+		return errors.New("backend down")
+	})
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder(opts...).Add(three, silent, broken).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func finalChunkCall(name string) ToolCall {
+	return ToolCall{ToolName: name, Input: ToolInput{CallID: name + "-1", ArgsJSON: []byte(`{}`)},
+		Metadata: map[string]any{"trace": "t1"}}
+}
+
+func TestWithFinalChunk_TerminatesSuccessfulCalls(t *testing.T) {
+	t.Parallel()
+	var summary ExecutionSummary
+	reg := finalChunkRegistry(t, WithFinalChunk(),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }))
+
+	var chunks []Chunk
+	collect := func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	}
+	require.NoError(t, reg.Execute(context.Background(), finalChunkCall("three"), collect))
+	require.Len(t, chunks, 4)
+	for _, c := range chunks[:3] {
+		assert.False(t, IsFinalChunk(c))
+	}
+	final := chunks[3]
+	assert.True(t, IsFinalChunk(final))
+	assert.Equal(t, "three-1", final.CallID)
+	assert.Equal(t, "three", final.ToolName)
+	assert.Empty(t, final.Data)
+	assert.Equal(t, 3, final.Metadata[FinalChunksDeliveredKey])
+	assert.IsType(t, time.Duration(0), final.Metadata[FinalDurationKey])
+	assert.Equal(t, "t1", final.Metadata["trace"])
+	assert.Equal(t, 3, summary.ChunksDelivered, "the marker is not counted")
+
+	chunks = nil
+	require.NoError(t, reg.Execute(context.Background(), finalChunkCall("silent"), collect))
+	require.Len(t, chunks, 1)
+	assert.True(t, IsFinalChunk(chunks[0]))
+	assert.Equal(t, 0, chunks[0].Metadata[FinalChunksDeliveredKey])
+
+	chunks = nil
+	require.Error(t, reg.Execute(context.Background(), finalChunkCall("broken"), collect))
+	assert.Empty(t, chunks, "failed calls get no final chunk")
+}
+
+func TestWithFinalChunk_OffByDefaultAndBatch(t *testing.T) {
+	t.Parallel()
+	var n int
+	require.NoError(t, finalChunkRegistry(t).Execute(context.Background(), finalChunkCall("silent"),
+		func(Chunk) error {
+			n++
+			return nil
+		}))
+	assert.Zero(t, n)
+
+	reg := finalChunkRegistry(t, WithFinalChunk())
+	var mu sync.Mutex
+	terminal := map[string]int{}
+	err := reg.ExecuteBatchStream(context.Background(),
+		[]ToolCall{finalChunkCall("three"), finalChunkCall("broken")},
+		func(c Chunk) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if IsFinalChunk(c) || c.IsError {
+				terminal[c.CallID]++
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"three-1": 1, "broken-1": 1}, terminal)
+}
@@ -0,0 +1,70 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+)
+
+// FinishReason classifies how one tool execution ended; see [ExecutionSummary.FinishReason].
+type FinishReason string
+
+// Finish reasons, checked in this order against [ExecutionSummary.Error].
+const (
+	// FinishSuccess: the tool returned nil (soft error chunks it yielded do not change that).
+	FinishSuccess FinishReason = "success"
+	// FinishPanic: the handler panicked and [WithRecoverPanics] turned it into an internal error.
+	FinishPanic FinishReason = "panic"
+	// FinishStreamAborted: the consumer's yield failed ([ErrStreamAborted]), however many chunks it accepted.
+	FinishStreamAborted FinishReason = "stream_aborted"
+	// FinishControl: the tool returned a control signal such as [ErrPause] or [ErrYield].
+	FinishControl FinishReason = "control"
+	// FinishTimeout: the execution deadline expired ([ErrTimeout]).
+	FinishTimeout FinishReason = "timeout"
+	// FinishCanceled: the caller canceled the context ([context.Canceled]).
+	FinishCanceled FinishReason = "canceled"
+	// FinishNotFound: the registry has no tool with the call's name ([CodeToolNotFound]).
+	FinishNotFound FinishReason = "not_found"
+	// FinishShutdown: the registry was shutting down ([ErrShutdown]).
+	FinishShutdown FinishReason = "shutdown"
+	// FinishClientError: the model or caller can fix the call: invalid arguments ([ClientCorrectable]
+	// codes), a policy or view that denies it, or a rate limit it should back off from.
+	FinishClientError FinishReason = "client_error"
+	// FinishSystemError: any other failure (internal errors, handler errors, dependencies, overload).
+	FinishSystemError FinishReason = "system_error"
+)
+
+// FinishReasonOf maps an execution error to its [FinishReason], as the registry does for
+// [ExecutionSummary.FinishReason]. Middleware can use it to label outcomes; below the registry a
+// handler's [context.Canceled] after the deadline has not yet been turned into [ErrTimeout].
+func FinishReasonOf(err error) FinishReason {
+	var pe *panicError
+	switch {
+	case err == nil:
+		return FinishSuccess
+	case errors.As(err, &pe):
+		return FinishPanic
+	case errors.Is(err, ErrStreamAborted):
+		return FinishStreamAborted
+	case IsControlError(err):
+		return FinishControl
+	case errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+		return FinishTimeout
+	case errors.Is(err, context.Canceled):
+		return FinishCanceled
+	}
+	te, ok := AsToolError(err)
+	if !ok {
+		return FinishSystemError
+	}
+	switch {
+	case te.Code == CodeToolNotFound:
+		return FinishNotFound
+	case te.Code == CodeShutdown:
+		return FinishShutdown
+	case ClientCorrectable(te.Code) || te.Code == CodePolicyDenied || te.Code == CodeCapabilityDenied ||
+		te.Code == CodeRateLimited || te.Code == CodeConfirmationDenied:
+		return FinishClientError
+	default:
+		return FinishSystemError
+	}
+}
@@ -0,0 +1,161 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionSummary_FinishReason(t *testing.T) {
+	type A struct {
+		N int `json:"n"`
+	}
+	type R struct{}
+	ok := func(_ context.Context, _ *RunEnv, _ A) (R, error) { return R{}, nil }
+	tests := []struct {
+		name    string
+		handler func(context.Context, *RunEnv, A) (R, error)
+		args    string
+		ctx     func() (context.Context, context.CancelFunc)
+		yield   func(Chunk) error
+		want    FinishReason
+	}{
+		{name: "success", handler: ok, want: FinishSuccess},
+		{name: "client error", handler: ok, args: `{"n":"x"}`, want: FinishClientError},
+		{
+			name: "system error",
+			handler: func(_ context.Context, _ *RunEnv, _ A) (R, error) {
+				return R{}, errors.New("db down")
+			},
+			want: FinishSystemError,
+		},
+		{
+			name: "control",
+			handler: func(_ context.Context, _ *RunEnv, _ A) (R, error) {
+				return R{}, ErrHalt
+			},
+			want: FinishControl,
+		},
+		{
+			name: "timeout",
+			handler: func(ctx context.Context, _ *RunEnv, _ A) (R, error) {
+				<-ctx.Done()
+				return R{}, ctx.Err()
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			want: FinishTimeout,
+		},
+		{
+			name: "canceled",
+			handler: func(ctx context.Context, _ *RunEnv, _ A) (R, error) {
+				<-ctx.Done()
+				return R{}, ctx.Err()
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			want: FinishCanceled,
+		},
+		{
+			name:    "stream aborted",
+			handler: ok,
+			yield:   func(Chunk) error { return errors.New("client gone") },
+			want:    FinishStreamAborted,
+		},
+		{
+			name: "recovered panic",
+			handler: func(_ context.Context, _ *RunEnv, _ A) (R, error) {
+				panic("boom")
+			},
+			want: FinishPanic,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tool, err := NewTool("t", "T", tc.handler)
+			require.NoError(t, err)
+			var got ExecutionSummary
+			reg := mustBuildRegistry(t, []Tool{tool}, WithRecoverPanics(true),
+				WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+					got = s
+				}))
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.ctx != nil {
+				ctx, cancel = tc.ctx()
+			}
+			defer cancel()
+			args, yield := tc.args, tc.yield
+			if args == "" {
+				args = `{"n":1}`
+			}
+			if yield == nil {
+				yield = func(Chunk) error { return nil }
+			}
+			_ = reg.Execute(ctx, ToolCall{ToolName: "t", Input: ToolInput{CallID: "1", ArgsJSON: []byte(args)}}, yield)
+			assert.Equal(t, tc.want, got.FinishReason, "error: %v", got.Error)
+		})
+	}
+}
+
+func TestExecutionSummary_FinishReasonStreamAbortedAfterDelivery(t *testing.T) {
+	tool, err := NewStreamTool("s", "S", func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		for range 3 {
+			if err := yield(Chunk{Event: EventResult, Data: []byte(`{}`), MimeType: MimeTypeJSON}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	var got ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{tool},
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { got = s }))
+	delivered := 0
+	_ = reg.Execute(context.Background(), ToolCall{ToolName: "s", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error {
+			if delivered == 2 {
+				return errors.New("client gone")
+			}
+			delivered++
+			return nil
+		})
+	assert.Equal(t, 2, got.ChunksDelivered)
+	assert.Equal(t, FinishStreamAborted, got.FinishReason)
+}
+
+func TestExecutionSummary_FinishReasonUnrecoveredPanic(t *testing.T) {
+	tool, err := NewTool("p", "P", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	var got ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{tool}, WithRecoverPanics(false),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { got = s }))
+	assert.Panics(t, func() {
+		_ = reg.Execute(context.Background(), ToolCall{ToolName: "p", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+			func(Chunk) error { return nil })
+	})
+	assert.Equal(t, FinishPanic, got.FinishReason)
+}
+
+func TestExecutionSummary_FinishReasonBeforeStart(t *testing.T) {
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "known")})
+	call := ToolCall{ToolName: "missing", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	summary, err := reg.executeWithSummary(context.Background(), call, func(Chunk) error { return nil }, true)
+	require.ErrorIs(t, err, ErrToolNotFound)
+	assert.Equal(t, FinishNotFound, summary.FinishReason)
+
+	require.NoError(t, reg.Shutdown(context.Background()))
+	call.ToolName = "known"
+	summary, err = reg.executeWithSummary(context.Background(), call, func(Chunk) error { return nil }, true)
+	require.ErrorIs(t, err, ErrShutdown)
+	assert.Equal(t, FinishShutdown, summary.FinishReason)
+}
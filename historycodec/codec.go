@@ -14,6 +14,7 @@ type wireToolCall struct {
 	ToolName string `json:"tool_name"`
 	CallID   string `json:"call_id,omitempty"`
 	ArgsJSON []byte `json:"args_json"`
+	Encoding string `json:"args_encoding,omitempty"`
 }
 
 type wireToolResult struct {
@@ -32,6 +33,7 @@ func MarshalToolCall(call toolsy.ToolCall) ([]byte, error) {
 		ToolName: call.ToolName,
 		CallID:   call.Input.CallID,
 		ArgsJSON: call.Input.ArgsJSON,
+		Encoding: call.ArgsEncoding,
 	})
 }
 
@@ -50,6 +52,7 @@ func UnmarshalToolCall(data []byte) (toolsy.ToolCall, error) {
 			CallID:   w.CallID,
 			ArgsJSON: w.ArgsJSON,
 		},
+		ArgsEncoding: w.Encoding,
 	}, nil
 }
 
@@ -27,6 +27,21 @@ func TestMarshalUnmarshalToolCall_Golden(t *testing.T) {
 	require.Equal(t, call, back)
 }
 
+func TestMarshalUnmarshalToolCall_ArgsEncoding(t *testing.T) {
+	call := toolsy.ToolCall{
+		ToolName:     "weather",
+		Input:        toolsy.ToolInput{CallID: "call-2", ArgsJSON: []byte("city=Paris")},
+		ArgsEncoding: toolsy.ArgsEncodingForm,
+	}
+	data, err := historycodec.MarshalToolCall(call)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"args_encoding":"form"`)
+
+	back, err := historycodec.UnmarshalToolCall(data)
+	require.NoError(t, err)
+	require.Equal(t, call, back)
+}
+
 func TestMarshalUnmarshalToolResult_Golden(t *testing.T) {
 	chunk := toolsy.Chunk{
 		CallID:   "call-1",
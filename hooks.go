@@ -0,0 +1,149 @@
+package toolsy
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Hook names reported to [WithOnHookPanic].
+const (
+	HookBeforeExecute = "before_execute"
+	HookAfterExecute  = "after_execute"
+	HookError         = "error"
+	HookChunk         = "chunk"
+	HookChunkProgress = "chunk_progress"
+	HookBatch         = "batch"
+	HookDeprecated    = "deprecated_call"
+)
+
+// WithOnHookPanic sets the callback for a panic raised by an execution hook ([WithOnBeforeExecute],
+// [WithOnAfterExecute], [WithOnError], [WithOnChunk], [WithOnChunkProgress], [WithOnBatch],
+// [WithOnDeprecatedCall]). The
+// panic is recovered, the remaining hooks still run, and the execution result is unchanged. hook is
+// one of the Hook* names. Without a callback the panic is logged with [slog.Default] at error level.
+func WithOnHookPanic(fn func(ctx context.Context, hook string, recovered any)) RegistryOption {
+	return func(o *registryOptions) {
+		o.onHookPanic = fn
+	}
+}
+
+// WithRedactedHooks makes the registry pass hooks ([WithOnBeforeExecute], [WithOnAfterExecute],
+// [WithOnError], [WithOnBatch], and the matching [RegistryScopeSpec] hooks) a call whose
+// Input.ArgsJSON went through [RedactArgs], so sensitive argument fields never reach audit or
+// telemetry callbacks. The tool itself still receives the original arguments.
+func WithRedactedHooks() RegistryOption {
+	return func(o *registryOptions) {
+		o.redactHooks = true
+	}
+}
+
+// hookCall returns the call passed to hooks: call itself, or a copy with redacted args under
+// [WithRedactedHooks]. Unknown tools have no sensitive fields; their args are passed unchanged.
+func (r *Registry) hookCall(call ToolCall) ToolCall {
+	if !r.opts.redactHooks {
+		return call
+	}
+	out := cloneToolCall(call)
+	t, _ := r.lookupTool(call.ToolName)
+	out.Input.ArgsJSON = RedactArgs(t, call.Input.ArgsJSON)
+	return out
+}
+
+// guardHook runs fn, recovering a panic and reporting it as a panic of hook.
+func (r *Registry) guardHook(ctx context.Context, hook string, fn func()) {
+	defer func() {
+		if p := recover(); p != nil {
+			if r.opts.onHookPanic != nil {
+				r.opts.onHookPanic(ctx, hook, p)
+				return
+			}
+			slog.Default().ErrorContext(ctx, "toolsy: execution hook panicked", "hook", hook, "panic", p)
+		}
+	}()
+	fn()
+}
+
+// Each hook receives its own copy of the call, so a hook that mutates it cannot affect the next one.
+
+func (r *Registry) runBeforeHooks(ctx context.Context, call ToolCall) {
+	if len(r.opts.onBefore) == 0 {
+		return
+	}
+	call = r.hookCall(call)
+	for _, fn := range r.opts.onBefore {
+		r.guardHook(ctx, HookBeforeExecute, func() { fn(ctx, cloneToolCall(call)) })
+	}
+}
+
+func (r *Registry) runAfterHooks(ctx context.Context, call ToolCall, summary ExecutionSummary, dur time.Duration) {
+	if len(r.opts.onAfter) == 0 {
+		return
+	}
+	call = r.hookCall(call)
+	for _, fn := range r.opts.onAfter {
+		r.guardHook(ctx, HookAfterExecute, func() { fn(ctx, cloneToolCall(call), summary, dur) })
+	}
+}
+
+func (r *Registry) runErrorHooks(ctx context.Context, call ToolCall, err error) {
+	if len(r.opts.onError) == 0 {
+		return
+	}
+	call = r.hookCall(call)
+	for _, fn := range r.opts.onError {
+		r.guardHook(ctx, HookError, func() { fn(ctx, cloneToolCall(call), err) })
+	}
+}
+
+func (r *Registry) runDeprecatedHooks(ctx context.Context, call ToolCall, message string) {
+	if len(r.opts.onDeprecated) == 0 {
+		return
+	}
+	call = r.hookCall(call)
+	for _, fn := range r.opts.onDeprecated {
+		r.guardHook(ctx, HookDeprecated, func() { fn(ctx, cloneToolCall(call), message) })
+	}
+}
+
+func (r *Registry) runChunkHooks(ctx context.Context, c Chunk) {
+	for _, fn := range r.opts.onChunk {
+		r.guardHook(ctx, HookChunk, func() { fn(ctx, c) })
+	}
+}
+
+func (r *Registry) runChunkProgressHooks(ctx context.Context, c Chunk, progress ChunkProgress) {
+	for _, fn := range r.opts.onChunkProgress {
+		r.guardHook(ctx, HookChunkProgress, func() { fn(ctx, c, progress) })
+	}
+}
+
+// runBatchHooks nests the batch hooks in registration order: each one derives its context from the
+// previous one's, and the finish funcs run in reverse.
+func (r *Registry) runBatchHooks(ctx context.Context, calls []ToolCall) (context.Context, func(error)) {
+	finishes := make([]func(error), 0, len(r.opts.onBatch))
+	hookCalls := make([]ToolCall, len(calls))
+	for i, call := range calls {
+		hookCalls[i] = r.hookCall(call)
+	}
+	for _, fn := range r.opts.onBatch {
+		copied := make([]ToolCall, len(hookCalls))
+		for i, call := range hookCalls {
+			copied[i] = cloneToolCall(call)
+		}
+		r.guardHook(ctx, HookBatch, func() {
+			next, finish := fn(ctx, copied)
+			if next != nil {
+				ctx = next
+			}
+			if finish != nil {
+				finishes = append(finishes, finish)
+			}
+		})
+	}
+	return ctx, func(err error) {
+		for i := len(finishes) - 1; i >= 0; i-- {
+			r.guardHook(ctx, HookBatch, func() { finishes[i](err) })
+		}
+	}
+}
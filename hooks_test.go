@@ -0,0 +1,126 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *hookLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *hookLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+func hookOptions(log *hookLog, name string) []RegistryOption {
+	return []RegistryOption{
+		WithOnBeforeExecute(func(context.Context, ToolCall) { log.add(name + ":before") }),
+		WithOnChunk(func(context.Context, Chunk) { log.add(name + ":chunk") }),
+		WithOnAfterExecute(func(context.Context, ToolCall, ExecutionSummary, time.Duration) {
+			log.add(name + ":after")
+		}),
+		WithOnError(func(context.Context, ToolCall, error) { log.add(name + ":error") }),
+	}
+}
+
+func TestRegistryHooks_ComposeInRegistrationOrder(t *testing.T) {
+	log := &hookLog{}
+	opts := append(hookOptions(log, "metrics"), hookOptions(log, "audit")...)
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "ok")}, opts...)
+	call := ToolCall{ToolName: "ok", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.Equal(t, []string{
+		"metrics:before", "audit:before", "metrics:chunk", "audit:chunk", "metrics:after", "audit:after",
+	}, log.snapshot())
+
+	log.events = nil
+	call.ToolName = "missing"
+	require.Error(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.Equal(t, []string{"metrics:error", "audit:error", "metrics:after", "audit:after"}, log.snapshot())
+}
+
+func TestRegistryHooks_PanicIsIsolated(t *testing.T) {
+	log := &hookLog{}
+	var panics []string
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "ok")},
+		WithOnBeforeExecute(func(context.Context, ToolCall) { panic("before broke") }),
+		WithOnAfterExecute(func(context.Context, ToolCall, ExecutionSummary, time.Duration) { panic("after broke") }),
+		WithOnChunk(func(context.Context, Chunk) { panic("chunk broke") }),
+		WithOnBeforeExecute(func(context.Context, ToolCall) { log.add("before") }),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+			log.add("after")
+			assert.NoError(t, s.Error)
+		}),
+		WithOnHookPanic(func(_ context.Context, hook string, recovered any) {
+			panics = append(panics, fmt.Sprintf("%s: %v", hook, recovered))
+		}),
+	)
+	delivered := 0
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "ok", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error {
+			delivered++
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, []string{"before", "after"}, log.snapshot())
+	assert.Equal(t, []string{
+		HookBeforeExecute + ": before broke", HookChunk + ": chunk broke", HookAfterExecute + ": after broke",
+	}, panics)
+}
+
+func TestRegistryHooks_ScopeDoesNotLeakIntoParent(t *testing.T) {
+	log := &hookLog{}
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "ok")}, hookOptions(log, "parent")...)
+	scope, err := reg.NewScope(RegistryScopeSpec{
+		OnBeforeExecute: func(context.Context, ToolCall) { log.add("scope:before") },
+	})
+	require.NoError(t, err)
+	call := ToolCall{ToolName: "ok", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	require.NoError(t, scope.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.Equal(t, []string{"parent:before", "scope:before"}, log.snapshot()[:2])
+
+	log.events = nil
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.NotContains(t, log.snapshot(), "scope:before")
+}
+
+type hookCtxKey struct{}
+
+func TestRegistryHooks_BatchHooksNest(t *testing.T) {
+	log := &hookLog{}
+	batchHook := func(name string) RegistryOption {
+		return WithOnBatch(func(ctx context.Context, _ []ToolCall) (context.Context, func(error)) {
+			parent, _ := ctx.Value(hookCtxKey{}).(string)
+			log.add(name + ":start(" + parent + ")")
+			return context.WithValue(ctx, hookCtxKey{}, name), func(error) { log.add(name + ":finish") }
+		})
+	}
+	broken := WithOnBatch(func(context.Context, []ToolCall) (context.Context, func(error)) {
+		panic(errors.New("batch hook broke"))
+	})
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "ok")}, batchHook("outer"), broken, batchHook("inner"),
+		WithOnHookPanic(func(_ context.Context, hook string, _ any) { log.add("panic:" + hook) }))
+	calls := []ToolCall{{ToolName: "ok", Input: ToolInput{ArgsJSON: []byte(`{}`)}}}
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(Chunk) error { return nil }))
+	assert.Equal(t, []string{
+		"outer:start()", "panic:" + HookBatch, "inner:start(outer)", "inner:finish", "outer:finish",
+	}, log.snapshot())
+}
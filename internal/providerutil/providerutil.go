@@ -0,0 +1,50 @@
+// Package providerutil holds the conversion helpers shared by the providers/anthropic and
+// providers/openai adapters, which accept the same tool names and object schemas.
+package providerutil
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// ToolNamePattern is the tool name syntax accepted by the Anthropic and OpenAI APIs.
+var ToolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// CheckToolName returns an error wrapping sentinel when name does not match [ToolNamePattern].
+func CheckToolName(name string, sentinel error) error {
+	if !ToolNamePattern.MatchString(name) {
+		return fmt.Errorf("%w %q: must match %s", sentinel, name, ToolNamePattern)
+	}
+	return nil
+}
+
+// ObjectSchema deep-copies schema and fills in what the APIs require of a top-level input schema:
+// a nil schema becomes an empty object, a missing "type" becomes "object", and an object schema
+// without "properties" gets an empty properties map.
+func ObjectSchema(schema map[string]any) map[string]any {
+	out := toolsy.CloneSchema(schema)
+	if out == nil {
+		out = map[string]any{}
+	}
+	if _, ok := out["type"]; !ok {
+		out["type"] = "object"
+	}
+	if _, ok := out["properties"]; !ok && out["type"] == "object" {
+		out["properties"] = map[string]any{}
+	}
+	return out
+}
+
+// ResultText returns the chunk data as text. With noResultText set, a no-result chunk
+// (see [toolsy.NoResultReason]) renders as "no results" or "no results: <reason>" instead.
+func ResultText(c toolsy.Chunk, noResultText bool) string {
+	if reason, ok := toolsy.NoResultReason(c); ok && noResultText {
+		if reason == "" {
+			return "no results"
+		}
+		return "no results: " + reason
+	}
+	return string(c.Data)
+}
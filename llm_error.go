@@ -0,0 +1,135 @@
+package toolsy
+
+import (
+	"encoding/json"
+)
+
+// LLMErrorCode is the stable, lowercase error category of [ErrorToLLMJSON], meant for prompt
+// templates and metrics labels. It is coarser than [ErrorCode] where the model cannot act on the
+// difference, and finer where it can: argument errors are split into invalid JSON, schema
+// violations, and business rules.
+type LLMErrorCode string
+
+// LLM error codes, as returned by [LLMErrorCodeOf].
+const (
+	// LLMInvalidJSON: the arguments are not valid JSON or could not be decoded ([CodeSchemaInvalid]
+	// wrapping a decode error).
+	LLMInvalidJSON LLMErrorCode = "invalid_json"
+	// LLMSchemaViolation: the arguments do not match the parameters schema; see the violations.
+	LLMSchemaViolation LLMErrorCode = "schema_violation"
+	// LLMBusinessRule: the arguments match the schema but the tool rejected them ([Validatable] or a
+	// handler's [NewValidationError]).
+	LLMBusinessRule LLMErrorCode = "business_rule"
+	// LLMToolNotFound: no tool has the called name ([CodeToolNotFound]).
+	LLMToolNotFound LLMErrorCode = "tool_not_found"
+	// LLMUnauthorized: a policy or capability check denied the call.
+	LLMUnauthorized LLMErrorCode = "unauthorized"
+	// LLMDeclined: the user declined the call ([CodeConfirmationDenied]).
+	LLMDeclined LLMErrorCode = "declined"
+	// LLMRateLimited: the call was rate limited; retry after the delay in the message.
+	LLMRateLimited LLMErrorCode = "rate_limited"
+	// LLMUnavailable: the registry is overloaded or another worker holds the call's lease; retry later.
+	LLMUnavailable LLMErrorCode = "unavailable"
+	// LLMTimeout: the execution deadline expired.
+	LLMTimeout LLMErrorCode = "timeout"
+	// LLMBudgetExceeded: the call exceeded a usage budget ([CodeBudgetExceeded]).
+	LLMBudgetExceeded LLMErrorCode = "budget_exceeded"
+	// LLMInternalError: any other failure. Its message is generic so internals do not reach the model.
+	LLMInternalError LLMErrorCode = "internal_error"
+)
+
+// llmInternalMessage replaces the message of every [LLMInternalError].
+const llmInternalMessage = "internal error"
+
+// LLMErrorCodeOf maps an execution error to its [LLMErrorCode]; it returns "" for nil. Errors that
+// are not a [ToolError] are classified like error chunks ([NewErrorChunkFromErr]): deadlines are
+// timeouts and everything else is an internal error.
+func LLMErrorCodeOf(err error) LLMErrorCode {
+	if err == nil {
+		return ""
+	}
+	return llmErrorCode(toolErrorFromExecutionErr(err))
+}
+
+func llmErrorCode(te *ToolError) LLMErrorCode {
+	if te == nil {
+		return LLMInternalError
+	}
+	switch te.Code { //nolint:exhaustive // every other code is an internal error
+	case CodeSchemaInvalid:
+		if te.Err != nil {
+			return LLMInvalidJSON
+		}
+		return LLMSchemaViolation
+	case CodeValidationFailed:
+		if len(te.Violations) > 0 {
+			return LLMSchemaViolation
+		}
+		return LLMBusinessRule
+	case CodeToolNotFound:
+		return LLMToolNotFound
+	case CodePolicyDenied, CodeCapabilityDenied:
+		return LLMUnauthorized
+	case CodeConfirmationDenied:
+		return LLMDeclined
+	case CodeRateLimited:
+		return LLMRateLimited
+	case CodeOverloaded, CodeLeaseHeld:
+		return LLMUnavailable
+	case CodeTimeout:
+		return LLMTimeout
+	case CodeBudgetExceeded:
+		return LLMBudgetExceeded
+	default:
+		return LLMInternalError
+	}
+}
+
+type llmErrorEnvelope struct {
+	Error llmErrorBody `json:"error"`
+}
+
+type llmErrorBody struct {
+	Code       LLMErrorCode     `json:"code"`
+	Message    string           `json:"message"`
+	Retryable  bool             `json:"retryable"`
+	Violations []FieldViolation `json:"violations,omitempty"`
+}
+
+// ErrorToLLMJSON renders err as the JSON object to feed back to the model:
+//
+//	{"error":{"code":"schema_violation","message":"...","retryable":false,"violations":[...]}}
+//
+// The code is [LLMErrorCodeOf]. The message is the error's [ToolError.SafeMessage] or Reason reduced
+// to one line; internal errors get a generic message and no details. Violations list the schema
+// violations of argument errors. It returns nil for a nil err.
+func ErrorToLLMJSON(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	te := toolErrorFromExecutionErr(err)
+	body := llmErrorBody{Code: llmErrorCode(te), Message: llmInternalMessage, Retryable: false, Violations: nil}
+	if te != nil {
+		body.Retryable = te.Retryable
+	}
+	if body.Code != LLMInternalError {
+		body.Message = llmErrorMessage(te)
+		body.Violations = append([]FieldViolation(nil), te.Violations...)
+	}
+	data, marshalErr := json.Marshal(llmErrorEnvelope{Error: body})
+	if marshalErr != nil {
+		body = llmErrorBody{Code: LLMInternalError, Message: llmInternalMessage, Retryable: false, Violations: nil}
+		data, _ = json.Marshal(llmErrorEnvelope{Error: body})
+	}
+	return data
+}
+
+func llmErrorMessage(te *ToolError) string {
+	if msg := sanitizeErrorReason(te.SafeMessage); msg != "" {
+		return msg
+	}
+	if msg := sanitizeErrorReason(te.Reason); msg != "" {
+		return msg
+	}
+	return string(te.Code)
+}
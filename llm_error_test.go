@@ -0,0 +1,114 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type llmErrorArgs struct {
+	Count int `json:"count" minimum:"1"`
+}
+
+func (a llmErrorArgs) Validate() error {
+	if a.Count == 13 {
+		return errors.New("count 13 is reserved")
+	}
+	return nil
+}
+
+type llmEnvelope struct {
+	Error struct {
+		Code       LLMErrorCode     `json:"code"`
+		Message    string           `json:"message"`
+		Retryable  bool             `json:"retryable"`
+		Violations []FieldViolation `json:"violations"`
+	} `json:"error"`
+}
+
+func decodeLLMEnvelope(t *testing.T, err error) llmEnvelope {
+	t.Helper()
+	var env llmEnvelope
+	require.NoError(t, json.Unmarshal(ErrorToLLMJSON(err), &env))
+	return env
+}
+
+func TestLLMErrorCodeOf_ArgumentErrors(t *testing.T) {
+	ext, err := NewExtractor[llmErrorArgs](false)
+	require.NoError(t, err)
+	parse := func(args string) error {
+		_, err := ext.ParseAndValidate([]byte(args))
+		require.Error(t, err)
+		return err
+	}
+
+	assert.Equal(t, LLMInvalidJSON, LLMErrorCodeOf(parse(`{"count":`)))
+
+	schemaErr := parse(`{"count":0}`)
+	assert.Equal(t, LLMSchemaViolation, LLMErrorCodeOf(schemaErr))
+	env := decodeLLMEnvelope(t, schemaErr)
+	assert.Equal(t, LLMSchemaViolation, env.Error.Code)
+	assert.False(t, env.Error.Retryable)
+	require.Len(t, env.Error.Violations, 1)
+	assert.Equal(t, "/count", env.Error.Violations[0].Path)
+	assert.Equal(t, "minimum", env.Error.Violations[0].Keyword)
+
+	ruleErr := parse(`{"count":13}`)
+	assert.Equal(t, LLMBusinessRule, LLMErrorCodeOf(ruleErr))
+	env = decodeLLMEnvelope(t, ruleErr)
+	assert.Equal(t, "count 13 is reserved", env.Error.Message)
+	assert.Empty(t, env.Error.Violations)
+}
+
+func TestLLMErrorCodeOf_Codes(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want LLMErrorCode
+	}{
+		{nil, ""},
+		{NewSchemaError("arguments must be an object"), LLMSchemaViolation},
+		{NewValidationError("quantity exceeds stock"), LLMBusinessRule},
+		{NewUnknownToolError("serch", "search"), LLMToolNotFound},
+		{NewPolicyDeniedError("not allowed"), LLMUnauthorized},
+		{NewConfirmationDeniedError(), LLMDeclined},
+		{NewRateLimitedError(time.Second), LLMRateLimited},
+		{NewOverloadedError(4, 4, time.Second), LLMUnavailable},
+		{NewLeaseHeldError("k"), LLMUnavailable},
+		{NewTimeoutError(true), LLMTimeout},
+		{fmt.Errorf("call: %w", context.DeadlineExceeded), LLMTimeout},
+		{NewBudgetExceededError("token budget"), LLMBudgetExceeded},
+		{NewShutdownError(), LLMInternalError},
+		{NewInternalError(errors.New("db password rejected")), LLMInternalError},
+		{errors.New("plain failure"), LLMInternalError},
+	} {
+		assert.Equal(t, tc.want, LLMErrorCodeOf(tc.err), "%v", tc.err)
+	}
+}
+
+func TestErrorToLLMJSON(t *testing.T) {
+	assert.Nil(t, ErrorToLLMJSON(nil))
+
+	assert.JSONEq(t, `{"error":{"code":"internal_error","message":"internal error","retryable":false}}`,
+		string(ErrorToLLMJSON(NewInternalError(errors.New("db password rejected")))))
+	assert.JSONEq(t, `{"error":{"code":"internal_error","message":"internal error","retryable":false}}`,
+		string(ErrorToLLMJSON(errors.New("stack trace with secrets"))))
+
+	env := decodeLLMEnvelope(t, NewRateLimitedError(2*time.Second))
+	assert.Equal(t, LLMRateLimited, env.Error.Code)
+	assert.True(t, env.Error.Retryable)
+	assert.Contains(t, env.Error.Message, "2s")
+
+	safe := WithSafeMessage(NewPolicyDeniedError("subject 42 lacks role admin"), "you may not delete projects")
+	env = decodeLLMEnvelope(t, safe)
+	assert.Equal(t, LLMUnauthorized, env.Error.Code)
+	assert.Equal(t, "you may not delete projects", env.Error.Message)
+
+	env = decodeLLMEnvelope(t, NewValidationError("first line\nsecond line"))
+	assert.Equal(t, "first line", env.Error.Message)
+}
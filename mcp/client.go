@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -170,6 +171,10 @@ func (c *Client) handleProgress(params []byte) {
 }
 
 // GetTools returns an iterator over all tools from the server (handles pagination via cursor).
+// Remote names with characters outside [toolsy.DefaultToolNamePattern] (for example
+// "github/create_issue") get '_' in their place, and names are cut to 64 bytes; calls still reach the
+// server under the original name. Remote names that map to the same local name fail at
+// [toolsy.RegistryBuilder.Build] as duplicates.
 func (c *Client) GetTools(ctx context.Context) iter.Seq2[toolsy.Tool, error] {
 	fetch := func(ctx context.Context, cursor string) ([]MCPTool, string, error) {
 		params := ToolsListParams{Cursor: cursor}
@@ -215,17 +220,40 @@ func defaultToolInputSchemaJSON() []byte {
 	return []byte(`{"type":"object","properties":{}}`)
 }
 
+// maxImportedToolNameLen matches the length limit of [toolsy.DefaultToolNamePattern].
+const maxImportedToolNameLen = 64
+
+// importedToolName maps a remote tool name such as "github/create_issue" onto
+// [toolsy.DefaultToolNamePattern]: other characters become '_' and the result is cut to 64 bytes.
+func importedToolName(name string) string {
+	mapped := []byte(name)
+	for i, b := range mapped {
+		valid := b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '_' || b == '.' ||
+			b == '-'
+		if !valid {
+			mapped[i] = '_'
+		}
+	}
+	if len(mapped) > maxImportedToolNameLen {
+		mapped = mapped[:maxImportedToolNameLen]
+	}
+	return string(mapped)
+}
+
+// toolToProxy names the proxy with [importedToolName] so remote names pass toolsy's name validation;
+// tools/call still sends the server's original name.
 func (c *Client) toolToProxy(m *MCPTool) (toolsy.Tool, error) {
-	name := m.Name
+	remoteName := m.Name
 	description := toolDescription(m)
 	schema := m.InputSchema
 	if len(schema) == 0 {
 		schema = defaultToolInputSchemaJSON()
 	}
 	handler := func(ctx context.Context, _ *toolsy.RunEnv, rawArgs []byte, yield func(toolsy.Chunk) error) error {
-		return c.runMCPToolCall(ctx, name, rawArgs, yield)
+		return c.runMCPToolCall(ctx, remoteName, rawArgs, yield)
 	}
-	return toolsy.NewProxyTool(name, description, schema, handler, mcpToolPolicyOptions(m.Annotations)...)
+	return toolsy.NewProxyTool(importedToolName(remoteName), description, schema, handler,
+		mcpToolPolicyOptions(m.Annotations)...)
 }
 
 type callResultWithErr struct {
@@ -243,10 +271,20 @@ func (c *Client) notifyCancelledRequest(parentCtx context.Context, requestID str
 	_ = c.transport.Notify(
 		ctx,
 		MethodCancelled,
-		CancelledParams{RequestID: json.RawMessage(`"` + requestID + `"`)},
+		CancelledParams{RequestID: cancelledRequestID(requestID)},
 	)
 }
 
+// cancelledRequestID encodes requestID with the JSON type the request used: the built-in transports
+// send numeric IDs, and servers match notifications/cancelled against the raw ID.
+func cancelledRequestID(requestID string) json.RawMessage {
+	if _, err := strconv.ParseUint(requestID, 10, 64); err == nil {
+		return json.RawMessage(requestID)
+	}
+	quoted, _ := json.Marshal(requestID)
+	return quoted
+}
+
 func (c *Client) startToolsCallAsync(
 	ctx context.Context,
 	transport Transport,
@@ -259,15 +297,6 @@ func (c *Client) startToolsCallAsync(
 	}()
 }
 
-func readRequestIDIfReady(ch <-chan callResultWithErr) string {
-	select {
-	case r := <-ch:
-		return r.requestID
-	default:
-		return ""
-	}
-}
-
 func (c *Client) newToolProgressForwarder(progressCh chan toolsy.Chunk, done chan struct{}) func([]byte) {
 	return func(params []byte) {
 		select {
@@ -322,14 +351,21 @@ func (c *Client) runMCPToolCall(
 	c.progressCallbacks.Store(progressToken, progressFn)
 	defer c.progressCallbacks.Delete(progressToken)
 
+	// The request gets its own context so an abandoned stream stops the Call and yields its ID.
+	callCtx, cancelCall := context.WithCancel(ctx)
+	defer cancelCall()
 	resultCh := make(chan callResultWithErr, 1)
-	c.startToolsCallAsync(ctx, transport, callParams, resultCh)
+	c.startToolsCallAsync(callCtx, transport, callParams, resultCh)
+	abandon := func() {
+		cancelCall()
+		c.notifyCancelledRequest(ctx, (<-resultCh).requestID)
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			c.notifyCancelledRequest(ctx, readRequestIDIfReady(resultCh))
-			return toolsy.ErrStreamAborted
+			abandon()
+			return ctx.Err()
 		case r := <-resultCh:
 			return c.handleToolCallResult(ctx, r, yield)
 		case ch, ok := <-progressCh:
@@ -337,7 +373,7 @@ func (c *Client) runMCPToolCall(
 				return nil
 			}
 			if err := yield(ch); err != nil {
-				c.notifyCancelledRequest(ctx, readRequestIDIfReady(resultCh))
+				abandon()
 				return toolsy.ErrStreamAborted
 			}
 		}
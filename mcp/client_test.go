@@ -561,3 +561,11 @@ func TestHandleToolCallResult_InterruptInChainOverReadLimit(t *testing.T) {
 	)
 	require.ErrorIs(t, err, context.Canceled)
 }
+
+func TestFormatContentItems_ImageFieldNames(t *testing.T) {
+	out := FormatContentItems([]ContentItem{
+		{Type: "image", Data: "iVA=", MimeType: "image/jpeg"},
+		{Type: "image", Base64: "R0k=", MediaType: "image/gif"},
+	})
+	require.Equal(t, "![image](data:image/jpeg;base64,iVA=)\n![image](data:image/gif;base64,R0k=)", string(out))
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -52,11 +53,8 @@ func formatContentItems(items []ContentItem) []byte {
 		case "text":
 			b.WriteString(item.Text)
 		case "image":
-			mediaType := item.MediaType
-			if mediaType == "" {
-				mediaType = "image/png"
-			}
-			_, _ = fmt.Fprintf(&b, "![image](data:%s;base64,%s)", mediaType, item.Base64)
+			mediaType := cmp.Or(item.MimeType, item.MediaType, "image/png")
+			_, _ = fmt.Fprintf(&b, "![image](data:%s;base64,%s)", mediaType, cmp.Or(item.Data, item.Base64))
 		default:
 			if item.Text != "" {
 				b.WriteString(item.Text)
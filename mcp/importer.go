@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// ImportTools lists every tool the server exposes (following tools/list pagination) and returns one
+// proxy per entry, ready for [toolsy.RegistryBuilder.Add]. Each proxy validates arguments against the
+// server's inputSchema, forwards calls via tools/call and maps the result content to chunks: text and
+// images become the result data, and a result with isError becomes a client error the model can
+// correct. Cancelling the call context (including its deadline expiring) cancels the remote request with
+// notifications/cancelled.
+//
+// ImportTools is also the refresh function: call it again after [Client.OnToolsChanged] fires and
+// build a new registry from the result.
+func ImportTools(ctx context.Context, c *Client) ([]toolsy.Tool, error) {
+	var tools []toolsy.Tool
+	for tool, err := range c.GetTools(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// OnToolsChanged registers fn to run when the server sends notifications/tools/list_changed, replacing
+// any earlier handler. fn runs on its own goroutine, so it may call [ImportTools] directly; it is not
+// serialized with other invocations.
+func (c *Client) OnToolsChanged(fn func()) {
+	c.transport.OnNotification(MethodToolsListChanged, func([]byte) {
+		if fn != nil {
+			go fn()
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// pipeTransport is a client [Transport] wired to an in-process [Serve] over pipes.
+type pipeTransport struct {
+	in   *io.PipeWriter
+	out  *io.PipeReader
+	done chan error
+
+	nextID   atomic.Uint64
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	pending  map[string]chan serverMessage
+	handlers map[string]func([]byte)
+	notified []string
+}
+
+func newPipeTransport(t *testing.T, reg *toolsy.Registry) *pipeTransport {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	p := &pipeTransport{
+		in:       inW,
+		out:      outR,
+		done:     make(chan error, 1),
+		pending:  map[string]chan serverMessage{},
+		handlers: map[string]func([]byte){},
+	}
+	go func() {
+		p.done <- Serve(context.Background(), reg, NewStdioServerTransport(inR, outW),
+			WithServerLogger(slog.New(slog.DiscardHandler)))
+		_ = outW.Close()
+	}()
+	t.Cleanup(func() {
+		_ = p.Close()
+		select {
+		case err := <-p.done:
+			require.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Error("Serve did not return after the transport closed")
+		}
+	})
+	return p
+}
+
+func (p *pipeTransport) Start(context.Context) error {
+	go p.readLoop()
+	return nil
+}
+
+func (p *pipeTransport) readLoop() {
+	scanner := bufio.NewScanner(p.out)
+	for scanner.Scan() {
+		var m serverMessage
+		if json.Unmarshal(scanner.Bytes(), &m) != nil {
+			continue
+		}
+		p.mu.Lock()
+		if m.Method != "" {
+			handler := p.handlers[m.Method]
+			p.mu.Unlock()
+			if handler != nil {
+				handler(m.Params)
+			}
+			continue
+		}
+		ch := p.pending[string(m.ID)]
+		delete(p.pending, string(m.ID))
+		p.mu.Unlock()
+		if ch != nil {
+			ch <- m
+		}
+	}
+}
+
+func (p *pipeTransport) write(msg any) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err = p.in.Write(append(line, '\n'))
+	return err
+}
+
+func (p *pipeTransport) Call(ctx context.Context, method string, params any) ([]byte, string, error) {
+	id := strconv.FormatUint(p.nextID.Add(1), 10)
+	ch := make(chan serverMessage, 1)
+	p.mu.Lock()
+	p.pending[id] = ch
+	p.mu.Unlock()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, id, err
+	}
+	req := Request{JSONRPC: JSONRPCVersion, ID: json.RawMessage(id), Method: method, Params: raw}
+	if err := p.write(req); err != nil {
+		return nil, id, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, id, ctx.Err()
+	case m := <-ch:
+		if m.Error != nil {
+			return nil, id, errors.New(m.Error.Message)
+		}
+		return m.Result, id, nil
+	}
+}
+
+func (p *pipeTransport) Notify(_ context.Context, method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.notified = append(p.notified, method)
+	p.mu.Unlock()
+	return p.write(Request{JSONRPC: JSONRPCVersion, Method: method, Params: raw})
+}
+
+func (p *pipeTransport) OnNotification(method string, handler func([]byte)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[method] = handler
+}
+
+// emit delivers a server notification as if it had arrived on the wire.
+func (p *pipeTransport) emit(method string) {
+	p.mu.Lock()
+	handler := p.handlers[method]
+	p.mu.Unlock()
+	handler(nil)
+}
+
+func (p *pipeTransport) Close() error {
+	return p.in.Close()
+}
+
+func importRegistry(t *testing.T, remote *toolsy.Registry) (*toolsy.Registry, *Client, *pipeTransport) {
+	t.Helper()
+	transport := newPipeTransport(t, remote)
+	client, err := Connect(context.Background(), transport)
+	require.NoError(t, err)
+	tools, err := ImportTools(context.Background(), client)
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistryBuilder().Add(tools...).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Shutdown(context.Background()) })
+	return reg, client, transport
+}
+
+func executeCollect(ctx context.Context, reg *toolsy.Registry, name, args string) ([]toolsy.Chunk, error) {
+	var chunks []toolsy.Chunk
+	err := reg.Execute(ctx, toolsy.ToolCall{ToolName: name, Input: toolsy.ToolInput{ArgsJSON: []byte(args)}},
+		func(c toolsy.Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	return chunks, err
+}
+
+func TestImportTools_EndToEnd(t *testing.T) {
+	reg, _, _ := importRegistry(t, newServerRegistry(t))
+
+	manifests := map[string]toolsy.ToolManifest{}
+	for _, tool := range reg.GetAllTools() {
+		manifests[tool.Manifest().Name] = tool.Manifest()
+	}
+	require.Len(t, manifests, 2)
+	require.True(t, manifests["search"].ReadOnly)
+	require.True(t, manifests["drop_table"].Dangerous)
+
+	chunks, err := executeCollect(context.Background(), reg, "search", `{"query":"go"}`)
+	require.NoError(t, err)
+	result := chunks[len(chunks)-1]
+	require.Equal(t, toolsy.EventResult, result.Event)
+	require.JSONEq(t, `"results for go"`, string(result.Data))
+
+	_, err = executeCollect(context.Background(), reg, "search", `{"query":"go","limit":50}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation, "the server's inputSchema is enforced locally")
+
+	chunks, err = executeCollect(context.Background(), reg, "drop_table", `{}`)
+	require.NoError(t, err)
+	result = chunks[len(chunks)-1]
+	require.True(t, result.IsError, "isError content becomes a client error chunk")
+	require.Contains(t, string(result.Data), toolsy.CodeValidationFailed)
+	require.NotContains(t, string(result.Data), "password")
+}
+
+func TestImportTools_SanitizesRemoteNames(t *testing.T) {
+	remoteTool, err := toolsy.NewTool("github/create_issue", "Create an issue",
+		func(_ context.Context, _ *toolsy.RunEnv, a serverSearchArgs) (string, error) {
+			return "created " + a.Query, nil
+		}, toolsy.WithNameValidation(nil))
+	require.NoError(t, err)
+	remote, err := toolsy.NewRegistryBuilder(toolsy.WithToolNameValidation(regexp.MustCompile(`^.+$`))).
+		Add(remoteTool).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = remote.Shutdown(context.Background()) })
+
+	reg, _, _ := importRegistry(t, remote)
+	_, ok := reg.GetTool("github_create_issue")
+	require.True(t, ok, "the proxy name passes the default name validation")
+
+	chunks, err := executeCollect(context.Background(), reg, "github_create_issue", `{"query":"bug"}`)
+	require.NoError(t, err, "tools/call uses the remote name")
+	require.JSONEq(t, `"created bug"`, string(chunks[len(chunks)-1].Data))
+}
+
+func TestImportedToolName(t *testing.T) {
+	require.Equal(t, "github_create_issue", importedToolName("github/create_issue"))
+	require.Equal(t, "a.b-c_d", importedToolName("a.b-c_d"))
+	require.Len(t, importedToolName(strings.Repeat("x", 80)), 64)
+}
+
+func TestImportTools_CancellationReachesServer(t *testing.T) {
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	slow, err := toolsy.NewTool("slow", "Waits for cancellation",
+		func(ctx context.Context, _ *toolsy.RunEnv, _ struct{}) (string, error) {
+			close(started)
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return "", ctx.Err()
+		})
+	require.NoError(t, err)
+	reg, _, transport := importRegistry(t, newServerRegistry(t, slow))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = executeCollect(ctx, reg, "slow", `{}`)
+	require.Error(t, err)
+	require.Equal(t, toolsy.FinishTimeout, toolsy.FinishReasonOf(err))
+	<-started
+	select {
+	case err := <-stopped:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote tool was not cancelled")
+	}
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	require.Contains(t, transport.notified, MethodCancelled)
+}
+
+func TestClient_OnToolsChanged(t *testing.T) {
+	_, client, transport := importRegistry(t, newServerRegistry(t))
+	refreshed := make(chan []toolsy.Tool, 1)
+	client.OnToolsChanged(func() {
+		tools, err := ImportTools(context.Background(), client)
+		if err == nil {
+			refreshed <- tools
+		}
+	})
+	transport.emit(MethodToolsListChanged)
+	select {
+	case tools := <-refreshed:
+		require.Len(t, tools, 2)
+	case <-time.After(2 * time.Second):
+		t.Fatal("tools were not re-imported")
+	}
+}
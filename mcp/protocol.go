@@ -1,5 +1,6 @@
 // Package mcp provides a Model Context Protocol (MCP) client that bridges
 // MCP servers to toolsy's Tool/Registry interface. It supports stdio and SSE transports.
+// [Serve] goes the other way and exposes a Registry as an MCP server.
 package mcp
 
 import (
@@ -139,12 +140,15 @@ type ToolsCallResult struct {
 	IsError bool          `json:"isError,omitempty"`
 }
 
-// ContentItem is a single content piece (text or base64).
+// ContentItem is a single content piece (text or base64). Data and MimeType are the spec field names
+// for image content; Base64 and MediaType are accepted from older servers.
 type ContentItem struct {
 	Type      string `json:"type"` // "text" or "image"
 	Text      string `json:"text,omitempty"`
 	Base64    string `json:"base64,omitempty"`
 	MediaType string `json:"mediaType,omitempty"`
+	Data      string `json:"data,omitempty"`
+	MimeType  string `json:"mimeType,omitempty"`
 }
 
 // Resources: resources/read.
@@ -250,4 +254,6 @@ const (
 	MethodProgress = "notifications/progress"
 	// MethodCancelled is notifications/cancelled.
 	MethodCancelled = "notifications/cancelled"
+	// MethodToolsListChanged is notifications/tools/list_changed.
+	MethodToolsListChanged = "notifications/tools/list_changed"
 )
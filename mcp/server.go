@@ -0,0 +1,474 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// JSON-RPC error codes sent by [Serve].
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// MethodPing is the MCP ping request.
+const MethodPing = "ping"
+
+// serverProtocolVersions lists the protocol revisions [Serve] speaks, newest last.
+var serverProtocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"} //nolint:gochecknoglobals // read-only
+
+// maxServerMessageBytes caps one incoming message on [NewStdioServerTransport] (4 MiB).
+const maxServerMessageBytes = 4 << 20
+
+// ServerTransport carries JSON-RPC messages between [Serve] and one MCP client.
+type ServerTransport interface {
+	// Receive returns the next message from the client, or [io.EOF] once the client has gone.
+	Receive() ([]byte, error)
+	// Send delivers one message to the client. It is called from several goroutines.
+	Send(msg []byte) error
+}
+
+// NewStdioServerTransport returns the stdio server transport: newline-delimited messages read from
+// in (usually os.Stdin) and written to out (usually os.Stdout). Messages are limited to 4 MiB.
+func NewStdioServerTransport(in io.Reader, out io.Writer) ServerTransport {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxServerMessageBytes)
+	return &stdioServerTransport{scanner: scanner, mu: sync.Mutex{}, out: out}
+}
+
+type stdioServerTransport struct {
+	scanner *bufio.Scanner
+	mu      sync.Mutex
+	out     io.Writer
+}
+
+func (t *stdioServerTransport) Receive() ([]byte, error) {
+	for t.scanner.Scan() {
+		if line := bytes.TrimSpace(t.scanner.Bytes()); len(line) > 0 {
+			return bytes.Clone(line), nil
+		}
+	}
+	if err := t.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("mcp server: read message: %w", err)
+	}
+	return nil, io.EOF
+}
+
+func (t *stdioServerTransport) Send(msg []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.out.Write(append(bytes.Clone(msg), '\n'))
+	return err
+}
+
+// ServerOption configures [Serve].
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	info         ServerInfo
+	instructions string
+	logger       *slog.Logger
+}
+
+// WithServerInfo sets the name and version reported in the initialize result
+// (default "toolsy-mcp-server" "0.1.0").
+func WithServerInfo(name, version string) ServerOption {
+	return func(o *serverOptions) {
+		o.info = ServerInfo{Name: name, Version: version}
+	}
+}
+
+// WithServerInstructions sets the instructions returned from initialize, which clients may add to the
+// model's context.
+func WithServerInstructions(instructions string) ServerOption {
+	return func(o *serverOptions) {
+		o.instructions = instructions
+	}
+}
+
+// WithServerLogger sets the logger that records the details of system errors hidden from the client
+// (default [slog.Default]).
+func WithServerLogger(logger *slog.Logger) ServerOption {
+	return func(o *serverOptions) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// Serve exposes reg as an MCP server on transport until the client disconnects.
+//
+// tools/list returns every registry tool not marked [toolsy.WithHidden] with its name, description
+// and Parameters as inputSchema; ReadOnly, Dangerous and Idempotent manifests set the readOnlyHint,
+// destructiveHint and idempotentHint annotations. tools/call runs the tool through
+// [toolsy.Registry.Execute]: result chunks become the result content (text for UTF-8 data, image or
+// audio content for those MIME types, an embedded blob otherwise) and, when the client sent a
+// progress token, every chunk is also reported as a notifications/progress message while the tool
+// runs. Errors the model can fix (invalid arguments, denials) come back as isError content with the
+// model-facing message; other failures come back as isError content with a generic message and are
+// logged. An unknown tool is a JSON-RPC invalid params error. notifications/cancelled cancels the
+// matching call.
+//
+// Calls run concurrently. Serve returns nil when Receive reports [io.EOF], after the running calls
+// have finished; cancelling ctx cancels them, and Serve returns once Receive does.
+func Serve(ctx context.Context, reg *toolsy.Registry, transport ServerTransport, opts ...ServerOption) error {
+	o := serverOptions{
+		info:         ServerInfo{Name: "toolsy-mcp-server", Version: "0.1.0"},
+		instructions: "",
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := &server{reg: reg, transport: transport, opts: o, mu: sync.Mutex{}, inFlight: map[string]context.CancelFunc{}}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		msg, err := transport.Receive()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.handle(ctx, msg, &wg)
+	}
+}
+
+type server struct {
+	reg       *toolsy.Registry
+	transport ServerTransport
+	opts      serverOptions
+
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc
+}
+
+func (s *server) handle(ctx context.Context, msg []byte, wg *sync.WaitGroup) {
+	var req Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		s.sendError(nil, codeParseError, "parse error")
+		return
+	}
+	if req.JSONRPC != JSONRPCVersion || req.Method == "" {
+		if len(req.ID) > 0 {
+			s.sendError(req.ID, codeInvalidRequest, "invalid request")
+		}
+		return
+	}
+	isNotification := len(req.ID) == 0
+	switch req.Method {
+	case MethodInitialize:
+		s.initialize(req)
+	case MethodPing:
+		s.sendResult(req.ID, struct{}{})
+	case MethodToolsList:
+		s.sendResult(req.ID, ToolsListResult{Tools: s.listTools(), NextCursor: ""})
+	case MethodToolsCall:
+		callCtx, cancel := context.WithCancel(ctx)
+		key := string(req.ID)
+		s.mu.Lock()
+		s.inFlight[key] = cancel
+		s.mu.Unlock()
+		wg.Go(func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.inFlight, key)
+				s.mu.Unlock()
+				cancel()
+			}()
+			s.callTool(callCtx, req)
+		})
+	case MethodCancelled:
+		var params CancelledParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			s.mu.Lock()
+			if cancel, ok := s.inFlight[string(params.RequestID)]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+		}
+	default:
+		if !isNotification {
+			s.sendError(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+		}
+	}
+}
+
+func (s *server) initialize(req Request) {
+	var params InitializeParams
+	_ = json.Unmarshal(req.Params, &params)
+	version := serverProtocolVersions[len(serverProtocolVersions)-1]
+	if slices.Contains(serverProtocolVersions, params.ProtocolVersion) {
+		version = params.ProtocolVersion
+	}
+	s.sendResult(req.ID, InitializeResult{
+		ProtocolVersion: version,
+		Capabilities: ServerCapabilities{
+			Tools:     &ToolsCapability{ListChanged: false},
+			Resources: nil,
+			Prompts:   nil,
+			Logging:   nil,
+		},
+		ServerInfo:   s.opts.info,
+		Instructions: s.opts.instructions,
+	})
+}
+
+func (s *server) listTools() []MCPTool {
+	tools := s.reg.GetVisibleTools()
+	out := make([]MCPTool, 0, len(tools))
+	for _, tool := range tools {
+		m := tool.Manifest()
+		schema, err := json.Marshal(m.Parameters)
+		if err != nil || m.Parameters == nil {
+			schema = []byte(`{"type":"object"}`)
+		}
+		out = append(out, MCPTool{
+			Name:        m.Name,
+			Description: toolsy.ModelDescription(m),
+			Title:       "",
+			InputSchema: schema,
+			Annotations: manifestAnnotations(m),
+		})
+	}
+	return out
+}
+
+// manifestAnnotations maps manifest policy flags onto MCP tool annotations (nil when none is set).
+func manifestAnnotations(m toolsy.ToolManifest) *ToolAnnotations {
+	if !m.ReadOnly && !m.Dangerous && !m.Idempotent {
+		return nil
+	}
+	hint := func(set bool) *bool {
+		if !set {
+			return nil
+		}
+		return &set
+	}
+	return &ToolAnnotations{
+		Title:           "",
+		ReadOnlyHint:    hint(m.ReadOnly),
+		DestructiveHint: hint(m.Dangerous),
+		IdempotentHint:  hint(m.Idempotent),
+		OpenWorldHint:   nil,
+	}
+}
+
+// serverCallParams accepts the progress token both in _meta (as the specification puts it) and at
+// the top level (as [Client] sends it).
+type serverCallParams struct {
+	Name          string          `json:"name"`
+	Arguments     json.RawMessage `json:"arguments"`
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+	Meta          struct {
+		ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+	} `json:"_meta"`
+}
+
+type serverProgressParams struct {
+	ProgressToken json.RawMessage `json:"progressToken"`
+	Progress      float64         `json:"progress"`
+	Total         *float64        `json:"total,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
+
+// serverContent is one item of a tools/call result in the specification's shape.
+type serverContent struct {
+	Type     string                 `json:"type"`
+	Text     string                 `json:"text,omitempty"`
+	Data     string                 `json:"data,omitempty"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Resource *serverResourceContent `json:"resource,omitempty"`
+}
+
+type serverResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Blob     string `json:"blob"`
+}
+
+type serverCallResult struct {
+	Content []serverContent `json:"content"`
+	IsError bool            `json:"isError,omitempty"`
+}
+
+func (s *server) callTool(ctx context.Context, req Request) {
+	var params serverCallParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+		s.sendError(req.ID, codeInvalidParams, "tools/call needs a tool name")
+		return
+	}
+	token := params.Meta.ProgressToken
+	if len(token) == 0 {
+		token = params.ProgressToken
+	}
+	args := params.Arguments
+	if len(bytes.TrimSpace(args)) == 0 || bytes.Equal(args, []byte("null")) {
+		args = []byte(`{}`)
+	}
+	call := toolsy.ToolCall{ //nolint:exhaustruct // a tools/call request carries only name, id and arguments
+		ToolName: params.Name,
+		Input:    toolsy.ToolInput{CallID: requestCallID(req.ID), ArgsJSON: args, Attachments: nil},
+	}
+	result := serverCallResult{Content: []serverContent{}, IsError: false}
+	progress := &serverProgress{token: token, chunks: 0, last: 0, total: nil}
+	err := s.reg.Execute(ctx, call, func(c toolsy.Chunk) error {
+		if len(token) > 0 {
+			if err := s.sendProgress(progress, c); err != nil {
+				return err
+			}
+		}
+		if c.Event != toolsy.EventResult {
+			return nil
+		}
+		if c.IsError {
+			result.IsError = true
+		}
+		result.Content = append(result.Content, chunkContent(call.Input.CallID, len(result.Content), c))
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, toolsy.ErrStreamAborted) || ctx.Err() != nil {
+			return
+		}
+		if toolsy.FinishReasonOf(err) == toolsy.FinishNotFound {
+			s.sendError(req.ID, codeInvalidParams, "unknown tool: "+params.Name)
+			return
+		}
+		result.IsError = true
+		result.Content = append(result.Content, serverContent{
+			Type: "text", Text: s.errorText(ctx, call, err), Data: "", MimeType: "", Resource: nil,
+		})
+	}
+	s.sendResult(req.ID, result)
+}
+
+// errorText is the model-facing message for a failed call: the formatted tool error when the model
+// can fix the call, a generic internal error otherwise.
+func (s *server) errorText(ctx context.Context, call toolsy.ToolCall, err error) string {
+	if toolsy.FinishReasonOf(err) != toolsy.FinishClientError {
+		s.opts.logger.ErrorContext(ctx, "mcp server: tool call failed", "tool", call.ToolName, "error", err)
+		err = toolsy.NewInternalError(err)
+	}
+	var wire struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(toolsy.NewErrorChunkFromErr(err).Data, &wire) != nil || wire.Message == "" {
+		return "Error executing tool"
+	}
+	return wire.Message
+}
+
+// serverProgress is the progress state of one tools/call. Each chunk counts as one step until the
+// tool reports a percentage; from then on the value is the percentage out of 100. The value sent is
+// clamped to the running maximum, so it never goes down when a tool mixes both.
+type serverProgress struct {
+	token  json.RawMessage
+	chunks int
+	last   float64
+	total  *float64
+}
+
+func (s *server) sendProgress(sp *serverProgress, c toolsy.Chunk) error {
+	sp.chunks++
+	switch {
+	case c.Progress != nil && c.Progress.Percent != nil:
+		total := float64(100)
+		sp.last, sp.total = max(sp.last, float64(*c.Progress.Percent)), &total
+	case sp.total == nil:
+		sp.last = max(sp.last, float64(sp.chunks))
+	}
+	params := serverProgressParams{ProgressToken: sp.token, Progress: sp.last, Total: sp.total, Message: ""}
+	if c.Progress != nil {
+		params.Message = c.Progress.Message
+	}
+	if params.Message == "" && utf8.Valid(c.Data) {
+		params.Message = string(c.Data)
+	}
+	return s.send(struct {
+		JSONRPC string               `json:"jsonrpc"`
+		Method  string               `json:"method"`
+		Params  serverProgressParams `json:"params"`
+	}{JSONRPC: JSONRPCVersion, Method: MethodProgress, Params: params})
+}
+
+// chunkContent converts one result chunk into a content item.
+func chunkContent(callID string, index int, c toolsy.Chunk) serverContent {
+	mediaType, _, _ := mime.ParseMediaType(c.MimeType)
+	item := serverContent{Type: "text", Text: "", Data: "", MimeType: "", Resource: nil}
+	switch {
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"):
+		item.Type, _, _ = strings.Cut(mediaType, "/")
+		item.Data = base64.StdEncoding.EncodeToString(c.Data)
+		item.MimeType = mediaType
+	case utf8.Valid(c.Data):
+		item.Text = string(c.Data)
+	default:
+		item.Type = "resource"
+		item.Resource = &serverResourceContent{
+			URI:      fmt.Sprintf("toolsy://call/%s/%d", callID, index),
+			MimeType: c.MimeType,
+			Blob:     base64.StdEncoding.EncodeToString(c.Data),
+		}
+	}
+	return item
+}
+
+// requestCallID turns a request id into [toolsy.ToolInput.CallID]: strings without quotes, numbers as written.
+func requestCallID(id json.RawMessage) string {
+	var s string
+	if json.Unmarshal(id, &s) == nil {
+		return s
+	}
+	return string(id)
+}
+
+func (s *server) sendResult(id json.RawMessage, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.sendError(id, codeInternalError, "cannot encode result")
+		return
+	}
+	_ = s.send(Response{JSONRPC: JSONRPCVersion, ID: id, Result: data, Error: nil})
+}
+
+func (s *server) sendError(id json.RawMessage, code int, message string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	_ = s.send(Response{
+		JSONRPC: JSONRPCVersion,
+		ID:      id,
+		Result:  nil,
+		Error:   &JSONRPCError{Code: code, Message: message, Data: nil},
+	})
+}
+
+func (s *server) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.transport.Send(data)
+}
@@ -0,0 +1,258 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type serverSearchArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty" maximum:"10"`
+}
+
+type serverMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *JSONRPCError   `json:"error"`
+}
+
+type serverHarness struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	out  *bufio.Scanner
+	done chan error
+}
+
+func startServer(t *testing.T, reg *toolsy.Registry) *serverHarness {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	h := &serverHarness{t: t, in: inW, out: bufio.NewScanner(outR), done: make(chan error, 1)}
+	go func() {
+		h.done <- Serve(context.Background(), reg, NewStdioServerTransport(inR, outW),
+			WithServerLogger(slog.New(slog.DiscardHandler)))
+		_ = outW.Close()
+	}()
+	t.Cleanup(func() {
+		_ = inW.Close()
+		go func() { _, _ = io.Copy(io.Discard, outR) }()
+		select {
+		case err := <-h.done:
+			require.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Error("Serve did not return after stdin closed")
+		}
+	})
+	return h
+}
+
+func (h *serverHarness) send(msg string) {
+	h.t.Helper()
+	_, err := io.WriteString(h.in, msg+"\n")
+	require.NoError(h.t, err)
+}
+
+func (h *serverHarness) recv() serverMessage {
+	h.t.Helper()
+	require.True(h.t, h.out.Scan(), "server closed its output")
+	var m serverMessage
+	require.NoError(h.t, json.Unmarshal(h.out.Bytes(), &m), h.out.Text())
+	return m
+}
+
+func (h *serverHarness) callResult(id, params string) serverCallResult {
+	h.t.Helper()
+	h.send(`{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":` + params + `}`)
+	m := h.recv()
+	require.Nil(h.t, m.Error)
+	var res serverCallResult
+	require.NoError(h.t, json.Unmarshal(m.Result, &res))
+	return res
+}
+
+func newServerRegistry(t *testing.T, extra ...toolsy.Tool) *toolsy.Registry {
+	t.Helper()
+	search, err := toolsy.NewTool("search", "Search the index",
+		func(_ context.Context, _ *toolsy.RunEnv, a serverSearchArgs) (string, error) {
+			return "results for " + a.Query, nil
+		}, toolsy.WithReadOnly(), toolsy.WithIdempotent())
+	require.NoError(t, err)
+	drop, err := toolsy.NewTool("drop_table", "Drop a table",
+		func(context.Context, *toolsy.RunEnv, struct{}) (string, error) {
+			return "", errors.New("pq: password authentication failed for user admin")
+		}, toolsy.WithDangerous())
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistryBuilder().Add(append([]toolsy.Tool{search, drop}, extra...)...).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Shutdown(context.Background()) })
+	return reg
+}
+
+func TestServe_InitializeAndListTools(t *testing.T) {
+	h := startServer(t, newServerRegistry(t))
+
+	h.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},` +
+		`"clientInfo":{"name":"test","version":"1"}}}`)
+	var init InitializeResult
+	require.NoError(t, json.Unmarshal(h.recv().Result, &init))
+	require.Equal(t, "2025-03-26", init.ProtocolVersion)
+	require.NotNil(t, init.Capabilities.Tools)
+	require.Equal(t, "toolsy-mcp-server", init.ServerInfo.Name)
+	h.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	h.send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	var list ToolsListResult
+	require.NoError(t, json.Unmarshal(h.recv().Result, &list))
+	require.Len(t, list.Tools, 2)
+	byName := map[string]MCPTool{}
+	for _, tool := range list.Tools {
+		byName[tool.Name] = tool
+	}
+	search := byName["search"]
+	require.Equal(t, "Search the index", search.Description)
+	require.Contains(t, string(search.InputSchema), `"query"`)
+	require.NotNil(t, search.Annotations)
+	require.True(t, *search.Annotations.ReadOnlyHint)
+	require.True(t, *search.Annotations.IdempotentHint)
+	require.Nil(t, search.Annotations.DestructiveHint)
+	require.True(t, *byName["drop_table"].Annotations.DestructiveHint)
+
+	h.send(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	require.JSONEq(t, `{}`, string(h.recv().Result))
+	h.send(`{"jsonrpc":"2.0","id":4,"method":"resources/list"}`)
+	require.Equal(t, codeMethodNotFound, h.recv().Error.Code)
+}
+
+func TestServe_ToolsCall(t *testing.T) {
+	h := startServer(t, newServerRegistry(t))
+
+	res := h.callResult(`1`, `{"name":"search","arguments":{"query":"go"}}`)
+	require.False(t, res.IsError)
+	require.Equal(t, []serverContent{{Type: "text", Text: `"results for go"`}}, res.Content, "JSON results stay JSON text")
+
+	res = h.callResult(`2`, `{"name":"search","arguments":{"query":"go","limit":50}}`)
+	require.True(t, res.IsError, "validation errors are tool errors the model can fix")
+	require.Contains(t, res.Content[0].Text, "limit")
+
+	res = h.callResult(`3`, `{"name":"drop_table","arguments":{}}`)
+	require.True(t, res.IsError)
+	require.NotContains(t, res.Content[0].Text, "password")
+
+	h.send(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"nope","arguments":{}}}`)
+	m := h.recv()
+	require.NotNil(t, m.Error)
+	require.Equal(t, codeInvalidParams, m.Error.Code)
+}
+
+func TestServe_ToolsCallStreamsProgress(t *testing.T) {
+	percent := 50
+	stream, err := toolsy.NewStreamTool("build", "Build", func(
+		_ context.Context, _ *toolsy.RunEnv, _ struct{}, yield func(toolsy.Chunk) error,
+	) error {
+		if err := yield(toolsy.Chunk{
+			Event: toolsy.EventProgress, Progress: &toolsy.ProgressInfo{Percent: &percent, Message: "compiling"},
+		}); err != nil {
+			return err
+		}
+		ok := toolsy.Chunk{Event: toolsy.EventResult, Data: []byte("ok"), MimeType: toolsy.MimeTypeText}
+		if err := yield(ok); err != nil {
+			return err
+		}
+		return yield(toolsy.Chunk{Event: toolsy.EventResult, Data: []byte{0x89, 0x50}, MimeType: "image/png"})
+	})
+	require.NoError(t, err)
+	h := startServer(t, newServerRegistry(t, stream))
+
+	h.send(`{"jsonrpc":"2.0","id":"b1","method":"tools/call","params":{"name":"build","arguments":{},` +
+		`"_meta":{"progressToken":42}}}`)
+	var progress []serverProgressParams
+	for range 3 {
+		m := h.recv()
+		require.Equal(t, MethodProgress, m.Method)
+		var p serverProgressParams
+		require.NoError(t, json.Unmarshal(m.Params, &p))
+		progress = append(progress, p)
+	}
+	require.JSONEq(t, `42`, string(progress[0].ProgressToken))
+	require.InDelta(t, 50, progress[0].Progress, 0)
+	require.Equal(t, "compiling", progress[0].Message)
+	require.Equal(t, "ok", progress[1].Message)
+	for _, p := range progress[1:] {
+		require.InDelta(t, 50, p.Progress, 0, "chunks after a percentage never move progress backwards")
+		require.NotNil(t, p.Total)
+	}
+
+	var res serverCallResult
+	require.NoError(t, json.Unmarshal(h.recv().Result, &res))
+	require.Equal(t, []serverContent{
+		{Type: "text", Text: "ok"},
+		{Type: "image", Data: "iVA=", MimeType: "image/png"},
+	}, res.Content)
+}
+
+func TestServe_ToolsCallProgressNeverDecreases(t *testing.T) {
+	stream, err := toolsy.NewStreamTool("mixed", "Mixed progress", func(
+		_ context.Context, _ *toolsy.RunEnv, _ struct{}, yield func(toolsy.Chunk) error,
+	) error {
+		for _, step := range []string{"one", "two", "three"} {
+			c := toolsy.Chunk{Event: toolsy.EventProgress, Data: []byte(step), MimeType: toolsy.MimeTypeText}
+			if err := yield(c); err != nil {
+				return err
+			}
+		}
+		for _, pct := range []int{2, 40, 10} {
+			if err := yield(toolsy.ProgressChunk(pct, "")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	h := startServer(t, newServerRegistry(t, stream))
+
+	h.send(`{"jsonrpc":"2.0","id":"m1","method":"tools/call","params":{"name":"mixed","arguments":{},` +
+		`"_meta":{"progressToken":"p"}}}`)
+	var values []float64
+	for range 6 {
+		m := h.recv()
+		require.Equal(t, MethodProgress, m.Method)
+		var p serverProgressParams
+		require.NoError(t, json.Unmarshal(m.Params, &p))
+		values = append(values, p.Progress)
+	}
+	require.Equal(t, []float64{1, 2, 3, 3, 40, 40}, values)
+	require.NotNil(t, h.recv().Result)
+}
+
+func TestServe_CancelledNotificationStopsCall(t *testing.T) {
+	stopped := make(chan struct{})
+	wait, err := toolsy.NewTool("wait", "Wait", func(ctx context.Context, _ *toolsy.RunEnv, _ struct{}) (string, error) {
+		<-ctx.Done()
+		close(stopped)
+		return "", ctx.Err()
+	})
+	require.NoError(t, err)
+	h := startServer(t, newServerRegistry(t, wait))
+
+	h.send(`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"wait","arguments":{}}}`)
+	h.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9}}`)
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancelled call kept running")
+	}
+	h.send(`{"jsonrpc":"2.0","id":10,"method":"ping"}`)
+	require.JSONEq(t, `10`, string(h.recv().ID), "no response is sent for the cancelled call")
+}
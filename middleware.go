@@ -2,7 +2,9 @@ package toolsy
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"runtime/debug"
 	"time"
 )
 
@@ -17,7 +19,12 @@ type ChainUnwrapper interface {
 	UnwrapNext() Tool
 }
 
-// WithLogging returns a middleware that logs start, end, duration, and errors.
+// WithLogging returns a middleware that logs start, end, duration, and errors. Panics are logged
+// with their stack trace and re-raised for the registry's recovery; an error that wraps a panic
+// recovered further in (such as by [WithRecovery]) is logged with its [PanicStack].
+// Chunk counts and bytes are measured by wrapping yield, so a streaming tool logs one
+// start/end pair per call regardless of how many chunks it yields. At debug level it also logs the
+// call arguments after [RedactArgs], so fields tagged `sensitive:"true"` are masked.
 func WithLogging(logger *slog.Logger) Middleware {
 	if logger == nil {
 		logger = slog.Default()
@@ -39,11 +46,29 @@ func WithRecovery() Middleware {
 	}
 }
 
-// toolBase delegates Tool to the wrapped Tool; used by middleware wrappers.
+// manifestViewer is implemented by the tools of this package to share their manifest without the
+// deep copy [Tool.Manifest] makes. The result is read-only: its maps and slices belong to the tool.
+type manifestViewer interface {
+	manifestView() ToolManifest
+}
+
+// toolManifest returns the manifest of t for read-only use by the registry and middlewares, which
+// read it on every call. Tools from other packages fall back to [Tool.Manifest].
+func toolManifest(t Tool) ToolManifest {
+	if v, ok := t.(manifestViewer); ok {
+		return v.manifestView()
+	}
+	return t.Manifest()
+}
+
+// toolBase delegates Tool to the wrapped Tool; used by middleware wrappers. Wrappers that change
+// the manifest override manifestView as well as Manifest.
 type toolBase struct{ next Tool }
 
 func (b *toolBase) Manifest() ToolManifest { return b.next.Manifest() }
 
+func (b *toolBase) manifestView() ToolManifest { return toolManifest(b.next) }
+
 func (b *toolBase) UnwrapNext() Tool { return b.next }
 
 type middlewareTool struct {
@@ -53,8 +78,11 @@ type middlewareTool struct {
 }
 
 func (m *middlewareTool) Execute(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
-	toolName := m.next.Manifest().Name
+	toolName := toolManifest(m.next).Name
 	m.logger.InfoContext(ctx, "tool start", "tool", toolName)
+	if m.logger.Enabled(ctx, slog.LevelDebug) {
+		m.logger.DebugContext(ctx, "tool args", "tool", toolName, "args", string(RedactArgs(m.next, input.ArgsJSON)))
+	}
 	start := time.Now()
 	var chunks, totalBytes, errorChunks int64
 	var lastErrorText string
@@ -75,9 +103,14 @@ func (m *middlewareTool) Execute(ctx context.Context, env *RunEnv, input ToolInp
 	var err error
 	defer func() {
 		dur := time.Since(start)
+		if p := recover(); p != nil {
+			// Log the panic with its stack and let the registry (or the caller) recover it.
+			m.logger.ErrorContext(ctx, "tool panic", "tool", toolName, "duration", dur,
+				"panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+			panic(p)
+		}
 		if err != nil || errorChunks > 0 {
-			m.logger.Error(
-				"tool error",
+			attrs := []any{
 				"tool",
 				toolName,
 				"duration",
@@ -92,7 +125,11 @@ func (m *middlewareTool) Execute(ctx context.Context, env *RunEnv, input ToolInp
 				lastErrorText,
 				"error",
 				err,
-			)
+			}
+			if stack := PanicStack(err); stack != nil {
+				attrs = append(attrs, "stack", string(stack))
+			}
+			m.logger.Error("tool error", attrs...)
 		} else {
 			m.logger.Info("tool end", "tool", toolName, "duration", dur, "chunks", chunks, "bytes", totalBytes)
 		}
@@ -111,7 +148,7 @@ func (r *recoveryTool) Execute(
 ) (err error) {
 	defer func() {
 		if p := recover(); p != nil {
-			err = NewInternalError(&panicError{p: p})
+			err = NewInternalError(newPanicError(p, true))
 		}
 	}()
 	return r.next.Execute(ctx, env, input, yield)
@@ -0,0 +1,232 @@
+package toolsy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry is the record [WithAudit] emits once per tool execution. Raw arguments are never
+// included; ArgsSHA256 identifies them (see [ArgsHash]).
+type AuditEntry struct {
+	Time       time.Time     `json:"time"`
+	CallID     string        `json:"call_id,omitempty"`
+	ViewID     string        `json:"view_id,omitempty"`
+	Tool       string        `json:"tool"`
+	Version    string        `json:"version,omitempty"`
+	Dangerous  bool          `json:"dangerous"`
+	Subject    any           `json:"subject,omitempty"`
+	ArgsSHA256 string        `json:"args_sha256"`
+	Outcome    FinishReason  `json:"outcome"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration_ns"`
+	Chunks     int64         `json:"chunks"`
+	Bytes      int64         `json:"bytes"`
+}
+
+// AuditSink stores audit entries. Record is called synchronously after each execution; an error is
+// logged and never fails the call.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditOption configures [WithAudit].
+type AuditOption func(*auditConfig)
+
+type auditConfig struct {
+	logger *slog.Logger
+}
+
+// WithAuditLogger sets the logger for sink failures (default [slog.Default]).
+func WithAuditLogger(logger *slog.Logger) AuditOption {
+	return func(c *auditConfig) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithAudit returns middleware that records one [AuditEntry] per execution of the wrapped tool:
+// the subject from the call context, tool name, version and dangerous flag, [ArgsHash] of the
+// arguments, outcome ([FinishReasonOf]), duration, and the chunks and bytes the consumer accepted.
+// A panicking tool is recorded with [FinishPanic] before the panic continues to the registry's
+// recovery. Sink errors and panics are logged and never change the call result.
+func WithAudit(sink AuditSink, opts ...AuditOption) Middleware {
+	cfg := auditConfig{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next Tool) Tool {
+		return &auditTool{toolBase: toolBase{next: next}, sink: sink, cfg: cfg}
+	}
+}
+
+type auditTool struct {
+	toolBase
+
+	sink AuditSink
+	cfg  auditConfig
+}
+
+func (t *auditTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	if t.sink == nil {
+		return t.next.Execute(ctx, run, input, yield)
+	}
+	m := toolManifest(t.next)
+	callCtx := run.CallContext()
+	entry := AuditEntry{
+		Time:       time.Now(),
+		CallID:     callCtx.Metadata.CallID,
+		ViewID:     callCtx.Metadata.ViewID,
+		Tool:       m.Name,
+		Version:    m.Version,
+		Dangerous:  m.Dangerous,
+		Subject:    callCtx.Subject,
+		ArgsSHA256: ArgsHash(input.ArgsJSON),
+		Outcome:    FinishPanic,
+		Error:      "",
+		Duration:   0,
+		Chunks:     0,
+		Bytes:      0,
+	}
+	if entry.CallID == "" {
+		entry.CallID = input.CallID
+	}
+	defer func() {
+		entry.Duration = time.Since(entry.Time)
+		t.record(ctx, entry)
+	}()
+	err := t.next.Execute(ctx, run, input, func(c Chunk) error {
+		if yieldErr := yield(c); yieldErr != nil {
+			return yieldErr
+		}
+		entry.Chunks++
+		entry.Bytes += int64(len(c.Data))
+		return nil
+	})
+	entry.Outcome = FinishReasonOf(err)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return err
+}
+
+// record hands entry to the sink, logging a failure or panic instead of propagating it.
+func (t *auditTool) record(ctx context.Context, entry AuditEntry) {
+	defer func() {
+		if p := recover(); p != nil {
+			t.cfg.logger.ErrorContext(ctx, "toolsy: audit sink panicked", "tool", entry.Tool, "panic", p)
+		}
+	}()
+	if err := t.sink.Record(context.WithoutCancel(ctx), entry); err != nil {
+		t.cfg.logger.ErrorContext(ctx, "toolsy: audit sink failed", "tool", entry.Tool, "error", err)
+	}
+}
+
+// ArgsHash returns the hex SHA-256 of the canonical form of argsJSON, so external systems can match
+// an [AuditEntry] against arguments they hold. The canonical form is the JSON value re-encoded with
+// object keys sorted by byte order, no insignificant whitespace, numbers exactly as written, and
+// strings escaped as Go's encoding/json does with HTML escaping off (only '"', '\\', control
+// characters, U+2028 and U+2029 are escaped). Arguments that are not valid JSON are hashed as is.
+func ArgsHash(argsJSON []byte) string {
+	sum := sha256.Sum256(canonicalArgsJSON(argsJSON))
+	return hex.EncodeToString(sum[:])
+}
+
+// JSONLinesAuditSink writes each [AuditEntry] as one JSON object per line.
+type JSONLinesAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+var _ AuditSink = (*JSONLinesAuditSink)(nil)
+
+// NewJSONLinesAuditSink returns a sink writing to w. Writes are serialized; each entry is a single
+// Write call.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{mu: sync.Mutex{}, w: w, closer: nil}
+}
+
+// auditFileMode keeps audit files readable by their owner only.
+const auditFileMode = 0o600
+
+// OpenAuditFile opens (or creates) path in append-only mode and returns a sink writing to it.
+// Close the sink to close the file.
+func OpenAuditFile(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, auditFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("toolsy: open audit file: %w", err)
+	}
+	return &JSONLinesAuditSink{mu: sync.Mutex{}, w: f, closer: f}, nil
+}
+
+// Record implements [AuditSink].
+func (s *JSONLinesAuditSink) Record(_ context.Context, entry AuditEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(entry); err != nil {
+		return fmt.Errorf("toolsy: encode audit entry: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == nil {
+		return errors.New("toolsy: audit sink is closed")
+	}
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+// Close closes the file opened by [OpenAuditFile]; later Record calls fail. It is a no-op for
+// sinks from [NewJSONLinesAuditSink] apart from rejecting later records.
+func (s *JSONLinesAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w = nil
+	if s.closer == nil {
+		return nil
+	}
+	err := s.closer.Close()
+	s.closer = nil
+	return err
+}
+
+// NewSlogAuditSink returns a sink logging each entry as an info-level "tool audit" record.
+// A nil logger uses [slog.Default].
+func NewSlogAuditSink(logger *slog.Logger) AuditSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return slogAuditSink{logger: logger}
+}
+
+type slogAuditSink struct {
+	logger *slog.Logger
+}
+
+func (s slogAuditSink) Record(ctx context.Context, e AuditEntry) error {
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "tool audit",
+		slog.String("call_id", e.CallID),
+		slog.String("tool", e.Tool),
+		slog.String("version", e.Version),
+		slog.Bool("dangerous", e.Dangerous),
+		slog.Any("subject", e.Subject),
+		slog.String("args_sha256", e.ArgsSHA256),
+		slog.String("outcome", string(e.Outcome)),
+		slog.String("error", e.Error),
+		slog.Duration("duration", e.Duration),
+		slog.Int64("chunks", e.Chunks),
+		slog.Int64("bytes", e.Bytes),
+	)
+	return nil
+}
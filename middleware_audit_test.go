@@ -0,0 +1,137 @@
+package toolsy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	err     error
+}
+
+func (s *memoryAuditSink) Record(_ context.Context, e AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	return s.err
+}
+
+type auditArgs struct {
+	ID int `json:"id"`
+}
+
+func newAuditRegistry(t *testing.T, sink AuditSink, opts ...AuditOption) *Registry {
+	t.Helper()
+	tool, err := NewTool("delete_user", "Delete", func(_ context.Context, _ *RunEnv, a auditArgs) (string, error) {
+		if a.ID < 0 {
+			panic("negative id")
+		}
+		if a.ID == 0 {
+			return "", errors.New("db down")
+		}
+		return "deleted", nil
+	}, WithDangerous(), WithVersion("v2"))
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Use(WithAudit(sink, opts...)).Add(tool).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func auditCall(args string) ToolCall {
+	call := ToolCall{ToolName: "delete_user", Input: ToolInput{CallID: "call-1", ArgsJSON: []byte(args)}}
+	call.CallContext.Subject = "alice"
+	return call
+}
+
+func TestWithAudit_RecordsEachExecutionOnce(t *testing.T) {
+	sink := &memoryAuditSink{}
+	reg := newAuditRegistry(t, sink)
+	noop := func(Chunk) error { return nil }
+
+	require.NoError(t, reg.Execute(context.Background(), auditCall(`{"id":7}`), noop))
+	require.Error(t, reg.Execute(context.Background(), auditCall(`{"id":0}`), noop))
+	require.Error(t, reg.Execute(context.Background(), auditCall(`{"id":-1}`), noop))
+
+	require.Len(t, sink.entries, 3)
+	ok := sink.entries[0]
+	assert.Equal(t, "call-1", ok.CallID)
+	assert.Equal(t, "delete_user", ok.Tool)
+	assert.Equal(t, "v2", ok.Version)
+	assert.True(t, ok.Dangerous)
+	assert.Equal(t, "alice", ok.Subject)
+	assert.Equal(t, ArgsHash([]byte(`{"id":7}`)), ok.ArgsSHA256)
+	assert.Equal(t, FinishSuccess, ok.Outcome)
+	assert.Equal(t, int64(1), ok.Chunks)
+	assert.Equal(t, int64(len(`"deleted"`)), ok.Bytes)
+	assert.False(t, ok.Time.IsZero())
+
+	assert.Equal(t, FinishSystemError, sink.entries[1].Outcome)
+	assert.NotEmpty(t, sink.entries[1].Error)
+	assert.Equal(t, FinishPanic, sink.entries[2].Outcome)
+}
+
+func TestWithAudit_SinkFailureDoesNotFailCall(t *testing.T) {
+	var logs bytes.Buffer
+	sink := &memoryAuditSink{err: errors.New("disk full")}
+	reg := newAuditRegistry(t, sink, WithAuditLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	require.NoError(t, reg.Execute(context.Background(), auditCall(`{"id":1}`), func(Chunk) error { return nil }))
+	assert.Len(t, sink.entries, 1)
+	assert.Contains(t, logs.String(), "disk full")
+}
+
+func TestArgsHash_Canonical(t *testing.T) {
+	want := sha256.Sum256([]byte(`{"a":"<x>","b":[1.50,{"c":null,"d":true}]}`))
+	assert.Equal(t, hex.EncodeToString(want[:]), ArgsHash([]byte(`{"b":[1.50,{"d":true,"c":null}], "a":"<x>"}`)))
+	raw := sha256.Sum256([]byte(`not json`))
+	assert.Equal(t, hex.EncodeToString(raw[:]), ArgsHash([]byte(`not json`)))
+}
+
+func TestOpenAuditFile_WritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := OpenAuditFile(path)
+	require.NoError(t, err)
+	reg := newAuditRegistry(t, sink)
+	for range 2 {
+		require.NoError(t, reg.Execute(context.Background(), auditCall(`{"id":1}`), func(Chunk) error { return nil }))
+	}
+	require.NoError(t, sink.Close())
+	require.Error(t, sink.Record(context.Background(), AuditEntry{}))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lines := 0
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.Equal(t, "delete_user", entry["tool"])
+		assert.Equal(t, "success", entry["outcome"])
+		assert.NotContains(t, entry, "args")
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestNewSlogAuditSink(t *testing.T) {
+	var logs bytes.Buffer
+	sink := NewSlogAuditSink(slog.New(slog.NewJSONHandler(&logs, nil)))
+	require.NoError(t, sink.Record(context.Background(), AuditEntry{Tool: "t", Outcome: FinishSuccess}))
+	assert.Contains(t, logs.String(), `"msg":"tool audit"`)
+	assert.Contains(t, logs.String(), `"outcome":"success"`)
+}
@@ -0,0 +1,234 @@
+package toolsy
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultCacheBypassTag is the [CallMetadata] tag that makes [WithCache] run the tool without reading or
+// writing the cache; override it with [WithCacheBypassTag].
+const DefaultCacheBypassTag = "toolsy.no_cache"
+
+// CacheStore holds chunk sequences recorded by [WithCache]. Implementations must treat the stored slice
+// as read-only and expire entries after ttl (ttl <= 0 means no expiry).
+type CacheStore interface {
+	Get(ctx context.Context, key string) ([]Chunk, bool, error)
+	Put(ctx context.Context, key string, chunks []Chunk, ttl time.Duration) error
+}
+
+// CacheOption configures [WithCache].
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	bypassTag string
+	dangerous bool
+	keyFn     func(ToolManifest, ToolInput) string
+}
+
+// WithCacheBypassTag replaces [DefaultCacheBypassTag]; calls whose [CallMetadata.Tags] contain tag skip
+// the cache. An empty tag disables bypassing.
+func WithCacheBypassTag(tag string) CacheOption {
+	return func(c *cacheConfig) {
+		c.bypassTag = tag
+	}
+}
+
+// WithCacheDangerous also caches tools marked Dangerous, which [WithCache] otherwise always executes.
+func WithCacheDangerous() CacheOption {
+	return func(c *cacheConfig) {
+		c.dangerous = true
+	}
+}
+
+// WithCacheKey replaces [CacheKey] as the way entries are keyed.
+func WithCacheKey(keyFn func(ToolManifest, ToolInput) string) CacheOption {
+	return func(c *cacheConfig) {
+		if keyFn != nil {
+			c.keyFn = keyFn
+		}
+	}
+}
+
+// WithCache serves repeated calls with identical arguments from store instead of executing the tool.
+// On a hit the recorded chunks are replayed to yield in order. On a miss the tool runs and the chunks
+// the consumer accepted are stored for ttl, but only when the tool returned nil and yielded no error
+// or control chunk. Tools marked Dangerous are never cached unless [WithCacheDangerous] is set.
+// Store errors count as misses: caching never fails a call that could run.
+func WithCache(store CacheStore, ttl time.Duration, opts ...CacheOption) Middleware {
+	if store == nil {
+		panic("toolsy: WithCache requires non-nil store")
+	}
+	cfg := cacheConfig{bypassTag: DefaultCacheBypassTag, dangerous: false, keyFn: CacheKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next Tool) Tool {
+		return &cacheTool{toolBase: toolBase{next: next}, store: store, ttl: ttl, cfg: cfg}
+	}
+}
+
+type cacheTool struct {
+	toolBase
+
+	store CacheStore
+	ttl   time.Duration
+	cfg   cacheConfig
+}
+
+func (t *cacheTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	manifest := t.next.Manifest()
+	if (manifest.Dangerous && !t.cfg.dangerous) || t.bypassed(run) {
+		return t.next.Execute(ctx, run, input, yield)
+	}
+	key := t.cfg.keyFn(manifest, input)
+	if cached, ok, err := t.store.Get(ctx, key); err == nil && ok {
+		for _, c := range cached {
+			if yieldErr := yield(cloneCachedChunk(c)); yieldErr != nil {
+				return yieldErr
+			}
+		}
+		return nil
+	}
+
+	var recorded []Chunk
+	cacheable := true
+	err := t.next.Execute(ctx, run, input, func(c Chunk) error {
+		if c.IsError || c.Event == EventControl {
+			cacheable = false
+		}
+		if yieldErr := yield(c); yieldErr != nil {
+			return yieldErr
+		}
+		recorded = append(recorded, cloneCachedChunk(c))
+		return nil
+	})
+	if err != nil || !cacheable {
+		return err
+	}
+	_ = t.store.Put(ctx, key, recorded, t.ttl)
+	return nil
+}
+
+func (t *cacheTool) bypassed(run *RunEnv) bool {
+	return t.cfg.bypassTag != "" && run != nil && slices.Contains(run.CallContext().Metadata.Tags, t.cfg.bypassTag)
+}
+
+// CacheKey is the default [WithCache] key: a SHA-256 of the tool name, the tool version, and the
+// arguments re-encoded with sorted object keys and no insignificant whitespace, so `{"a":1,"b":2}`
+// and `{ "b":2, "a":1 }` share an entry while calc@1.0.0 and calc@2.0.0 do not. Arguments that are
+// not valid JSON are hashed as is.
+func CacheKey(m ToolManifest, input ToolInput) string {
+	h := sha256.New()
+	h.Write([]byte(m.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(m.Version))
+	h.Write([]byte{0})
+	h.Write(canonicalArgsJSON(input.ArgsJSON))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalArgsJSON is the canonical argument encoding shared by [CacheKey] and [ArgsHash].
+func canonicalArgsJSON(raw []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return raw
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return raw
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// cloneCachedChunk copies the parts of c a consumer may mutate so stored entries stay intact.
+func cloneCachedChunk(c Chunk) Chunk {
+	c.Data = bytes.Clone(c.Data)
+	c.Metadata = maps.Clone(c.Metadata)
+	return c
+}
+
+// MemoryCacheStore is an in-process LRU [CacheStore] for tests and single-node deployments.
+type MemoryCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used; values are *memoryCacheEntry
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	chunks  []Chunk
+	expires time.Time
+}
+
+// NewMemoryCacheStore returns an LRU store holding at most capacity entries (minimum 1); the least
+// recently used entry is evicted when a new key is added to a full store.
+func NewMemoryCacheStore(capacity int) *MemoryCacheStore {
+	return &MemoryCacheStore{
+		mu:       sync.Mutex{},
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryCacheStore) Get(_ context.Context, key string) ([]Chunk, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry, _ := el.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && !s.now().Before(entry.expires) {
+		s.order.Remove(el)
+		delete(s.items, key)
+		return nil, false, nil
+	}
+	s.order.MoveToFront(el)
+	return entry.chunks, true, nil
+}
+
+func (s *MemoryCacheStore) Put(_ context.Context, key string, chunks []Chunk, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &memoryCacheEntry{key: key, chunks: slices.Clone(chunks), expires: time.Time{}}
+	if ttl > 0 {
+		entry.expires = s.now().Add(ttl)
+	}
+	if el, ok := s.items[key]; ok {
+		el.Value = entry
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.items[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		if evicted, ok := oldest.Value.(*memoryCacheEntry); ok {
+			delete(s.items, evicted.key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,198 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type geocodeArgs struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty"`
+}
+
+func geocodeStreamTool(t *testing.T, fail *atomic.Bool, opts ...ToolOption) (Tool, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	tool, err := NewStreamTool("geocode", "Geocode", func(
+		_ context.Context, _ *RunEnv, a geocodeArgs, yield func(Chunk) error,
+	) error {
+		calls.Add(1)
+		if err := yield(Chunk{Event: EventProgress, Data: []byte("looking up"), MimeType: MimeTypeText}); err != nil {
+			return err
+		}
+		if fail != nil && fail.Load() {
+			return errors.New("geocoder down")
+		}
+		return yield(Chunk{Event: EventResult, Data: []byte(`{"city":"` + a.City + `"}`), MimeType: MimeTypeJSON})
+	}, opts...)
+	require.NoError(t, err)
+	return tool, &calls
+}
+
+func cachedRegistry(t *testing.T, tool Tool, opts ...CacheOption) *Registry {
+	t.Helper()
+	reg, err := NewRegistryBuilder().Use(WithCache(NewMemoryCacheStore(8), time.Minute, opts...)).Add(tool).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func executeEvents(t *testing.T, reg *Registry, call ToolCall) ([]EventType, []string, error) {
+	t.Helper()
+	var events []EventType
+	var data []string
+	err := reg.Execute(context.Background(), call, func(c Chunk) error {
+		events = append(events, c.Event)
+		data = append(data, string(c.Data))
+		return nil
+	})
+	return events, data, err
+}
+
+func geocodeCall(args string) ToolCall {
+	return ToolCall{ToolName: "geocode", Input: ToolInput{ArgsJSON: []byte(args)}}
+}
+
+func TestWithCache_ReplaysCanonicalizedHit(t *testing.T) {
+	tool, calls := geocodeStreamTool(t, nil)
+	reg := cachedRegistry(t, tool)
+
+	events, data, err := executeEvents(t, reg, geocodeCall(`{"city":"Oslo","country":"NO"}`))
+	require.NoError(t, err)
+	replayEvents, replayData, err := executeEvents(t, reg, geocodeCall(`{ "country": "NO", "city": "Oslo" }`))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, []EventType{EventProgress, EventResult}, replayEvents)
+	assert.Equal(t, events, replayEvents)
+	assert.Equal(t, data, replayData)
+
+	_, _, err = executeEvents(t, reg, geocodeCall(`{"city":"Rome"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "different args miss")
+}
+
+func TestWithCache_DoesNotStoreFailures(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	tool, calls := geocodeStreamTool(t, &fail)
+	reg := cachedRegistry(t, tool)
+
+	_, _, err := executeEvents(t, reg, geocodeCall(`{"city":"Oslo"}`))
+	require.Error(t, err)
+	fail.Store(false)
+	_, _, err = executeEvents(t, reg, geocodeCall(`{"city":"Oslo"}`))
+	require.NoError(t, err)
+	_, _, err = executeEvents(t, reg, geocodeCall(`{"city":"Oslo"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWithCache_BypassTag(t *testing.T) {
+	tool, calls := geocodeStreamTool(t, nil)
+	reg := cachedRegistry(t, tool)
+	call := geocodeCall(`{"city":"Oslo"}`)
+	_, _, err := executeEvents(t, reg, call)
+	require.NoError(t, err)
+
+	call.CallContext.Metadata.Tags = []string{DefaultCacheBypassTag}
+	_, _, err = executeEvents(t, reg, call)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	reg = cachedRegistry(t, tool, WithCacheBypassTag("fresh"))
+	call.CallContext.Metadata.Tags = []string{"fresh"}
+	for range 2 {
+		_, _, err = executeEvents(t, reg, call)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestWithCache_SkipsDangerousTools(t *testing.T) {
+	tool, calls := geocodeStreamTool(t, nil, WithDangerous())
+	reg := cachedRegistry(t, tool)
+	for range 2 {
+		_, _, err := executeEvents(t, reg, geocodeCall(`{"city":"Oslo"}`))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+
+	reg = cachedRegistry(t, tool, WithCacheDangerous())
+	for range 2 {
+		_, _, err := executeEvents(t, reg, geocodeCall(`{"city":"Oslo"}`))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestCacheKey_CanonicalizesArgs(t *testing.T) {
+	m := ToolManifest{Name: "t"}
+	key := func(args string) string { return CacheKey(m, ToolInput{ArgsJSON: []byte(args)}) }
+	assert.Equal(t, key(`{"a":1,"b":{"y":2,"x":[1,2]}}`), key(` {"b":{"x":[1, 2],"y":2}, "a":1}`))
+	assert.NotEqual(t, key(`{"a":1}`), key(`{"a":1.0}`), "number text is preserved")
+	assert.NotEqual(t, key(`{"a":1}`), CacheKey(ToolManifest{Name: "u"}, ToolInput{ArgsJSON: []byte(`{"a":1}`)}))
+	assert.Equal(t, key(`not json`), key(`not json`))
+}
+
+func TestCacheKey_SeparatesVersions(t *testing.T) {
+	input := ToolInput{ArgsJSON: []byte(`{"a":1}`)}
+	v1 := CacheKey(ToolManifest{Name: "calc", Version: "1.0.0"}, input)
+	v2 := CacheKey(ToolManifest{Name: "calc", Version: "2.0.0"}, input)
+	assert.NotEqual(t, v1, v2)
+	assert.Equal(t, v1, CacheKey(ToolManifest{Name: "calc", Version: "1.0.0"}, input))
+}
+
+func TestWithCache_SeparatesToolVersions(t *testing.T) {
+	calc := func(version, result string) Tool {
+		tool, err := NewStreamTool("calc", "Calculator",
+			func(_ context.Context, _ *RunEnv, _ geocodeArgs, yield func(Chunk) error) error {
+				return yield(Chunk{Event: EventResult, Data: []byte(result), MimeType: MimeTypeText})
+			}, WithVersion(version))
+		require.NoError(t, err)
+		return tool
+	}
+	reg, err := NewRegistryBuilder().
+		Use(WithCache(NewMemoryCacheStore(8), time.Minute)).
+		AddVersioned(calc("1.0.0", "one"), calc("2.0.0", "two")).
+		Build()
+	require.NoError(t, err)
+
+	for _, want := range []struct{ name, data string }{
+		{"calc@1.0.0", "one"}, {"calc@2.0.0", "two"}, {"calc@1.0.0", "one"},
+	} {
+		call := ToolCall{ToolName: want.name, Input: ToolInput{ArgsJSON: []byte(`{"city":"Oslo"}`)}}
+		_, data, err := executeEvents(t, reg, call)
+		require.NoError(t, err)
+		assert.Equal(t, []string{want.data}, data, want.name)
+	}
+}
+
+func TestMemoryCacheStore_LRUAndTTL(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCacheStore(2)
+	now := time.Unix(1_700_000_000, 0)
+	store.now = func() time.Time { return now }
+	chunk := []Chunk{{Event: EventResult, Data: []byte(`1`)}}
+
+	require.NoError(t, store.Put(ctx, "a", chunk, 0))
+	require.NoError(t, store.Put(ctx, "b", chunk, time.Second))
+	_, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, store.Put(ctx, "c", chunk, 0))
+	assert.Equal(t, 2, store.Len())
+	_, ok, _ = store.Get(ctx, "b")
+	assert.False(t, ok, "least recently used entry is evicted")
+
+	require.NoError(t, store.Put(ctx, "b", chunk, time.Second))
+	now = now.Add(time.Second)
+	_, ok, _ = store.Get(ctx, "b")
+	assert.False(t, ok, "expired entry is a miss")
+	_, ok, _ = store.Get(ctx, "c")
+	assert.True(t, ok)
+}
@@ -125,6 +125,10 @@ func formatToolErrorMessage(te *ToolError) string {
 	if te.Code == CodeToolsContractMissing {
 		return "Error executing tool: required tools are not registered. Hint: Register missing tools or adjust the contract."
 	}
+	if te.Code == CodeConfirmationDenied {
+		return "Error executing tool: the user declined this tool call. " +
+			"Hint: Do not repeat it; ask the user or choose another approach."
+	}
 	if te.Code == CodeBudgetExceeded {
 		return "Error executing tool: " + sanitizeErrorReason(te.Reason) +
 			". Hint: Narrow the query or reduce tool usage."
@@ -44,11 +44,10 @@ func (t *idempotencyTool) Execute(
 	input ToolInput,
 	yield func(Chunk) error,
 ) error {
-	manifest := t.next.Manifest()
-	if !manifest.Idempotent {
+	if !toolManifest(t.next).Idempotent {
 		return t.next.Execute(ctx, run, input, yield)
 	}
-	key := t.keyFn(manifest, input)
+	key := t.keyFn(t.next.Manifest(), input)
 	if cached, ok, err := t.store.Get(ctx, key); err != nil {
 		return NewInternalError(fmt.Errorf("toolsy: idempotency get: %w", err))
 	} else if ok {
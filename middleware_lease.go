@@ -0,0 +1,191 @@
+package toolsy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// leaseExtendDivisor sets the extension interval to ttl/leaseExtendDivisor.
+const leaseExtendDivisor = 2
+
+// leaseTokenBytes is the size of the random owner token of a [MemoryLeaseProvider] lease.
+const leaseTokenBytes = 16
+
+// Lease is a held execution lease returned by [LeaseProvider.Acquire].
+type Lease interface {
+	// Extend pushes the expiry to now+ttl. It fails when the lease was lost (expired and taken over).
+	Extend(ctx context.Context, ttl time.Duration) error
+	// Release gives the lease up early.
+	Release(ctx context.Context) error
+}
+
+// LeaseProvider grants cross-process execution leases (for example Redis SET NX PX or an etcd lease).
+// Acquire must return an error wrapping [ErrLeaseHeld] when another owner holds an unexpired lease for key.
+type LeaseProvider interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+// WithLeasing guards tools marked Dangerous in their manifest with an execution lease, so replicas
+// consuming the same call queue do not both run a destructive call. keyFn derives the lease key;
+// nil uses the same tool-name+args hash as [WithIdempotency]. When the lease is held elsewhere the
+// call fails with a retryable [CodeLeaseHeld] error. While the tool runs the lease is extended every
+// ttl/2; if extension fails the execution context is canceled and the call fails with an internal
+// error wrapping [ErrLeaseLost], so it is not mistaken for a caller cancel. Other tools pass through
+// unchanged.
+func WithLeasing(provider LeaseProvider, ttl time.Duration, keyFn func(ToolManifest, ToolInput) string) Middleware {
+	if provider == nil {
+		panic("toolsy: WithLeasing requires non-nil provider")
+	}
+	if ttl <= 0 {
+		panic("toolsy: WithLeasing requires positive ttl")
+	}
+	if keyFn == nil {
+		keyFn = defaultIdempotencyKey
+	}
+	return func(next Tool) Tool {
+		return &leaseTool{
+			toolBase: toolBase{next: next},
+			provider: provider,
+			ttl:      ttl,
+			keyFn:    keyFn,
+		}
+	}
+}
+
+type leaseTool struct {
+	toolBase
+
+	provider LeaseProvider
+	ttl      time.Duration
+	keyFn    func(ToolManifest, ToolInput) string
+}
+
+func (t *leaseTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	if !toolManifest(t.next).Dangerous {
+		return t.next.Execute(ctx, run, input, yield)
+	}
+	key := t.keyFn(t.next.Manifest(), input)
+	lease, err := t.provider.Acquire(ctx, key, t.ttl)
+	if err != nil {
+		if errors.Is(err, ErrLeaseHeld) {
+			return NewLeaseHeldError(key)
+		}
+		return NewInternalError(fmt.Errorf("toolsy: lease acquire: %w", err))
+	}
+	defer func() { _ = lease.Release(context.WithoutCancel(ctx)) }()
+
+	execCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopped := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() { t.extendUntilDone(execCtx, lease, stopped, cancel) })
+	err = t.next.Execute(execCtx, run, input, yield)
+	close(stopped)
+	wg.Wait()
+	if cause := context.Cause(execCtx); ctx.Err() == nil && cause != nil {
+		return NewInternalError(cause)
+	}
+	return err
+}
+
+// extendUntilDone extends lease every ttl/2 until stopped closes or ctx ends.
+func (t *leaseTool) extendUntilDone(
+	ctx context.Context,
+	lease Lease,
+	stopped <-chan struct{},
+	cancel context.CancelCauseFunc,
+) {
+	ticker := time.NewTicker(t.ttl / leaseExtendDivisor)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopped:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := lease.Extend(ctx, t.ttl); err != nil {
+				cancel(fmt.Errorf("%w: %w", ErrLeaseLost, err))
+				return
+			}
+		}
+	}
+}
+
+// MemoryLeaseProvider is an in-process [LeaseProvider] for tests and single-node deployments.
+type MemoryLeaseProvider struct {
+	mu     sync.Mutex
+	leases map[string]memoryLeaseEntry
+}
+
+type memoryLeaseEntry struct {
+	token   string
+	expires time.Time
+}
+
+// NewMemoryLeaseProvider creates an empty in-process lease provider.
+func NewMemoryLeaseProvider() *MemoryLeaseProvider {
+	return &MemoryLeaseProvider{ //nolint:exhaustruct // mu zero value is valid
+		leases: make(map[string]memoryLeaseEntry),
+	}
+}
+
+// Acquire grants the lease unless another owner holds an unexpired one.
+func (p *MemoryLeaseProvider) Acquire(_ context.Context, key string, ttl time.Duration) (Lease, error) {
+	var raw [leaseTokenBytes]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return nil, fmt.Errorf("toolsy: lease token: %w", err)
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entry, ok := p.leases[key]; ok && now.Before(entry.expires) {
+		return nil, ErrLeaseHeld
+	}
+	token := hex.EncodeToString(raw[:])
+	p.leases[key] = memoryLeaseEntry{token: token, expires: now.Add(ttl)}
+	return &memoryLease{provider: p, key: key, token: token}, nil
+}
+
+// Held reports whether key currently has an unexpired lease.
+func (p *MemoryLeaseProvider) Held(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.leases[key]
+	return ok && time.Now().Before(entry.expires)
+}
+
+type memoryLease struct {
+	provider *MemoryLeaseProvider
+	key      string
+	token    string
+}
+
+func (l *memoryLease) Extend(_ context.Context, ttl time.Duration) error {
+	p := l.provider
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.leases[l.key]
+	if !ok || entry.token != l.token || !now.Before(entry.expires) {
+		return errors.New("toolsy: lease expired or taken over")
+	}
+	entry.expires = now.Add(ttl)
+	p.leases[l.key] = entry
+	return nil
+}
+
+func (l *memoryLease) Release(_ context.Context) error {
+	p := l.provider
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entry, ok := p.leases[l.key]; ok && entry.token == l.token {
+		delete(p.leases, l.key)
+	}
+	return nil
+}
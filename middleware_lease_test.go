@@ -0,0 +1,143 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deleteArgs struct {
+	Record string `json:"record"`
+}
+
+func newDeleteTool(t *testing.T, started chan<- struct{}, release <-chan struct{}, runs *atomic.Int32) Tool {
+	t.Helper()
+	tool, err := NewTool("delete_record", "Delete", func(ctx context.Context, _ *RunEnv, a deleteArgs) (string, error) {
+		runs.Add(1)
+		started <- struct{}{}
+		select {
+		case <-release:
+			return "deleted " + a.Record, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, WithDangerous())
+	require.NoError(t, err)
+	return tool
+}
+
+func TestWithLeasing_SecondReplicaGetsRetryableError(t *testing.T) {
+	provider := NewMemoryLeaseProvider()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	replica := func() *Registry {
+		reg, err := NewRegistryBuilder().Use(WithLeasing(provider, time.Minute, nil)).
+			Add(newDeleteTool(t, started, release, &runs)).Build()
+		require.NoError(t, err)
+		return reg
+	}
+	a, b := replica(), replica()
+	call := ToolCall{ToolName: "delete_record", Input: ToolInput{ArgsJSON: []byte(`{"record":"42"}`)}}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Execute(context.Background(), call, func(Chunk) error { return nil }) }()
+	<-started
+
+	err := b.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrLeaseHeld)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeLeaseHeld, te.Code)
+	assert.True(t, te.Retryable)
+
+	other := ToolCall{ToolName: "delete_record", Input: ToolInput{ArgsJSON: []byte(`{"record":"7"}`)}}
+	otherDone := make(chan error, 1)
+	go func() { otherDone <- b.Execute(context.Background(), other, func(Chunk) error { return nil }) }()
+	<-started
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-otherDone)
+	assert.Equal(t, int32(2), runs.Load())
+
+	key := defaultIdempotencyKey(a.tools["delete_record"].Manifest(), call.Input)
+	assert.False(t, provider.Held(key), "lease is released after execution")
+}
+
+func TestWithLeasing_SkipsNonDangerousTools(t *testing.T) {
+	provider := NewMemoryLeaseProvider()
+	require.NoError(t, func() error {
+		_, err := provider.Acquire(context.Background(), "fixed", time.Minute)
+		return err
+	}())
+	reg, err := NewRegistryBuilder().
+		Use(WithLeasing(provider, time.Minute, func(ToolManifest, ToolInput) string { return "fixed" })).
+		Add(mustNamedTool(t, "read")).Build()
+	require.NoError(t, err)
+	require.NoError(t, reg.Execute(context.Background(),
+		ToolCall{ToolName: "read", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(Chunk) error { return nil }))
+}
+
+func TestWithLeasing_ExtendsLongExecutions(t *testing.T) {
+	provider := NewMemoryLeaseProvider()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	const ttl = 40 * time.Millisecond
+	reg, err := NewRegistryBuilder().Use(WithLeasing(provider, ttl, func(ToolManifest, ToolInput) string { return "k" })).
+		Add(newDeleteTool(t, started, release, &runs)).Build()
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.Execute(context.Background(),
+			ToolCall{ToolName: "delete_record", Input: ToolInput{ArgsJSON: []byte(`{"record":"1"}`)}},
+			func(Chunk) error { return nil })
+	}()
+	<-started
+	time.Sleep(4 * ttl)
+	_, err = provider.Acquire(context.Background(), "k", ttl)
+	require.ErrorIs(t, err, ErrLeaseHeld, "lease must be extended while the tool runs")
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.False(t, provider.Held("k"))
+}
+
+// lostLeaseProvider grants leases whose extension always fails.
+type lostLeaseProvider struct{}
+
+type lostLease struct{}
+
+func (lostLeaseProvider) Acquire(context.Context, string, time.Duration) (Lease, error) {
+	return lostLease{}, nil
+}
+
+func (lostLease) Extend(context.Context, time.Duration) error { return errors.New("gone") }
+
+func (lostLease) Release(context.Context) error { return nil }
+
+func TestWithLeasing_LostLeaseCancelsExecution(t *testing.T) {
+	started := make(chan struct{}, 1)
+	var runs atomic.Int32
+	reg, err := NewRegistryBuilder().Use(WithLeasing(lostLeaseProvider{}, 10*time.Millisecond, nil)).
+		Add(newDeleteTool(t, started, make(chan struct{}), &runs)).Build()
+	require.NoError(t, err)
+
+	err = reg.Execute(context.Background(),
+		ToolCall{ToolName: "delete_record", Input: ToolInput{ArgsJSON: []byte(`{"record":"1"}`)}},
+		func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrLeaseLost)
+	assert.NotErrorIs(t, err, context.Canceled)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.ErrorContains(t, te.Err, "gone")
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err))
+}
@@ -0,0 +1,143 @@
+package toolsy
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitMode selects what [WithRateLimit] and [WithGlobalRateLimit] do with a call over the limit.
+type RateLimitMode int
+
+const (
+	// RateLimitWait delays the call until a token is available, first yielding a [StatusRateLimited]
+	// [StatusChunk] whose RetryAfter is the wait. A call whose ctx deadline would expire before then
+	// fails at once with a [CodeRateLimited] error; cancellation while waiting returns ctx.Err().
+	RateLimitWait RateLimitMode = iota
+	// RateLimitReject fails the call at once with a retryable [CodeRateLimited] error whose Reason says
+	// when to retry.
+	RateLimitReject
+)
+
+// WithRateLimit caps how often each wrapped tool runs: a token bucket refilled at perSecond tokens per
+// second holding at most burst tokens (minimum 1); every call takes one. Each tool the middleware wraps
+// gets its own bucket; use [WithGlobalRateLimit] for one budget across the registry.
+// perSecond <= 0 disables the limit.
+func WithRateLimit(perSecond float64, burst int, mode RateLimitMode) Middleware {
+	return func(next Tool) Tool {
+		limiter := newRateLimiter(perSecond, burst)
+		if limiter == nil {
+			return next
+		}
+		return &rateLimitTool{toolBase: toolBase{next: next}, limiter: limiter, mode: mode}
+	}
+}
+
+type rateLimitTool struct {
+	toolBase
+
+	limiter *rateLimiter
+	mode    RateLimitMode
+}
+
+func (t *rateLimitTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	if err := t.limiter.admit(ctx, t.mode, rateLimitStatus(yield)); err != nil {
+		return err
+	}
+	return t.next.Execute(ctx, run, input, yield)
+}
+
+// rateLimiter is a token bucket. Waiting callers reserve their token up front (the balance may go
+// negative), so concurrent waiters are served in arrival order without polling.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if perSecond <= 0 || math.IsNaN(perSecond) {
+		return nil
+	}
+	b := float64(max(burst, 1))
+	return &rateLimiter{
+		mu:        sync.Mutex{},
+		perSecond: perSecond,
+		burst:     b,
+		tokens:    b,
+		last:      time.Time{},
+		now:       time.Now,
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it. With allowDebt
+// false and no token available, it takes nothing and returns the wait with ok false.
+func (l *rateLimiter) reserve(allowDebt bool) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	wait := time.Duration(math.Ceil((1 - l.tokens) / l.perSecond * float64(time.Second)))
+	if !allowDebt {
+		return wait, false
+	}
+	l.tokens--
+	return wait, true
+}
+
+// unreserve returns a token taken by a reserve whose caller gave up waiting.
+func (l *rateLimiter) unreserve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// rateLimitStatus reports a wait to the caller as a [StatusRateLimited] [StatusChunk].
+func rateLimitStatus(yield func(Chunk) error) func(time.Duration) error {
+	return func(wait time.Duration) error {
+		return yield(StatusChunk(Status{
+			Phase:      StatusRateLimited,
+			Detail:     "waiting for a rate limit token",
+			RetryAfter: wait,
+		}))
+	}
+}
+
+// admit takes a token for one call according to mode. A nil limiter admits everything. Before
+// waiting for a token it calls notify with the wait; a notify error gives the token back and is returned.
+func (l *rateLimiter) admit(ctx context.Context, mode RateLimitMode, notify func(time.Duration) error) error {
+	if l == nil {
+		return nil
+	}
+	wait, ok := l.reserve(mode == RateLimitWait)
+	if !ok {
+		return NewRateLimitedError(wait)
+	}
+	if wait == 0 {
+		return nil
+	}
+	if deadline, has := ctx.Deadline(); has && time.Until(deadline) < wait {
+		l.unreserve()
+		return NewRateLimitedError(wait)
+	}
+	if err := notify(wait); err != nil {
+		l.unreserve()
+		return err
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		l.unreserve()
+		return err
+	}
+	return nil
+}
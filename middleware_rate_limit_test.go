@@ -0,0 +1,200 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeClockLimiter(perSecond float64, burst int) (*rateLimiter, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newRateLimiter(perSecond, burst)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	l, now := fakeClockLimiter(2, 2)
+	for range 2 {
+		wait, ok := l.reserve(false)
+		require.True(t, ok)
+		assert.Zero(t, wait)
+	}
+	wait, ok := l.reserve(false)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	*now = now.Add(250 * time.Millisecond)
+	wait, ok = l.reserve(true)
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, wait, "debt reservation waits for the missing half token")
+	wait, ok = l.reserve(false)
+	assert.False(t, ok)
+	assert.Equal(t, 750*time.Millisecond, wait, "the next caller queues behind the reservation")
+
+	*now = now.Add(time.Hour)
+	for range 2 {
+		_, ok = l.reserve(false)
+		require.True(t, ok)
+	}
+	_, ok = l.reserve(false)
+	assert.False(t, ok, "refill is capped at burst")
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, newRateLimiter(0, 10))
+	assert.Nil(t, newRateLimiter(-1, 10))
+	require.NoError(t, (*rateLimiter)(nil).admit(context.Background(), RateLimitReject, nil))
+}
+
+func rateCountingTool(t *testing.T, name string) (Tool, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	tool, err := NewTool(name, "Count", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		calls.Add(1)
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	return tool, &calls
+}
+
+func TestWithRateLimit_RejectCarriesRetryAfter(t *testing.T) {
+	tool, calls := rateCountingTool(t, "search")
+	wrapped := WithRateLimit(1, 1, RateLimitReject)(tool)
+	_, err := executeCollect(t, wrapped, `{}`)
+	require.NoError(t, err)
+
+	_, err = executeCollect(t, wrapped, `{}`)
+	require.ErrorIs(t, err, ErrRateLimited)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeRateLimited, te.Code)
+	assert.True(t, te.Retryable)
+	assert.Contains(t, te.Reason, "retry after")
+	var rl *RateLimitedError
+	require.ErrorAs(t, err, &rl)
+	assert.Greater(t, rl.RetryAfter, 900*time.Millisecond)
+	assert.Equal(t, FinishClientError, FinishReasonOf(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithRateLimit_WaitsForToken(t *testing.T) {
+	tool, calls := rateCountingTool(t, "search")
+	wrapped := WithRateLimit(50, 1, RateLimitWait)(tool)
+	start := time.Now()
+	var statuses []Status
+	for range 3 {
+		chunks, err := executeCollect(t, wrapped, `{}`)
+		require.NoError(t, err)
+		for _, c := range chunks {
+			if status, ok := StatusFromChunk(c); ok {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, statuses, 2, "every call that waits reports it first")
+	for _, s := range statuses {
+		assert.Equal(t, StatusRateLimited, s.Phase)
+		assert.Positive(t, s.RetryAfter)
+	}
+}
+
+func TestWithRateLimit_WaitRespectsContext(t *testing.T) {
+	tool, calls := rateCountingTool(t, "search")
+	wrapped := WithRateLimit(0.1, 1, RateLimitWait)(tool)
+	_, err := executeCollect(t, wrapped, `{}`)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = wrapped.Execute(ctx, NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrRateLimited, "a wait longer than the deadline fails fast")
+
+	ctx, cancelNow := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancelNow)
+	err = wrapped.Execute(ctx, NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithGlobalRateLimit_SharedAcrossTools(t *testing.T) {
+	a, _ := rateCountingTool(t, "a")
+	b, _ := rateCountingTool(t, "b")
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{a, b}, WithGlobalRateLimit(1, 1, RateLimitReject),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }))
+	noop := func(Chunk) error { return nil }
+	call := ToolCall{ToolName: "a", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	require.NoError(t, reg.Execute(context.Background(), call, noop))
+	call.ToolName = "b"
+	err := reg.Execute(context.Background(), call, noop)
+	require.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, FinishClientError, summary.FinishReason)
+	assert.Zero(t, reg.InFlight())
+}
+
+func TestWithGlobalRateLimit_WaitYieldsStatus(t *testing.T) {
+	tool, _ := rateCountingTool(t, "t")
+	var summary ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{tool}, WithGlobalRateLimit(50, 1, RateLimitWait),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }))
+	call := ToolCall{ToolName: "t", Input: ToolInput{CallID: "c1", ArgsJSON: []byte(`{}`)}}
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+
+	var chunks []Chunk
+	require.NoError(t, reg.Execute(context.Background(), call, func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	}))
+	require.Len(t, chunks, 2)
+	status, ok := StatusFromChunk(chunks[0])
+	require.True(t, ok)
+	assert.Equal(t, StatusRateLimited, status.Phase)
+	assert.Equal(t, "c1", chunks[0].CallID)
+	assert.Equal(t, "t", chunks[0].ToolName)
+	assert.Equal(t, 1, chunks[1].Seq, "the status chunk takes the first sequence number")
+	assert.Equal(t, 1, summary.ProgressChunks)
+	assert.Equal(t, 1, summary.ChunksDelivered)
+
+	gone := errors.New("client went away")
+	err := reg.Execute(context.Background(), call, func(Chunk) error { return gone })
+	require.ErrorIs(t, err, ErrStreamAborted)
+	require.ErrorIs(t, err, gone)
+}
+
+func TestWithGlobalRateLimit_BatchCompletesWithoutHoldingCapacity(t *testing.T) {
+	tool, calls := rateCountingTool(t, "t")
+	reg := mustBuildRegistry(t, []Tool{tool}, WithGlobalRateLimit(200, 1, RateLimitWait), WithLoadShedding(2, 1))
+	batch := make([]ToolCall, 50)
+	for i := range batch {
+		batch[i] = ToolCall{ToolName: "t", Input: ToolInput{CallID: strconv.Itoa(i), ArgsJSON: []byte(`{}`)}}
+	}
+	failures := 0
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := reg.ExecuteBatchStream(ctx, batch, func(c Chunk) error {
+		if c.IsError {
+			failures++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err(), "batch must not deadlock")
+	assert.Equal(t, int32(50), calls.Load())
+	assert.Zero(t, failures, "waiting calls must not count toward load shedding")
+}
+
+func TestRateLimitedError_WireRoundTrip(t *testing.T) {
+	te := NewRateLimitedError(1500 * time.Millisecond)
+	assert.Equal(t, "toolsy: rate limit exceeded; retry after 1.5s", te.Reason)
+	require.ErrorIs(t, te, ErrRateLimited)
+	assert.Equal(t, ErrRateLimited, sentinelForErrorCode(CodeRateLimited))
+}
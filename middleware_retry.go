@@ -0,0 +1,145 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryAttemptMetadataKey is the [Chunk.Metadata] key holding the 1-based attempt number on chunks
+// yielded by a retried attempt ([WithRetry]); chunks of first attempts do not carry it.
+const RetryAttemptMetadataKey = "toolsy.retry_attempt"
+
+// Defaults applied by [WithRetry] for zero [RetryPolicy] fields.
+const (
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy configures [WithRetry].
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first; values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt (default 100ms); each later delay doubles,
+	// capped at MaxBackoff (default 5s).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter in [0, 1] shortens each delay by a random fraction up to Jitter, so replicas that failed
+	// together do not retry together.
+	Jitter float64
+	// Retryable decides whether an error is worth another attempt; nil uses [DefaultRetryable].
+	Retryable func(error) bool
+	// Logger, when set, logs every retry at warn level with the attempt number and error.
+	Logger *slog.Logger
+}
+
+// DefaultRetryable retries transient failures: retryable [ToolError]s, internal (system) errors, and
+// plain errors. Errors the model must fix ([ClientCorrectable] codes), policy denials, control signals,
+// cancellation, and [ErrStreamAborted] are never retried.
+func DefaultRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrStreamAborted) || errors.Is(err, context.Canceled) || IsControlError(err) {
+		return false
+	}
+	te, ok := AsToolError(err)
+	if !ok {
+		return true
+	}
+	if te.Retryable {
+		return true
+	}
+	return te.Code == CodeInternal
+}
+
+// WithRetry re-runs a failing tool with exponential backoff while policy.Retryable accepts the error.
+// An attempt is retried only if it yielded no chunk: once output reached the caller, a retry would
+// duplicate it, so the attempt's error is returned as is. Tools marked Dangerous without Idempotent run
+// once. Before each wait a [StatusRetrying] [StatusChunk] carrying the delay is yielded; it does not
+// count as attempt output. Waiting between attempts stops when ctx is done, returning ctx.Err(). The registry itself
+// never retries (see the README's zero-resiliency notes); this middleware is opt-in via [RegistryBuilder.Use].
+func WithRetry(policy RetryPolicy) Middleware {
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultRetryMaxBackoff
+	}
+	policy.Jitter = min(max(policy.Jitter, 0), 1)
+	if policy.Retryable == nil {
+		policy.Retryable = DefaultRetryable
+	}
+	return func(next Tool) Tool {
+		return &retryTool{toolBase: toolBase{next: next}, policy: policy}
+	}
+}
+
+type retryTool struct {
+	toolBase
+
+	policy RetryPolicy
+}
+
+func (t *retryTool) Execute(ctx context.Context, run *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	manifest := toolManifest(t.next)
+	if t.policy.MaxAttempts < 2 || (manifest.Dangerous && !manifest.Idempotent) {
+		return t.next.Execute(ctx, run, input, yield)
+	}
+	backoff := t.policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		delivered := false
+		attemptYield := func(c Chunk) error {
+			delivered = true
+			if attempt > 1 {
+				c.Metadata = maps.Clone(c.Metadata)
+				if c.Metadata == nil {
+					c.Metadata = make(map[string]any, 1)
+				}
+				c.Metadata[RetryAttemptMetadataKey] = attempt
+			}
+			return yield(c)
+		}
+		err := t.next.Execute(ctx, run, input, attemptYield)
+		if err == nil || delivered || attempt >= t.policy.MaxAttempts || !t.policy.Retryable(err) {
+			return err
+		}
+		if t.policy.Logger != nil {
+			t.policy.Logger.WarnContext(ctx, "tool retry", "tool", manifest.Name, "attempt", attempt+1,
+				"error", err)
+		}
+		delay := t.jittered(backoff)
+		// The status goes straight to yield: it is not attempt output and must not block the retry.
+		status := StatusChunk(Status{
+			Phase:      StatusRetrying,
+			Detail:     fmt.Sprintf("attempt %d of %d", attempt+1, t.policy.MaxAttempts),
+			RetryAfter: delay,
+		})
+		if yieldErr := yield(status); yieldErr != nil {
+			return yieldErr
+		}
+		if waitErr := sleepContext(ctx, delay); waitErr != nil {
+			return waitErr
+		}
+		backoff = min(2*backoff, t.policy.MaxBackoff)
+	}
+}
+
+func (t *retryTool) jittered(d time.Duration) time.Duration {
+	if t.policy.Jitter == 0 {
+		return d
+	}
+	return d - time.Duration(rand.Float64()*t.policy.Jitter*float64(d)) //nolint:gosec // jitter needs no crypto
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
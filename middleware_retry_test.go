@@ -0,0 +1,136 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flakyStreamTool(t *testing.T, failures int32, fail error, beforeFail func(yield func(Chunk) error) error) (
+	Tool, *atomic.Int32,
+) {
+	t.Helper()
+	var calls atomic.Int32
+	fn := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		if calls.Add(1) <= failures {
+			if beforeFail != nil {
+				if err := beforeFail(yield); err != nil {
+					return err
+				}
+			}
+			return fail
+		}
+		return yield(Chunk{Event: EventResult, Data: []byte(`{"ok":true}`), MimeType: MimeTypeJSON})
+	}
+	tool, err := NewStreamTool("flaky", "Flaky", fn)
+	require.NoError(t, err)
+	return tool, &calls
+}
+
+func retryPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestWithRetry_RetriesSystemErrors(t *testing.T) {
+	tool, calls := flakyStreamTool(t, 2, NewInternalError(errors.New("503 from upstream")), nil)
+	var chunks []Chunk
+	wrapped := WithRetry(retryPolicy(3))(tool)
+	err := wrapped.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)},
+		func(c Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, chunks, 3)
+	for i, c := range chunks[:2] {
+		status, ok := StatusFromChunk(c)
+		require.True(t, ok, "a status precedes each backoff")
+		assert.Equal(t, StatusRetrying, status.Phase)
+		assert.Equal(t, fmt.Sprintf("attempt %d of 3", i+2), status.Detail)
+		assert.Positive(t, status.RetryAfter)
+	}
+	assert.Equal(t, 3, chunks[2].Metadata[RetryAttemptMetadataKey])
+}
+
+func TestWithRetry_StatusChunkAbortStopsRetrying(t *testing.T) {
+	tool, calls := flakyStreamTool(t, 2, NewInternalError(errors.New("503 from upstream")), nil)
+	gone := errors.New("client went away")
+	err := WithRetry(retryPolicy(3))(tool).Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return gone })
+	require.ErrorIs(t, err, gone)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	fail := NewInternalError(errors.New("db hiccup"))
+	tool, calls := flakyStreamTool(t, 5, fail, nil)
+	err := WithRetry(retryPolicy(2))(tool).Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, fail)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWithRetry_DoesNotRetryClientErrors(t *testing.T) {
+	tool, calls := flakyStreamTool(t, 1, NewValidationError("bad date", "date"), nil)
+	err := WithRetry(retryPolicy(3))(tool).Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return nil })
+	requireToolErrorCode(t, err, CodeValidationFailed)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithRetry_NoRetryAfterDeliveredChunk(t *testing.T) {
+	fail := NewInternalError(errors.New("connection reset"))
+	tool, calls := flakyStreamTool(t, 1, fail, func(yield func(Chunk) error) error {
+		return yield(Chunk{Event: EventProgress, Data: []byte("half done"), MimeType: MimeTypeText})
+	})
+	delivered := 0
+	err := WithRetry(retryPolicy(3))(tool).Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error {
+			delivered++
+			return nil
+		})
+	require.ErrorIs(t, err, fail)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 1, delivered)
+}
+
+func TestWithRetry_StopsWaitingWhenContextIsDone(t *testing.T) {
+	tool, calls := flakyStreamTool(t, 5, NewInternalError(errors.New("down")), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	err := WithRetry(policy)(tool).Execute(ctx, NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)},
+		func(Chunk) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithRetry_SkipsDangerousNonIdempotentTools(t *testing.T) {
+	var calls atomic.Int32
+	tool, err := NewTool("wire", "Wire money", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		calls.Add(1)
+		return "", errors.New("gateway timeout")
+	}, WithDangerous())
+	require.NoError(t, err)
+	err = WithRetry(retryPolicy(3))(tool).Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error { return nil })
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	assert.True(t, DefaultRetryable(errors.New("plain")))
+	assert.True(t, DefaultRetryable(NewInternalError(errors.New("x"))))
+	assert.True(t, DefaultRetryable(NewOverloadedError(10, 10, time.Second)))
+	assert.False(t, DefaultRetryable(NewValidationError("x")))
+	assert.False(t, DefaultRetryable(wrapYieldError(errors.New("gone"))))
+	assert.False(t, DefaultRetryable(context.Canceled))
+	assert.False(t, DefaultRetryable(ErrHalt))
+}
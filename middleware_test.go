@@ -197,6 +197,114 @@ func TestRegistryBuilderUse(t *testing.T) {
 	assert.Equal(t, 1, strings.Count(buf.String(), "tool start"))
 }
 
+func newFiveChunkStreamTool(t *testing.T) Tool {
+	t.Helper()
+	type A struct{}
+	stream := func(ctx context.Context, _ *RunEnv, _ A, yield func(Chunk) error) error {
+		for i := range 5 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := yield(Chunk{Event: EventResult, Data: []byte{byte('a' + i)}, MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tool, err := NewStreamTool("stream_me", "desc", stream)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestMiddleware_StreamingToolUnderLoggingAndRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	reg, err := NewRegistryBuilder().Use(WithLogging(logger), WithRecovery()).Add(newFiveChunkStreamTool(t)).Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var got []byte
+	err = reg.Execute(ctx, ToolCall{ToolName: "stream_me", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(c Chunk) error {
+		got = append(got, c.Data...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abcde", string(got))
+
+	logStr := buf.String()
+	assert.Equal(t, 1, strings.Count(logStr, "tool start"))
+	assert.Equal(t, 1, strings.Count(logStr, "tool end"))
+	assert.Contains(t, logStr, "chunks=5")
+	assert.Contains(t, logStr, "bytes=5")
+}
+
+func TestWithRecovery_CatchesPanicFromYield(t *testing.T) {
+	wrapped := WithRecovery()(newFiveChunkStreamTool(t))
+	var delivered int
+	err := wrapped.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)}, func(Chunk) error {
+		delivered++
+		if delivered == 2 {
+			panic("consumer panic")
+		}
+		return nil
+	})
+	var te *ToolError
+	require.ErrorAs(t, err, &te)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.Contains(t, te.Err.Error(), "consumer panic")
+	assert.Equal(t, 2, delivered)
+	assert.Contains(t, string(PanicStack(err)), "middleware_test.go")
+}
+
+func TestWithLogging_LogsPanicStacks(t *testing.T) {
+	panicky := newMiddlewareMinTool("boom", func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error {
+		panic("kaboom")
+	})
+	call := ToolCall{ToolName: "boom", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	reg, err := NewRegistryBuilder().Use(WithLogging(logger)).Add(panicky).Build()
+	require.NoError(t, err)
+	err = reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	assert.Equal(t, FinishPanic, FinishReasonOf(err), "the registry still recovers the re-raised panic")
+	assert.Contains(t, buf.String(), "tool panic")
+	assert.Contains(t, buf.String(), "panic=kaboom")
+	assert.Contains(t, buf.String(), "stack=")
+	assert.NotContains(t, buf.String(), "tool end")
+
+	buf.Reset()
+	reg, err = NewRegistryBuilder().Use(WithLogging(logger), WithRecovery()).Add(panicky).Build()
+	require.NoError(t, err)
+	err = reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), "tool error")
+	assert.Contains(t, buf.String(), "stack=")
+}
+
+func TestMiddleware_CallerDeadlineKeepsYieldedChunks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	reg, err := NewRegistryBuilder().Use(WithLogging(logger), WithRecovery()).Add(newFiveChunkStreamTool(t)).Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	var got []byte
+	err = reg.Execute(ctx, ToolCall{ToolName: "stream_me", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(c Chunk) error {
+		got = append(got, c.Data...)
+		if len(got) == 3 {
+			cancel(context.DeadlineExceeded)
+		}
+		return nil
+	})
+	require.Error(t, err)
+	assert.Equal(t, "abc", string(got), "chunks yielded before the deadline stand")
+	assert.Equal(t, 1, strings.Count(buf.String(), "tool start"))
+	assert.Contains(t, buf.String(), "chunks=3")
+}
+
 func TestMiddlewareShortCircuitSkipsInnerTool(t *testing.T) {
 	var called atomic.Bool
 	inner := newMiddlewareMinTool(
@@ -0,0 +1,53 @@
+package toolsy
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// NoResultMetadataKey marks [NoResult] chunks in Envelope.Metadata.
+const NoResultMetadataKey = "toolsy.no_result"
+
+// noResultPayload is the documented wire envelope of a [NoResult] chunk.
+type noResultPayload struct {
+	NoResult bool   `json:"noResult"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// NoResult encodes "nothing found / not applicable" as a successful [EventResult] chunk, so models and
+// analytics can tell it apart from an error and from an empty object. Data is the JSON envelope
+// {"noResult": true, "reason": reason} and Envelope.Metadata carries [NoResultMetadataKey].
+// Delivered no-result chunks set [ExecutionSummary.NoResult].
+func NoResult(reason string) Chunk {
+	data, _ := json.Marshal(noResultPayload{NoResult: true, Reason: reason})
+	return Chunk{ //nolint:exhaustruct // CallID/ToolName are filled by the registry
+		Event:    EventResult,
+		Data:     data,
+		MimeType: MimeTypeJSON,
+		Envelope: NewResultEnvelope(nil, data, MimeTypeJSON, DeliveryClassStructured, AudienceModel,
+			map[string]any{NoResultMetadataKey: true}),
+	}
+}
+
+// IsNoResult reports whether c was built by [NoResult]. Chunks that lost their envelope (for
+// example after a history round trip) are recognized by their JSON payload.
+func IsNoResult(c Chunk) bool {
+	_, ok := NoResultReason(c)
+	return ok
+}
+
+// NoResultReason returns the reason of a [NoResult] chunk. It reports false for any other chunk.
+func NoResultReason(c Chunk) (string, bool) {
+	if c.IsError || c.Event != EventResult {
+		return "", false
+	}
+	marked := c.Envelope != nil && c.Envelope.Metadata[NoResultMetadataKey] == true
+	if !marked && (c.MimeType != MimeTypeJSON || !bytes.Contains(c.Data, []byte(`"noResult"`))) {
+		return "", false
+	}
+	var p noResultPayload
+	if err := json.Unmarshal(c.Data, &p); err != nil || !p.NoResult {
+		return "", marked
+	}
+	return p.Reason, true
+}
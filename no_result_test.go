@@ -0,0 +1,65 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoResult_EnvelopeShape(t *testing.T) {
+	c := NoResult("no invoices for March")
+	assert.Equal(t, EventResult, c.Event)
+	assert.False(t, c.IsError)
+	assert.Equal(t, MimeTypeJSON, c.MimeType)
+	assert.JSONEq(t, `{"noResult":true,"reason":"no invoices for March"}`, string(c.Data))
+	require.NotNil(t, c.Envelope)
+	assert.Equal(t, true, c.Envelope.Metadata[NoResultMetadataKey])
+
+	reason, ok := NoResultReason(c)
+	require.True(t, ok)
+	assert.Equal(t, "no invoices for March", reason)
+
+	// The payload alone is enough once the envelope is gone.
+	bare := Chunk{Event: EventResult, Data: c.Data, MimeType: MimeTypeJSON}
+	assert.True(t, IsNoResult(bare))
+
+	assert.False(t, IsNoResult(Chunk{Event: EventResult, Data: []byte(`{}`), MimeType: MimeTypeJSON}))
+	assert.False(t, IsNoResult(Chunk{Event: EventResult, Data: []byte(`{"noResult":false}`), MimeType: MimeTypeJSON}))
+	assert.False(t, IsNoResult(Chunk{Event: EventResult, Data: []byte(`{"noResult":true}`), MimeType: MimeTypeText}))
+}
+
+func TestRegistry_Execute_SummaryNoResult(t *testing.T) {
+	search, err := NewStreamTool("search", "Search",
+		func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+			return yield(NoResult("nothing matched"))
+		})
+	require.NoError(t, err)
+	hit, err := NewTool("hit", "Hit", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		return "found", nil
+	})
+	require.NoError(t, err)
+
+	summaries := map[string]ExecutionSummary{}
+	reg := mustBuildRegistry(t, []Tool{search, hit}, WithOnAfterExecute(
+		func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+			summaries[s.ToolName] = s
+		}))
+	for _, name := range []string{"search", "hit"} {
+		var got []Chunk
+		err := reg.Execute(context.Background(), ToolCall{
+			ToolName: name,
+			Input:    ToolInput{CallID: "c-" + name, ArgsJSON: []byte(`{}`)},
+		}, func(c Chunk) error {
+			got = append(got, c)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, name == "search", IsNoResult(got[0]))
+	}
+	assert.True(t, summaries["search"].NoResult)
+	assert.False(t, summaries["hit"].NoResult)
+}
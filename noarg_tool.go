@@ -0,0 +1,61 @@
+package toolsy
+
+import (
+	"bytes"
+	"context"
+)
+
+// actionResult is the canonical result of [NewActionTool].
+type actionResult struct {
+	OK bool `json:"ok"`
+}
+
+// NewNoArgTool builds a Tool from a function without arguments. Its parameters schema is the empty
+// object {"type":"object","properties":{},"additionalProperties":false}, and empty, whitespace-only,
+// or null arguments are accepted as {} because models often omit them.
+func NewNoArgTool[R any](
+	name, description string,
+	fn func(ctx context.Context) (R, error),
+	opts ...ToolOption,
+) (Tool, error) {
+	t, err := NewTool(name, description, func(ctx context.Context, _ *RunEnv, _ struct{}) (R, error) {
+		return fn(ctx)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	inner, _ := t.(*tool)
+	inner.manifest.Parameters["properties"] = map[string]any{}
+	execute, validate := inner.execute, inner.validate
+	inner.execute = func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
+		input.ArgsJSON = emptyArgsAsObject(input.ArgsJSON)
+		return execute(ctx, env, input, yield)
+	}
+	inner.validate = func(argsJSON []byte) error {
+		return validate(emptyArgsAsObject(argsJSON))
+	}
+	return inner, nil
+}
+
+func emptyArgsAsObject(argsJSON []byte) []byte {
+	trimmed := bytes.TrimSpace(argsJSON)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return []byte("{}")
+	}
+	return argsJSON
+}
+
+// NewActionTool builds a Tool for side effects that return only an error. A successful call yields
+// a single {"ok":true} result chunk, and the output schema describes that payload.
+func NewActionTool[T any](
+	name, description string,
+	fn func(ctx context.Context, args T) error,
+	opts ...ToolOption,
+) (Tool, error) {
+	return NewTool(name, description, func(ctx context.Context, _ *RunEnv, args T) (actionResult, error) {
+		if err := fn(ctx, args); err != nil {
+			return actionResult{OK: false}, err
+		}
+		return actionResult{OK: true}, nil
+	}, opts...)
+}
@@ -0,0 +1,71 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNoArgTool(t *testing.T) {
+	t.Parallel()
+	tool, err := NewNoArgTool("server_time", "Current server time", func(context.Context) (string, error) {
+		return "12:00", nil
+	}, WithReadOnly())
+	require.NoError(t, err)
+	assert.True(t, tool.Manifest().ReadOnly)
+	params, err := json.Marshal(tool.Manifest().Parameters)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{},"additionalProperties":false}`, string(params))
+
+	reg, err := NewRegistryBuilder().Add(tool).Build()
+	require.NoError(t, err)
+	for _, args := range []string{"", "  ", "null", "{}"} {
+		var got []byte
+		call := ToolCall{ToolName: "server_time", Input: ToolInput{ArgsJSON: []byte(args)}}
+		require.NoError(t, reg.Execute(context.Background(), call, func(c Chunk) error {
+			got = c.Data
+			return nil
+		}), "args %q", args)
+		assert.JSONEq(t, `"12:00"`, string(got))
+		require.NoError(t, reg.ValidateCall(call), "args %q", args)
+	}
+	call := ToolCall{ToolName: "server_time", Input: ToolInput{ArgsJSON: []byte(`{"tz":"UTC"}`)}}
+	require.ErrorIs(t, reg.ValidateCall(call), ErrValidation)
+}
+
+func TestNewActionTool(t *testing.T) {
+	t.Parallel()
+	type sendArgs struct {
+		To string `json:"to"`
+	}
+	var sent []string
+	tool, err := NewActionTool("send_email", "Send an email", func(_ context.Context, args sendArgs) error {
+		if args.To == "" {
+			return errors.New("smtp: no recipient")
+		}
+		sent = append(sent, args.To)
+		return nil
+	}, WithDangerous())
+	require.NoError(t, err)
+	assert.True(t, tool.Manifest().Dangerous)
+	assert.Contains(t, tool.Manifest().OutputSchema["properties"], "ok")
+
+	var chunks []Chunk
+	collect := func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	}
+	err = tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{"to":"a@b.c"}`)}, collect)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.JSONEq(t, `{"ok":true}`, string(chunks[0].Data))
+	assert.Equal(t, []string{"a@b.c"}, sent)
+
+	err = tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{"to":""}`)}, collect)
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err))
+	assert.Len(t, chunks, 1, "failed actions yield no result chunk")
+}
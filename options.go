@@ -2,7 +2,12 @@ package toolsy
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
 	"maps"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -10,6 +15,27 @@ import (
 type SchemaConfig struct {
 	Strict   bool
 	Registry *SchemaRegistry
+
+	// AllValidationErrors reports every schema violation of a call in one [ToolError] instead of
+	// stopping at the first one, so the LLM can fix all arguments in a single retry.
+	AllValidationErrors bool
+
+	// Transforms post-process the parameters schema in order ([WithSchemaTransform]).
+	Transforms []SchemaTransform
+
+	// Nullable selects how null-or-T unions are written ([WithNullableStyle]).
+	Nullable NullableStyle
+
+	// OptionalAsNullable makes strict mode require `optional:"true"` fields but accept null for
+	// them ([WithStrictOptionalAsNullable]).
+	OptionalAsNullable bool
+
+	// PreserveUnions leaves anyOf/oneOf branches of raw schemas untouched in strict mode
+	// ([WithStrictPreserveUnions]).
+	PreserveUnions bool
+
+	// CloseFreeform makes strict mode close free-form objects too ([WithStrictCloseFreeform]).
+	CloseFreeform bool
 }
 
 // ToolManifest contains metadata exposed to orchestrators and discovery layers.
@@ -22,17 +48,56 @@ type ToolManifest struct {
 	Version      string
 	Requirements ToolRequirements
 
+	// Strict reports that Parameters were generated or normalized by [WithStrict];
+	// provider exporters use it to request strict function calling.
+	Strict bool
+
+	// BoundArgs lists arguments fixed by [NewBoundTool]; they are hidden from Parameters.
+	BoundArgs map[string]any
+
+	// SensitiveArgs lists JSON pointers of argument fields masked by [RedactArgs]; a "*" segment
+	// matches every array element or map value. Typed tools fill it from `sensitive:"true"` struct
+	// tags; see [WithSensitiveArgs].
+	SensitiveArgs []string
+
 	CompletionPolicy     CompletionPolicy
 	ReadOnly             bool
 	RequiresConfirmation bool
 	Dangerous            bool
 	Idempotent           bool
+
+	// Deprecated marks a tool the model should stop using ([WithDeprecated]); DeprecationMessage says
+	// what to use instead. Execution is unaffected.
+	Deprecated         bool
+	DeprecationMessage string
+
+	// Hidden keeps the tool out of listings advertised to the model ([Registry.GetVisibleTools],
+	// [Registry.EffectiveDescriptors], provider exporters) while it stays executable by name.
+	Hidden bool
+
+	// Examples are example arguments ([WithExamples]), also carried by Parameters as "examples".
+	Examples []json.RawMessage
 }
 
 // ToolConfig is the internal split configuration for a tool.
 type ToolConfig struct {
 	Schema   SchemaConfig
 	Manifest ToolManifest
+
+	// ValidateOutput checks results against Manifest.OutputSchema ([WithOutputValidation]).
+	ValidateOutput bool
+
+	// Examples are the example arguments of [WithExamples], marshaled into Manifest.Examples when
+	// the tool is built.
+	Examples []any
+
+	// NamePattern replaces [DefaultToolNamePattern] and SkipNameValidation disables the name check
+	// ([WithNameValidation]).
+	NamePattern        *regexp.Regexp
+	SkipNameValidation bool
+
+	// RequireDescription rejects tools without a description ([WithRequireDescription]).
+	RequireDescription bool
 }
 
 // ToolOption configures a tool (e.g. WithStrict, WithSchemaRegistry).
@@ -43,6 +108,44 @@ type ToolOption func(*ToolConfig)
 func WithStrict() ToolOption {
 	return func(c *ToolConfig) {
 		c.Schema.Strict = true
+		c.Manifest.Strict = true
+	}
+}
+
+// WithStrictOptionalAsNullable changes how [WithStrict] treats fields tagged `optional:"true"`:
+// instead of leaving them out of "required", it requires them and adds null to their type, the form
+// OpenAI structured outputs recommends for optional fields. Calls must then send null rather than
+// omit the field. It has no effect without WithStrict.
+func WithStrictOptionalAsNullable() ToolOption {
+	return func(c *ToolConfig) {
+		c.Schema.OptionalAsNullable = true
+	}
+}
+
+// WithStrictPreserveUnions keeps [WithStrict] out of anyOf/oneOf branches of dynamic and proxy
+// schemas. By default strict mode closes objects in those branches with additionalProperties: false
+// but leaves their "required" alone; with this option the branches are not modified at all.
+func WithStrictPreserveUnions() ToolOption {
+	return func(c *ToolConfig) {
+		c.Schema.PreserveUnions = true
+	}
+}
+
+// WithStrictCloseFreeform makes [WithStrict] set additionalProperties: false on free-form objects
+// (json.RawMessage, map[string]any, `freeform:"object"` fields, and raw objects without
+// properties), which strict mode otherwise leaves open. Use it for providers that reject open
+// objects in strict mode; such fields then accept only {}.
+func WithStrictCloseFreeform() ToolOption {
+	return func(c *ToolConfig) {
+		c.Schema.CloseFreeform = true
+	}
+}
+
+// WithAllValidationErrors makes argument validation collect every schema violation in one pass
+// and return them together in [ToolError.Violations]. By default only the first violation is reported.
+func WithAllValidationErrors() ToolOption {
+	return func(c *ToolConfig) {
+		c.Schema.AllValidationErrors = true
 	}
 }
 
@@ -89,6 +192,44 @@ func WithRequiresConfirmation() ToolOption {
 	}
 }
 
+// WithHidden marks an internal tool that the orchestrator calls programmatically but never
+// advertises to the model. Execute and GetTool still resolve it by name.
+func WithHidden() ToolOption {
+	return func(c *ToolConfig) {
+		c.Manifest.Hidden = true
+	}
+}
+
+// WithDeprecated marks the tool as deprecated with a message such as "use search_v2". Exporters append
+// the notice to the description shown to the model (see [ModelDescription]), [WithOnDeprecatedCall]
+// hooks fire when it still runs, and [ExecutionSummary.Deprecated] is set. Execution is unaffected.
+func WithDeprecated(message string) ToolOption {
+	return func(c *ToolConfig) {
+		c.Manifest.Deprecated = true
+		c.Manifest.DeprecationMessage = message
+	}
+}
+
+// ModelDescription returns the description exporters show the model: m.Description, followed for
+// deprecated tools by " (deprecated: <message>)" or " (deprecated)". A description that already ends
+// with the notice is returned unchanged, so applying it twice is harmless.
+func ModelDescription(m ToolManifest) string {
+	if !m.Deprecated {
+		return m.Description
+	}
+	notice := "(deprecated)"
+	if m.DeprecationMessage != "" {
+		notice = "(deprecated: " + m.DeprecationMessage + ")"
+	}
+	if strings.HasSuffix(m.Description, notice) {
+		return m.Description
+	}
+	if m.Description == "" {
+		return notice
+	}
+	return m.Description + " " + notice
+}
+
 // WithIdempotent marks mutating tools as safe to retry with identical arguments.
 func WithIdempotent() ToolOption {
 	return func(c *ToolConfig) {
@@ -119,16 +260,43 @@ func WithOutputSchema(schema map[string]any) ToolOption {
 type RegistryOption func(*registryOptions)
 
 type registryOptions struct {
-	recoverPanics   bool
-	validator       Validator
-	policy          Policy
-	policyDigest    string
-	policyIDMissing bool
-	authorizer      Authorizer
-	view            RegistryViewSnapshot
-	onBefore        func(context.Context, ToolCall)
-	onAfter         func(context.Context, ToolCall, ExecutionSummary, time.Duration)
-	onChunk         func(context.Context, Chunk)
+	recoverPanics    bool
+	validator        Validator
+	policy           Policy
+	policyDigest     string
+	policyIDMissing  bool
+	authorizer       Authorizer
+	view             RegistryViewSnapshot
+	maxTools         int
+	maxInFlight      int
+	shedThreshold    float64
+	rateLimiter      *rateLimiter
+	rateLimitMode    RateLimitMode
+	dedup            *dedupGroup
+	maxMetadataSize  int
+	batchFailFast    bool
+	batchLLMErrors   bool
+	noSuggestions    bool
+	namePattern      *regexp.Regexp
+	noPanicStacks    bool
+	chunkBuffer      int
+	finalChunk       bool
+	watchdogInterval time.Duration
+	onAbandoned      func(AbandonedExecution)
+	ownershipLogger  *slog.Logger
+	argsCodecs       map[string]ArgsCodec
+	toolLoader       func(context.Context, string) (Tool, error)
+	// Execution hooks run in registration order; see [WithOnHookPanic] for panics they raise.
+	onBefore        []func(context.Context, ToolCall)
+	onAfter         []func(context.Context, ToolCall, ExecutionSummary, time.Duration)
+	onError         []func(context.Context, ToolCall, error)
+	onChunk         []func(context.Context, Chunk)
+	onChunkProgress []func(context.Context, Chunk, ChunkProgress)
+	onBatch         []func(context.Context, []ToolCall) (context.Context, func(error))
+	onDeprecated    []func(context.Context, ToolCall, string)
+	onHookPanic     func(context.Context, string, any)
+	redactHooks     bool
+	confirm         *confirmationGate
 }
 
 // WithRecoverPanics enables panic recovery in Execute (returns [ToolError] with [CodeInternal]).
@@ -138,6 +306,52 @@ func WithRecoverPanics(enable bool) RegistryOption {
 	}
 }
 
+// WithPanicStacks controls whether panics recovered by the registry ([WithRecoverPanics]) keep the
+// goroutine stack for [PanicStack] and [ExecutionSummary.PanicStack]. Enabled by default; disable it
+// to save the allocation on hot paths. The stack never appears in error messages.
+func WithPanicStacks(enable bool) RegistryOption {
+	return func(o *registryOptions) {
+		o.noPanicStacks = !enable
+	}
+}
+
+// WithBatchErrorsAsChunks controls how [Registry.ExecuteBatchStream] surfaces per-call failures.
+// Enabled (the default), tool, validation, not-found, and shutdown errors become IsError chunks and
+// the batch keeps running. Disabled, the first such error cancels the remaining calls and is returned.
+func WithBatchErrorsAsChunks(enable bool) RegistryOption {
+	return func(o *registryOptions) {
+		o.batchFailFast = !enable
+	}
+}
+
+// WithBatchLLMErrors makes the IsError chunks of [Registry.ExecuteBatchStream] carry the
+// [ErrorToLLMJSON] object in Data, with [MimeTypeJSON], so the chunk can go back to the model as is.
+// Envelope keeps the [ToolError] and the [MimeTypeToolErrorJSON] wire in Raw, and the registry's
+// error-chunk helpers still decode the chunk. Disabled by default.
+func WithBatchLLMErrors(enable bool) RegistryOption {
+	return func(o *registryOptions) {
+		o.batchLLMErrors = enable
+	}
+}
+
+// WithToolSuggestions controls the "did you mean" part of [CodeToolNotFound] errors. Enabled (the
+// default), Execute and ValidateCall list up to three registered names close to the unknown one so the
+// model can correct itself. Disable it when revealing registered tool names is a leak.
+func WithToolSuggestions(enable bool) RegistryOption {
+	return func(o *registryOptions) {
+		o.noSuggestions = !enable
+	}
+}
+
+// WithMaxTools caps the number of tools a [RegistryBuilder] may build into one registry.
+// Build fails with [ErrTooManyTools] when the cap is exceeded; n <= 0 disables the limit. It also
+// bounds the [WithToolLoader] cache, which evicts its oldest tools beyond n.
+func WithMaxTools(n int) RegistryOption {
+	return func(o *registryOptions) {
+		o.maxTools = n
+	}
+}
+
 // WithValidator configures a low-level reject-only validator run before tool unmarshaling (fail-closed).
 //
 // Use [ArgsBinder] through [NewTypedTool] or [NewPolicyTool] when validation
@@ -172,26 +386,92 @@ func WithRequirementsPolicy[TSubject, TScope any](
 	return WithPolicy(policyID, NewRequirementsPolicy(fn))
 }
 
-// WithOnBeforeExecute sets a hook called before each tool execution.
+// WithOnBeforeExecute adds a hook called before each tool execution.
+//
+// Hooks compose: every With* hook option appends to the hooks already registered instead of
+// replacing them, and hooks of one kind run in registration order (after hooks too). A panicking
+// hook is recovered and reported to [WithOnHookPanic]; the other hooks still run.
 func WithOnBeforeExecute(fn func(context.Context, ToolCall)) RegistryOption {
 	return func(o *registryOptions) {
-		o.onBefore = fn
+		o.onBefore = appendHook(o.onBefore, fn)
+	}
+}
+
+// hookFunc lists the execution hook signatures held by [registryOptions].
+type hookFunc interface {
+	~func(context.Context, ToolCall) |
+		~func(context.Context, ToolCall, ExecutionSummary, time.Duration) |
+		~func(context.Context, ToolCall, error) |
+		~func(context.Context, Chunk) |
+		~func(context.Context, Chunk, ChunkProgress) |
+		~func(context.Context, []ToolCall) (context.Context, func(error)) |
+		~func(context.Context, ToolCall, string)
+}
+
+// appendHook adds fn to hooks, ignoring nil. The result never shares a backing array with hooks, so
+// registries derived from the same options cannot see each other's hooks.
+func appendHook[F hookFunc](hooks []F, fn F) []F {
+	if fn == nil {
+		return hooks
 	}
+	return append(slices.Clip(hooks), fn)
 }
 
-// WithOnAfterExecute sets a hook called after each tool execution (always invoked via defer,
+// WithOnAfterExecute adds a hook called after each tool execution (always invoked via defer,
 // even on partial success or error). Summary reports delivered success chunks/bytes,
-// delivered error chunks (soft errors), and final hard error.
+// delivered error chunks (soft errors), and final hard error. Calls rejected before the tool runs
+// ([ErrToolNotFound], [ErrShutdown], load shedding) also reach it, with zero chunks.
 func WithOnAfterExecute(fn func(context.Context, ToolCall, ExecutionSummary, time.Duration)) RegistryOption {
 	return func(o *registryOptions) {
-		o.onAfter = fn
+		o.onAfter = appendHook(o.onAfter, fn)
+	}
+}
+
+// WithOnError adds a hook called exactly once for every call that fails, with the final error
+// (the one Execute returns and [ExecutionSummary.Error] holds, panic recovery included). It also
+// fires for calls rejected before the tool runs, such as [ErrToolNotFound], [ErrShutdown], and
+// load shedding. In [Registry.ExecuteBatchStream] it sees the error before it becomes a soft error chunk.
+// It runs before [WithOnAfterExecute]. Observability only.
+func WithOnError(fn func(context.Context, ToolCall, error)) RegistryOption {
+	return func(o *registryOptions) {
+		o.onError = appendHook(o.onError, fn)
+	}
+}
+
+// WithOnDeprecatedCall adds a hook called once per call of a tool marked [WithDeprecated], after
+// [WithOnBeforeExecute], with the tool's deprecation message. Use it to log that the model still calls
+// the tool. Observability only.
+func WithOnDeprecatedCall(fn func(ctx context.Context, call ToolCall, message string)) RegistryOption {
+	return func(o *registryOptions) {
+		o.onDeprecated = appendHook(o.onDeprecated, fn)
 	}
 }
 
-// WithOnChunk sets a hook called for each non-error chunk successfully delivered (when yield returns nil). Observability only.
+// WithOnChunk adds a hook called for each non-error chunk successfully delivered (when yield returns nil).
+// Observability only.
 func WithOnChunk(fn func(context.Context, Chunk)) RegistryOption {
 	return func(o *registryOptions) {
-		o.onChunk = fn
+		o.onChunk = appendHook(o.onChunk, fn)
+	}
+}
+
+// WithOnChunkProgress adds a hook called for each non-error result chunk successfully delivered, together with
+// running per-call totals; [EventProgress] chunks are counted in [ExecutionSummary.ProgressChunks] instead. In [Registry.ExecuteBatchStream] the totals are tracked per call, not per batch.
+// Observability only; it runs after [WithOnChunk].
+func WithOnChunkProgress(fn func(context.Context, Chunk, ChunkProgress)) RegistryOption {
+	return func(o *registryOptions) {
+		o.onChunkProgress = appendHook(o.onChunkProgress, fn)
+	}
+}
+
+// WithOnBatch adds a hook called once per [Registry.ExecuteBatchStream] before any call starts. The
+// context it returns is the parent of every call in the batch (for example one carrying a batch
+// span, so per-call spans become its children), and finish runs with the batch's result after all
+// calls have ended. The calls slice is a copy. Empty batches do not invoke the hook. Several batch
+// hooks nest: each derives its context from the previous one's, and their finish funcs run in reverse.
+func WithOnBatch(fn func(ctx context.Context, calls []ToolCall) (context.Context, func(err error))) RegistryOption {
+	return func(o *registryOptions) {
+		o.onBatch = appendHook(o.onBatch, fn)
 	}
 }
 
@@ -21,6 +21,7 @@ func TestWithStrict(t *testing.T) {
 		return R{Y: a.X}, nil
 	}, WithStrict())
 	require.NoError(t, err)
+	assert.True(t, tool.Manifest().Strict)
 
 	var res R
 	err = tool.Execute(
@@ -88,7 +88,7 @@ func (s *Session) RunCall(ctx context.Context, call ToolCall) (ToolOutcome, erro
 
 	outcome := ToolOutcome{ToolName: call.ToolName} //nolint:exhaustruct // filled during Execute
 	if tool, ok := s.reg.GetTool(call.ToolName); ok {
-		outcome.CompletionPolicy = tool.Manifest().CompletionPolicy
+		outcome.CompletionPolicy = toolManifest(tool).CompletionPolicy
 	}
 
 	var terminalBusiness bool
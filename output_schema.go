@@ -0,0 +1,76 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ToolOutputSchema is implemented by tools that advertise the JSON Schema of their results.
+// Tools built by this package implement it; OutputSchema returns a copy of [ToolManifest.OutputSchema]
+// (nil when the tool declares none).
+type ToolOutputSchema interface {
+	OutputSchema() map[string]any
+}
+
+// OutputSchema returns a copy of the tool's output schema.
+func (t *tool) OutputSchema() map[string]any {
+	return deepCloneMap(t.manifest.OutputSchema)
+}
+
+// WithOutputValidation validates every JSON result chunk against [ToolManifest.OutputSchema] before
+// it is yielded. A mismatch is a tool bug rather than bad LLM input, so the chunk is dropped and the
+// call fails with a [CodeInternal] error wrapping the violations. [NoResult] chunks, error chunks,
+// non-JSON chunks, and [WireJSONResult] results are not checked. Building a tool with this option
+// fails when it has no output schema; [NewStreamTool] and [NewProxyTool] need [WithOutputSchema].
+func WithOutputValidation() ToolOption {
+	return func(c *ToolConfig) {
+		c.ValidateOutput = true
+	}
+}
+
+var errOutputSchemaRequired = errors.New("toolsy: WithOutputValidation requires an output schema")
+
+type outputValidator struct {
+	schema   map[string]any
+	compiled schemaValidator
+}
+
+// newOutputValidator compiles the output schema of cfg when [WithOutputValidation] is set.
+// It returns nil when results are not validated.
+func newOutputValidator(cfg ToolConfig) (*outputValidator, error) {
+	if !cfg.ValidateOutput {
+		return nil, nil //nolint:nilnil // nil validator disables output validation
+	}
+	if len(cfg.Manifest.OutputSchema) == 0 {
+		return nil, errOutputSchemaRequired
+	}
+	schemaCopy, err := deepCopySchemaFromMap(cfg.Manifest.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+	stripSchemaIDs(schemaCopy)
+	compiled, err := compileRawSchema(schemaCopy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile output schema: %w", err)
+	}
+	return &outputValidator{schema: schemaCopy, compiled: compiled}, nil
+}
+
+// check returns an internal error when c is a JSON result that does not match the output schema.
+func (v *outputValidator) check(c Chunk) error {
+	if v == nil || c.Event != EventResult || c.IsError || c.MimeType != MimeTypeJSON || IsNoResult(c) {
+		return nil
+	}
+	if _, wire := c.TypedResult.(WireJSONResult); wire {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(c.Data, &decoded); err != nil {
+		return NewInternalError(fmt.Errorf("toolsy: result is not valid JSON: %w", err))
+	}
+	if err := validateAgainstSchema(v.compiled, v.schema, decoded, true); err != nil {
+		return NewInternalError(fmt.Errorf("toolsy: result does not match output schema: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputScore struct {
+	Score int `json:"score" minimum:"1"`
+}
+
+func executeCollect(t *testing.T, tool Tool, args string) ([]Chunk, error) {
+	t.Helper()
+	var chunks []Chunk
+	err := tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(args)}, func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	return chunks, err
+}
+
+func TestWithOutputValidation_TypedTool(t *testing.T) {
+	type args struct {
+		Score int `json:"score"`
+	}
+	tool, err := NewTool("score", "Score", func(_ context.Context, _ *RunEnv, a args) (outputScore, error) {
+		return outputScore{Score: a.Score}, nil
+	}, WithOutputValidation())
+	require.NoError(t, err)
+
+	schema, ok := tool.(ToolOutputSchema)
+	require.True(t, ok)
+	assert.Equal(t, "object", schema.OutputSchema()["type"])
+
+	chunks, err := executeCollect(t, tool, `{"score":3}`)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.JSONEq(t, `{"score":3}`, string(chunks[0].Data))
+
+	chunks, err = executeCollect(t, tool, `{"score":0}`)
+	require.Error(t, err)
+	assert.Empty(t, chunks, "invalid results must not be yielded")
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.NotErrorIs(t, err, ErrStreamAborted)
+	var inner *ToolError
+	require.ErrorAs(t, te.Err, &inner)
+	require.Len(t, inner.Violations, 1)
+	assert.Equal(t, "/score", inner.Violations[0].Path)
+}
+
+func TestWithOutputValidation_StreamToolRequiresSchema(t *testing.T) {
+	fn := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		if err := yield(Chunk{Event: EventProgress, Data: []byte("working"), MimeType: MimeTypeText}); err != nil {
+			return err
+		}
+		if err := yield(NoResult("nothing")); err != nil {
+			return err
+		}
+		return yield(Chunk{Event: EventResult, Data: []byte(`{"score":"high"}`), MimeType: MimeTypeJSON})
+	}
+	_, err := NewStreamTool("stream", "Stream", fn, WithOutputValidation())
+	require.ErrorIs(t, err, errOutputSchemaRequired)
+
+	tool, err := NewStreamTool("stream", "Stream", fn, WithOutputValidation(), WithOutputSchema(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"score": map[string]any{"type": "integer"}},
+	}))
+	require.NoError(t, err)
+	chunks, err := executeCollect(t, tool, `{}`)
+	te, ok := AsToolError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, CodeInternal, te.Code)
+	require.Len(t, chunks, 2, "progress and no-result chunks are not validated")
+	assert.True(t, IsNoResult(chunks[1]))
+}
+
+func TestDynamicToolSpec_OutputSchema(t *testing.T) {
+	result := `{"ok":true}`
+	tool, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:         "dyn",
+		Description:  "Dynamic",
+		Schema:       MapSchemaProvider{"type": "object"},
+		OutputSchema: map[string]any{"type": "object", "required": []any{"ok"}},
+		Handler: func(_ context.Context, _ *RunEnv, _ map[string]any, yield func(Chunk) error) error {
+			return yield(Chunk{Event: EventResult, Data: []byte(result), MimeType: MimeTypeJSON})
+		},
+		Options: []ToolOption{WithOutputValidation()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"ok"}, tool.Manifest().OutputSchema["required"])
+
+	_, err = executeCollect(t, tool, `{}`)
+	require.NoError(t, err)
+
+	result = `{}`
+	_, err = executeCollect(t, tool, `{}`)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+}
@@ -138,7 +138,21 @@ func (t *overriddenTool) Manifest() ToolManifest {
 		manifest.Description = *t.opts.description
 	}
 	if t.opts.parameters != nil {
-		manifest.Parameters = maps.Clone(t.opts.parameters)
+		manifest.Parameters = deepCopySchema(t.opts.parameters)
+	}
+	return manifest
+}
+
+func (t *overriddenTool) manifestView() ToolManifest {
+	manifest := toolManifest(t.next)
+	if t.opts.name != nil {
+		manifest.Name = *t.opts.name
+	}
+	if t.opts.description != nil {
+		manifest.Description = *t.opts.description
+	}
+	if t.opts.parameters != nil {
+		manifest.Parameters = t.opts.parameters
 	}
 	return manifest
 }
@@ -1,7 +1,9 @@
 package toolsy
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -21,6 +23,9 @@ type Decision struct {
 	FixableArgs []string
 	Retryable   bool
 	Err         error
+	// Args, when set on an allowing decision, replaces the call arguments before validation and
+	// execution ([AllowWithArgsDecision]). Registry-level policies only; other checks ignore it.
+	Args json.RawMessage
 }
 
 // AllowDecision explicitly permits a call.
@@ -33,6 +38,34 @@ func AllowDecision() Decision {
 		FixableArgs: nil,
 		Retryable:   false,
 		Err:         nil,
+		Args:        nil,
+	}
+}
+
+// AllowWithArgsDecision permits a call with rewritten arguments, for example to clamp a limit or
+// inject a tenant filter. The registry validates and executes args instead of the model's
+// arguments; args must be a JSON value.
+func AllowWithArgsDecision(args json.RawMessage) Decision {
+	d := AllowDecision()
+	d.Args = bytes.Clone(args)
+	return d
+}
+
+// ErrorDecision reports that the policy could not decide (for example its backing store failed).
+// The call fails closed with [CodeInternal], a system error rather than a denial the model could fix.
+func ErrorDecision(err error) Decision {
+	if err == nil {
+		err = errors.New("policy returned a nil error")
+	}
+	return Decision{
+		Allow:       false,
+		Code:        CodeInternal,
+		Reason:      "toolsy: policy evaluation failed: " + err.Error(),
+		SafeMessage: "",
+		FixableArgs: nil,
+		Retryable:   false,
+		Err:         err,
+		Args:        nil,
 	}
 }
 
@@ -46,6 +79,7 @@ func DenyDecision(reason string, fixableArgs ...string) Decision {
 		FixableArgs: append([]string(nil), fixableArgs...),
 		Retryable:   false,
 		Err:         ErrPolicyDenied,
+		Args:        nil,
 	}
 }
 
@@ -87,6 +121,7 @@ func NewPolicyDeniedError(reason string, fixableArgs ...string) *ToolError {
 		Retryable:   false,
 		FixableArgs: append([]string(nil), fixableArgs...),
 		SafeMessage: "",
+		Violations:  nil,
 		Err:         ErrPolicyDenied,
 	}
 }
@@ -102,6 +137,7 @@ func NewPolicyDeniedErrorFrom(err error) *ToolError {
 		Retryable:   false,
 		FixableArgs: nil,
 		SafeMessage: "",
+		Violations:  nil,
 		Err:         fmt.Errorf("%w: %w", ErrPolicyDenied, err),
 	}
 }
@@ -121,6 +157,7 @@ func NewCapabilityDeniedError(toolName string, view RegistryViewSnapshot) *ToolE
 		Retryable:   false,
 		FixableArgs: []string{"tool_name"},
 		SafeMessage: "",
+		Violations:  nil,
 		Err:         ErrCapabilityDenied,
 	}
 }
@@ -144,15 +181,30 @@ func decisionError(d Decision) error {
 		Retryable:   d.Retryable,
 		FixableArgs: append([]string(nil), d.FixableArgs...),
 		SafeMessage: d.SafeMessage,
+		Violations:  nil,
 		Err:         d.Err,
 	}
 }
 
 func evaluatePolicy(ctx context.Context, p Policy, req PolicyRequest) error {
+	_, err := evaluatePolicyArgs(ctx, p, req)
+	return err
+}
+
+// evaluatePolicyArgs is [evaluatePolicy] that also returns the arguments an allowing decision
+// rewrote, or nil when they are unchanged.
+func evaluatePolicyArgs(ctx context.Context, p Policy, req PolicyRequest) (json.RawMessage, error) {
 	if p == nil {
-		return nil
+		return nil, nil
 	}
-	return decisionError(p.Decide(ctx, req))
+	d := p.Decide(ctx, req)
+	if err := decisionError(d); err != nil {
+		return nil, err
+	}
+	if d.Args != nil && !json.Valid(d.Args) {
+		return nil, NewInternalError(errors.New("toolsy: policy rewrote arguments to invalid JSON"))
+	}
+	return d.Args, nil
 }
 
 type requirementsPolicyMarker interface {
@@ -173,7 +225,8 @@ type compositePolicy struct {
 }
 
 func (p compositePolicy) Decide(ctx context.Context, req PolicyRequest) Decision {
-	if err := evaluatePolicy(ctx, p.first, req); err != nil {
+	args, err := evaluatePolicyArgs(ctx, p.first, req)
+	if err != nil {
 		if te, ok := AsToolError(err); ok {
 			return Decision{
 				Allow:       false,
@@ -183,11 +236,20 @@ func (p compositePolicy) Decide(ctx context.Context, req PolicyRequest) Decision
 				FixableArgs: te.FixableArgs,
 				Retryable:   te.Retryable,
 				Err:         te.Err,
+				Args:        nil,
 			}
 		}
 		return DenyDecision(err.Error())
 	}
-	return p.second.Decide(ctx, req)
+	if args != nil {
+		// The second policy decides on, and may rewrite again, the arguments the first one produced.
+		req.Input.ArgsJSON = bytes.Clone(args)
+	}
+	d := p.second.Decide(ctx, req)
+	if d.Allow && d.Args == nil {
+		d.Args = args
+	}
+	return d
 }
 
 func (p compositePolicy) enforcesRequirements() bool {
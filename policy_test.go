@@ -0,0 +1,104 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchPolicyArgs struct {
+	Query  string `json:"query"`
+	Limit  int    `json:"limit"            maximum:"100"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+func newPolicySearchTool(t *testing.T, got *searchPolicyArgs) Tool {
+	t.Helper()
+	tool, err := NewTool("search", "Search", func(_ context.Context, _ *RunEnv, a searchPolicyArgs) (string, error) {
+		*got = a
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+// rewriteArgs returns a policy that applies edit to the decoded arguments.
+func rewriteArgs(edit func(map[string]any)) Policy {
+	return PolicyFunc(func(_ context.Context, req PolicyRequest) Decision {
+		var args map[string]any
+		if err := json.Unmarshal(req.Input.ArgsJSON, &args); err != nil {
+			return ErrorDecision(err)
+		}
+		edit(args)
+		out, err := json.Marshal(args)
+		if err != nil {
+			return ErrorDecision(err)
+		}
+		return AllowWithArgsDecision(out)
+	})
+}
+
+func policyCall(args string) ToolCall {
+	return ToolCall{ToolName: "search", Input: ToolInput{CallID: "c1", ArgsJSON: []byte(args)}}
+}
+
+func TestPolicy_AllowWithArgsRewritesBeforeValidation(t *testing.T) {
+	var got searchPolicyArgs
+	clamp := rewriteArgs(func(a map[string]any) {
+		if limit, ok := a["limit"].(float64); ok && limit > 10 {
+			a["limit"] = 10
+		}
+	})
+	tenant := rewriteArgs(func(a map[string]any) {
+		assert.InDelta(t, 10, a["limit"], 0, "later policies see earlier rewrites")
+		a["tenant"] = "acme"
+	})
+	reg := mustBuildRegistry(t, []Tool{newPolicySearchTool(t, &got)},
+		WithPolicy("clamp", clamp), WithPolicy("tenant", tenant))
+
+	call := policyCall(`{"query":"q","limit":500}`)
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.Equal(t, searchPolicyArgs{Query: "q", Limit: 10, Tenant: "acme"}, got)
+	assert.JSONEq(t, `{"query":"q","limit":500}`, string(call.Input.ArgsJSON), "caller's args are not modified")
+}
+
+func TestPolicy_RewrittenArgsAreValidated(t *testing.T) {
+	var got searchPolicyArgs
+	broken := rewriteArgs(func(a map[string]any) { a["limit"] = 1000 })
+	reg := mustBuildRegistry(t, []Tool{newPolicySearchTool(t, &got)}, WithPolicy("broken", broken))
+	err := reg.Execute(context.Background(), policyCall(`{"query":"q","limit":5}`), func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrValidation)
+	assert.Zero(t, got)
+
+	invalid := PolicyFunc(func(context.Context, PolicyRequest) Decision {
+		return AllowWithArgsDecision(json.RawMessage(`{"query":`))
+	})
+	reg = mustBuildRegistry(t, []Tool{newPolicySearchTool(t, &got)}, WithPolicy("invalid", invalid))
+	err = reg.Execute(context.Background(), policyCall(`{"query":"q","limit":5}`), func(Chunk) error { return nil })
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err))
+}
+
+func TestPolicy_DenyAndErrorDecisions(t *testing.T) {
+	var got searchPolicyArgs
+	deny := PolicyFunc(func(context.Context, PolicyRequest) Decision { return DenyDecision("limit too high", "limit") })
+	reg := mustBuildRegistry(t, []Tool{newPolicySearchTool(t, &got)}, WithPolicy("deny", deny))
+	err := reg.Execute(context.Background(), policyCall(`{"query":"q","limit":5}`), func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrPolicyDenied)
+	assert.Equal(t, FinishClientError, FinishReasonOf(err))
+	assert.ErrorContains(t, err, "limit too high")
+
+	storeDown := errors.New("policy store unavailable")
+	failing := PolicyFunc(func(context.Context, PolicyRequest) Decision { return ErrorDecision(storeDown) })
+	reg = mustBuildRegistry(t, []Tool{newPolicySearchTool(t, &got)}, WithPolicy("failing", failing))
+	err = reg.Execute(context.Background(), policyCall(`{"query":"q","limit":5}`), func(Chunk) error { return nil })
+	require.ErrorIs(t, err, storeDown)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err))
+	assert.Zero(t, got)
+}
@@ -69,7 +69,16 @@ func NewPolicyTool[TSubject, TScope, TArgs any](spec ToolPolicySpec[TSubject, TS
 	if hasRequirements(spec.Requirements) {
 		manifest.Requirements = cloneRequirements(spec.Requirements)
 	}
-	cfg := ensureSchemaConfig(SchemaConfig{Strict: false, Registry: nil})
+	cfg := ensureSchemaConfig(SchemaConfig{
+		Strict:              false,
+		Registry:            nil,
+		AllValidationErrors: false,
+		Transforms:          nil,
+		Nullable:            NullableTypeArray,
+		OptionalAsNullable:  false,
+		PreserveUnions:      false,
+		CloseFreeform:       false,
+	})
 	ext, err := NewExtractorWithConfig[TArgs](cfg)
 	if err != nil {
 		return nil, err
@@ -113,7 +122,8 @@ func NewPolicyTool[TSubject, TScope, TArgs any](spec ToolPolicySpec[TSubject, TS
 			return yield(applyPolicyToolEnvelope(c, spec.DeliveryClass, spec.Audience, spec.EnvelopeMetadata))
 		})
 	}
-	return &tool{manifest: manifest, execute: execute}, nil
+	// The args binder needs the run context, so [Registry.ValidateCall] cannot pre-validate.
+	return &tool{manifest: manifest, execute: execute, validate: nil}, nil
 }
 
 func applyPolicyToolEnvelope(
@@ -0,0 +1,132 @@
+package toolsy
+
+import "time"
+
+// Defaults applied by the built-in profiles.
+const (
+	productionMaxInFlight      = 256
+	productionShedThreshold    = 0.9
+	productionMaxMetadataBytes = 64 << 10
+	productionWatchdogInterval = 30 * time.Second
+
+	// openAIMaxTools is the number of functions the OpenAI chat completions API accepts per request.
+	openAIMaxTools = 128
+)
+
+// ProfileSettingKind tells whether a [ProfileSetting] configures the registry or the tools built for it.
+type ProfileSettingKind string
+
+const (
+	ProfileSettingRegistry ProfileSettingKind = "registry"
+	ProfileSettingTool     ProfileSettingKind = "tool"
+)
+
+// ProfileSetting is one option of a [Profile] together with a human-readable description
+// (for example "WithRecoverPanics(true)") used when auditing effective configuration.
+type ProfileSetting struct {
+	Kind        ProfileSettingKind
+	Description string
+	registry    RegistryOption
+	tool        ToolOption
+}
+
+// RegistrySetting wraps a registry option for a [Profile].
+func RegistrySetting(description string, opt RegistryOption) ProfileSetting {
+	return ProfileSetting{Kind: ProfileSettingRegistry, Description: description, registry: opt, tool: nil}
+}
+
+// ToolSetting wraps a default tool option for a [Profile]; see [Profile.ToolOptions].
+func ToolSetting(description string, opt ToolOption) ProfileSetting {
+	return ProfileSetting{Kind: ProfileSettingTool, Description: description, registry: nil, tool: opt}
+}
+
+// String returns "<kind>: <description>".
+func (s ProfileSetting) String() string {
+	return string(s.Kind) + ": " + s.Description
+}
+
+// Profile is a named, immutable bundle of registry options and default tool options, so services
+// share one audited preset instead of copying option lists. Settings apply in order; later ones win.
+type Profile struct {
+	Name     string
+	settings []ProfileSetting
+}
+
+// NewProfile creates a profile from settings.
+func NewProfile(name string, settings ...ProfileSetting) Profile {
+	return Profile{Name: name, settings: append([]ProfileSetting(nil), settings...)}
+}
+
+// With returns a copy of p with settings appended. Compose profiles with p.With(other.Settings()...).
+func (p Profile) With(settings ...ProfileSetting) Profile {
+	out := make([]ProfileSetting, 0, len(p.settings)+len(settings))
+	out = append(out, p.settings...)
+	out = append(out, settings...)
+	return Profile{Name: p.Name, settings: out}
+}
+
+// Settings returns a copy of every setting in application order, for introspection and composition.
+func (p Profile) Settings() []ProfileSetting {
+	return append([]ProfileSetting(nil), p.settings...)
+}
+
+// RegistryOptions returns the registry options of p in order.
+func (p Profile) RegistryOptions() []RegistryOption {
+	var out []RegistryOption
+	for _, s := range p.settings {
+		if s.registry != nil {
+			out = append(out, s.registry)
+		}
+	}
+	return out
+}
+
+// ToolOptions returns the default tool options of p in order. Tools are built before the registry,
+// so pass them explicitly: NewTool(name, desc, fn, p.ToolOptions()...).
+func (p Profile) ToolOptions() []ToolOption {
+	var out []ToolOption
+	for _, s := range p.settings {
+		if s.tool != nil {
+			out = append(out, s.tool)
+		}
+	}
+	return out
+}
+
+// NewRegistryFromProfile returns a [RegistryBuilder] configured with the registry options of p
+// followed by overrides, which therefore take precedence.
+func NewRegistryFromProfile(p Profile, overrides ...RegistryOption) *RegistryBuilder {
+	return NewRegistryBuilder(append(p.RegistryOptions(), overrides...)...)
+}
+
+// ProfileProduction recovers panics, sheds load above 90% of 256 in-flight executions, caps chunk
+// metadata at 64 KiB, and tracks abandoned executions with a 30s watchdog. The core has no timeouts
+// or retries to configure; wrap the registry for those (see the README's zero-resiliency notes).
+func ProfileProduction() Profile {
+	return NewProfile("production",
+		RegistrySetting("WithRecoverPanics(true)", WithRecoverPanics(true)),
+		RegistrySetting("WithLoadShedding(256, 0.9)",
+			WithLoadShedding(productionMaxInFlight, productionShedThreshold)),
+		RegistrySetting("WithMaxMetadataBytes(65536)", WithMaxMetadataBytes(productionMaxMetadataBytes)),
+		RegistrySetting("WithExecutionWatchdog(30s, nil)", WithExecutionWatchdog(productionWatchdogInterval, nil)),
+	)
+}
+
+// ProfileStrictOpenAI builds tools with strict schemas for OpenAI Structured Outputs and rejects
+// registries larger than the 128 functions OpenAI accepts per request.
+func ProfileStrictOpenAI() Profile {
+	return NewProfile("strict-openai",
+		ToolSetting("WithStrict()", WithStrict()),
+		RegistrySetting("WithMaxTools(128)", WithMaxTools(openAIMaxTools)),
+	)
+}
+
+// ProfileDev lets panics crash the caller, warns about reused chunk buffers via [slog.Default],
+// and reports every schema violation of a call, trading throughput for diagnostics.
+func ProfileDev() Profile {
+	return NewProfile("dev",
+		RegistrySetting("WithRecoverPanics(false)", WithRecoverPanics(false)),
+		RegistrySetting("WithStrictChunkOwnership(slog.Default())", WithStrictChunkOwnership(nil)),
+		ToolSetting("WithAllValidationErrors()", WithAllValidationErrors()),
+	)
+}
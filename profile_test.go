@@ -0,0 +1,139 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func profileDescriptions(p Profile) []string {
+	var out []string
+	for _, s := range p.Settings() {
+		out = append(out, s.String())
+	}
+	return out
+}
+
+func panickingTool(name string) Tool {
+	return newMiddlewareMinTool(name, func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error {
+		panic("boom")
+	})
+}
+
+func TestProfileProduction_Effects(t *testing.T) {
+	p := ProfileProduction()
+	assert.Equal(t, "production", p.Name)
+	assert.Equal(t, []string{
+		"registry: WithRecoverPanics(true)",
+		"registry: WithLoadShedding(256, 0.9)",
+		"registry: WithMaxMetadataBytes(65536)",
+		"registry: WithExecutionWatchdog(30s, nil)",
+	}, profileDescriptions(p))
+	assert.Empty(t, p.ToolOptions())
+
+	reg, err := NewRegistryFromProfile(p).Add(
+		panickingTool("panic"),
+		metadataTool("meta", map[string]any{"doc": strings.Repeat("x", 65<<10)}),
+	).Build()
+	require.NoError(t, err)
+
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "panic"}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "meta"}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrMetadataTooLarge)
+
+	used, limit, ok := reg.Capacity()
+	assert.True(t, ok)
+	assert.Equal(t, 0, used)
+	assert.Equal(t, 256, limit)
+	assert.InDelta(t, 0.9, reg.opts.shedThreshold, 1e-9)
+	require.NotNil(t, reg.state.watchdog)
+	assert.Equal(t, 30*time.Second, reg.state.watchdog.interval)
+}
+
+func TestProfileStrictOpenAI_Effects(t *testing.T) {
+	p := ProfileStrictOpenAI()
+	assert.Equal(t, []string{"tool: WithStrict()", "registry: WithMaxTools(128)"}, profileDescriptions(p))
+
+	type args struct {
+		Query string `json:"query,omitempty"`
+	}
+	tool, err := NewTool("search", "Search", func(_ context.Context, _ *RunEnv, _ args) (string, error) {
+		return "", nil
+	}, p.ToolOptions()...)
+	require.NoError(t, err)
+	assert.True(t, tool.Manifest().Strict)
+	assert.Equal(t, false, tool.Manifest().Parameters["additionalProperties"])
+	assert.Equal(t, []any{"query"}, tool.Manifest().Parameters["required"])
+
+	b := NewRegistryFromProfile(p)
+	assert.Equal(t, 128, b.Remaining())
+	for i := range 129 {
+		b.Add(mustNamedTool(t, fmt.Sprintf("t%d", i)))
+	}
+	_, err = b.Build()
+	require.ErrorIs(t, err, ErrTooManyTools)
+}
+
+func TestProfileDev_Effects(t *testing.T) {
+	p := ProfileDev()
+	assert.Equal(t, []string{
+		"registry: WithRecoverPanics(false)",
+		"registry: WithStrictChunkOwnership(slog.Default())",
+		"tool: WithAllValidationErrors()",
+	}, profileDescriptions(p))
+
+	reg, err := NewRegistryFromProfile(p).Add(panickingTool("panic")).Build()
+	require.NoError(t, err)
+	assert.NotNil(t, reg.opts.ownershipLogger)
+	assert.Panics(t, func() {
+		_ = reg.Execute(context.Background(), ToolCall{ToolName: "panic"}, func(Chunk) error { return nil })
+	})
+
+	type args struct {
+		A string `json:"a"`
+		B int    `json:"b"`
+	}
+	tool, err := NewTool("two", "Two", func(_ context.Context, _ *RunEnv, _ args) (string, error) {
+		return "", nil
+	}, p.ToolOptions()...)
+	require.NoError(t, err)
+	reg = mustBuildRegistry(t, []Tool{tool}, p.RegistryOptions()...)
+	err = reg.Execute(context.Background(), ToolCall{
+		ToolName: "two",
+		Input:    ToolInput{ArgsJSON: []byte(`{}`)},
+	}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Len(t, te.Violations, 2)
+}
+
+func TestProfile_WithAndOverrides(t *testing.T) {
+	base := ProfileDev()
+	composed := base.With(ProfileStrictOpenAI().Settings()...)
+	assert.Equal(t, "dev", composed.Name)
+	assert.Len(t, composed.Settings(), 5)
+	assert.Len(t, base.Settings(), 3, "With must not modify the receiver")
+	assert.Len(t, composed.ToolOptions(), 2)
+
+	reg, err := NewRegistryFromProfile(composed, WithRecoverPanics(true)).Add(panickingTool("panic")).Build()
+	require.NoError(t, err)
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "panic"}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.Equal(t, 128, NewRegistryFromProfile(composed).Remaining())
+
+	custom := NewProfile("custom", RegistrySetting("WithMaxTools(1)", WithMaxTools(1)))
+	assert.Equal(t, []string{"registry: WithMaxTools(1)"}, profileDescriptions(custom))
+	_, err = NewRegistryFromProfile(custom).Add(mustNamedTool(t, "a"), mustNamedTool(t, "b")).Build()
+	require.ErrorIs(t, err, ErrTooManyTools)
+}
@@ -0,0 +1,58 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressChunk builds an [EventProgress] chunk with [ProgressInfo.Percent] clamped to 0..100 and
+// the given message. Streaming handlers yield it directly; other handlers use [ReportProgress].
+func ProgressChunk(percent int, message string) Chunk {
+	percent = min(max(percent, 0), 100)
+	return Chunk{ //nolint:exhaustruct // CallID/ToolName are filled by the registry; progress carries no data
+		Event: EventProgress,
+		Progress: &ProgressInfo{ //nolint:exhaustruct // total/label/status/token are for richer producers
+			Percent: &percent,
+			Message: message,
+		},
+	}
+}
+
+type progressReporterKey struct{}
+
+// progressReporter forwards [ReportProgress] calls to the yield of the running call.
+// It is closed when the tool returns so late reports never reach a finished stream.
+type progressReporter struct {
+	mu     sync.Mutex
+	yield  func(Chunk) error
+	closed bool
+}
+
+func withProgressReporter(ctx context.Context, yield func(Chunk) error) (context.Context, func()) {
+	p := &progressReporter{mu: sync.Mutex{}, yield: yield, closed: false}
+	return context.WithValue(ctx, progressReporterKey{}, p), func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+	}
+}
+
+// ReportProgress emits a [ProgressChunk] from any handler running inside a [Registry] call, including
+// plain [NewTool] functions. Progress chunks are counted in [ExecutionSummary.ProgressChunks] rather
+// than ChunksDelivered/TotalBytes and are seen by [WithOnChunk] hooks. Reports are serialized with each
+// other but not with the handler's own yield. It is a no-op outside a registry call, after the tool
+// returned, or once the consumer rejected a chunk.
+func ReportProgress(ctx context.Context, percent int, message string) {
+	p, ok := ctx.Value(progressReporterKey{}).(*progressReporter)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	if err := p.yield(ProgressChunk(percent, message)); err != nil {
+		p.closed = true
+	}
+}
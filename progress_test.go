@@ -0,0 +1,83 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportProgress_FromNewTool(t *testing.T) {
+	t.Parallel()
+	tool, err := NewTool("import", "Import rows", func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		ReportProgress(ctx, -5, "starting")
+		ReportProgress(ctx, 50, "halfway")
+		ReportProgress(ctx, 150, "done")
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	var hooked int
+	var summary ExecutionSummary
+	reg, err := NewRegistryBuilder(
+		WithOnChunk(func(_ context.Context, c Chunk) {
+			if c.Event == EventProgress {
+				hooked++
+			}
+		}),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) { summary = s }),
+	).Add(tool).Build()
+	require.NoError(t, err)
+
+	var chunks []Chunk
+	call := ToolCall{ToolName: "import", Input: ToolInput{CallID: "c1", ArgsJSON: []byte(`{}`)}}
+	err = reg.Execute(context.Background(), call, func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+	var percents []int
+	for _, c := range chunks[:3] {
+		require.Equal(t, EventProgress, c.Event)
+		require.Equal(t, "c1", c.CallID)
+		percents = append(percents, *c.Progress.Percent)
+	}
+	assert.Equal(t, []int{0, 50, 100}, percents)
+	assert.Equal(t, "halfway", chunks[1].Progress.Message)
+	assert.Equal(t, EventResult, chunks[3].Event)
+
+	assert.Equal(t, 3, hooked, "WithOnChunk sees progress chunks")
+	assert.Equal(t, 3, summary.ProgressChunks)
+	assert.Equal(t, 1, summary.ChunksDelivered)
+	assert.Equal(t, int64(len(`"ok"`)), summary.TotalBytes)
+}
+
+func TestReportProgress_NoopOutsideRegistryAndAfterAbort(t *testing.T) {
+	t.Parallel()
+	ReportProgress(context.Background(), 10, "ignored")
+
+	var reports int
+	tool, err := NewTool("slow", "Slow", func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		for i := range 3 {
+			ReportProgress(ctx, i*50, "")
+		}
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Add(tool).Build()
+	require.NoError(t, err)
+	stop := errors.New("consumer closed")
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "slow", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(c Chunk) error {
+			if c.Event == EventProgress {
+				reports++
+			}
+			return stop
+		})
+	require.Error(t, err)
+	assert.Equal(t, 1, reports, "a rejected progress chunk closes the reporter")
+}
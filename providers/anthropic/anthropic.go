@@ -0,0 +1,130 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/internal/providerutil"
+)
+
+// BlockTypeToolUse is the content block type accepted by [ToToolCall].
+const BlockTypeToolUse = "tool_use"
+
+// ErrInvalidToolName is returned when a tool name does not match Anthropic's ^[a-zA-Z0-9_-]{1,64}$.
+var ErrInvalidToolName = errors.New("anthropic: invalid tool name")
+
+// Tool is one entry of the Messages API "tools" array.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// ToolUse is an assistant "tool_use" content block. Input is the decoded JSON object
+// (for example map[string]any or json.RawMessage).
+type ToolUse struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Input any    `json:"input"`
+}
+
+// ToTools converts tools (for example reg.GetVisibleTools()) to Anthropic tool definitions, preserving order.
+// Tools marked [toolsy.WithHidden] are skipped.
+func ToTools(tools []toolsy.Tool) ([]Tool, error) {
+	out := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		if t == nil {
+			return nil, errors.New("anthropic: nil tool")
+		}
+		m := t.Manifest()
+		if m.Hidden {
+			continue
+		}
+		def, err := ToTool(m)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, def)
+	}
+	return out, nil
+}
+
+// ToTool converts one manifest. The input schema is deep-copied, so callers (and SDKs) may mutate it
+// without touching the tool; an empty schema becomes an empty object schema. The description is
+// [toolsy.ModelDescription], so deprecated tools carry their notice.
+func ToTool(m toolsy.ToolManifest) (Tool, error) {
+	if err := providerutil.CheckToolName(m.Name, ErrInvalidToolName); err != nil {
+		return Tool{}, err
+	}
+	return Tool{
+		Name:        m.Name,
+		Description: toolsy.ModelDescription(m),
+		InputSchema: providerutil.ObjectSchema(m.Parameters),
+	}, nil
+}
+
+// ToToolCall converts a "tool_use" block into a [toolsy.ToolCall], re-marshaling Input to JSON.
+// A nil Input becomes "{}".
+func ToToolCall(block ToolUse) (toolsy.ToolCall, error) {
+	if block.Type != "" && block.Type != BlockTypeToolUse {
+		return toolsy.ToolCall{}, fmt.Errorf("anthropic: unsupported content block type %q", block.Type)
+	}
+	if block.Name == "" {
+		return toolsy.ToolCall{}, errors.New("anthropic: tool_use name is required")
+	}
+	args := json.RawMessage("{}")
+	if block.Input != nil {
+		raw, err := json.Marshal(block.Input)
+		if err != nil {
+			return toolsy.ToolCall{}, fmt.Errorf("anthropic: marshal tool_use input: %w", err)
+		}
+		args = raw
+	}
+	return toolsy.ToolCall{ //nolint:exhaustruct // Env, CallContext, and Metadata are host-owned
+		ToolName: block.Name,
+		Input: toolsy.ToolInput{ //nolint:exhaustruct // no attachments on tool_use blocks
+			CallID:   block.ID,
+			ArgsJSON: args,
+		},
+	}, nil
+}
+
+// ToToolCalls converts "tool_use" blocks, failing on the first invalid entry.
+func ToToolCalls(blocks []ToolUse) ([]toolsy.ToolCall, error) {
+	out := make([]toolsy.ToolCall, 0, len(blocks))
+	for i, block := range blocks {
+		call, err := ToToolCall(block)
+		if err != nil {
+			return nil, fmt.Errorf("anthropic: content[%d]: %w", i, err)
+		}
+		out = append(out, call)
+	}
+	return out, nil
+}
+
+// BlockTypeToolResult is the content block type produced by [ToToolResult].
+const BlockTypeToolResult = "tool_result"
+
+// ToolResult is a user "tool_result" content block answering one "tool_use" block.
+type ToolResult struct {
+	Type      string `json:"type"`
+	ToolUseID string `json:"tool_use_id"`
+	Content   string `json:"content"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// ResultOptions configures [ToToolResult].
+type ResultOptions struct {
+	// NoResultText renders [toolsy.NoResult] chunks as "no results: <reason>" instead of their JSON envelope.
+	NoResultText bool
+}
+
+// ToToolResult converts the result chunk of a call (for example the last chunk yielded by Execute)
+// into a "tool_result" block. ToolUseID is the chunk's CallID; soft error chunks set IsError.
+func ToToolResult(c toolsy.Chunk, opts ResultOptions) ToolResult {
+	content := providerutil.ResultText(c, opts.NoResultText)
+	return ToolResult{Type: BlockTypeToolResult, ToolUseID: c.CallID, Content: content, IsError: c.IsError}
+}
@@ -0,0 +1,115 @@
+package anthropic_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/providers/anthropic"
+)
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type shipArgs struct {
+	Item    string  `json:"item"`
+	Address address `json:"address"`
+}
+
+func newShipTool(t *testing.T) toolsy.Tool {
+	t.Helper()
+	tool, err := toolsy.NewTool("ship", "Ship an item", func(_ context.Context, _ *toolsy.RunEnv, a shipArgs) (string, error) {
+		return a.Item + " to " + a.Address.City, nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestToTools_NestedSchemaIsDeepCopied(t *testing.T) {
+	tool := newShipTool(t)
+	before, err := json.Marshal(tool.Manifest().Parameters)
+	require.NoError(t, err)
+
+	out, err := anthropic.ToTools([]toolsy.Tool{tool})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, "ship", out[0].Name)
+	assert.Equal(t, "Ship an item", out[0].Description)
+
+	exported, err := json.Marshal(out[0].InputSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(exported))
+
+	props := out[0].InputSchema["properties"].(map[string]any)
+	nested := props["address"].(map[string]any)
+	nested["properties"].(map[string]any)["city"].(map[string]any)["type"] = "integer"
+	delete(props, "item")
+
+	after, err := json.Marshal(tool.Manifest().Parameters)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(after))
+}
+
+func TestToTool_EmptySchemaAndNames(t *testing.T) {
+	def, err := anthropic.ToTool(toolsy.ToolManifest{Name: "ping"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, def.InputSchema)
+
+	_, err = anthropic.ToTool(toolsy.ToolManifest{Name: strings.Repeat("x", 65)})
+	require.ErrorIs(t, err, anthropic.ErrInvalidToolName)
+}
+
+func TestToToolCalls_RoundTripThroughRegistry(t *testing.T) {
+	var blocks []anthropic.ToolUse
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type":"tool_use","id":"toolu_1","name":"ship","input":{"item":"book","address":{"city":"Oslo"}}},
+		{"type":"tool_use","id":"toolu_2","name":"ship"}
+	]`), &blocks))
+
+	calls, err := anthropic.ToToolCalls(blocks)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "toolu_1", calls[0].Input.CallID)
+	assert.JSONEq(t, `{"item":"book","address":{"city":"Oslo"}}`, string(calls[0].Input.ArgsJSON))
+	assert.JSONEq(t, `{}`, string(calls[1].Input.ArgsJSON))
+
+	reg, err := toolsy.NewRegistry(newShipTool(t))
+	require.NoError(t, err)
+	var got string
+	require.NoError(t, reg.Execute(context.Background(), calls[0], func(c toolsy.Chunk) error {
+		return json.Unmarshal(c.Data, &got)
+	}))
+	assert.Equal(t, "book to Oslo", got)
+}
+
+func TestToToolCall_RejectsOtherBlocks(t *testing.T) {
+	_, err := anthropic.ToToolCall(anthropic.ToolUse{Type: "text", Name: "ship"})
+	require.Error(t, err)
+	_, err = anthropic.ToToolCalls([]anthropic.ToolUse{{Type: "tool_use", ID: "x"}})
+	require.ErrorContains(t, err, "content[0]")
+}
+
+func TestToToolResult_NoResult(t *testing.T) {
+	c := toolsy.NoResult("")
+	c.CallID = "toolu_1"
+
+	raw := anthropic.ToToolResult(c, anthropic.ResultOptions{NoResultText: false})
+	assert.JSONEq(t, `{"noResult":true}`, raw.Content)
+
+	text := anthropic.ToToolResult(c, anthropic.ResultOptions{NoResultText: true})
+	data, err := json.Marshal(text)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"tool_result","tool_use_id":"toolu_1","content":"no results"}`, string(data))
+
+	failed := anthropic.ToToolResult(toolsy.Chunk{CallID: "toolu_2", IsError: true, Data: []byte(`{"code":"internal"}`)},
+		anthropic.ResultOptions{NoResultText: true})
+	assert.True(t, failed.IsError)
+	assert.JSONEq(t, `{"code":"internal"}`, failed.Content)
+}
@@ -0,0 +1,5 @@
+// Package anthropic converts toolsy tools to Anthropic Messages API tool definitions
+// (name, description, input_schema) and converts "tool_use" content blocks back into [toolsy.ToolCall].
+//
+// Like providers/openai, the package uses plain structs instead of an SDK dependency.
+package anthropic
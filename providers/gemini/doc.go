@@ -0,0 +1,8 @@
+// Package gemini converts toolsy tools to Gemini function declarations.
+//
+// Gemini accepts a restricted OpenAPI-style schema: no $defs or $ref, no additionalProperties,
+// string-only enums, and a short list of format values. [ToDeclarations] inlines local $ref
+// targets, translates what has an equivalent (nullable type unions and "nullable": true, const, oneOf), and reports
+// everything else. The [Schema] struct mirrors the genai JSON shape, so the output can be
+// marshaled and decoded straight into SDK types.
+package gemini
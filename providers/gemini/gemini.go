@@ -0,0 +1,433 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// Gemini schema type names.
+const (
+	TypeString  = "STRING"
+	TypeNumber  = "NUMBER"
+	TypeInteger = "INTEGER"
+	TypeBoolean = "BOOLEAN"
+	TypeArray   = "ARRAY"
+	TypeObject  = "OBJECT"
+)
+
+// ErrInvalidToolName is returned when a tool name is not a valid Gemini function name.
+var ErrInvalidToolName = errors.New("gemini: invalid tool name")
+
+// ErrUnsupportedSchema is returned in strict mode when a schema uses constructs Gemini cannot represent.
+var ErrUnsupportedSchema = errors.New("gemini: unsupported schema constructs")
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.:-]{0,63}$`)
+
+// Options configures [ToDeclarations].
+type Options struct {
+	// BestEffort drops unsupported keywords (and properties whose type cannot be represented)
+	// instead of failing with [ErrUnsupportedSchema].
+	BestEffort bool
+}
+
+// FunctionDeclaration is one entry of a Gemini tool's "functionDeclarations" array.
+type FunctionDeclaration struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Parameters  *Schema `json:"parameters,omitempty"`
+}
+
+// Schema is the subset of the Gemini (OpenAPI 3.0) schema object produced by this package.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinItems    *int64             `json:"minItems,omitempty"`
+	MaxItems    *int64             `json:"maxItems,omitempty"`
+	MinLength   *int64             `json:"minLength,omitempty"`
+	MaxLength   *int64             `json:"maxLength,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+}
+
+// ToDeclarations converts tools (for example reg.GetVisibleTools()) to Gemini function
+// declarations, preserving order and skipping tools marked [toolsy.WithHidden]. In strict mode (the
+// default) the error lists every unsupported construct by path.
+func ToDeclarations(tools []toolsy.Tool, opts Options) ([]FunctionDeclaration, error) {
+	out := make([]FunctionDeclaration, 0, len(tools))
+	for _, t := range tools {
+		if t == nil {
+			return nil, errors.New("gemini: nil tool")
+		}
+		m := t.Manifest()
+		if m.Hidden {
+			continue
+		}
+		decl, err := ToDeclaration(m, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, decl)
+	}
+	return out, nil
+}
+
+// ToDeclaration converts one manifest. A schema without properties yields a declaration without
+// parameters, because Gemini rejects empty OBJECT schemas. The description is
+// [toolsy.ModelDescription], so deprecated tools carry their notice.
+func ToDeclaration(m toolsy.ToolManifest, opts Options) (FunctionDeclaration, error) {
+	if !toolNamePattern.MatchString(m.Name) {
+		return FunctionDeclaration{}, fmt.Errorf("%w %q: must match %s", ErrInvalidToolName, m.Name, toolNamePattern)
+	}
+	var params *Schema
+	c := &converter{root: m.Parameters, issues: nil, resolving: map[string]bool{}}
+	if len(m.Parameters) > 0 {
+		params = c.convert(m.Parameters, "")
+	}
+	if len(c.issues) > 0 && !opts.BestEffort {
+		return FunctionDeclaration{}, fmt.Errorf("%w in tool %q: %s", ErrUnsupportedSchema, m.Name,
+			strings.Join(c.issues, "; "))
+	}
+	if params != nil && len(params.Properties) == 0 {
+		params = nil
+	}
+	return FunctionDeclaration{
+		Name:        m.Name,
+		Description: toolsy.ModelDescription(m),
+		Parameters:  params,
+	}, nil
+}
+
+// ignoredKeywords carry no validation meaning for the model and are dropped silently.
+var ignoredKeywords = map[string]bool{ //nolint:gochecknoglobals // read-only lookup table
+	"$schema": true, "$id": true, "$comment": true, "$defs": true, "definitions": true,
+	"examples": true, "default": true, "readOnly": true, "writeOnly": true, "deprecated": true,
+	"additionalProperties": true, // handled in convertObject
+}
+
+// supportedFormats lists the format values Gemini accepts per type.
+var supportedFormats = map[string][]string{ //nolint:gochecknoglobals // read-only lookup table
+	TypeString:  {"enum", "date-time"},
+	TypeInteger: {"int32", "int64"},
+	TypeNumber:  {"float", "double"},
+}
+
+type converter struct {
+	root      map[string]any
+	issues    []string
+	resolving map[string]bool
+}
+
+func (c *converter) report(path, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+	c.issues = append(c.issues, path+": "+fmt.Sprintf(format, args...))
+}
+
+// convert returns nil when the node cannot be represented at all.
+func (c *converter) convert(node map[string]any, path string) *Schema {
+	if node == nil {
+		c.report(path, "schema must be an object")
+		return nil
+	}
+	if ref, ok := node["$ref"].(string); ok {
+		return c.convertRef(ref, node, path)
+	}
+	out := &Schema{} //nolint:exhaustruct // fields are filled from the JSON Schema keywords below
+	handled := map[string]bool{}
+	c.convertType(node, out, path, handled)
+	if out.Type == "" {
+		if _, hasProps := node["properties"]; hasProps {
+			out.Type = TypeObject
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		if handled[key] || ignoredKeywords[key] {
+			continue
+		}
+		if !c.convertKeyword(key, node[key], out, joinPath(path, key)) {
+			c.report(joinPath(path, key), "keyword not supported")
+		}
+	}
+	if out.Type == TypeObject && !c.convertObject(node, out, path) {
+		return nil
+	}
+	if out.Type == "" && len(out.AnyOf) == 0 {
+		c.report(path, "schema has no representable type")
+		return nil
+	}
+	return out
+}
+
+func (c *converter) convertRef(ref string, node map[string]any, path string) *Schema {
+	target, ok := c.lookupRef(ref)
+	if !ok {
+		c.report(path, "unresolvable $ref %q", ref)
+		return nil
+	}
+	if c.resolving[ref] {
+		c.report(path, "recursive $ref %q cannot be inlined", ref)
+		return nil
+	}
+	c.resolving[ref] = true
+	out := c.convert(target, path)
+	delete(c.resolving, ref)
+	if out != nil {
+		if desc, ok := node["description"].(string); ok && desc != "" {
+			out.Description = desc
+		}
+	}
+	return out
+}
+
+func (c *converter) lookupRef(ref string) (map[string]any, bool) {
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		name, ok := strings.CutPrefix(ref, prefix)
+		if !ok {
+			continue
+		}
+		defs, _ := c.root[strings.TrimSuffix(strings.TrimPrefix(prefix, "#/"), "/")].(map[string]any)
+		target, ok := defs[name].(map[string]any)
+		return target, ok
+	}
+	return nil, false
+}
+
+// convertType maps "type" (including ["null", T] unions), const, enum, and format.
+func (c *converter) convertType(node map[string]any, out *Schema, path string, handled map[string]bool) {
+	handled["type"], handled["const"], handled["enum"], handled["format"] = true, true, true, true
+	switch typ := node["type"].(type) {
+	case string:
+		out.Type = geminiType(typ)
+		if out.Type == "" {
+			c.report(joinPath(path, "type"), "type %q not supported", typ)
+		}
+	case []any:
+		var nonNull []string
+		for _, v := range typ {
+			s, _ := v.(string)
+			if s == "null" {
+				out.Nullable = true
+				continue
+			}
+			nonNull = append(nonNull, s)
+		}
+		if len(nonNull) == 1 && geminiType(nonNull[0]) != "" {
+			out.Type = geminiType(nonNull[0])
+		} else {
+			c.report(joinPath(path, "type"), "type union %v not supported", typ)
+		}
+	case nil:
+	default:
+		c.report(joinPath(path, "type"), "malformed type")
+	}
+	c.convertEnum(node, out, path)
+	if format, ok := node["format"].(string); ok {
+		if slices.Contains(supportedFormats[out.Type], format) {
+			out.Format = format
+		} else {
+			c.report(joinPath(path, "format"), "format %q not supported", format)
+		}
+	}
+}
+
+func (c *converter) convertEnum(node map[string]any, out *Schema, path string) {
+	values, hasEnum := node["enum"].([]any)
+	if constVal, ok := node["const"]; ok {
+		values, hasEnum = []any{constVal}, true
+	}
+	if !hasEnum {
+		if raw, ok := node["enum"].([]string); ok {
+			out.Enum = slices.Clone(raw)
+			out.Format = "enum"
+		}
+		return
+	}
+	if out.Type != "" && out.Type != TypeString {
+		c.report(joinPath(path, "enum"), "enum on %s not supported (Gemini enums are strings)", out.Type)
+		return
+	}
+	enum := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			c.report(joinPath(path, "enum"), "non-string enum value %v not supported", v)
+			return
+		}
+		enum = append(enum, s)
+	}
+	out.Type = TypeString
+	out.Format = "enum"
+	out.Enum = enum
+}
+
+// convertKeyword maps one remaining keyword and reports whether it is supported.
+func (c *converter) convertKeyword(key string, value any, out *Schema, path string) bool {
+	switch key {
+	case "title":
+		out.Title, _ = value.(string)
+	case "description":
+		out.Description, _ = value.(string)
+	case "pattern":
+		out.Pattern, _ = value.(string)
+	case "nullable": // OpenAPI 3.0 spelling, as written by [toolsy.NullableFlag]
+		nullable, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		out.Nullable = out.Nullable || nullable
+	case "minimum":
+		out.Minimum = float64Ptr(value)
+	case "maximum":
+		out.Maximum = float64Ptr(value)
+	case "minItems":
+		out.MinItems = int64Ptr(value)
+	case "maxItems":
+		out.MaxItems = int64Ptr(value)
+	case "minLength":
+		out.MinLength = int64Ptr(value)
+	case "maxLength":
+		out.MaxLength = int64Ptr(value)
+	case "properties":
+		c.convertProperties(value, out, path)
+	case "required":
+		out.Required = stringSlice(value)
+	case "items":
+		items, _ := value.(map[string]any)
+		out.Items = c.convert(items, path)
+	case "anyOf", "oneOf":
+		c.convertAnyOf(value, out, path)
+	default:
+		return false
+	}
+	return true
+}
+
+func (c *converter) convertProperties(value any, out *Schema, path string) {
+	props, _ := value.(map[string]any)
+	out.Properties = make(map[string]*Schema, len(props))
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		prop, _ := props[name].(map[string]any)
+		converted := c.convert(prop, joinPath(path, name))
+		if converted == nil {
+			continue // reported; best-effort mode drops the property
+		}
+		out.Properties[name] = converted
+	}
+}
+
+func (c *converter) convertAnyOf(value any, out *Schema, path string) {
+	variants, _ := value.([]any)
+	for i, v := range variants {
+		variant, _ := v.(map[string]any)
+		if t, _ := variant["type"].(string); t == "null" {
+			out.Nullable = true
+			continue
+		}
+		if converted := c.convert(variant, joinPath(path, strconv.Itoa(i))); converted != nil {
+			out.AnyOf = append(out.AnyOf, converted)
+		}
+	}
+	if len(out.AnyOf) == 1 && out.Type == "" {
+		nullable := out.Nullable
+		*out = *out.AnyOf[0]
+		out.Nullable = out.Nullable || nullable
+	}
+}
+
+// convertObject drops closed-object markers, reports typed maps Gemini cannot express, and prunes
+// required names of dropped properties. It returns false for nested objects without properties.
+func (c *converter) convertObject(node map[string]any, out *Schema, path string) bool {
+	if ap, ok := node["additionalProperties"].(map[string]any); ok && len(ap) > 0 {
+		c.report(joinPath(path, "additionalProperties"), "typed map values not supported")
+	}
+	out.Required = slices.DeleteFunc(out.Required, func(name string) bool { return out.Properties[name] == nil })
+	if len(out.Properties) == 0 && path != "" {
+		c.report(path, "object without properties not supported")
+		return false
+	}
+	return true
+}
+
+func geminiType(jsonType string) string {
+	switch jsonType {
+	case "string":
+		return TypeString
+	case "number":
+		return TypeNumber
+	case "integer":
+		return TypeInteger
+	case "boolean":
+		return TypeBoolean
+	case "array":
+		return TypeArray
+	case "object":
+		return TypeObject
+	default:
+		return ""
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func float64Ptr(v any) *float64 {
+	switch n := v.(type) {
+	case float64:
+		return &n
+	case int:
+		f := float64(n)
+		return &f
+	case int64:
+		f := float64(n)
+		return &f
+	default:
+		return nil
+	}
+}
+
+func int64Ptr(v any) *int64 {
+	f := float64Ptr(v)
+	if f == nil {
+		return nil
+	}
+	n := int64(*f)
+	return &n
+}
+
+func stringSlice(v any) []string {
+	switch s := v.(type) {
+	case []string:
+		return slices.Clone(s)
+	case []any:
+		out := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
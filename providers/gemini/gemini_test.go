@@ -0,0 +1,163 @@
+package gemini_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/providers/gemini"
+)
+
+type location struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty"`
+}
+
+type forecastArgs struct {
+	Where location `json:"where"`
+	Days  *int     `json:"days,omitempty"`
+}
+
+func TestToDeclarations_TypedToolDowngrades(t *testing.T) {
+	tool, err := toolsy.NewTool("forecast", "Weather forecast",
+		func(_ context.Context, _ *toolsy.RunEnv, _ forecastArgs) (string, error) { return "", nil })
+	require.NoError(t, err)
+
+	out, err := gemini.ToDeclarations([]toolsy.Tool{tool}, gemini.Options{})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	raw, err := json.Marshal(out[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name":"forecast",
+		"description":"Weather forecast",
+		"parameters":{
+			"type":"OBJECT",
+			"properties":{
+				"where":{
+					"type":"OBJECT",
+					"properties":{"city":{"type":"STRING"},"country":{"type":"STRING"}},
+					"required":["city"]
+				},
+				"days":{"type":"INTEGER","nullable":true}
+			},
+			"required":["where"]
+		}
+	}`, string(raw))
+}
+
+func TestToDeclarations_NullableFlagRoundTrip(t *testing.T) {
+	tool, err := toolsy.NewTool("forecast", "Weather forecast",
+		func(_ context.Context, _ *toolsy.RunEnv, _ forecastArgs) (string, error) { return "", nil },
+		toolsy.WithNullableStyle(toolsy.NullableFlag))
+	require.NoError(t, err)
+	props, _ := tool.Manifest().Parameters["properties"].(map[string]any)
+	require.Equal(t, map[string]any{"type": "integer", "nullable": true}, props["days"])
+
+	out, err := gemini.ToDeclarations([]toolsy.Tool{tool}, gemini.Options{})
+	require.NoError(t, err, "strict mode accepts the nullable keyword")
+	require.Len(t, out, 1)
+	days := out[0].Parameters.Properties["days"]
+	assert.Equal(t, gemini.TypeInteger, days.Type)
+	assert.True(t, days.Nullable)
+}
+
+func TestToDeclaration_InlinesRefsAndTranslatesKeywords(t *testing.T) {
+	decl, err := gemini.ToDeclaration(toolsy.ToolManifest{
+		Name: "book",
+		Parameters: map[string]any{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type":    "object",
+			"$defs": map[string]any{
+				"Seat": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"row": map[string]any{"type": "integer", "format": "int32"}},
+				},
+			},
+			"properties": map[string]any{
+				"seat":  map[string]any{"$ref": "#/$defs/Seat", "description": "Seat choice"},
+				"class": map[string]any{"type": "string", "enum": []any{"economy", "business"}},
+				"mode":  map[string]any{"const": "online"},
+				"note":  map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "null"}}},
+			},
+			"additionalProperties": false,
+		},
+	}, gemini.Options{})
+	require.NoError(t, err)
+
+	p := decl.Parameters
+	require.NotNil(t, p)
+	assert.Equal(t, gemini.TypeObject, p.Properties["seat"].Type)
+	assert.Equal(t, "Seat choice", p.Properties["seat"].Description)
+	assert.Equal(t, "int32", p.Properties["seat"].Properties["row"].Format)
+	assert.Equal(t, []string{"economy", "business"}, p.Properties["class"].Enum)
+	assert.Equal(t, "enum", p.Properties["class"].Format)
+	assert.Equal(t, []string{"online"}, p.Properties["mode"].Enum)
+	assert.Equal(t, gemini.TypeString, p.Properties["note"].Type)
+	assert.True(t, p.Properties["note"].Nullable)
+}
+
+func unsupportedManifest() toolsy.ToolManifest {
+	return toolsy.ToolManifest{
+		Name: "tag",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":     map[string]any{"type": "string", "format": "uuid"},
+				"labels": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+				"level":  map[string]any{"type": "integer", "enum": []any{1, 2, 3}},
+				"name":   map[string]any{"type": "string"},
+			},
+			"required": []any{"id", "labels", "name"},
+		},
+	}
+}
+
+func TestToDeclaration_StrictReportsEveryUnsupportedConstruct(t *testing.T) {
+	_, err := gemini.ToDeclaration(unsupportedManifest(), gemini.Options{})
+	require.ErrorIs(t, err, gemini.ErrUnsupportedSchema)
+	msg := err.Error()
+	assert.Contains(t, msg, `properties.id.format: format "uuid" not supported`)
+	assert.Contains(t, msg, "properties.labels.additionalProperties: typed map values not supported")
+	assert.Contains(t, msg, "properties.level.enum")
+}
+
+func TestToDeclaration_BestEffortDrops(t *testing.T) {
+	decl, err := gemini.ToDeclaration(unsupportedManifest(), gemini.Options{BestEffort: true})
+	require.NoError(t, err)
+	p := decl.Parameters
+	assert.Empty(t, p.Properties["id"].Format)
+	assert.NotContains(t, p.Properties, "labels")
+	assert.Empty(t, p.Properties["level"].Enum)
+	assert.Equal(t, []string{"id", "name"}, p.Required)
+}
+
+func TestToDeclaration_EmptySchemaAndNames(t *testing.T) {
+	decl, err := gemini.ToDeclaration(toolsy.ToolManifest{Name: "ping", Parameters: map[string]any{"type": "object"}},
+		gemini.Options{})
+	require.NoError(t, err)
+	assert.Nil(t, decl.Parameters)
+
+	_, err = gemini.ToDeclaration(toolsy.ToolManifest{Name: "9lives"}, gemini.Options{})
+	require.ErrorIs(t, err, gemini.ErrInvalidToolName)
+}
+
+func TestToDeclaration_RecursiveRefIsUnsupported(t *testing.T) {
+	_, err := gemini.ToDeclaration(toolsy.ToolManifest{
+		Name: "tree",
+		Parameters: map[string]any{
+			"type": "object",
+			"$defs": map[string]any{"Node": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"child": map[string]any{"$ref": "#/$defs/Node"}},
+			}},
+			"properties": map[string]any{"root": map[string]any{"$ref": "#/$defs/Node"}},
+		},
+	}, gemini.Options{})
+	require.ErrorContains(t, err, "recursive $ref")
+}
@@ -0,0 +1,6 @@
+// Package openai converts toolsy tools to the OpenAI function-calling "tools" array and converts
+// OpenAI "tool_calls" entries back into [toolsy.ToolCall].
+//
+// The package mirrors the wire shapes with plain structs so it does not depend on an OpenAI SDK;
+// marshal them with encoding/json or copy the fields into SDK types.
+package openai
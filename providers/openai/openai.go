@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/internal/providerutil"
+)
+
+// TypeFunction is the only tool type emitted and accepted by this package.
+const TypeFunction = "function"
+
+// ErrInvalidToolName is returned when a tool name does not match OpenAI's ^[a-zA-Z0-9_-]{1,64}$.
+var ErrInvalidToolName = errors.New("openai: invalid tool name")
+
+// FunctionDefinition is the "function" object of an OpenAI tool.
+type FunctionDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+	Strict      bool           `json:"strict,omitempty"`
+}
+
+// Tool is one entry of the chat completions "tools" array.
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionCall is the "function" object of an assistant tool call.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolCall is one entry of an assistant message "tool_calls" array.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// ToTools converts tools (for example reg.GetVisibleTools()) to OpenAI tool definitions, preserving order.
+// Tools marked [toolsy.WithHidden] are skipped.
+func ToTools(tools []toolsy.Tool) ([]Tool, error) {
+	out := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		if t == nil {
+			return nil, errors.New("openai: nil tool")
+		}
+		m := t.Manifest()
+		if m.Hidden {
+			continue
+		}
+		def, err := ToTool(m)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, def)
+	}
+	return out, nil
+}
+
+// ToTool converts one manifest. Parameters are deep-copied; an empty schema becomes an empty object
+// schema, and Strict is set when the tool was built with [toolsy.WithStrict]. The description is
+// [toolsy.ModelDescription], so deprecated tools carry their notice.
+func ToTool(m toolsy.ToolManifest) (Tool, error) {
+	if err := providerutil.CheckToolName(m.Name, ErrInvalidToolName); err != nil {
+		return Tool{}, err
+	}
+	return Tool{
+		Type: TypeFunction,
+		Function: FunctionDefinition{
+			Name:        m.Name,
+			Description: toolsy.ModelDescription(m),
+			Parameters:  providerutil.ObjectSchema(m.Parameters),
+			Strict:      m.Strict,
+		},
+	}, nil
+}
+
+// ToToolCall converts an OpenAI tool call into a [toolsy.ToolCall]. Empty arguments become "{}".
+func ToToolCall(tc ToolCall) (toolsy.ToolCall, error) {
+	if tc.Type != "" && tc.Type != TypeFunction {
+		return toolsy.ToolCall{}, fmt.Errorf("openai: unsupported tool call type %q", tc.Type)
+	}
+	if tc.Function.Name == "" {
+		return toolsy.ToolCall{}, errors.New("openai: tool call function name is required")
+	}
+	args := json.RawMessage(tc.Function.Arguments)
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	return toolsy.ToolCall{ //nolint:exhaustruct // Env, CallContext, and Metadata are host-owned
+		ToolName: tc.Function.Name,
+		Input: toolsy.ToolInput{ //nolint:exhaustruct // no attachments on OpenAI tool calls
+			CallID:   tc.ID,
+			ArgsJSON: args,
+		},
+	}, nil
+}
+
+// ToToolCalls converts a "tool_calls" array, failing on the first invalid entry.
+func ToToolCalls(calls []ToolCall) ([]toolsy.ToolCall, error) {
+	out := make([]toolsy.ToolCall, 0, len(calls))
+	for i, tc := range calls {
+		call, err := ToToolCall(tc)
+		if err != nil {
+			return nil, fmt.Errorf("openai: tool_calls[%d]: %w", i, err)
+		}
+		out = append(out, call)
+	}
+	return out, nil
+}
+
+// RoleTool is the chat message role of tool results.
+const RoleTool = "tool"
+
+// ToolMessage is a "tool" role chat message answering one assistant tool call.
+type ToolMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ResultOptions configures [ToToolMessage].
+type ResultOptions struct {
+	// NoResultText renders [toolsy.NoResult] chunks as "no results: <reason>" instead of their JSON envelope.
+	NoResultText bool
+}
+
+// ToToolMessage converts the result chunk of a call (for example the last chunk yielded by Execute)
+// into a tool message. ToolCallID is the chunk's CallID and Content is the chunk data as text.
+func ToToolMessage(c toolsy.Chunk, opts ResultOptions) ToolMessage {
+	content := providerutil.ResultText(c, opts.NoResultText)
+	return ToolMessage{Role: RoleTool, ToolCallID: c.CallID, Content: content}
+}
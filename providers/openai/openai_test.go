@@ -0,0 +1,153 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/providers/openai"
+)
+
+type weatherArgs struct {
+	City string `json:"city" jsonschema:"City name"`
+}
+
+func TestToTools_StrictAndPlain(t *testing.T) {
+	strictTool, err := toolsy.NewTool("weather", "Get weather", func(_ context.Context, _ *toolsy.RunEnv, a weatherArgs) (string, error) {
+		return a.City, nil
+	}, toolsy.WithStrict())
+	require.NoError(t, err)
+	plainTool, err := toolsy.NewTool("echo", "Echo", func(_ context.Context, _ *toolsy.RunEnv, a weatherArgs) (string, error) {
+		return a.City, nil
+	})
+	require.NoError(t, err)
+
+	out, err := openai.ToTools([]toolsy.Tool{strictTool, plainTool})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	raw, err := json.Marshal(out[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type":"function",
+		"function":{
+			"name":"weather",
+			"description":"Get weather",
+			"strict":true,
+			"parameters":{
+				"type":"object",
+				"properties":{"city":{"type":"string","description":"City name"}},
+				"required":["city"],
+				"additionalProperties":false
+			}
+		}
+	}`, string(raw))
+	assert.False(t, out[1].Function.Strict)
+}
+
+func TestToTool_EmptySchemaIsObject(t *testing.T) {
+	def, err := openai.ToTool(toolsy.ToolManifest{Name: "ping"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, def.Function.Parameters)
+}
+
+func TestToTool_DeepCopiesParameters(t *testing.T) {
+	params := map[string]any{"type": "object", "properties": map[string]any{"q": map[string]any{"type": "string"}}}
+	def, err := openai.ToTool(toolsy.ToolManifest{Name: "search", Parameters: params})
+	require.NoError(t, err)
+	def.Function.Parameters["properties"].(map[string]any)["q"].(map[string]any)["type"] = "integer"
+	assert.Equal(t, "string", params["properties"].(map[string]any)["q"].(map[string]any)["type"])
+}
+
+func TestToTool_RejectsInvalidNames(t *testing.T) {
+	for _, name := range []string{"", strings.Repeat("a", 65), "has space", "dotted.name"} {
+		_, err := openai.ToTool(toolsy.ToolManifest{Name: name})
+		require.ErrorIs(t, err, openai.ErrInvalidToolName, name)
+	}
+	_, err := openai.ToTool(toolsy.ToolManifest{Name: strings.Repeat("a", 64)})
+	require.NoError(t, err)
+}
+
+func TestToToolCalls_RoundTripThroughRegistry(t *testing.T) {
+	var payload []openai.ToolCall
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id":"call_1","type":"function","function":{"name":"weather","arguments":"{\"city\":\"Paris\"}"}},
+		{"id":"call_2","type":"function","function":{"name":"weather","arguments":""}}
+	]`), &payload))
+
+	calls, err := openai.ToToolCalls(payload)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "call_1", calls[0].Input.CallID)
+	assert.Equal(t, "weather", calls[0].ToolName)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(calls[0].Input.ArgsJSON))
+	assert.JSONEq(t, `{}`, string(calls[1].Input.ArgsJSON))
+
+	tool, err := toolsy.NewTool("weather", "Get weather", func(_ context.Context, _ *toolsy.RunEnv, a weatherArgs) (string, error) {
+		return "sunny in " + a.City, nil
+	})
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistry(tool)
+	require.NoError(t, err)
+	var got string
+	require.NoError(t, reg.Execute(context.Background(), calls[0], func(c toolsy.Chunk) error {
+		return json.Unmarshal(c.Data, &got)
+	}))
+	assert.Equal(t, "sunny in Paris", got)
+}
+
+func TestToToolCall_RejectsUnsupportedType(t *testing.T) {
+	_, err := openai.ToToolCall(openai.ToolCall{ID: "x", Type: "custom", Function: openai.FunctionCall{Name: "n"}})
+	require.Error(t, err)
+	_, err = openai.ToToolCalls([]openai.ToolCall{{ID: "x"}})
+	require.ErrorContains(t, err, "tool_calls[0]")
+}
+
+func TestToToolMessage_NoResult(t *testing.T) {
+	c := toolsy.NoResult("no flights on that date")
+	c.CallID = "call_1"
+
+	raw := openai.ToToolMessage(c, openai.ResultOptions{NoResultText: false})
+	assert.Equal(t, openai.RoleTool, raw.Role)
+	assert.Equal(t, "call_1", raw.ToolCallID)
+	assert.JSONEq(t, `{"noResult":true,"reason":"no flights on that date"}`, raw.Content)
+
+	text := openai.ToToolMessage(c, openai.ResultOptions{NoResultText: true})
+	data, err := json.Marshal(text)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"tool","tool_call_id":"call_1","content":"no results: no flights on that date"}`,
+		string(data))
+
+	plain := openai.ToToolMessage(toolsy.Chunk{CallID: "call_2", Data: []byte(`{"ok":true}`)},
+		openai.ResultOptions{NoResultText: true})
+	assert.JSONEq(t, `{"ok":true}`, plain.Content)
+}
+
+func TestToTools_SkipsHidden(t *testing.T) {
+	handler := func(_ context.Context, _ *toolsy.RunEnv, a weatherArgs) (string, error) { return a.City, nil }
+	visible, err := toolsy.NewTool("weather", "Get weather", handler)
+	require.NoError(t, err)
+	hidden, err := toolsy.NewTool("__memorize", "Internal", handler, toolsy.WithHidden())
+	require.NoError(t, err)
+
+	out, err := openai.ToTools([]toolsy.Tool{hidden, visible})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, "weather", out[0].Function.Name)
+}
+
+func TestToTools_DeprecatedNotice(t *testing.T) {
+	tool, err := toolsy.NewTool("search_v1", "Search the web",
+		func(_ context.Context, _ *toolsy.RunEnv, a weatherArgs) (string, error) { return a.City, nil },
+		toolsy.WithDeprecated("use search_v2"))
+	require.NoError(t, err)
+	out, err := openai.ToTools([]toolsy.Tool{tool})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, "Search the web (deprecated: use search_v2)", out[0].Function.Description)
+}
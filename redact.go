@@ -0,0 +1,219 @@
+package toolsy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue replaces sensitive argument values in the output of [RedactArgs].
+const RedactedValue = "***"
+
+// sensitiveWildcard is the JSON pointer segment in [ToolManifest.SensitiveArgs] that matches every
+// array element or map value.
+const sensitiveWildcard = "*"
+
+// WithSensitiveArgs adds JSON pointers (RFC 6901) of argument fields that [RedactArgs] masks, for
+// tools whose arguments are not a Go struct ([NewProxyTool], [NewDynamicToolFromSpec]). A "*" segment
+// matches every array element or map value, e.g. "/accounts/*/token". Typed tools derive the list
+// from `sensitive:"true"` struct tags; pointers added here are merged with the derived ones.
+func WithSensitiveArgs(pointers ...string) ToolOption {
+	return func(c *ToolConfig) {
+		c.Manifest.SensitiveArgs = mergeSensitiveArgs(c.Manifest.SensitiveArgs, pointers)
+	}
+}
+
+// RedactArgs returns a copy of argsJSON with every field listed in the tool's
+// [ToolManifest.SensitiveArgs] replaced by [RedactedValue]. Use it before logging or exporting
+// arguments; the bytes passed to the tool are never modified. Args of a tool without sensitive
+// fields are copied unchanged. Args that are not valid JSON cannot be inspected, so for a tool with
+// sensitive fields the whole value is replaced.
+func RedactArgs(tool Tool, argsJSON []byte) []byte {
+	if tool == nil {
+		return bytes.Clone(argsJSON)
+	}
+	return redactArgsJSON(toolManifest(tool).SensitiveArgs, argsJSON)
+}
+
+func redactArgsJSON(pointers []string, argsJSON []byte) []byte {
+	if len(pointers) == 0 || len(bytes.TrimSpace(argsJSON)) == 0 {
+		return bytes.Clone(argsJSON)
+	}
+	dec := json.NewDecoder(bytes.NewReader(argsJSON))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return []byte(strconv.Quote(RedactedValue))
+	}
+	changed := false
+	for _, ptr := range pointers {
+		if redactPointer(doc, splitJSONPointer(ptr)) {
+			changed = true
+		}
+	}
+	if !changed {
+		return bytes.Clone(argsJSON)
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return []byte(strconv.Quote(RedactedValue))
+	}
+	return out
+}
+
+// splitJSONPointer splits an RFC 6901 pointer into unescaped reference tokens.
+func splitJSONPointer(ptr string) []string {
+	ptr = strings.TrimPrefix(ptr, "/")
+	if ptr == "" {
+		return nil
+	}
+	parts := strings.Split(ptr, "/")
+	for i, p := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(p)
+	}
+	return parts
+}
+
+// redactPointer masks the values path selects in node and reports whether any was found.
+func redactPointer(node any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	head, rest := path[0], path[1:]
+	switch v := node.(type) {
+	case map[string]any:
+		if head == sensitiveWildcard {
+			found := false
+			for key := range v {
+				if redactChild(v, key, rest) {
+					found = true
+				}
+			}
+			return found
+		}
+		if _, ok := v[head]; !ok {
+			return false
+		}
+		return redactChild(v, head, rest)
+	case []any:
+		if head == sensitiveWildcard {
+			found := false
+			for i := range v {
+				if redactElement(v, i, rest) {
+					found = true
+				}
+			}
+			return found
+		}
+		i, err := strconv.Atoi(head)
+		if err != nil || i < 0 || i >= len(v) {
+			return false
+		}
+		return redactElement(v, i, rest)
+	default:
+		return false
+	}
+}
+
+func redactChild(m map[string]any, key string, rest []string) bool {
+	if len(rest) == 0 {
+		if m[key] == nil {
+			return false
+		}
+		m[key] = RedactedValue
+		return true
+	}
+	return redactPointer(m[key], rest)
+}
+
+func redactElement(s []any, i int, rest []string) bool {
+	if len(rest) == 0 {
+		if s[i] == nil {
+			return false
+		}
+		s[i] = RedactedValue
+		return true
+	}
+	return redactPointer(s[i], rest)
+}
+
+// sensitiveArgPointers lists JSON pointers for the fields of typ tagged `sensitive:"true"`, recursing
+// into nested structs, pointers, slices, arrays, and maps ("*" segments). Recursive types are walked once.
+func sensitiveArgPointers(typ reflect.Type) ([]string, error) {
+	w := sensitiveWalker{active: make(map[reflect.Type]bool)}
+	if err := w.walk(typ, ""); err != nil {
+		return nil, err
+	}
+	return w.pointers, nil
+}
+
+type sensitiveWalker struct {
+	active   map[reflect.Type]bool
+	pointers []string
+}
+
+func (w *sensitiveWalker) walk(typ reflect.Type, prefix string) error {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() { //nolint:exhaustive // only container and struct kinds carry nested fields
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		return w.walk(typ.Elem(), prefix+"/"+sensitiveWildcard)
+	case reflect.Map:
+		return w.walk(typ.Elem(), prefix+"/"+sensitiveWildcard)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if w.active[typ] {
+		return nil
+	}
+	w.active[typ] = true
+	defer delete(w.active, typ)
+	for _, field := range reflect.VisibleFields(typ) {
+		name, ok := schemaFieldName(field)
+		if !ok {
+			continue
+		}
+		ptr := prefix + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+		if raw, tagged := field.Tag.Lookup("sensitive"); tagged {
+			sensitive, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				return &fieldPathError{
+					Path: strings.TrimPrefix(ptr, "/"),
+					Type: field.Type,
+					Err:  fmt.Errorf("invalid sensitive tag %q: must be a boolean", raw),
+				}
+			}
+			if sensitive {
+				w.pointers = append(w.pointers, ptr)
+				continue
+			}
+		}
+		if err := w.walk(field.Type, ptr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSensitiveArgs returns the union of a and b in first-seen order, as a new slice.
+func mergeSensitiveArgs(a, b []string) []string {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(a)+len(b))
+	for _, ptr := range slices.Concat(a, b) {
+		if ptr != "" && !slices.Contains(out, ptr) {
+			out = append(out, ptr)
+		}
+	}
+	return out
+}
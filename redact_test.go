@@ -0,0 +1,111 @@
+package toolsy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redactAccount struct {
+	Owner string `json:"owner"`
+	Token string `json:"token" sensitive:"true"`
+}
+
+type redactArgs struct {
+	APIKey   string                   `json:"api_key"           sensitive:"true"`
+	Query    string                   `json:"query"`
+	Primary  *redactAccount           `json:"primary,omitempty"`
+	Accounts []redactAccount          `json:"accounts,omitempty"`
+	ByName   map[string]redactAccount `json:"by_name,omitempty"`
+	Email    string                   `json:"email,omitempty"    sensitive:"false"`
+}
+
+func newRedactTool(t *testing.T, seen *[]byte, opts ...ToolOption) Tool {
+	t.Helper()
+	tool, err := NewTool("search", "Search", func(_ context.Context, _ *RunEnv, a redactArgs) (string, error) {
+		if seen != nil {
+			*seen = []byte(a.APIKey + "|" + a.Accounts[0].Token)
+		}
+		return a.Query, nil
+	}, opts...)
+	require.NoError(t, err)
+	return tool
+}
+
+const redactInput = `{"api_key":"sk-1","query":"q","primary":{"owner":"ada","token":"t0"},` +
+	`"accounts":[{"owner":"bob","token":"t1"},{"owner":"eve","token":"t2"}],"by_name":{"x":{"owner":"x","token":"t3"}}}`
+
+func TestRedactArgs_SensitiveTags(t *testing.T) {
+	tool := newRedactTool(t, nil)
+	assert.Equal(t, []string{"/api_key", "/primary/token", "/accounts/*/token", "/by_name/*/token"},
+		tool.Manifest().SensitiveArgs)
+
+	input := []byte(redactInput)
+	out := RedactArgs(tool, input)
+	assert.JSONEq(t, `{"api_key":"***","query":"q","primary":{"owner":"ada","token":"***"},`+
+		`"accounts":[{"owner":"bob","token":"***"},{"owner":"eve","token":"***"}],`+
+		`"by_name":{"x":{"owner":"x","token":"***"}}}`, string(out))
+	assert.Equal(t, redactInput, string(input), "input bytes are never modified")
+}
+
+func TestRedactArgs_EdgeCases(t *testing.T) {
+	tool := newRedactTool(t, nil)
+	assert.JSONEq(t, `{"query":"q","api_key":null}`, string(RedactArgs(tool, []byte(`{"query":"q","api_key":null}`))))
+	assert.Equal(t, `"***"`, string(RedactArgs(tool, []byte(`{"api_key":"sk-1"`))))
+	assert.Empty(t, RedactArgs(tool, nil))
+
+	plain := mustNamedTool(t, "plain")
+	args := []byte(`{"password":"x"}`)
+	out := RedactArgs(plain, args)
+	assert.Equal(t, args, out)
+	out[0] = '['
+	assert.Equal(t, byte('{'), args[0], "result is a copy")
+}
+
+func TestWithSensitiveArgs_ProxyTool(t *testing.T) {
+	schema := []byte(`{"type":"object","properties":{"auth":{"type":"object"},"items":{"type":"array"}}}`)
+	tool, err := NewProxyTool("proxy", "Proxy", schema,
+		func(context.Context, *RunEnv, []byte, func(Chunk) error) error { return nil },
+		WithSensitiveArgs("/auth/secret", "/items/*/key", "/a~1b"))
+	require.NoError(t, err)
+	out := RedactArgs(tool, []byte(`{"auth":{"secret":"s","user":"u"},"items":[{"key":"k"}],"a/b":1}`))
+	assert.JSONEq(t, `{"auth":{"secret":"***","user":"u"},"items":[{"key":"***"}],"a/b":"***"}`, string(out))
+}
+
+func TestSensitiveTag_Invalid(t *testing.T) {
+	type badArgs struct {
+		Key string `json:"key" sensitive:"maybe"`
+	}
+	_, err := NewTool("bad", "Bad", func(context.Context, *RunEnv, badArgs) (string, error) { return "", nil })
+	require.ErrorContains(t, err, `args field "key"`)
+}
+
+func TestWithRedactedHooks(t *testing.T) {
+	var seen []byte
+	var hooked []string
+	record := func(_ context.Context, call ToolCall) { hooked = append(hooked, string(call.Input.ArgsJSON)) }
+	reg := mustBuildRegistry(t, []Tool{newRedactTool(t, &seen)}, WithRedactedHooks(), WithOnBeforeExecute(record))
+	call := ToolCall{ToolName: "search", Input: ToolInput{ArgsJSON: []byte(redactInput)}}
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+
+	assert.Equal(t, "sk-1|t1", string(seen), "the tool receives the original arguments")
+	require.Len(t, hooked, 1)
+	assert.NotContains(t, hooked[0], "sk-1")
+	assert.NotContains(t, hooked[0], "t1")
+	assert.Equal(t, redactInput, string(call.Input.ArgsJSON))
+}
+
+func TestWithLogging_RedactsArgs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tool := WithLogging(logger)(newRedactTool(t, nil))
+	err := tool.Execute(context.Background(), nil, ToolInput{ArgsJSON: []byte(redactInput)},
+		func(Chunk) error { return nil })
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "tool args")
+	assert.NotContains(t, buf.String(), "sk-1")
+}
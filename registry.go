@@ -5,16 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"runtime/debug"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // RegistryBuilder is mutable setup API that produces an immutable Registry for runtime use.
 type RegistryBuilder struct {
 	tools       []Tool
+	versioned   []Tool
 	middlewares []Middleware
 	opts        registryOptions
+	errs        []error
 }
 
 // NewRegistryBuilder creates a mutable registry builder with defaults and applies options.
@@ -26,23 +31,55 @@ func NewRegistryBuilder(opts ...RegistryOption) *RegistryBuilder {
 	}
 	return &RegistryBuilder{
 		tools:       nil,
+		versioned:   nil,
 		middlewares: nil,
 		opts:        o,
+		errs:        nil,
 	}
 }
 
-// Add appends tools to the builder.
+// Add appends tools to the builder. Two tools with the same name make [RegistryBuilder.Build] fail
+// with [ErrDuplicateTool]; use [RegistryBuilder.AddOrReplace] when a later tool should win.
 func (b *RegistryBuilder) Add(tools ...Tool) *RegistryBuilder {
 	b.tools = append(b.tools, tools...)
 	return b
 }
 
+// AddOrReplace adds tools like [RegistryBuilder.Add], first dropping any tool already added under
+// the same manifest name, so the last registration wins. This includes every version registered with
+// [RegistryBuilder.AddVersioned] or mounted from a versioned registry. A nil tool makes Build fail.
+func (b *RegistryBuilder) AddOrReplace(tools ...Tool) *RegistryBuilder {
+	for _, t := range tools {
+		if t == nil {
+			b.errs = append(b.errs, errors.New("toolsy: nil tool in registry builder"))
+			continue
+		}
+		name := t.Manifest().Name
+		sameName := func(existing Tool) bool {
+			return existing != nil && existing.Manifest().Name == name
+		}
+		b.tools = slices.DeleteFunc(b.tools, sameName)
+		b.versioned = slices.DeleteFunc(b.versioned, sameName)
+		b.tools = append(b.tools, t)
+	}
+	return b
+}
+
 // Use appends middlewares. The first middleware is outermost.
 func (b *RegistryBuilder) Use(middlewares ...Middleware) *RegistryBuilder {
 	b.middlewares = append(b.middlewares, middlewares...)
 	return b
 }
 
+// Remaining reports how many more tools fit under [WithMaxTools], or -1 when no limit is set.
+// Importers can use it to stop generating schemas before Build would reject the registry.
+func (b *RegistryBuilder) Remaining() int {
+	if b.opts.maxTools <= 0 {
+		return -1
+	}
+	return max(b.opts.maxTools-len(b.tools)-len(b.versioned), 0)
+}
+
 // WithOptions applies registry options to the builder.
 func (b *RegistryBuilder) WithOptions(opts ...RegistryOption) *RegistryBuilder {
 	for _, opt := range opts {
@@ -54,51 +91,93 @@ func (b *RegistryBuilder) WithOptions(opts ...RegistryOption) *RegistryBuilder {
 // Build creates an immutable runtime registry.
 // Rejects tools with more than one [AsAsyncTool] layer anywhere in the chain (see [ChainUnwrapper]).
 func (b *RegistryBuilder) Build() (*Registry, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
 	if b.opts.policyIDMissing || (b.opts.policy != nil && b.opts.policyDigest == "") {
 		return nil, errors.New("toolsy: registry policy id is required")
 	}
+	if n := len(b.tools) + len(b.versioned); b.opts.maxTools > 0 && n > b.opts.maxTools {
+		return nil, fmt.Errorf("%w: %d tools, limit %d", ErrTooManyTools, n, b.opts.maxTools)
+	}
 	tools := make(map[string]Tool, len(b.tools))
 	for _, raw := range b.tools {
-		if raw == nil {
-			return nil, errors.New("toolsy: nil tool in registry builder")
-		}
-		t := raw
-		if n := countAsyncLayers(t); n > 1 {
-			return nil, fmt.Errorf(
-				"toolsy: tool %q is wrapped in multiple AsAsyncTool layers, which is invalid",
-				t.Manifest().Name,
-			)
-		}
-		var asyncOpts *asyncOptions
-		if aw, ok := t.(*asyncTool); ok {
-			asyncOpts = &aw.opts
-			t = aw.next
-		}
-		for i := len(b.middlewares) - 1; i >= 0; i-- {
-			t = b.middlewares[i](t)
-		}
-		if asyncOpts != nil {
-			t = &asyncTool{
-				toolBase: toolBase{next: t},
-				opts:     *asyncOpts,
-			}
+		t, err := wrapRegistryTool(raw, b.middlewares)
+		if err != nil {
+			return nil, err
 		}
 		name := t.Manifest().Name
-		if name == "" {
-			return nil, errors.New("toolsy: tool manifest name is required")
-		}
 		if _, exists := tools[name]; exists {
-			return nil, fmt.Errorf("toolsy: duplicate tool name %q", name)
+			return nil, fmt.Errorf("%w %q", ErrDuplicateTool, name)
 		}
 		tools[name] = t
 	}
+	versions, err := b.buildVersions(tools)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRegistryToolNames(tools, b.opts.namePattern); err != nil {
+		return nil, err
+	}
+	state := newRegistryRuntimeState()
+	if b.opts.watchdogInterval > 0 {
+		state.watchdog = newExecutionWatchdog(b.opts.watchdogInterval, b.opts.onAbandoned)
+	}
+	if b.opts.toolLoader != nil {
+		state.loader = newToolLoaderCache()
+	}
 	return &Registry{
-		tools: tools,
-		opts:  b.opts,
-		state: newRegistryRuntimeState(),
+		tools:       tools,
+		versions:    versions,
+		middlewares: slices.Clone(b.middlewares),
+		opts:        b.opts,
+		state:       state,
+		footprints:  &sync.Map{},
+		descriptors: &sync.Map{},
 	}, nil
 }
 
+// MustBuild is [RegistryBuilder.Build] for registries assembled at startup: it panics on error.
+func (b *RegistryBuilder) MustBuild() *Registry {
+	r, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// wrapRegistryTool applies builder middlewares to raw (inside any [AsAsyncTool] layer) and checks its name.
+func wrapRegistryTool(raw Tool, middlewares []Middleware) (Tool, error) {
+	if raw == nil {
+		return nil, errors.New("toolsy: nil tool in registry builder")
+	}
+	t := raw
+	if n := countAsyncLayers(t); n > 1 {
+		return nil, fmt.Errorf(
+			"toolsy: tool %q is wrapped in multiple AsAsyncTool layers, which is invalid",
+			t.Manifest().Name,
+		)
+	}
+	var asyncOpts *asyncOptions
+	if aw, ok := t.(*asyncTool); ok {
+		asyncOpts = &aw.opts
+		t = aw.next
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		t = middlewares[i](t)
+	}
+	if asyncOpts != nil {
+		t = &asyncTool{
+			toolBase: toolBase{next: t},
+			opts:     *asyncOpts,
+		}
+	}
+	if t.Manifest().Name == "" {
+		return nil, errors.New("toolsy: tool manifest name is required")
+	}
+	return t, nil
+}
+
 // countAsyncLayers walks toolBase chains and counts AsAsyncTool wrappers.
 func countAsyncLayers(t Tool) int {
 	n := 0
@@ -121,6 +200,10 @@ type registryRuntimeState struct {
 	done     chan struct{}
 	running  sync.WaitGroup
 	closeMux sync.Once
+	inFlight atomic.Int64
+	avgNanos atomic.Int64 // moving average of execution durations, see observeDuration
+	watchdog *executionWatchdog
+	loader   *toolLoaderCache // tools loaded through WithToolLoader, shared by derived registries
 }
 
 func newRegistryRuntimeState() *registryRuntimeState {
@@ -129,27 +212,52 @@ func newRegistryRuntimeState() *registryRuntimeState {
 		done:     make(chan struct{}),
 		running:  sync.WaitGroup{},
 		closeMux: sync.Once{},
+		inFlight: atomic.Int64{},
+		avgNanos: atomic.Int64{},
+		watchdog: nil,
+		loader:   nil,
 	}
 }
 
 // tryStartExecution registers an in-flight execution unless the registry is shut down.
-func (s *registryRuntimeState) tryStartExecution() bool {
+// It returns the in-flight count including the new execution.
+func (s *registryRuntimeState) tryStartExecution() (int, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	select {
 	case <-s.done:
-		return false
+		return 0, false
 	default:
 		s.running.Add(1)
+		return int(s.inFlight.Add(1)), true
+	}
+}
+
+// isShutdown reports whether Shutdown has started.
+func (s *registryRuntimeState) isShutdown() bool {
+	select {
+	case <-s.done:
 		return true
+	default:
+		return false
 	}
 }
 
+// finishExecution releases one execution registered by tryStartExecution.
+func (s *registryRuntimeState) finishExecution() {
+	s.inFlight.Add(-1)
+	s.running.Done()
+}
+
 // Registry holds tools and executes them with optional panic recovery.
 type Registry struct {
-	tools map[string]Tool
-	opts  registryOptions
-	state *registryRuntimeState
+	tools       map[string]Tool
+	versions    map[string][]Tool // name -> every version added with AddVersioned, oldest first
+	middlewares []Middleware      // builder middlewares, reapplied to scope-local tools
+	opts        registryOptions
+	state       *registryRuntimeState
+	footprints  *sync.Map // tool name -> int64, see [Registry.MemoryFootprint]
+	descriptors *sync.Map // tool name -> EffectiveDescriptor, see [Registry.EffectiveDescriptors]
 }
 
 // NewRegistry creates an immutable registry from tools with default options.
@@ -191,14 +299,31 @@ func (r *Registry) GetAllTools() []Tool {
 	return out
 }
 
-// GetTool returns the tool with the given name, or (nil, false) if not found.
+// GetVisibleTools returns the registered tools not marked [WithHidden], sorted by manifest name.
+// Use it instead of [Registry.GetAllTools] when building the tool list sent to a model.
+// A nil receiver returns nil.
+func (r *Registry) GetVisibleTools() []Tool {
+	if r == nil {
+		return nil
+	}
+	names := r.sortedToolNames()
+	out := make([]Tool, 0, len(names))
+	for _, name := range names {
+		if t := r.tools[name]; !toolManifest(t).Hidden {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// GetTool returns the tool with the given name, or (nil, false) if not found. Like Execute, it also
+// resolves "name@version" references (see [Registry.GetToolVersion]).
 // A nil receiver returns (nil, false).
 func (r *Registry) GetTool(name string) (Tool, bool) {
 	if r == nil {
 		return nil, false
 	}
-	t, ok := r.tools[name]
-	return t, ok
+	return r.lookupTool(name)
 }
 
 // Has reports whether a tool with the given name is registered in this view's tool map.
@@ -258,6 +383,7 @@ func (r *Registry) subsetWithPolicy(policy Policy, allowedNames ...string) (*Reg
 	opts.policy = composePolicies(opts.policy, policy)
 	seen := make(map[string]struct{}, len(allowedNames))
 	tools := make(map[string]Tool, len(allowedNames))
+	var versions map[string][]Tool
 	for _, name := range allowedNames {
 		if _, dup := seen[name]; dup {
 			continue
@@ -268,61 +394,135 @@ func (r *Registry) subsetWithPolicy(policy Policy, allowedNames ...string) (*Reg
 			return nil, NewToolNotFoundInSubsetError(name)
 		}
 		tools[name] = tool
+		if list, versioned := r.versions[name]; versioned {
+			if versions == nil {
+				versions = make(map[string][]Tool)
+			}
+			versions[name] = list
+		}
 	}
 	return &Registry{
-		tools: tools,
-		opts:  opts,
-		state: r.state,
+		tools:       tools,
+		versions:    versions,
+		middlewares: r.middlewares,
+		opts:        opts,
+		state:       r.state,
+		footprints:  r.footprints,
+		descriptors: r.descriptors,
 	}, nil
 }
 
 // accountDeliveredChunk updates ExecutionSummary after a chunk was successfully yielded to the consumer.
-func (r *Registry) accountDeliveredChunk(ctx context.Context, c Chunk, summary *ExecutionSummary) {
+func (r *Registry) accountDeliveredChunk(ctx context.Context, c Chunk, summary *ExecutionSummary, start time.Time) {
 	if c.IsError {
 		summary.ErrorChunks++
 		summary.LastErrorText = errorChunkSummaryText(c, nil)
 		return
 	}
+	r.runChunkHooks(ctx, c)
+	if c.Event == EventProgress {
+		summary.ProgressChunks++
+		return
+	}
 	summary.ChunksDelivered++
 	summary.TotalBytes += int64(len(c.Data))
-	if r.opts.onChunk != nil {
-		r.opts.onChunk(ctx, c)
+	if IsNoResult(c) {
+		summary.NoResult = true
+	}
+	if len(r.opts.onChunkProgress) > 0 {
+		r.runChunkProgressHooks(ctx, c, ChunkProgress{
+			Index:      summary.ChunksDelivered - 1,
+			BytesSoFar: summary.TotalBytes,
+			Elapsed:    time.Since(start),
+			FirstChunk: summary.ChunksDelivered == 1,
+		})
 	}
 }
 
 // wrapYieldWithCallMeta fills CallID/ToolName, validates chunks, updates summary counters,
-// and invokes onChunk for delivered non-error chunks.
+// and invokes onChunk/onChunkProgress for delivered non-error chunks. With a non-nil buffer,
+// prepared chunks are queued and delivered by the buffer's forwarder instead of inline.
 func (r *Registry) wrapYieldWithCallMeta(
 	ctx context.Context,
 	call ToolCall,
 	summary *ExecutionSummary,
+	start time.Time,
 	yield func(Chunk) error,
+	buffer *chunkBuffer,
 ) func(Chunk) error {
+	ownership := newChunkOwnershipTracker(r.opts.ownershipLogger, call.ToolName)
 	return func(c Chunk) error {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		ownership.observe(c)
 		if c.CallID == "" {
 			c.CallID = call.Input.CallID
 		}
 		if c.ToolName == "" {
 			c.ToolName = call.ToolName
 		}
+		toolMetadata := c.Metadata
+		c.Metadata = mergeChunkMetadata(call.Metadata, c.Metadata)
 		prepared, err := prepareChunk(c)
 		if err != nil {
 			return err
 		}
 		c = prepared
-		yieldErr := yield(c)
-		if yieldErr != nil {
-			return yieldErr
+		metadataBytes, err := r.checkChunkMetadata(c, toolMetadata)
+		if err != nil {
+			return err
 		}
-		r.accountDeliveredChunk(ctx, c, summary)
-		return nil
+		if buffer != nil {
+			return buffer.push(bufferedChunk{chunk: c, metadataBytes: metadataBytes})
+		}
+		return r.deliverChunk(ctx, c, metadataBytes, summary, start, yield)
+	}
+}
+
+// mergeChunkMetadata returns call metadata overlaid with the tool's chunk metadata. Without tool
+// metadata the chunk shares the call's map; otherwise the result is a new map.
+func mergeChunkMetadata(call, chunk map[string]any) map[string]any {
+	if len(chunk) == 0 {
+		return call
+	}
+	if len(call) == 0 {
+		return maps.Clone(chunk)
+	}
+	out := make(map[string]any, len(call)+len(chunk))
+	maps.Copy(out, call)
+	maps.Copy(out, chunk)
+	return out
+}
+
+// stampChunk assigns the per-call sequence number (every chunk delivered so far, whatever its kind)
+// and the forwarding time.
+func stampChunk(c *Chunk, summary *ExecutionSummary) {
+	c.Seq = summary.ChunksDelivered + summary.ProgressChunks + summary.ErrorChunks
+	c.Timestamp = time.Now()
+}
+
+// deliverChunk hands a prepared chunk to the consumer and accounts it once yield accepted it.
+func (r *Registry) deliverChunk(
+	ctx context.Context,
+	c Chunk,
+	metadataBytes int64,
+	summary *ExecutionSummary,
+	start time.Time,
+	yield func(Chunk) error,
+) error {
+	stampChunk(&c, summary)
+	if err := yield(c); err != nil {
+		return err
 	}
+	summary.MetadataBytes += metadataBytes
+	r.accountDeliveredChunk(ctx, c, summary, start)
+	return nil
 }
 
 // Execute runs one tool call and streams chunks to yield. Returns on first yield error or tool error.
+// Every forwarded chunk carries CallID and ToolName from call when the tool left them empty; the same
+// chunk value is what [WithOnChunk] observes.
 // The after-execution hook (WithOnAfterExecute) is always invoked via defer with ExecutionSummary.
 // ChunksDelivered and TotalBytes count only chunks with !IsError. ErrorChunks/LastErrorText
 // describe delivered soft-error chunks.
@@ -338,7 +538,7 @@ func (r *Registry) execute(
 	call ToolCall,
 	yield func(Chunk) error,
 ) error {
-	_, _, err := r.executeWithSummary(ctx, call, yield, true)
+	_, err := r.executeWithSummary(ctx, call, yield, true)
 	return err
 }
 
@@ -352,30 +552,76 @@ func (r *Registry) executeWithSummary(
 	call ToolCall,
 	yield func(Chunk) error,
 	withAfterHook bool,
-) (summary ExecutionSummary, summaryReady bool, err error) {
+) (summary ExecutionSummary, err error) {
+	entered := time.Now()
+	// Calls rejected before the tool runs still report a summary to the hooks; they hold no execution slot.
+	notStarted := func(e error) (ExecutionSummary, error) {
+		summary.CallID = call.Input.CallID
+		summary.ToolName = call.ToolName
+		summary.Metadata = maps.Clone(call.Metadata)
+		summary.StartedAt = entered
+		summary.Error = e
+		summary.FinishReason = FinishReasonOf(e)
+		r.observeError(ctx, call, e)
+		if withAfterHook {
+			r.runAfterHooks(ctx, call, summary, time.Since(entered))
+		}
+		return summary, e
+	}
 	state, stateErr := r.requireRuntimeState()
 	if stateErr != nil {
-		return summary, false, stateErr
+		return notStarted(stateErr)
+	}
+	// Rate-limit waits happen before the call counts as in flight, so waiting never occupies capacity.
+	if r.opts.rateLimiter != nil {
+		statusYield := r.wrapYieldWithCallMeta(ctx, call, &summary, entered, yield, nil)
+		limitErr := r.opts.rateLimiter.admit(ctx, r.opts.rateLimitMode, func(wait time.Duration) error {
+			yErr := rateLimitStatus(statusYield)(wait)
+			if yErr == nil || ctx.Err() != nil {
+				return yErr
+			}
+			return wrapYieldError(yErr)
+		})
+		if limitErr != nil {
+			return notStarted(limitErr)
+		}
 	}
-	if !state.tryStartExecution() {
-		return summary, false, NewShutdownError()
+	inFlight, started := state.tryStartExecution()
+	if !started {
+		return notStarted(NewShutdownError())
+	}
+	if shedErr := r.shedLoad(state, inFlight); shedErr != nil {
+		state.finishExecution()
+		return notStarted(shedErr)
+	}
+	tool, ok := r.lookupTool(call.ToolName)
+	if !ok && state.loader != nil && r.opts.view.ID == "" {
+		loaded, loadErr := r.loadTool(ctx, call.ToolName)
+		if loadErr != nil {
+			state.finishExecution()
+			return notStarted(loadErr)
+		}
+		tool, ok = loaded, loaded != nil
 	}
-	tool, ok := r.tools[call.ToolName]
 	if !ok {
-		state.running.Done()
+		state.finishExecution()
 		if r.opts.view.ID != "" {
-			return summary, false, NewCapabilityDeniedError(call.ToolName, r.opts.view)
+			return notStarted(NewCapabilityDeniedError(call.ToolName, r.opts.view))
 		}
-		return summary, false, NewToolNotFoundError()
+		return notStarted(r.toolNotFoundError(call.ToolName))
 	}
 
+	if state.watchdog != nil {
+		defer state.watchdog.track(ctx, call.ToolName, call.Input.CallID)()
+	}
 	var releaseOnce sync.Once
-	release := func() { releaseOnce.Do(func() { state.running.Done() }) }
+	release := func() { releaseOnce.Do(state.finishExecution) }
 	execEnv := call.Env
 	if execEnv == nil {
 		execEnv = NewRunEnv(nil)
 	}
 	call.Input = call.Input.Clone()
+	call.Metadata = maps.Clone(call.Metadata)
 	call.CallContext = bindCallMetadata(call.CallContext, call)
 	if r.opts.view.ID != "" {
 		call.CallContext = bindViewMetadata(call.CallContext, r.opts.view.ID)
@@ -390,33 +636,70 @@ func (r *Registry) executeWithSummary(
 
 	summary.CallID = call.Input.CallID
 	summary.ToolName = call.ToolName
-	summaryReady = true
+	summary.Metadata = call.Metadata
+	manifest := toolManifest(tool)
+	summary.Deprecated = manifest.Deprecated
 	start := time.Now()
+	summary.StartedAt = start
+	defer func() { state.observeDuration(time.Since(start)) }()
 	if withAfterHook {
 		defer func() {
-			dur := time.Since(start)
-			if r.opts.onAfter != nil {
-				r.opts.onAfter(ctx, cloneToolCall(call), summary, dur)
-			}
+			r.runAfterHooks(ctx, call, summary, time.Since(start))
 		}()
 	}
+	// Runs after panic recovery has rewritten summary.Error and before the after hook. Without
+	// recovery a panic unwinds with a nil Error, which returned reports.
+	returned := false
+	defer func() {
+		summary.FinishReason = FinishReasonOf(summary.Error)
+		if !returned && summary.Error == nil {
+			summary.FinishReason = FinishPanic
+		}
+		r.observeError(ctx, call, summary.Error)
+	}()
 	if r.opts.recoverPanics {
 		defer func() {
 			if p := recover(); p != nil {
-				summary.Error = NewInternalError(&panicError{p: p})
+				pe := newPanicError(p, !r.opts.noPanicStacks)
+				summary.Error = NewInternalError(pe)
+				summary.PanicStack = pe.stack
 				err = summary.Error
 			}
 		}()
 	}
 
-	if r.opts.onBefore != nil {
-		r.opts.onBefore(ctx, cloneToolCall(call))
+	if decErr := r.decodeCallArgs(&call, tool); decErr != nil {
+		summary.Error = decErr
+		returned = true
+		return summary, decErr
+	}
+	r.runBeforeHooks(ctx, call)
+	if manifest.Deprecated {
+		r.runDeprecatedHooks(ctx, call, manifest.DeprecationMessage)
 	}
 
-	toolYield := r.wrapYieldWithCallMeta(ctx, call, &summary, yield)
-	r.runToolWithValidationAndExecute(ctx, call, execEnv, tool, toolYield, &summary)
+	if r.opts.chunkBuffer > 0 {
+		r.runToolBuffered(ctx, call, execEnv, tool, &summary, start, yield)
+	} else {
+		toolYield := r.wrapYieldWithCallMeta(ctx, call, &summary, start, yield, nil)
+		r.runToolWithValidationAndExecute(ctx, call, execEnv, tool, toolYield, &summary)
+	}
+	if summary.Error == nil && r.opts.finalChunk {
+		final := newFinalChunk(call, &summary, time.Since(start))
+		stampChunk(&final, &summary)
+		if yErr := yield(final); yErr != nil {
+			summary.Error = wrapYieldError(yErr)
+		}
+	}
 	err = summary.Error
-	return summary, summaryReady, err
+	returned = true
+	return summary, err
+}
+
+func (r *Registry) observeError(ctx context.Context, call ToolCall, err error) {
+	if err != nil {
+		r.runErrorHooks(ctx, call, err)
+	}
 }
 
 // runToolWithValidationAndExecute runs optional validator then tool.Execute; maps DeadlineExceeded to ErrTimeout.
@@ -428,7 +711,7 @@ func (r *Registry) runToolWithValidationAndExecute(
 	toolYield func(Chunk) error,
 	summary *ExecutionSummary,
 ) {
-	manifest := cloneManifestForPolicy(tool.Manifest())
+	manifest := toolManifest(tool)
 	if err := enforceRequirementsPolicy(manifest.Requirements, r.opts.policy); err != nil {
 		summary.Error = err
 		return
@@ -441,11 +724,7 @@ func (r *Registry) runToolWithValidationAndExecute(
 			View:        cloneRegistryViewSnapshot(r.opts.view),
 		}
 		if aErr := r.opts.authorizer.Authorize(ctx, req); aErr != nil {
-			if _, ok := AsToolError(aErr); ok {
-				summary.Error = aErr
-			} else {
-				summary.Error = NewPolicyDeniedErrorFrom(aErr)
-			}
+			summary.Error = authorizationDenied(aErr)
 			return
 		}
 	}
@@ -456,10 +735,14 @@ func (r *Registry) runToolWithValidationAndExecute(
 			CallContext: call.CallContext,
 			View:        cloneRegistryViewSnapshot(r.opts.view),
 		}
-		if pErr := evaluatePolicy(ctx, r.opts.policy, req); pErr != nil {
+		args, pErr := evaluatePolicyArgs(ctx, r.opts.policy, req)
+		if pErr != nil {
 			summary.Error = pErr
 			return
 		}
+		if args != nil {
+			call.Input.ArgsJSON = []byte(args)
+		}
 	}
 	if err := enforceRuntimeRequirements(manifest.Requirements, env); err != nil {
 		summary.Error = err
@@ -473,8 +756,20 @@ func (r *Registry) runToolWithValidationAndExecute(
 			return
 		}
 	}
-	summary.Error = tool.Execute(ctx, env, call.Input, toolYield)
-	summary.Error = normalizeExecutionInterrupt(summary.Error)
+	if err := r.opts.confirm.confirm(ctx, call, tool, manifest, toolYield); err != nil {
+		summary.Error = normalizeExecutionInterrupt(ctx, err)
+		return
+	}
+	execStart := time.Now()
+	execCtx, closeProgress := withProgressReporter(ctx, toolYield)
+	if r.opts.dedup != nil {
+		summary.Error = r.opts.dedup.execute(execCtx, call, env, tool, toolYield)
+	} else {
+		summary.Error = tool.Execute(execCtx, env, call.Input, toolYield)
+	}
+	closeProgress()
+	summary.ExecDuration = time.Since(execStart)
+	summary.Error = normalizeExecutionInterrupt(ctx, summary.Error)
 }
 
 func enforceRequirementsPolicy(req ToolRequirements, policy Policy) error {
@@ -494,14 +789,17 @@ func enforceRuntimeRequirements(req ToolRequirements, env *RunEnv) error {
 	return NewDependencyMissingError("session state")
 }
 
-func normalizeExecutionInterrupt(err error) error {
+// normalizeExecutionInterrupt classifies context interrupts returned by a tool. Once the deadline of the
+// execution context has passed, every interrupt is an [ErrTimeout], including cancellations the handler
+// derived from it; otherwise a cancellation stays [context.Canceled].
+func normalizeExecutionInterrupt(ctx context.Context, err error) error {
 	if err == nil {
 		return nil
 	}
-	if errors.Is(err, context.Canceled) {
+	if !isContextInterrupt(err) {
 		return err
 	}
-	if !isContextInterrupt(err) {
+	if errors.Is(err, context.Canceled) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		return err
 	}
 	if te, ok := AsToolError(err); ok && te.Code == CodeTimeout {
@@ -512,7 +810,8 @@ func normalizeExecutionInterrupt(err error) error {
 
 // ExecuteIter runs one tool call and returns an iterator over (Chunk, error) pairs.
 // Push-to-push: no channels or extra goroutines; the iterator calls Execute with a callback that forwards to yield.
-// When the consumer breaks out of the loop, cancel() is called and Execute exits via [context.Canceled].
+// When the consumer breaks out of the loop, cancel() is called and the tool's yield returns
+// [ErrStreamAborted] (wrapping [context.Canceled]), so the call finishes as [FinishStreamAborted].
 // Once yield returns false, the iterator must not call yield again (iter contract).
 // Env/session binding: same as [Registry.Execute] (no automatic ValidateRunEnvSession).
 func (r *Registry) ExecuteIter(ctx context.Context, call ToolCall) iter.Seq2[Chunk, error] {
@@ -523,11 +822,67 @@ func (r *Registry) ExecuteIter(ctx context.Context, call ToolCall) iter.Seq2[Chu
 
 		err := r.execute(ctxChild, call, func(c Chunk) error {
 			if consumerStopped {
-				return context.Canceled
+				return wrapYieldError(context.Canceled)
 			}
 			if !yield(c, nil) {
 				consumerStopped = true
 				cancel()
+				return wrapYieldError(context.Canceled)
+			}
+			return nil
+		})
+
+		if !consumerStopped && err != nil && !isContextInterrupt(err) {
+			yield(Chunk{}, err)
+		}
+	}
+}
+
+// ExecuteChan runs one tool call on a new goroutine and returns its chunks on a channel with the
+// given buffer size (negative means unbuffered), for consumers built around select loops. The chunk
+// channel is closed when the call ends; then exactly one value, the [Registry.Execute] result (nil on
+// success), is sent on the error channel, which is closed afterwards.
+//
+// Backpressure: once the buffer is full, the tool's yield blocks until the consumer reads, so a slow
+// consumer slows the tool instead of growing memory. Cancelling ctx unblocks a pending yield, which
+// then returns the context error to the tool. A consumer that abandons both channels must cancel ctx;
+// the goroutine then exits after the tool returns.
+func (r *Registry) ExecuteChan(ctx context.Context, call ToolCall, buffer int) (<-chan Chunk, <-chan error) {
+	chunks := make(chan Chunk, max(buffer, 0))
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		err := r.Execute(ctx, call, func(c Chunk) error {
+			select {
+			case chunks <- c:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(chunks)
+		errc <- err
+	}()
+	return chunks, errc
+}
+
+// ExecuteBatchIter is the iterator form of [Registry.ExecuteBatchStream]: chunks from all calls
+// arrive in delivery order as (Chunk, nil) pairs, already tagged with CallID and ToolName, and a
+// critical batch error (stream abort, suspend, fail-fast) arrives as a final (Chunk{}, err) pair.
+// Breaking out of the loop makes the running tools' yield return [ErrStreamAborted] and cancels
+// the batch context; the iterator returns only after every call has finished, so no goroutines
+// outlive the loop. Context cancellation ends the iteration without an error pair, as in
+// [Registry.ExecuteIter].
+func (r *Registry) ExecuteBatchIter(ctx context.Context, calls []ToolCall) iter.Seq2[Chunk, error] {
+	return func(yield func(Chunk, error) bool) {
+		// ExecuteBatchStream serializes the callback, so consumerStopped needs no extra locking.
+		var consumerStopped bool
+		err := r.ExecuteBatchStream(ctx, calls, func(c Chunk) error {
+			if consumerStopped {
+				return context.Canceled
+			}
+			if !yield(c, nil) {
+				consumerStopped = true
 				return context.Canceled
 			}
 			return nil
@@ -598,6 +953,8 @@ func (r *Registry) handleBatchToolError(
 	case errors.Is(execErr, ErrStreamAborted):
 		recordStreamAbort(execErr)
 	case isContextInterrupt(execErr):
+	case r.opts.batchFailFast:
+		recordStreamAbort(execErr)
 	default:
 		errChunk := NewErrorChunkFromErr(execErr)
 		prepared, prepErr := prepareChunk(errChunk)
@@ -609,12 +966,17 @@ func (r *Registry) handleBatchToolError(
 		errChunk = prepared
 		errChunk.CallID = call.Input.CallID
 		errChunk.ToolName = call.ToolName
+		summaryText := errorChunkSummaryText(errChunk, execErr)
+		if r.opts.batchLLMErrors {
+			errChunk.Data, errChunk.MimeType = ErrorToLLMJSON(execErr), MimeTypeJSON
+		}
+		stampChunk(&errChunk, summary)
 		yieldErr := safeYield(errChunk)
 		if yieldErr == nil {
 			if summaryReady {
 				summary.Error = nil
 				summary.ErrorChunks++
-				summary.LastErrorText = errorChunkSummaryText(errChunk, execErr)
+				summary.LastErrorText = summaryText
 			}
 			return
 		}
@@ -636,10 +998,10 @@ func (r *Registry) runBatchStreamWorker(
 	var summary ExecutionSummary
 	var summaryReady bool
 	defer func() {
-		if !summaryReady || r.opts.onAfter == nil {
+		if !summaryReady {
 			return
 		}
-		r.opts.onAfter(batchCtx, cloneToolCall(call), summary, time.Since(start))
+		r.runAfterHooks(batchCtx, call, summary, time.Since(start))
 	}()
 	toolYield := func(c Chunk) error {
 		if c.CallID == "" {
@@ -650,24 +1012,38 @@ func (r *Registry) runBatchStreamWorker(
 		}
 		return gate.safeYield(c)
 	}
-	execSummary, ready, err := r.executeWithSummary(batchCtx, call, toolYield, false)
+	// summaryReady stays false while an unrecovered panic unwinds, so the hook is skipped.
+	execSummary, err := r.executeWithSummary(batchCtx, call, toolYield, false)
 	summary = execSummary
-	summaryReady = ready
+	summaryReady = true
 	r.handleBatchToolError(call, err, &summary, summaryReady, gate.safeYield, recordStreamAbort, suspendErr, suspendMu)
 }
 
 // ExecuteBatchStream runs all calls in parallel and streams chunks via yield. Each chunk is
 // tagged with CallID and ToolName. Non-suspend execution failures (including pre-tool dispatch
 // errors and tool/middleware failures) are sent as Chunk with IsError: true; the method returns
-// error only for critical failures (context canceled, stream aborted, suspend). After [Registry.Shutdown],
+// error only for critical failures (context canceled, stream aborted, suspend). [WithBatchErrorsAsChunks](false)
+// restores fail-fast: the first per-call error cancels the siblings and is returned. A yield error likewise
+// cancels the shared batch context so still-running sibling tools can stop via ctx.Done. After [Registry.Shutdown],
 // new calls receive a soft error chunk (IsError: true) per call, not [ErrShutdown] from ExecuteBatchStream itself.
 // For [AsAsyncTool], batch yields sync chunks (typically AsyncAccepted) and returns while background work
 // continues; [Registry.Shutdown] still waits for those background jobs via the async runtime tracker.
 // The library serializes calls to yield with a mutex so the caller's callback need not be thread-safe.
+// [WithOnBatch] wraps the whole batch, for example in a parent trace span.
 func (r *Registry) ExecuteBatchStream(ctx context.Context, calls []ToolCall, yield func(Chunk) error) error {
 	if len(calls) == 0 {
 		return nil
 	}
+	if len(r.opts.onBatch) == 0 {
+		return r.executeBatchStream(ctx, calls, yield)
+	}
+	hookCtx, finish := r.runBatchHooks(ctx, calls)
+	err := r.executeBatchStream(hookCtx, calls, yield)
+	finish(err)
+	return err
+}
+
+func (r *Registry) executeBatchStream(ctx context.Context, calls []ToolCall, yield func(Chunk) error) error {
 	batchCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -720,6 +1096,8 @@ func (r *Registry) ExecuteBatchStream(ctx context.Context, calls []ToolCall, yie
 }
 
 // Shutdown closes the registry for new calls and waits for in-flight executions or ctx to cancel.
+// The registry keeps no admission queue, so every call issued after Shutdown starts fails fast with
+// [ErrShutdown]; concurrency limits applied outside toolsy must re-check their own shutdown signal.
 // Both synchronous executions and background jobs started by AsAsyncTool (when run via Registry) are tracked;
 // Shutdown blocks until all of them finish or ctx is cancelled.
 //
@@ -746,9 +1124,37 @@ func (r *Registry) Shutdown(ctx context.Context) error {
 	}
 }
 
-// panicError wraps a recovered panic value for internal [ToolError].
-type panicError struct{ p any }
+// panicError wraps a recovered panic value for internal [ToolError]. The stack stays out of Error,
+// which may reach the model; [PanicStack] returns it for logs.
+type panicError struct {
+	p     any
+	stack []byte
+}
+
+// newPanicError must be called from the deferred function that recovered p, so the captured stack
+// still holds the panicking frames.
+func newPanicError(p any, captureStack bool) *panicError {
+	e := &panicError{p: p, stack: nil}
+	if captureStack {
+		e.stack = debug.Stack()
+	}
+	return e
+}
 
 func (e *panicError) Error() string {
 	return "panic: " + fmt.Sprint(e.p)
 }
+
+// Stack returns the goroutine stack captured when the panic was recovered, or nil.
+func (e *panicError) Stack() []byte { return e.stack }
+
+// PanicStack returns the stack trace of the recovered panic err wraps, or nil when err is not a
+// recovered panic or its stack was not captured ([WithPanicStacks]). The stack is never part of
+// err's message.
+func PanicStack(err error) []byte {
+	var pe *panicError
+	if errors.As(err, &pe) {
+		return pe.stack
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+)
+
+// CallResult is the collected outcome of one call in [Registry.ExecuteBatch].
+// Result and MimeType hold the last delivered [EventResult] chunk; progress chunks are dropped.
+// Error is set for hard failures returned by Execute and for soft error chunks (as a [*ToolError]).
+type CallResult struct {
+	CallID   string
+	ToolName string
+	Result   []byte
+	MimeType string
+	Error    error
+}
+
+// ExecuteBatch runs all calls in parallel and returns one [CallResult] per call, in input order.
+// The returned slice always has len(calls) entries. Failures are per call: a missing tool, rejected
+// arguments, or a tool error never cancels the other calls, so callers handle partial success by
+// inspecting each Error. Every call goes through [Registry.Execute], including load shedding and
+// the execution hooks. Multi-chunk streams keep only their last result chunk; use
+// [Registry.ExecuteBatchStream] to observe every chunk.
+func (r *Registry) ExecuteBatch(ctx context.Context, calls []ToolCall) []CallResult {
+	results := make([]CallResult, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		results[i] = CallResult{
+			CallID:   call.Input.CallID,
+			ToolName: call.ToolName,
+			Result:   nil,
+			MimeType: "",
+			Error:    nil,
+		}
+		res := &results[i]
+		wg.Go(func() {
+			err := r.Execute(ctx, call, func(c Chunk) error {
+				collectCallResult(res, c)
+				return nil
+			})
+			if err != nil {
+				res.Error = err
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+func collectCallResult(res *CallResult, c Chunk) {
+	if c.IsError {
+		res.Error = executionErrorFromChunk(c)
+		return
+	}
+	if c.Event != EventResult {
+		return
+	}
+	res.Result = c.Data
+	res.MimeType = c.MimeType
+}
@@ -0,0 +1,178 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ExecuteBatch_MixedOutcomesInInputOrder(t *testing.T) {
+	type A struct {
+		X int `json:"x"`
+	}
+	double, err := NewTool("double", "Double", func(_ context.Context, _ *RunEnv, a A) (int, error) {
+		return a.X * 2, nil
+	})
+	require.NoError(t, err)
+	stream, err := NewStreamTool("count", "Count", func(_ context.Context, _ *RunEnv, a A, yield func(Chunk) error) error {
+		if err := yield(Chunk{Event: EventProgress, Data: []byte("half"), MimeType: MimeTypeText}); err != nil {
+			return err
+		}
+		for i := range a.X {
+			if err := yield(Chunk{Event: EventResult, Data: []byte{byte('1' + i)}, MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{double, stream})
+
+	calls := []ToolCall{
+		{ToolName: "double", Input: ToolInput{CallID: "ok", ArgsJSON: []byte(`{"x": 21}`)}},
+		{ToolName: "double", Input: ToolInput{CallID: "bad-args", ArgsJSON: []byte(`{"x": "one"}`)}},
+		{ToolName: "missing", Input: ToolInput{CallID: "no-tool", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "count", Input: ToolInput{CallID: "stream", ArgsJSON: []byte(`{"x": 3}`)}},
+	}
+	results := reg.ExecuteBatch(context.Background(), calls)
+	require.Len(t, results, len(calls))
+	for i, res := range results {
+		assert.Equal(t, calls[i].Input.CallID, res.CallID)
+		assert.Equal(t, calls[i].ToolName, res.ToolName)
+	}
+
+	require.NoError(t, results[0].Error)
+	assert.JSONEq(t, `42`, string(results[0].Result))
+	assert.Equal(t, MimeTypeJSON, results[0].MimeType)
+
+	te, ok := AsToolError(results[1].Error)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+	assert.Nil(t, results[1].Result)
+
+	require.ErrorIs(t, results[2].Error, ErrToolNotFound)
+
+	require.NoError(t, results[3].Error)
+	assert.Equal(t, "3", string(results[3].Result), "multi-chunk streams keep the last result chunk")
+}
+
+func TestRegistry_ExecuteBatch_SoftErrorChunkAndEmptyBatch(t *testing.T) {
+	soft := newMiddlewareMinTool("soft", func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+		return yield(NewErrorChunkFromErr(errors.New("upstream refused")))
+	})
+	reg := mustBuildRegistry(t, []Tool{soft})
+
+	results := reg.ExecuteBatch(context.Background(), []ToolCall{
+		{ToolName: "soft", Input: ToolInput{CallID: "s", ArgsJSON: []byte(`{}`)}},
+	})
+	require.Len(t, results, 1)
+	var te *ToolError
+	require.ErrorAs(t, results[0].Error, &te)
+
+	assert.Empty(t, reg.ExecuteBatch(context.Background(), nil))
+}
+
+func TestRegistry_WithBatchLLMErrors(t *testing.T) {
+	type A struct {
+		X int `json:"x"`
+	}
+	double, err := NewTool("double", "Double", func(_ context.Context, _ *RunEnv, a A) (int, error) {
+		return a.X * 2, nil
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{double}, WithBatchLLMErrors(true))
+	calls := []ToolCall{
+		{ToolName: "double", Input: ToolInput{CallID: "bad-args", ArgsJSON: []byte(`{"x": "one"}`)}},
+		{ToolName: "missing", Input: ToolInput{CallID: "no-tool", ArgsJSON: []byte(`{}`)}},
+	}
+
+	var mu sync.Mutex
+	chunks := map[string]Chunk{}
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		chunks[c.CallID] = c
+		return nil
+	}))
+	for callID, code := range map[string]LLMErrorCode{"bad-args": LLMSchemaViolation, "no-tool": LLMToolNotFound} {
+		c := chunks[callID]
+		require.True(t, c.IsError, callID)
+		assert.Equal(t, MimeTypeJSON, c.MimeType)
+		var body struct {
+			Error struct {
+				Code LLMErrorCode `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(c.Data, &body))
+		assert.Equal(t, code, body.Error.Code, callID)
+		require.NotNil(t, c.Envelope)
+		assert.Equal(t, MimeTypeToolErrorJSON, c.Envelope.MimeType, "the envelope keeps the structured wire")
+	}
+	assert.Equal(t, CodeToolNotFound, executionErrorFromChunk(chunks["no-tool"]).Code)
+
+	results := reg.ExecuteBatch(context.Background(), calls)
+	te, ok := AsToolError(results[0].Error)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+	require.ErrorIs(t, results[1].Error, ErrToolNotFound)
+}
+
+func TestRegistry_ExecuteBatch_CanceledContextReportedPerCall(t *testing.T) {
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "a"), mustNamedTool(t, "b")})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := reg.ExecuteBatch(ctx, []ToolCall{
+		{ToolName: "a", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		{ToolName: "b", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+	})
+	require.Len(t, results, 2)
+	for _, res := range results {
+		require.ErrorIs(t, res.Error, context.Canceled)
+	}
+}
+
+type batchCtxKey struct{}
+
+func TestRegistry_WithOnBatch_ParentContextAndFinish(t *testing.T) {
+	var seen []string
+	tool, err := NewTool("echo", "Echo", func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		v, _ := ctx.Value(batchCtxKey{}).(string)
+		return v, nil
+	})
+	require.NoError(t, err)
+	var finished []error
+	reg := mustBuildRegistry(t, []Tool{tool}, WithOnBatch(func(ctx context.Context, calls []ToolCall) (
+		context.Context, func(error),
+	) {
+		for _, c := range calls {
+			seen = append(seen, c.Input.CallID)
+		}
+		calls[0].ToolName = "mutated"
+		return context.WithValue(ctx, batchCtxKey{}, "batch-1"), func(err error) { finished = append(finished, err) }
+	}))
+	calls := []ToolCall{
+		{ToolName: "echo", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "echo", Input: ToolInput{CallID: "b", ArgsJSON: []byte(`{}`)}},
+	}
+	var results []string
+	var mu sync.Mutex
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, string(c.Data))
+		return nil
+	}))
+	assert.Equal(t, []string{"a", "b"}, seen)
+	assert.Equal(t, "echo", calls[0].ToolName, "the hook gets a copy")
+	assert.Equal(t, []string{`"batch-1"`, `"batch-1"`}, results)
+	assert.Equal(t, []error{nil}, finished)
+
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), nil, func(Chunk) error { return nil }))
+	assert.Len(t, finished, 1, "empty batches skip the hook")
+}
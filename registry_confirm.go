@@ -0,0 +1,68 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithConfirmationHandler requires approval before every call to a tool marked [WithDangerous] or
+// [WithRequiresConfirmation]. The registry first yields a [StatusChunk] with
+// [StatusConfirmationRequired], so streaming UIs can render the prompt, then calls fn with the call and
+// the tool. fn returning false fails the call with [CodeConfirmationDenied] ([ErrConfirmationDenied]),
+// which tells the model the user declined; an error fails it with [CodeInternal].
+//
+// Confirmation runs after authorization, policy, and validators and before the tool, so its latency
+// is excluded from [ExecutionSummary.ExecDuration]. Deadlines belong to the caller's ctx; give the
+// tool its own deadline inside the tool or a middleware if waiting for the user must not consume it.
+// Prompts are serialized per registry (views and scopes share the gate): in
+// [Registry.ExecuteBatchStream] one call waits for approval at a time while the others keep running.
+// A nil fn disables confirmation.
+func WithConfirmationHandler(fn func(ctx context.Context, call ToolCall, tool Tool) (bool, error)) RegistryOption {
+	return func(o *registryOptions) {
+		if fn == nil {
+			o.confirm = nil
+			return
+		}
+		o.confirm = &confirmationGate{fn: fn, turn: make(chan struct{}, 1)}
+	}
+}
+
+// confirmationGate runs the confirmation handler; turn admits one prompt at a time.
+type confirmationGate struct {
+	fn   func(context.Context, ToolCall, Tool) (bool, error)
+	turn chan struct{}
+}
+
+func (g *confirmationGate) confirm(
+	ctx context.Context,
+	call ToolCall,
+	tool Tool,
+	manifest ToolManifest,
+	yield func(Chunk) error,
+) error {
+	if g == nil || (!manifest.Dangerous && !manifest.RequiresConfirmation) {
+		return nil
+	}
+	select {
+	case g.turn <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-g.turn }()
+	prompt := StatusChunk(Status{Phase: StatusConfirmationRequired, Detail: manifest.Name, RetryAfter: 0})
+	if err := yield(prompt); err != nil {
+		return err
+	}
+	approved, err := g.fn(ctx, cloneToolCall(call), tool)
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		return err
+	case err != nil:
+		return NewInternalError(fmt.Errorf("toolsy: confirmation handler: %w", err))
+	case !approved:
+		return NewConfirmationDeniedError()
+	default:
+		return nil
+	}
+}
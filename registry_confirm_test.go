@@ -0,0 +1,130 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type confirmArgs struct {
+	Path string `json:"path,omitempty"`
+}
+
+func newConfirmRegistry(
+	t *testing.T,
+	ran *atomic.Int32,
+	handler func(context.Context, ToolCall, Tool) (bool, error),
+	opts ...RegistryOption,
+) *Registry {
+	t.Helper()
+	run := func(context.Context, *RunEnv, confirmArgs) (string, error) {
+		ran.Add(1)
+		return "done", nil
+	}
+	rm, err := NewTool("rm", "Remove", run, WithDangerous())
+	require.NoError(t, err)
+	send, err := NewTool("send", "Send", run, WithRequiresConfirmation())
+	require.NoError(t, err)
+	ls, err := NewTool("ls", "List", run, WithReadOnly())
+	require.NoError(t, err)
+	return mustBuildRegistry(t, []Tool{rm, send, ls}, append(opts, WithConfirmationHandler(handler))...)
+}
+
+func confirmCall(tool string) ToolCall {
+	return ToolCall{ToolName: tool, Input: ToolInput{CallID: tool + "-1", ArgsJSON: []byte(`{"path":"/tmp/x"}`)}}
+}
+
+func TestWithConfirmationHandler_Approved(t *testing.T) {
+	var ran atomic.Int32
+	var prompted []string
+	reg := newConfirmRegistry(t, &ran, func(_ context.Context, call ToolCall, tool Tool) (bool, error) {
+		prompted = append(prompted, call.ToolName+":"+tool.Manifest().Name+":"+string(call.Input.ArgsJSON))
+		return true, nil
+	})
+	for _, name := range []string{"rm", "send", "ls"} {
+		var chunks []Chunk
+		require.NoError(t, reg.Execute(context.Background(), confirmCall(name), func(c Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		}))
+		status, isStatus := StatusFromChunk(chunks[0])
+		if name == "ls" {
+			assert.False(t, isStatus, "tools that need no confirmation are not prompted")
+			continue
+		}
+		require.True(t, isStatus)
+		assert.Equal(t, StatusConfirmationRequired, status.Phase)
+		assert.Equal(t, name, status.Detail)
+		assert.Equal(t, name+"-1", chunks[0].CallID)
+		assert.Equal(t, EventResult, chunks[1].Event)
+	}
+	assert.Equal(t, []string{`rm:rm:{"path":"/tmp/x"}`, `send:send:{"path":"/tmp/x"}`}, prompted)
+	assert.Equal(t, int32(3), ran.Load())
+}
+
+func TestWithConfirmationHandler_Declined(t *testing.T) {
+	var ran atomic.Int32
+	var summary ExecutionSummary
+	recordSummary := WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+		summary = s
+	})
+	decline := func(context.Context, ToolCall, Tool) (bool, error) { return false, nil }
+	reg := newConfirmRegistry(t, &ran, decline, recordSummary)
+	err := reg.Execute(context.Background(), confirmCall("rm"), func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrConfirmationDenied)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeConfirmationDenied, te.Code)
+	assert.False(t, te.Retryable)
+	assert.Equal(t, FinishClientError, summary.FinishReason)
+	assert.Contains(t, formatExecutionError(err), "the user declined this tool call")
+	assert.Zero(t, ran.Load())
+}
+
+func TestWithConfirmationHandler_HandlerError(t *testing.T) {
+	var ran atomic.Int32
+	uiDown := errors.New("approval UI unreachable")
+	reg := newConfirmRegistry(t, &ran, func(context.Context, ToolCall, Tool) (bool, error) { return false, uiDown })
+	err := reg.Execute(context.Background(), confirmCall("send"), func(Chunk) error { return nil })
+	require.ErrorIs(t, err, uiDown)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.Zero(t, ran.Load())
+}
+
+func TestWithConfirmationHandler_BatchSerializesPrompts(t *testing.T) {
+	var ran, active, maxActive atomic.Int32
+	reg := newConfirmRegistry(t, &ran, func(context.Context, ToolCall, Tool) (bool, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			seen := maxActive.Load()
+			if n <= seen || maxActive.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return true, nil
+	})
+	calls := []ToolCall{confirmCall("rm"), confirmCall("send"), confirmCall("rm"), confirmCall("ls")}
+	calls[2].Input.CallID = "rm-2"
+	var mu sync.Mutex
+	results := 0
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if c.Event == EventResult {
+			results++
+		}
+		return nil
+	}))
+	assert.Equal(t, 4, results)
+	assert.Equal(t, int32(1), maxActive.Load())
+}
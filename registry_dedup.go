@@ -0,0 +1,224 @@
+package toolsy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// DedupOption configures [WithDeduplication].
+type DedupOption func(*dedupGroup)
+
+// DedupByMetadata keeps calls whose [ToolCall.Metadata] differ apart. By default metadata is
+// correlation data and does not affect coalescing; each caller's chunks still carry its own metadata.
+func DedupByMetadata() DedupOption {
+	return func(g *dedupGroup) {
+		g.byMetadata = true
+	}
+}
+
+// WithDeduplication coalesces concurrent executions of the same call into one run of the tool. Calls
+// are the same when they share the tool name, the arguments up to object key order ([CacheKey]), the
+// [ToolCall.Env], and the subject and scope of [ToolCall.CallContext]; the CallID never matters.
+// Calls with attachments and tools marked Dangerous without Idempotent always run on their own.
+// Each caller still goes through admission, hooks, policy, and validation, and receives every chunk
+// of the shared run (including chunks emitted before it joined) tagged with its own CallID, followed
+// by the run's error. A caller whose yield fails or whose ctx ends leaves the run without affecting
+// the others; the run is canceled once no caller is left. The run executes on its own goroutine with
+// the first caller's context values and [RunEnv]. Views and scopes derived from the registry share
+// the in-flight set, but only calls resolving to the same tool instance are coalesced.
+func WithDeduplication(opts ...DedupOption) RegistryOption {
+	return func(o *registryOptions) {
+		g := &dedupGroup{mu: sync.Mutex{}, flights: make(map[string]*dedupFlight), byMetadata: false}
+		for _, opt := range opts {
+			opt(g)
+		}
+		o.dedup = g
+	}
+}
+
+type dedupGroup struct {
+	mu         sync.Mutex
+	flights    map[string]*dedupFlight
+	byMetadata bool
+}
+
+// dedupFlight is one shared tool execution and the callers subscribed to it.
+type dedupFlight struct {
+	members int // callers attached to the flight, guarded by dedupGroup.mu
+	mu      sync.Mutex
+	history []Chunk
+	subs    []*dedupSubscriber
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+	panic   any
+}
+
+type dedupSubscriber struct {
+	mu      sync.Mutex
+	yield   func(Chunk) error
+	left    bool
+	aborted chan error
+}
+
+// deliver forwards c unless the subscriber has left; a yield error detaches it from the flight.
+func (s *dedupSubscriber) deliver(c Chunk) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.left {
+		return false
+	}
+	if err := s.yield(cloneCachedChunk(c)); err != nil {
+		s.left = true
+		s.aborted <- err
+		return false
+	}
+	return true
+}
+
+// leave stops deliveries; it waits for a delivery in progress to finish.
+func (s *dedupSubscriber) leave() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.left = true
+}
+
+// key identifies a call. The resolved tool's identity is part of it, so views and scopes sharing
+// the group never coalesce calls that resolve the same name to different tools.
+func (g *dedupGroup) key(tool Tool, call ToolCall) (string, bool) {
+	if len(call.Input.Attachments) > 0 {
+		return "", false
+	}
+	var manifest ToolManifest
+	manifest.Name = call.ToolName
+	h := sha256.New()
+	h.Write([]byte(CacheKey(manifest, call.Input)))
+	_, _ = fmt.Fprintf(h, "\x00%T:%p", tool, tool)
+	_, _ = fmt.Fprintf(h, "\x00%p\x00%#v\x00%#v", call.Env, call.CallContext.Subject, call.CallContext.Scope)
+	if g.byMetadata {
+		meta, err := json.Marshal(call.Metadata)
+		if err != nil {
+			return "", false
+		}
+		h.Write(meta)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// execute runs tool for call, joining an identical flight in progress when there is one.
+func (g *dedupGroup) execute(
+	ctx context.Context,
+	call ToolCall,
+	env *RunEnv,
+	tool Tool,
+	yield func(Chunk) error,
+) error {
+	manifest := toolManifest(tool)
+	key, ok := g.key(tool, call)
+	if !ok || (manifest.Dangerous && !manifest.Idempotent) {
+		return tool.Execute(ctx, env, call.Input, yield)
+	}
+	sub := &dedupSubscriber{mu: sync.Mutex{}, yield: yield, left: false, aborted: make(chan error, 1)}
+	g.mu.Lock()
+	flight, joined := g.flights[key]
+	if !joined {
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		flight = &dedupFlight{
+			members: 0,
+			mu:      sync.Mutex{},
+			history: nil,
+			subs:    nil,
+			cancel:  cancel,
+			done:    make(chan struct{}),
+			err:     nil,
+			panic:   nil,
+		}
+		g.flights[key] = flight
+		go g.run(runCtx, key, flight, env, tool, call.Input)
+	}
+	flight.members++
+	g.mu.Unlock()
+	flight.subscribe(sub)
+
+	select {
+	case <-flight.done:
+		sub.leave()
+		select {
+		case err := <-sub.aborted:
+			return err
+		default:
+		}
+		if flight.panic != nil {
+			panic(flight.panic)
+		}
+		return flight.err
+	case err := <-sub.aborted:
+		g.unsubscribe(key, flight, sub)
+		return err
+	case <-ctx.Done():
+		sub.leave()
+		g.unsubscribe(key, flight, sub)
+		return ctx.Err()
+	}
+}
+
+func (g *dedupGroup) run(ctx context.Context, key string, f *dedupFlight, env *RunEnv, tool Tool, input ToolInput) {
+	defer func() {
+		f.panic = recover()
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+	broadcast := func(c Chunk) error {
+		f.mu.Lock()
+		f.history = append(f.history, cloneCachedChunk(c))
+		subs := slices.Clone(f.subs)
+		f.mu.Unlock()
+		for _, s := range subs {
+			s.deliver(c)
+		}
+		return nil
+	}
+	// Progress reported by the shared execution goes to every subscriber, not only the first caller.
+	ctx, closeProgress := withProgressReporter(ctx, broadcast)
+	defer closeProgress()
+	f.err = tool.Execute(ctx, env, input, broadcast)
+}
+
+// subscribe replays the chunks emitted so far to sub and adds it to the live set.
+func (f *dedupFlight) subscribe(sub *dedupSubscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.history {
+		if !sub.deliver(c) {
+			return
+		}
+	}
+	f.subs = append(f.subs, sub)
+}
+
+// unsubscribe drops sub; the last caller to leave cancels the run and retires the flight.
+func (g *dedupGroup) unsubscribe(key string, f *dedupFlight, sub *dedupSubscriber) {
+	f.mu.Lock()
+	f.subs = slices.DeleteFunc(f.subs, func(s *dedupSubscriber) bool { return s == sub })
+	f.mu.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f.members--
+	if f.members > 0 {
+		return
+	}
+	f.cancel()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
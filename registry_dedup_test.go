@@ -0,0 +1,242 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ratesArgs struct {
+	Base string `json:"base,omitempty"`
+	X    int    `json:"x,omitempty"`
+}
+
+type dedupHarness struct {
+	reg     *Registry
+	calls   atomic.Int32
+	release chan struct{}
+	fail    error
+}
+
+func newDedupHarness(t *testing.T, opts ...RegistryOption) *dedupHarness {
+	t.Helper()
+	h := &dedupHarness{release: make(chan struct{})}
+	tool, err := NewStreamTool("rates", "Rates", func(
+		ctx context.Context, _ *RunEnv, _ ratesArgs, yield func(Chunk) error,
+	) error {
+		h.calls.Add(1)
+		if err := yield(Chunk{Event: EventProgress, Data: []byte("fetching"), MimeType: MimeTypeText}); err != nil {
+			return err
+		}
+		select {
+		case <-h.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if h.fail != nil {
+			return h.fail
+		}
+		return yield(Chunk{Event: EventResult, Data: []byte(`{"usd":1}`), MimeType: MimeTypeJSON})
+	})
+	require.NoError(t, err)
+	h.reg = mustBuildRegistry(t, []Tool{tool}, append([]RegistryOption{WithDeduplication()}, opts...)...)
+	return h
+}
+
+// waitMembers blocks until n callers are attached to the single in-flight run.
+func (h *dedupHarness) waitMembers(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		g := h.reg.opts.dedup
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		for _, f := range g.flights {
+			if f.members == n {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, time.Millisecond)
+}
+
+type dedupResult struct {
+	chunks []Chunk
+	err    error
+}
+
+func (h *dedupHarness) start(call ToolCall, yield func(Chunk) error) <-chan dedupResult {
+	out := make(chan dedupResult, 1)
+	go func() {
+		var res dedupResult
+		res.err = h.reg.Execute(context.Background(), call, func(c Chunk) error {
+			res.chunks = append(res.chunks, c)
+			if yield != nil {
+				return yield(c)
+			}
+			return nil
+		})
+		out <- res
+	}()
+	return out
+}
+
+func ratesCall(callID, args string) ToolCall {
+	return ToolCall{ToolName: "rates", Input: ToolInput{CallID: callID, ArgsJSON: []byte(args)}}
+}
+
+func TestWithDeduplication_CoalescesIdenticalCalls(t *testing.T) {
+	h := newDedupHarness(t)
+	first := h.start(ratesCall("call-1", `{"base":"EUR","x":1}`), nil)
+	h.waitMembers(t, 1)
+	second := h.start(ratesCall("call-2", `{"x":1,"base":"EUR"}`), nil)
+	h.waitMembers(t, 2)
+	close(h.release)
+
+	for callID, ch := range map[string]<-chan dedupResult{"call-1": first, "call-2": second} {
+		res := <-ch
+		require.NoError(t, res.err)
+		require.Len(t, res.chunks, 2, "late joiner gets the replayed progress chunk")
+		for _, c := range res.chunks {
+			assert.Equal(t, callID, c.CallID)
+		}
+		assert.JSONEq(t, `{"usd":1}`, string(res.chunks[1].Data))
+	}
+	assert.Equal(t, int32(1), h.calls.Load())
+}
+
+func TestWithDeduplication_SharesError(t *testing.T) {
+	h := newDedupHarness(t)
+	h.fail = errors.New("upstream 503")
+	first := h.start(ratesCall("a", `{}`), nil)
+	second := h.start(ratesCall("b", `{}`), nil)
+	h.waitMembers(t, 2)
+	close(h.release)
+	require.ErrorIs(t, (<-first).err, h.fail)
+	require.ErrorIs(t, (<-second).err, h.fail)
+	assert.Equal(t, int32(1), h.calls.Load())
+}
+
+func TestWithDeduplication_AbortedSubscriberDoesNotAbortOthers(t *testing.T) {
+	h := newDedupHarness(t)
+	gone := errors.New("client went away")
+	steady := h.start(ratesCall("b", `{}`), nil)
+	h.waitMembers(t, 1)
+	aborting := h.start(ratesCall("a", `{}`), func(Chunk) error { return gone })
+	require.ErrorIs(t, (<-aborting).err, gone)
+	h.waitMembers(t, 1)
+	close(h.release)
+	res := <-steady
+	require.NoError(t, res.err)
+	assert.Len(t, res.chunks, 2)
+	assert.Equal(t, int32(1), h.calls.Load())
+}
+
+func TestWithDeduplication_LastCallerLeavingCancelsRun(t *testing.T) {
+	h := newDedupHarness(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var err error
+	go func() {
+		defer wg.Done()
+		err = h.reg.Execute(ctx, ratesCall("a", `{}`), func(Chunk) error { return nil })
+	}()
+	h.waitMembers(t, 1)
+	cancel()
+	wg.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Eventually(t, func() bool {
+		g := h.reg.opts.dedup
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return len(g.flights) == 0
+	}, 2*time.Second, time.Millisecond)
+}
+
+func TestWithDeduplication_KeepsDistinctCallsApart(t *testing.T) {
+	h := newDedupHarness(t, WithDeduplication(DedupByMetadata()))
+	base := ratesCall("a", `{}`)
+	otherArgs := ratesCall("b", `{"base":"GBP"}`)
+	otherSubject := base
+	otherSubject.CallContext.Subject = "user-2"
+	otherMeta := base
+	otherMeta.Metadata = map[string]any{"trace": "t2"}
+
+	results := []<-chan dedupResult{
+		h.start(base, nil), h.start(otherArgs, nil), h.start(otherSubject, nil), h.start(otherMeta, nil),
+	}
+	require.Eventually(t, func() bool { return h.calls.Load() == 4 }, 2*time.Second, time.Millisecond)
+	close(h.release)
+	for _, ch := range results {
+		require.NoError(t, (<-ch).err)
+	}
+}
+
+func TestWithDeduplication_KeepsScopeShadowedToolsApart(t *testing.T) {
+	h := newDedupHarness(t)
+	var localCalls atomic.Int32
+	local, err := NewStreamTool("rates", "Local rates", func(
+		ctx context.Context, _ *RunEnv, _ ratesArgs, yield func(Chunk) error,
+	) error {
+		localCalls.Add(1)
+		<-h.release
+		return yield(Chunk{Event: EventResult, Data: []byte(`{"usd":2}`), MimeType: MimeTypeJSON})
+	})
+	require.NoError(t, err)
+	scope, err := h.reg.NewScope(RegistryScopeSpec{Tools: []Tool{local}})
+	require.NoError(t, err)
+
+	parent := h.start(ratesCall("a", `{}`), nil)
+	h.waitMembers(t, 1)
+	scoped := make(chan dedupResult, 1)
+	go func() {
+		var res dedupResult
+		res.err = scope.Execute(context.Background(), ratesCall("b", `{}`), func(c Chunk) error {
+			res.chunks = append(res.chunks, c)
+			return nil
+		})
+		scoped <- res
+	}()
+	require.Eventually(t, func() bool { return localCalls.Load() == 1 }, 2*time.Second, time.Millisecond)
+	close(h.release)
+
+	res := <-scoped
+	require.NoError(t, res.err)
+	require.Len(t, res.chunks, 1)
+	assert.JSONEq(t, `{"usd":2}`, string(res.chunks[0].Data))
+	require.NoError(t, (<-parent).err)
+	assert.Equal(t, int32(1), h.calls.Load())
+}
+
+func TestWithDeduplication_SequentialCallsRunAgain(t *testing.T) {
+	h := newDedupHarness(t)
+	close(h.release)
+	for range 2 {
+		require.NoError(t, (<-h.start(ratesCall("a", `{}`), nil)).err)
+	}
+	assert.Equal(t, int32(2), h.calls.Load())
+}
+
+func TestWithDeduplication_BatchDuplicates(t *testing.T) {
+	h := newDedupHarness(t)
+	close(h.release)
+	calls := []ToolCall{ratesCall("a", `{}`), ratesCall("b", `{}`), ratesCall("c", `{"base":"EUR"}`)}
+	perCall := map[string]int{}
+	var mu sync.Mutex
+	require.NoError(t, h.reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if c.Event == EventResult {
+			perCall[c.CallID]++
+		}
+		return nil
+	}))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, perCall)
+	assert.LessOrEqual(t, h.calls.Load(), int32(3))
+}
@@ -0,0 +1,192 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Without returns a registry without the named tools, reporting whether any of them was present.
+// Unknown names are ignored. Like [Registry.Subset], the result shares runtime state (Shutdown,
+// in-flight tracking) and options with r, and r itself is not modified: executions already running
+// on r complete normally, while calls routed to the returned registry get [ErrToolNotFound].
+// Hosts that hot-swap tools keep the current registry behind an [sync/atomic.Pointer].
+func (r *Registry) Without(names ...string) (*Registry, bool) {
+	if r == nil {
+		return nil, false
+	}
+	tools := maps.Clone(r.tools)
+	versions := maps.Clone(r.versions)
+	removed := false
+	for _, name := range names {
+		if _, ok := tools[name]; ok {
+			delete(tools, name)
+			delete(versions, name)
+			removed = true
+		}
+	}
+	return r.derive(tools, versions), removed
+}
+
+// Replace returns a registry in which the tool registered as name is replaced by t. It fails with
+// [ErrToolNotFound] when name is not registered and rejects a t whose manifest name differs. For a
+// name added with [RegistryBuilder.AddVersioned], t replaces the registered version equal to its
+// own, and the highest version stays the one advertised. Builder middlewares are applied to t, and
+// the result shares runtime state with r exactly as in [Registry.Without].
+func (r *Registry) Replace(name string, t Tool) (*Registry, error) {
+	if r == nil {
+		return nil, NewRegistryStateError()
+	}
+	if _, ok := r.tools[name]; !ok {
+		return nil, fmt.Errorf("toolsy: replace %q: %w", name, ErrToolNotFound)
+	}
+	wrapped, err := wrapRegistryTool(t, r.middlewares)
+	if err != nil {
+		return nil, fmt.Errorf("toolsy: replace %q: %w", name, err)
+	}
+	if got := wrapped.Manifest().Name; got != name {
+		return nil, fmt.Errorf("toolsy: replace %q: replacement tool is named %q", name, got)
+	}
+	tools := maps.Clone(r.tools)
+	versions := r.versions
+	if list, versioned := r.versions[name]; versioned {
+		replaced, replaceErr := replaceToolVersion(list, wrapped)
+		if replaceErr != nil {
+			return nil, fmt.Errorf("toolsy: replace %q: %w", name, replaceErr)
+		}
+		versions = maps.Clone(r.versions)
+		versions[name] = replaced
+		wrapped = replaced[len(replaced)-1]
+	}
+	tools[name] = wrapped
+	return r.derive(tools, versions), nil
+}
+
+func (r *Registry) derive(tools map[string]Tool, versions map[string][]Tool) *Registry {
+	return &Registry{
+		tools:       tools,
+		versions:    versions,
+		middlewares: r.middlewares,
+		opts:        r.opts,
+		state:       r.state,
+		footprints:  &sync.Map{}, // replaced tools must not reuse cached footprints
+		descriptors: &sync.Map{},
+	}
+}
+
+// ReloadReport lists the tool names changed by [Registry.Reload], each sorted.
+type ReloadReport struct {
+	Added    []string
+	Replaced []string
+	Removed  []string
+}
+
+// Reload returns a registry whose config-defined tools match defs: tools built by [BuildTools] that
+// are missing from defs are removed, changed definitions are rebuilt, new ones are added, and
+// unchanged tools are kept as they are. Tools registered in code are never touched, and a definition
+// that collides with one is an error, as is any [BuildTools] failure. Rebuilt tools are checked
+// against [WithToolNameValidation] and the result against [WithMaxTools], as in [RegistryBuilder.Build];
+// r is unchanged on error. The result shares runtime state with r exactly as in [Registry.Without].
+func (r *Registry) Reload(defs []ToolDefinition, handlers map[string]DynamicHandler) (*Registry, ReloadReport, error) {
+	var report ReloadReport
+	if r == nil {
+		return nil, report, NewRegistryStateError()
+	}
+	current := make(map[string]ToolDefinition)
+	for name, t := range r.tools {
+		if def, ok := toolDefinitionOf(t); ok {
+			current[name] = def
+		}
+	}
+	var changed []ToolDefinition
+	wanted := make(map[string]struct{}, len(defs))
+	var errs []error
+	for _, def := range defs {
+		if _, dup := wanted[def.Name]; dup {
+			errs = append(errs, definitionError(def, "duplicate tool name"))
+			continue
+		}
+		wanted[def.Name] = struct{}{}
+		old, defined := current[def.Name]
+		switch {
+		case defined && sameToolDefinition(old, def):
+			continue
+		case defined:
+			report.Replaced = append(report.Replaced, def.Name)
+		case r.tools[def.Name] != nil:
+			errs = append(errs, definitionError(def, "name is already used by a tool registered in code"))
+			continue
+		default:
+			report.Added = append(report.Added, def.Name)
+		}
+		changed = append(changed, def)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, ReloadReport{}, err
+	}
+	built, err := BuildTools(changed, handlers)
+	if err != nil {
+		return nil, ReloadReport{}, err
+	}
+
+	tools := maps.Clone(r.tools)
+	for name := range current {
+		if _, ok := wanted[name]; !ok {
+			delete(tools, name)
+			report.Removed = append(report.Removed, name)
+		}
+	}
+	local := make(map[string]Tool, len(built))
+	for _, t := range built {
+		wrapped, wrapErr := wrapRegistryTool(t, r.middlewares)
+		if wrapErr != nil {
+			return nil, ReloadReport{}, fmt.Errorf("toolsy: reload %q: %w", t.Manifest().Name, wrapErr)
+		}
+		local[wrapped.Manifest().Name] = wrapped
+		tools[wrapped.Manifest().Name] = wrapped
+	}
+	if err := checkRegistryToolNames(local, r.opts.namePattern); err != nil {
+		return nil, ReloadReport{}, fmt.Errorf("toolsy: reload: %w", err)
+	}
+	if n := scopeToolCount(tools, r.versions); r.opts.maxTools > 0 && n > r.opts.maxTools {
+		return nil, ReloadReport{}, fmt.Errorf("toolsy: reload: %w: %d tools, limit %d", ErrTooManyTools, n, r.opts.maxTools)
+	}
+	slices.Sort(report.Added)
+	slices.Sort(report.Replaced)
+	slices.Sort(report.Removed)
+	return r.derive(tools, r.versions), report, nil
+}
+
+func sameToolDefinition(a, b ToolDefinition) bool {
+	a.Source, a.Line = "", 0
+	b.Source, b.Line = "", 0
+	if len(a.Tags) == 0 && len(b.Tags) == 0 {
+		a.Tags, b.Tags = nil, nil
+	}
+	return reflect.DeepEqual(normalizeDefinitionMaps(a), normalizeDefinitionMaps(b))
+}
+
+// normalizeDefinitionMaps round-trips the schema and config through JSON so definitions read from
+// YAML and JSON compare equal.
+func normalizeDefinitionMaps(def ToolDefinition) ToolDefinition {
+	def.Parameters = definitionParameters(def.Parameters)
+	for _, m := range []*map[string]any{&def.Parameters, &def.Config} {
+		if len(*m) == 0 {
+			*m = nil
+			continue
+		}
+		data, err := json.Marshal(*m)
+		if err != nil {
+			continue
+		}
+		var out map[string]any
+		if json.Unmarshal(data, &out) == nil {
+			*m = out
+		}
+	}
+	return def
+}
@@ -0,0 +1,117 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Without(t *testing.T) {
+	t.Parallel()
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "a"), mustNamedTool(t, "b")})
+
+	next, removed := reg.Without("a", "missing")
+	require.True(t, removed)
+	assert.Equal(t, []string{"b"}, next.ToolNames())
+	assert.Equal(t, []string{"a", "b"}, reg.ToolNames(), "parent is not modified")
+
+	err := next.Execute(context.Background(), ToolCall{ToolName: "a", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+
+	_, removed = next.Without("a")
+	assert.False(t, removed)
+}
+
+func TestRegistry_Replace(t *testing.T) {
+	t.Parallel()
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "a")})
+
+	_, err := reg.Replace("missing", mustNamedTool(t, "missing"))
+	require.ErrorIs(t, err, ErrToolNotFound)
+	_, err = reg.Replace("a", mustNamedTool(t, "b"))
+	require.ErrorContains(t, err, `named "b"`)
+
+	replacement := constTool(t, "a", "v2")
+	next, err := reg.Replace("a", replacement)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", executeString(t, next.Execute, "a"))
+}
+
+func TestRegistry_Without_InFlightCompletesAndStateIsShared(t *testing.T) {
+	t.Parallel()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	reg := mustBuildRegistry(t, []Tool{blockingTool("slow", started, release)})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.Execute(context.Background(), ToolCall{ToolName: "slow"}, func(Chunk) error { return nil })
+	}()
+	<-started
+	next, removed := reg.Without("slow")
+	require.True(t, removed)
+	assert.Equal(t, 1, next.InFlight())
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, next.Shutdown(context.Background()))
+	err := reg.Execute(context.Background(), ToolCall{ToolName: "slow"}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestRegistry_HotSwapConcurrent(t *testing.T) {
+	t.Parallel()
+	base := mustBuildRegistry(t, []Tool{constTool(t, "a", "v1"), constTool(t, "b", "b")})
+	replacement := constTool(t, "a", "v2")
+	var current atomic.Pointer[Registry]
+	current.Store(base)
+
+	stop := make(chan struct{})
+	swapped := make(chan struct{})
+	go func() {
+		defer close(swapped)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				next, _ := current.Load().Without("a")
+				current.Store(next)
+				continue
+			}
+			if next, err := base.Replace("a", replacement); err == nil {
+				current.Store(next)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 200 {
+				reg := current.Load()
+				errA := reg.Execute(context.Background(),
+					ToolCall{ToolName: "a", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+					func(Chunk) error { return nil })
+				if errA != nil {
+					assert.ErrorIs(t, errA, ErrToolNotFound)
+				}
+				errB := reg.Execute(context.Background(),
+					ToolCall{ToolName: "b", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+					func(Chunk) error { return nil })
+				assert.NoError(t, errB)
+			}
+		})
+	}
+	wg.Wait()
+	close(stop)
+	<-swapped
+	assert.Zero(t, base.InFlight())
+}
@@ -0,0 +1,123 @@
+package toolsy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// EffectiveDescriptor is the resolved, exporter-facing view of one registered tool.
+// Manifest is a deep copy of what the LLM should see: Parameters already reflect [WithStrict]
+// normalization and hide arguments fixed by [NewBoundTool], Description carries the deprecation
+// notice of [ModelDescription], and Strict/ReadOnly/Dangerous/Idempotent carry the annotations
+// provider exporters map to their own flags. Callers may mutate it freely.
+// Digest fingerprints the descriptor so exporters can cache their converted output per tool.
+// The registry applies no per-tool timeouts, so there is no effective timeout to report.
+type EffectiveDescriptor struct {
+	Manifest ToolManifest
+	ViewID   string
+	Digest   string
+}
+
+// DescribeOption configures [Registry.EffectiveDescriptors].
+type DescribeOption func(*describeOptions)
+
+type describeOptions struct {
+	filter      bool
+	callContext CallContext
+}
+
+// DescribeForCall resolves visibility for one caller: tools that the registry policy (including
+// view policies and requirements checks) denies for cc are omitted. Policies see the manifest,
+// call context, and view but empty input, because no arguments exist before the model chooses a tool.
+func DescribeForCall(cc CallContext) DescribeOption {
+	return func(o *describeOptions) {
+		o.filter = true
+		o.callContext = cc
+	}
+}
+
+// EffectiveDescriptors returns the effective descriptor of every tool in r, sorted by name.
+// Tools marked [WithHidden] are omitted.
+// It is the single source provider exporters should build on; [Tool.Manifest] stays the raw view.
+// Descriptors are computed once per tool and cached with the registry (views share the cache,
+// derived registries from [Registry.Replace] recompute). A nil receiver returns nil.
+func (r *Registry) EffectiveDescriptors(ctx context.Context, opts ...DescribeOption) []EffectiveDescriptor {
+	if r == nil {
+		return nil
+	}
+	var o describeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	names := r.sortedToolNames()
+	out := make([]EffectiveDescriptor, 0, len(names))
+	for _, name := range names {
+		base := r.toolDescriptor(name, r.tools[name])
+		if base.Manifest.Hidden {
+			continue
+		}
+		if o.filter && !r.describeVisible(ctx, base.Manifest, o.callContext) {
+			continue
+		}
+		manifest := cloneDescriptorManifest(base.Manifest)
+		manifest.Description = ModelDescription(manifest)
+		out = append(out, EffectiveDescriptor{
+			Manifest: manifest,
+			ViewID:   r.opts.view.ID,
+			Digest:   base.Digest,
+		})
+	}
+	return out
+}
+
+func (r *Registry) toolDescriptor(name string, t Tool) EffectiveDescriptor {
+	if r.descriptors != nil {
+		if cached, ok := r.descriptors.Load(name); ok {
+			if d, isDescriptor := cached.(EffectiveDescriptor); isDescriptor {
+				return d
+			}
+		}
+	}
+	manifest := cloneDescriptorManifest(t.Manifest())
+	d := EffectiveDescriptor{Manifest: manifest, ViewID: "", Digest: descriptorDigest(manifest)}
+	if r.descriptors != nil {
+		r.descriptors.Store(name, d)
+	}
+	return d
+}
+
+func (r *Registry) describeVisible(ctx context.Context, manifest ToolManifest, cc CallContext) bool {
+	if enforceRequirementsPolicy(manifest.Requirements, r.opts.policy) != nil {
+		return false
+	}
+	req := PolicyRequest{
+		Manifest:    cloneManifestForPolicy(manifest),
+		Input:       ToolInput{CallID: "", ArgsJSON: nil, Attachments: nil},
+		CallContext: cc,
+		View:        cloneRegistryViewSnapshot(r.opts.view),
+	}
+	if r.opts.authorizer != nil && r.opts.authorizer.Authorize(ctx, req) != nil {
+		return false
+	}
+	return evaluatePolicy(ctx, r.opts.policy, req) == nil
+}
+
+func cloneDescriptorManifest(m ToolManifest) ToolManifest {
+	out := cloneManifestForPolicy(m)
+	out.BoundArgs = deepCloneMap(m.BoundArgs)
+	return out
+}
+
+// descriptorDigest extends the view manifest digest with exporter-relevant fields it omits.
+func descriptorDigest(m ToolManifest) string {
+	h := sha256.New()
+	if err := writeManifestDigest(h, m); err != nil {
+		// Schemas that cannot be encoded still get a per-content digest from their printed form.
+		fmt.Fprintf(h, "%v\x00%v\x00", m.Parameters, m.OutputSchema)
+	}
+	fmt.Fprintf(h, "%t\x00%t\x00%s\x00", m.Strict, m.Deprecated, m.DeprecationMessage)
+	_ = writeDigestJSON(h, m.BoundArgs)
+	return hex.EncodeToString(h.Sum(nil))
+}
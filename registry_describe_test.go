@@ -0,0 +1,169 @@
+package toolsy
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type describeForecastArgs struct {
+	City string `json:"city"`
+	Days int    `json:"days,omitempty"`
+}
+
+type describeSearchArgs struct {
+	Query  string `json:"query"`
+	Tenant string `json:"tenant"`
+}
+
+type countingManifestTool struct {
+	Tool
+
+	calls *atomic.Int32
+}
+
+func (t countingManifestTool) Manifest() ToolManifest {
+	t.calls.Add(1)
+	return t.Tool.Manifest()
+}
+
+// exportedFunction is the shape a provider exporter derives from an EffectiveDescriptor.
+type exportedFunction struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+	Strict      bool
+	Destructive bool
+}
+
+func exportDescriptors(ds []EffectiveDescriptor) []exportedFunction {
+	out := make([]exportedFunction, 0, len(ds))
+	for _, d := range ds {
+		out = append(out, exportedFunction{
+			Name:        d.Manifest.Name,
+			Description: d.Manifest.Description,
+			Parameters:  d.Manifest.Parameters,
+			Strict:      d.Manifest.Strict,
+			Destructive: d.Manifest.Dangerous,
+		})
+	}
+	return out
+}
+
+func newDescribeRegistry(t *testing.T, opts ...RegistryOption) *Registry {
+	t.Helper()
+	forecast, err := NewTool(
+		"forecast",
+		"Weather forecast",
+		func(_ context.Context, _ *RunEnv, _ describeForecastArgs) (string, error) { return "", nil },
+		WithStrict(),
+	)
+	require.NoError(t, err)
+	search, err := NewTool(
+		"search_raw",
+		"Search",
+		func(_ context.Context, _ *RunEnv, _ describeSearchArgs) (string, error) { return "", nil },
+	)
+	require.NoError(t, err)
+	bound, err := NewBoundTool("search", search, map[string]any{"tenant": "acme"})
+	require.NoError(t, err)
+	purge, err := NewTool(
+		"purge",
+		"Purge cache",
+		func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) { return "", nil },
+		WithDangerous(),
+	)
+	require.NoError(t, err)
+	return mustBuildRegistry(t, []Tool{forecast, bound, purge}, opts...)
+}
+
+func TestRegistry_EffectiveDescriptors_ExporterMatchesHandComputed(t *testing.T) {
+	adminOnlyDangerous := PolicyFunc(func(_ context.Context, req PolicyRequest) Decision {
+		if req.Manifest.Dangerous && req.CallContext.Subject != "admin" {
+			return DenyDecision("admin only")
+		}
+		return AllowDecision()
+	})
+	reg := newDescribeRegistry(t, WithPolicy("admin-dangerous", adminOnlyDangerous))
+
+	forecast := exportedFunction{
+		Name:        "forecast",
+		Description: "Weather forecast",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"city": map[string]any{"type": "string"},
+				"days": map[string]any{"type": "integer"},
+			},
+			"required":             []any{"city", "days"},
+			"additionalProperties": false,
+		},
+		Strict: true,
+	}
+	search := exportedFunction{
+		Name:        "search",
+		Description: "Search",
+		Parameters: map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"query": map[string]any{"type": "string"}},
+			"required":             []any{"query"},
+			"additionalProperties": false,
+		},
+	}
+	purge := exportedFunction{
+		Name:        "purge",
+		Description: "Purge cache",
+		Parameters:  map[string]any{"type": "object", "additionalProperties": false},
+		Destructive: true,
+	}
+
+	all := reg.EffectiveDescriptors(context.Background())
+	assert.Equal(t, []exportedFunction{forecast, purge, search}, exportDescriptors(all))
+
+	user := reg.EffectiveDescriptors(context.Background(), DescribeForCall(CallContext{Subject: "user"}))
+	assert.Equal(t, []exportedFunction{forecast, search}, exportDescriptors(user))
+
+	admin := reg.EffectiveDescriptors(context.Background(), DescribeForCall(CallContext{Subject: "admin"}))
+	assert.Equal(t, []exportedFunction{forecast, purge, search}, exportDescriptors(admin))
+
+	require.Len(t, all, 3)
+	assert.Equal(t, map[string]any{"tenant": "acme"}, all[2].Manifest.BoundArgs)
+	for _, d := range all {
+		assert.Len(t, d.Digest, 64)
+	}
+	assert.NotEqual(t, all[0].Digest, all[1].Digest)
+}
+
+func TestRegistry_EffectiveDescriptors_CachedAndDefensivelyCopied(t *testing.T) {
+	var calls atomic.Int32
+	tool := countingManifestTool{Tool: mustNamedTool(t, "plain"), calls: &calls}
+	reg := mustBuildRegistry(t, []Tool{tool})
+	calls.Store(0)
+
+	first := reg.EffectiveDescriptors(context.Background())
+	require.Len(t, first, 1)
+	first[0].Manifest.Parameters["type"] = "mutated"
+	first[0].Manifest.Tags = append(first[0].Manifest.Tags, "mutated")
+
+	second := reg.EffectiveDescriptors(context.Background())
+	require.Len(t, second, 1)
+	assert.Equal(t, "object", second[0].Manifest.Parameters["type"])
+	assert.Empty(t, second[0].Manifest.Tags)
+	assert.Equal(t, first[0].Digest, second[0].Digest)
+	assert.Equal(t, int32(1), calls.Load(), "manifest resolved once per tool")
+
+	view, err := reg.View(RegistryViewSpec{Reason: "describe"})
+	require.NoError(t, err)
+	calls.Store(0)
+	viewed := view.EffectiveDescriptors(context.Background())
+	require.Len(t, viewed, 1)
+	assert.Equal(t, view.Snapshot().ID, viewed[0].ViewID)
+	assert.NotEmpty(t, viewed[0].ViewID)
+	assert.Zero(t, calls.Load(), "views share the descriptor cache")
+
+	var nilReg *Registry
+	assert.Nil(t, nilReg.EffectiveDescriptors(context.Background()))
+}
@@ -0,0 +1,84 @@
+package toolsy
+
+// Rough per-value costs used by [Registry.MemoryFootprint]. They approximate Go runtime headers
+// (interface, string, slice, map buckets) and only need to be stable, not exact.
+const (
+	footprintInterfaceBytes = 16
+	footprintStringBytes    = 16
+	footprintSliceBytes     = 24
+	footprintMapBytes       = 48
+	footprintMapEntryBytes  = 16
+)
+
+// MemoryFootprint returns an approximate number of bytes retained by tool manifests in this registry:
+// names, descriptions, tags, and the Parameters/OutputSchema maps.
+// Each tool is measured lazily on first call and cached; views share the cache with their parent.
+// A nil receiver returns 0.
+func (r *Registry) MemoryFootprint() int64 {
+	if r == nil {
+		return 0
+	}
+	var total int64
+	for name, t := range r.tools {
+		total += r.toolFootprint(name, t)
+	}
+	return total
+}
+
+func (r *Registry) toolFootprint(name string, t Tool) int64 {
+	if r.footprints == nil {
+		return manifestFootprint(toolManifest(t))
+	}
+	if cached, ok := r.footprints.Load(name); ok {
+		if n, isInt := cached.(int64); isInt {
+			return n
+		}
+	}
+	n := manifestFootprint(toolManifest(t))
+	r.footprints.Store(name, n)
+	return n
+}
+
+func manifestFootprint(m ToolManifest) int64 {
+	n := footprintStringBytes*3 + int64(len(m.Name)+len(m.Description)+len(m.Version))
+	n += footprintSliceBytes
+	for _, tag := range m.Tags {
+		n += footprintStringBytes + int64(len(tag))
+	}
+	n += valueFootprint(m.Parameters)
+	n += valueFootprint(m.OutputSchema)
+	return n
+}
+
+func valueFootprint(v any) int64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return footprintStringBytes + int64(len(x))
+	case map[string]any:
+		if x == nil {
+			return 0
+		}
+		n := int64(footprintMapBytes)
+		for k, child := range x {
+			n += footprintMapEntryBytes + footprintStringBytes + int64(len(k))
+			n += footprintInterfaceBytes + valueFootprint(child)
+		}
+		return n
+	case []any:
+		n := int64(footprintSliceBytes)
+		for _, child := range x {
+			n += footprintInterfaceBytes + valueFootprint(child)
+		}
+		return n
+	case []string:
+		n := int64(footprintSliceBytes)
+		for _, s := range x {
+			n += footprintStringBytes + int64(len(s))
+		}
+		return n
+	default:
+		return footprintInterfaceBytes
+	}
+}
@@ -0,0 +1,53 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func manySmallDynamicTools(t *testing.T, n int) []Tool {
+	t.Helper()
+	tools := make([]Tool, 0, n)
+	for i := range n {
+		tool, err := newDynamicTool(
+			fmt.Sprintf("dyn_%04d", i),
+			"small dynamic tool",
+			map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"q": map[string]any{"type": "string"}},
+			},
+			func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error { return nil },
+		)
+		require.NoError(t, err)
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+func TestRegistry_MemoryFootprint_StableAndLinear(t *testing.T) {
+	one := mustBuildRegistry(t, manySmallDynamicTools(t, 1))
+	many := mustBuildRegistry(t, manySmallDynamicTools(t, 500))
+
+	perTool := one.MemoryFootprint()
+	require.Positive(t, perTool)
+	total := many.MemoryFootprint()
+	assert.Equal(t, total, many.MemoryFootprint(), "cached estimate must be stable across calls")
+	assert.InDelta(t, float64(perTool*500), float64(total), float64(total)/100)
+}
+
+func TestRegistry_MemoryFootprint_ViewCountsOnlyVisibleTools(t *testing.T) {
+	reg := mustBuildRegistry(t, manySmallDynamicTools(t, 10))
+	sub, err := reg.Subset("dyn_0000", "dyn_0001")
+	require.NoError(t, err)
+	assert.Less(t, sub.MemoryFootprint(), reg.MemoryFootprint())
+	assert.Equal(t, reg.MemoryFootprint()/5, sub.MemoryFootprint())
+}
+
+func TestRegistry_MemoryFootprint_NilReceiver(t *testing.T) {
+	var reg *Registry
+	assert.Zero(t, reg.MemoryFootprint())
+}
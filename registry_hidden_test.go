@@ -0,0 +1,39 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHidden_ExecutableButNotListed(t *testing.T) {
+	t.Parallel()
+	handler := func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: "noted " + a.Name}, nil
+	}
+	visible, err := NewTool("greet", "Greets", handler)
+	require.NoError(t, err)
+	hidden, err := NewTool("__memorize", "Stores a note", handler, WithHidden())
+	require.NoError(t, err)
+	require.True(t, hidden.Manifest().Hidden)
+	reg, err := NewRegistryBuilder().Add(visible, hidden).Build()
+	require.NoError(t, err)
+
+	visibleTools := reg.GetVisibleTools()
+	require.Len(t, visibleTools, 1)
+	assert.Equal(t, "greet", visibleTools[0].Manifest().Name)
+	assert.Len(t, reg.GetAllTools(), 2)
+	_, ok := reg.GetTool("__memorize")
+	assert.True(t, ok)
+
+	descriptors := reg.EffectiveDescriptors(context.Background())
+	require.Len(t, descriptors, 1)
+	assert.Equal(t, "greet", descriptors[0].Manifest.Name)
+
+	out, err := reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "__memorize", Input: ToolInput{ArgsJSON: []byte(`{"name":"x"}`)}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"noted x"}`, string(out))
+}
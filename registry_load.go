@@ -0,0 +1,88 @@
+package toolsy
+
+import "time"
+
+// durationSmoothing is the weight of the newest sample in the execution-duration moving average (1/n).
+const durationSmoothing = 8
+
+// WithLoadShedding makes the registry reject calls instead of admitting them when utilization
+// (in-flight executions including the new call, divided by maxInFlight) would exceed threshold.
+// Rejected calls fail immediately with a retryable [CodeOverloaded] [ToolError] whose Err is an
+// [*OverloadedError] carrying a RetryAfter hint; nothing is queued. [Registry.ExecuteBatchStream]
+// applies the check per call and reports rejections as soft error chunks.
+// maxInFlight <= 0 disables shedding; threshold <= 0 defaults to 1 (shed only above maxInFlight).
+func WithLoadShedding(maxInFlight int, threshold float64) RegistryOption {
+	return func(o *registryOptions) {
+		o.maxInFlight = maxInFlight
+		o.shedThreshold = threshold
+	}
+}
+
+// WithGlobalRateLimit applies one token bucket (perSecond tokens per second, at most burst, minimum 1)
+// to every call the registry executes, across all tools, and to views and scopes derived from it.
+// The limiter runs before a call is admitted, so a [RateLimitWait] wait does not count toward
+// [Registry.InFlight] or [WithLoadShedding]. [Registry.ExecuteBatchStream] calls wait independently.
+// perSecond <= 0 disables the limit. For per-tool limits use the [WithRateLimit] middleware.
+func WithGlobalRateLimit(perSecond float64, burst int, mode RateLimitMode) RegistryOption {
+	return func(o *registryOptions) {
+		o.rateLimiter = newRateLimiter(perSecond, burst)
+		o.rateLimitMode = mode
+	}
+}
+
+// InFlight returns the number of executions currently running, including background [AsAsyncTool] work.
+// Views and scopes share the count with the registry they were derived from.
+func (r *Registry) InFlight() int {
+	if r == nil || r.state == nil {
+		return 0
+	}
+	return int(r.state.inFlight.Load())
+}
+
+// Capacity returns (used, max, ok): in-flight executions and the [WithLoadShedding] limit.
+// ok is false when load shedding is not configured (the registry itself never bounds concurrency).
+func (r *Registry) Capacity() (int, int, bool) {
+	used := r.InFlight()
+	if r == nil || r.opts.maxInFlight <= 0 {
+		return used, 0, false
+	}
+	return used, r.opts.maxInFlight, true
+}
+
+// shedLoad returns an overload error when admitting an execution that brought the count to inFlight
+// exceeds the configured utilization threshold.
+func (r *Registry) shedLoad(state *registryRuntimeState, inFlight int) error {
+	if r.opts.maxInFlight <= 0 {
+		return nil
+	}
+	threshold := r.opts.shedThreshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	if float64(inFlight)/float64(r.opts.maxInFlight) <= threshold {
+		return nil
+	}
+	return NewOverloadedError(inFlight-1, r.opts.maxInFlight, state.averageDuration())
+}
+
+// observeDuration folds d into the moving average used for [OverloadedError.RetryAfter].
+func (s *registryRuntimeState) observeDuration(d time.Duration) {
+	sample := int64(d)
+	for {
+		prev := s.avgNanos.Load()
+		next := sample
+		if prev != 0 {
+			next = prev + (sample-prev)/durationSmoothing
+		}
+		if next <= 0 {
+			next = 1
+		}
+		if s.avgNanos.CompareAndSwap(prev, next) {
+			return
+		}
+	}
+}
+
+func (s *registryRuntimeState) averageDuration() time.Duration {
+	return time.Duration(s.avgNanos.Load())
+}
@@ -0,0 +1,141 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingTool signals started once per execution and blocks until release is closed.
+func blockingTool(name string, started chan<- struct{}, release <-chan struct{}) Tool {
+	return &minTool{
+		manifest: ToolManifest{Name: name, Description: "d", Parameters: map[string]any{"type": "object"}},
+		execute: func(ctx context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			started <- struct{}{}
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return yield(Chunk{Event: EventResult, Data: []byte("ok"), MimeType: MimeTypeText})
+		},
+	}
+}
+
+func TestRegistry_InFlightAndCapacity(t *testing.T) {
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	reg := mustBuildRegistry(t, []Tool{blockingTool("slow", started, release)})
+
+	used, maxInFlight, ok := reg.Capacity()
+	assert.Equal(t, 0, used)
+	assert.Equal(t, 0, maxInFlight)
+	assert.False(t, ok)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Go(func() {
+			_ = reg.Execute(context.Background(), ToolCall{ToolName: "slow"}, func(Chunk) error { return nil })
+		})
+	}
+	for range 3 {
+		<-started
+	}
+	assert.Equal(t, 3, reg.InFlight())
+	view, err := reg.Subset("slow")
+	require.NoError(t, err)
+	assert.Equal(t, 3, view.InFlight(), "views share in-flight tracking")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 0, reg.InFlight())
+
+	require.ErrorIs(t, reg.Execute(context.Background(), ToolCall{ToolName: "missing"}, func(Chunk) error { return nil }),
+		ErrToolNotFound)
+	assert.Equal(t, 0, reg.InFlight())
+}
+
+func TestRegistry_LoadShedding_RejectsWithoutQueueing(t *testing.T) {
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	reg := mustBuildRegistry(t, []Tool{blockingTool("slow", started, release), mustNamedTool(t, "fast")},
+		WithLoadShedding(2, 1))
+	fastCall := ToolCall{ToolName: "fast", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	// Seed the duration average used for the retry-after hint.
+	require.NoError(t, reg.Execute(context.Background(), fastCall, func(Chunk) error { return nil }))
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			_ = reg.Execute(context.Background(), ToolCall{ToolName: "slow"}, func(Chunk) error { return nil })
+		})
+	}
+	<-started
+	<-started
+
+	used, maxInFlight, ok := reg.Capacity()
+	assert.Equal(t, 2, used)
+	assert.Equal(t, 2, maxInFlight)
+	assert.True(t, ok)
+
+	begin := time.Now()
+	err := reg.Execute(context.Background(), fastCall, func(Chunk) error { return nil })
+	assert.Less(t, time.Since(begin), time.Second, "shedding must not wait for capacity")
+	require.ErrorIs(t, err, ErrOverloaded)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeOverloaded, te.Code)
+	assert.True(t, te.Retryable)
+	var overloaded *OverloadedError
+	require.ErrorAs(t, err, &overloaded)
+	assert.Equal(t, 2, overloaded.InFlight)
+	assert.Equal(t, 2, overloaded.Max)
+	assert.Positive(t, overloaded.RetryAfter)
+	assert.Equal(t, 2, reg.InFlight(), "rejected calls are not counted")
+
+	close(release)
+	wg.Wait()
+	require.NoError(t, reg.Execute(context.Background(), fastCall, func(Chunk) error { return nil }))
+}
+
+func TestRegistry_LoadShedding_BatchAppliesPerCall(t *testing.T) {
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	reg := mustBuildRegistry(t, []Tool{blockingTool("slow", started, release), mustNamedTool(t, "fast")},
+		WithLoadShedding(1, 1))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.Execute(context.Background(), ToolCall{ToolName: "slow"}, func(Chunk) error { return nil })
+	}()
+	<-started
+
+	var mu sync.Mutex
+	var errorChunks int
+	err := reg.ExecuteBatchStream(context.Background(), []ToolCall{
+		{ToolName: "fast", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "fast", Input: ToolInput{CallID: "b", ArgsJSON: []byte(`{}`)}},
+	}, func(c Chunk) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if c.IsError {
+			errorChunks++
+			te := executionErrorFromChunk(c)
+			require.NotNil(t, te)
+			assert.Equal(t, CodeOverloaded, te.Code)
+			assert.True(t, errors.Is(te, ErrOverloaded))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, errorChunks)
+
+	close(release)
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,191 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// WithToolLoader sets a read-through loader for tools that are not registered, for catalogs too large
+// to register up front. When Execute (or ExecuteBatch, ExecuteIter, and the other call paths) misses,
+// the registry calls fn with the call's context, so loading counts against its deadline. The returned
+// tool gets the builder middlewares, is cached, and runs the call; GetTool sees it from then on.
+// A (nil, nil) result is [ErrToolNotFound], and the tool's manifest name must equal name and pass
+// [WithToolNameValidation] when it is set.
+//
+// Concurrent misses for one name share a single fn call. Nothing is loaded once [Registry.Shutdown]
+// has started, and [Registry.Unload] evicts cached tools. With [WithMaxTools] the cache keeps at most
+// that many loaded tools (registered tools are not counted) and evicts the oldest loaded first. Loaded tools are not listed by
+// [Registry.GetAllTools] and are never loaded through views or by [Registry.ValidateCall].
+func WithToolLoader(fn func(ctx context.Context, name string) (Tool, error)) RegistryOption {
+	return func(o *registryOptions) {
+		o.toolLoader = fn
+	}
+}
+
+type toolLoaderCache struct {
+	mu      sync.Mutex
+	tools   map[string]Tool
+	order   []string // cached names, oldest first
+	flights map[string]*toolLoadFlight
+}
+
+type toolLoadFlight struct {
+	done chan struct{}
+	tool Tool
+	err  error
+}
+
+func newToolLoaderCache() *toolLoaderCache {
+	return &toolLoaderCache{
+		mu:      sync.Mutex{},
+		tools:   make(map[string]Tool),
+		order:   nil,
+		flights: make(map[string]*toolLoadFlight),
+	}
+}
+
+// store caches t as name and evicts the oldest tools beyond limit; limit <= 0 keeps every tool.
+// The caller holds mu.
+func (c *toolLoaderCache) store(name string, t Tool, limit int) {
+	if _, ok := c.tools[name]; !ok {
+		c.order = append(c.order, name)
+	}
+	c.tools[name] = t
+	for limit > 0 && len(c.order) > limit {
+		delete(c.tools, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// Unload evicts tools cached by [WithToolLoader], reporting whether any was present; the next call
+// loads them again. Calls already running keep their tool. Registered tools are not affected.
+func (r *Registry) Unload(names ...string) bool {
+	if r == nil || r.state == nil || r.state.loader == nil {
+		return false
+	}
+	c := r.state.loader
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := false
+	for _, name := range names {
+		if _, ok := c.tools[name]; ok {
+			delete(c.tools, name)
+			removed = true
+		}
+	}
+	if removed {
+		c.order = slices.DeleteFunc(c.order, func(name string) bool {
+			_, ok := c.tools[name]
+			return !ok
+		})
+	}
+	return removed
+}
+
+func (r *Registry) loadedTool(name string) (Tool, bool) {
+	if r.state == nil || r.state.loader == nil || r.opts.view.ID != "" {
+		return nil, false
+	}
+	c := r.state.loader
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.tools[name]
+	return t, ok
+}
+
+// loadTool returns the cached or freshly loaded tool for name; (nil, nil) means the loader has none.
+// A waiter whose leader failed only because the leader's context ended retries with its own.
+func (r *Registry) loadTool(ctx context.Context, name string) (Tool, error) {
+	c := r.state.loader
+	for {
+		c.mu.Lock()
+		if t, ok := c.tools[name]; ok {
+			c.mu.Unlock()
+			return t, nil
+		}
+		if r.state.isShutdown() {
+			c.mu.Unlock()
+			return nil, NewShutdownError()
+		}
+		f, waiting := c.flights[name]
+		if !waiting {
+			f = &toolLoadFlight{done: make(chan struct{}), tool: nil, err: nil}
+			c.flights[name] = f
+		}
+		c.mu.Unlock()
+
+		if !waiting {
+			r.leadToolLoad(ctx, name, f)
+			return f.tool, f.err
+		}
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if f.err != nil && isContextInterrupt(f.err) && ctx.Err() == nil {
+			continue
+		}
+		return f.tool, f.err
+	}
+}
+
+// leadToolLoad runs the loader for the flight f. The deferred cleanup also runs when the loader
+// panics with [WithRecoverPanics] disabled, so waiters are released and later calls load again.
+func (r *Registry) leadToolLoad(ctx context.Context, name string, f *toolLoadFlight) {
+	c := r.state.loader
+	f.err = NewInternalError(fmt.Errorf("toolsy: load tool %q: loader panicked", name))
+	defer func() {
+		c.mu.Lock()
+		delete(c.flights, name)
+		if f.tool != nil && f.err == nil {
+			if r.state.isShutdown() {
+				f.tool, f.err = nil, NewShutdownError()
+			} else {
+				c.store(name, f.tool, r.opts.maxTools)
+			}
+		}
+		c.mu.Unlock()
+		close(f.done)
+	}()
+	f.tool, f.err = r.runToolLoader(ctx, name)
+}
+
+func (r *Registry) runToolLoader(ctx context.Context, name string) (_ Tool, err error) {
+	if r.opts.recoverPanics {
+		defer func() {
+			if p := recover(); p != nil {
+				err = NewInternalError(fmt.Errorf("toolsy: load tool %q: %w", name,
+					newPanicError(p, !r.opts.noPanicStacks)))
+			}
+		}()
+	}
+	raw, err := r.opts.toolLoader(ctx, name)
+	if err != nil {
+		if isContextInterrupt(err) {
+			return nil, err
+		}
+		if te, ok := AsToolError(err); ok {
+			return nil, te
+		}
+		return nil, NewInternalError(fmt.Errorf("toolsy: load tool %q: %w", name, err))
+	}
+	if raw == nil {
+		return nil, nil //nolint:nilnil // a nil tool means the loader does not know name
+	}
+	t, err := wrapRegistryTool(raw, r.middlewares)
+	if err != nil {
+		return nil, NewInternalError(fmt.Errorf("toolsy: load tool %q: %w", name, err))
+	}
+	if got := t.Manifest().Name; got != name {
+		return nil, NewInternalError(fmt.Errorf("toolsy: load tool %q: loader returned tool %q", name, got))
+	}
+	if r.opts.namePattern != nil {
+		if err := matchToolName(name, r.opts.namePattern); err != nil {
+			return nil, NewInternalError(fmt.Errorf("toolsy: load tool %q: %w", name, err))
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,193 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loaderCatalogTool(t *testing.T, name string) Tool {
+	t.Helper()
+	tool, err := NewTool(name, "Catalog tool", func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: name + ":" + a.Name}, nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestWithToolLoader_LoadsOnceAndAppliesMiddleware(t *testing.T) {
+	t.Parallel()
+	var loads, wrapped atomic.Int32
+	release := make(chan struct{})
+	loader := func(_ context.Context, name string) (Tool, error) {
+		loads.Add(1)
+		<-release
+		if name != "crm_lookup" {
+			return nil, nil
+		}
+		return loaderCatalogTool(t, name), nil
+	}
+	mw := func(next Tool) Tool { return &countingTool{toolBase: toolBase{next: next}, calls: &wrapped} }
+	reg, err := NewRegistryBuilder(WithToolLoader(loader)).Use(mw).Build()
+	require.NoError(t, err)
+
+	call := ToolCall{ToolName: "crm_lookup", Input: ToolInput{ArgsJSON: []byte(`{"name":"acme"}`)}}
+	var wg sync.WaitGroup
+	results := make([]error, 8)
+	for i := range results {
+		wg.Go(func() {
+			out, execErr := reg.ExecuteCollect(context.Background(), call)
+			if execErr == nil && string(out) != `{"greeting":"crm_lookup:acme"}` {
+				execErr = errors.New("unexpected result " + string(out))
+			}
+			results[i] = execErr
+		})
+	}
+	require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, execErr := range results {
+		require.NoError(t, execErr)
+	}
+	assert.Equal(t, int32(1), loads.Load())
+	assert.Equal(t, int32(len(results)), wrapped.Load())
+	_, ok := reg.GetTool("crm_lookup")
+	assert.True(t, ok)
+	assert.Empty(t, reg.GetAllTools())
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "unknown", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	require.ErrorIs(t, err, ErrToolNotFound)
+
+	assert.True(t, reg.Unload("crm_lookup"))
+	assert.False(t, reg.Unload("crm_lookup"))
+	_, err = reg.ExecuteCollect(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), loads.Load())
+}
+
+func TestWithToolLoader_DeadlineAndShutdown(t *testing.T) {
+	t.Parallel()
+	var loads atomic.Int32
+	loader := func(ctx context.Context, name string) (Tool, error) {
+		loads.Add(1)
+		if name == "slow" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		if name == "wrong" {
+			return loaderCatalogTool(t, "other"), nil
+		}
+		return loaderCatalogTool(t, name), nil
+	}
+	reg, err := NewRegistryBuilder(WithToolLoader(loader)).Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = reg.ExecuteCollect(ctx, ToolCall{ToolName: "slow", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "wrong", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+
+	require.NoError(t, reg.Shutdown(context.Background()))
+	before := loads.Load()
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "fresh", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	require.ErrorIs(t, err, ErrShutdown)
+	assert.Equal(t, before, loads.Load())
+}
+
+func TestWithToolLoader_PanicReleasesFlight(t *testing.T) {
+	t.Parallel()
+	var loads atomic.Int32
+	loader := func(_ context.Context, name string) (Tool, error) {
+		if loads.Add(1) == 1 {
+			panic("catalog unavailable")
+		}
+		return loaderCatalogTool(t, name), nil
+	}
+	reg, err := NewRegistryBuilder(WithToolLoader(loader)).Build()
+	require.NoError(t, err)
+	call := ToolCall{ToolName: "search", Input: ToolInput{ArgsJSON: []byte(`{"name":"x"}`)}}
+
+	_, err = reg.ExecuteCollect(context.Background(), call)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeInternal, te.Code)
+	assert.Equal(t, FinishPanic, FinishReasonOf(err))
+	assert.Equal(t, 0, reg.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	out, err := reg.ExecuteCollect(ctx, call)
+	require.NoError(t, err, "the next call loads again instead of waiting on the failed flight")
+	assert.JSONEq(t, `{"greeting":"search:x"}`, string(out))
+	assert.Equal(t, int32(2), loads.Load())
+
+	require.NoError(t, reg.Shutdown(ctx))
+}
+
+func TestWithToolLoader_AppliesNameValidation(t *testing.T) {
+	t.Parallel()
+	loader := func(_ context.Context, name string) (Tool, error) { return loaderCatalogTool(t, name), nil }
+	reg, err := NewRegistryBuilder(WithToolLoader(loader), WithToolNameValidation(regexp.MustCompile(`^[a-z]+$`))).
+		Build()
+	require.NoError(t, err)
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "crm_lookup", Input: ToolInput{ArgsJSON: []byte(`{"name":"x"}`)}})
+	require.ErrorIs(t, err, ErrInvalidToolName)
+	_, cached := reg.GetTool("crm_lookup")
+	assert.False(t, cached, "rejected tools are not cached")
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "search", Input: ToolInput{ArgsJSON: []byte(`{"name":"x"}`)}})
+	require.NoError(t, err)
+}
+
+func TestWithToolLoader_MaxToolsEvictsOldest(t *testing.T) {
+	t.Parallel()
+	var loads atomic.Int32
+	loader := func(_ context.Context, name string) (Tool, error) {
+		loads.Add(1)
+		return loaderCatalogTool(t, name), nil
+	}
+	reg, err := NewRegistryBuilder(WithToolLoader(loader), WithMaxTools(2)).Build()
+	require.NoError(t, err)
+	run := func(name string) {
+		t.Helper()
+		_, execErr := reg.ExecuteCollect(context.Background(),
+			ToolCall{ToolName: name, Input: ToolInput{ArgsJSON: []byte(`{"name":"x"}`)}})
+		require.NoError(t, execErr)
+	}
+
+	run("alpha")
+	run("beta")
+	run("gamma")
+	_, ok := reg.GetTool("alpha")
+	assert.False(t, ok, "the oldest loaded tool is evicted")
+	for _, name := range []string{"beta", "gamma"} {
+		_, ok = reg.GetTool(name)
+		assert.True(t, ok, name)
+	}
+	assert.Equal(t, int32(3), loads.Load())
+
+	assert.True(t, reg.Unload("beta"))
+	run("alpha")
+	run("delta")
+	_, ok = reg.GetTool("gamma")
+	assert.False(t, ok, "Unload frees a slot, so gamma is the oldest when delta arrives")
+	assert.Equal(t, int32(5), loads.Load())
+}
@@ -0,0 +1,273 @@
+package toolsy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// ManifestDigestPrefix prefixes the digest in [Registry.Manifest] documents.
+const ManifestDigestPrefix = "sha256:"
+
+// ManifestChangeKind says how a tool differs between two [Registry.Manifest] documents.
+type ManifestChangeKind string
+
+const (
+	ManifestToolAdded   ManifestChangeKind = "added"
+	ManifestToolRemoved ManifestChangeKind = "removed"
+	ManifestToolChanged ManifestChangeKind = "changed"
+)
+
+// ManifestChange is one difference reported by [CompareManifests].
+type ManifestChange struct {
+	Kind ManifestChangeKind
+	Tool string
+	// Fields lists the changed manifest fields ("description", "parameters", "tags", "version",
+	// "dangerous") of a [ManifestToolChanged] tool.
+	Fields []string
+	// Schema summarizes a parameters change, one line per difference, such as `added property "q"`,
+	// `required "q"`, or `changed keyword "additionalProperties"`.
+	Schema []string
+}
+
+// The fields of these documents are declared in key order so the encoding has sorted keys.
+type manifestDocument struct {
+	Digest string          `json:"digest"`
+	Tools  []manifestEntry `json:"tools"`
+}
+
+type manifestEntry struct {
+	Dangerous   bool            `json:"dangerous"`
+	Description string          `json:"description"`
+	Name        string          `json:"name"`
+	Parameters  json.RawMessage `json:"parameters"`
+	Tags        []string        `json:"tags"`
+	Version     string          `json:"version"`
+}
+
+// Manifest returns a canonical JSON snapshot of the tools a model sees: every visible tool (see
+// [Registry.GetVisibleTools]) sorted by name, with its model-facing description (see
+// [ModelDescription]), parameters schema, version, sorted tags, and dangerous flag. Object keys are
+// sorted and insignificant whitespace is dropped, so equal registries encode to identical bytes.
+// The "digest" field is [ManifestDigestPrefix] followed by the hex SHA-256 of the encoded "tools"
+// array. A nil receiver produces an empty tool list.
+func (r *Registry) Manifest() ([]byte, error) {
+	doc, err := r.manifestDocument()
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(doc)
+}
+
+// ManifestDigest returns the digest of [Registry.Manifest], a cheap key for detecting tool changes
+// (for example to reuse a provider-side prompt cache). It returns "" if a schema cannot be encoded.
+func (r *Registry) ManifestDigest() string {
+	doc, err := r.manifestDocument()
+	if err != nil {
+		return ""
+	}
+	return doc.Digest
+}
+
+func (r *Registry) manifestDocument() (manifestDocument, error) {
+	visible := r.GetVisibleTools()
+	doc := manifestDocument{Digest: "", Tools: make([]manifestEntry, 0, len(visible))}
+	for _, t := range visible {
+		m := t.Manifest()
+		params, err := canonicalJSON(m.Parameters)
+		if err != nil {
+			return manifestDocument{}, fmt.Errorf("toolsy: manifest %q: %w", m.Name, err)
+		}
+		tags := slices.Clone(m.Tags)
+		if tags == nil {
+			tags = []string{}
+		}
+		slices.Sort(tags)
+		doc.Tools = append(doc.Tools, manifestEntry{
+			Dangerous:   m.Dangerous,
+			Description: ModelDescription(m),
+			Name:        m.Name,
+			Parameters:  params,
+			Tags:        tags,
+			Version:     m.Version,
+		})
+	}
+	encoded, err := canonicalJSON(doc.Tools)
+	if err != nil {
+		return manifestDocument{}, fmt.Errorf("toolsy: manifest: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	doc.Digest = ManifestDigestPrefix + hex.EncodeToString(sum[:])
+	return doc, nil
+}
+
+// canonicalJSON encodes v with sorted object keys, numbers kept as written, no HTML escaping, and
+// no insignificant whitespace.
+func canonicalJSON(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// CompareManifests reports how manifest b differs from manifest a, both produced by
+// [Registry.Manifest]: added and removed tools, and changed tools with the fields that differ and
+// a property-level summary of parameter schema changes. Changes are sorted by tool name; equal
+// manifests give none.
+func CompareManifests(a, b []byte) ([]ManifestChange, error) {
+	before, err := parseManifest(a)
+	if err != nil {
+		return nil, fmt.Errorf("toolsy: compare manifests: first: %w", err)
+	}
+	after, err := parseManifest(b)
+	if err != nil {
+		return nil, fmt.Errorf("toolsy: compare manifests: second: %w", err)
+	}
+	names := slices.Collect(maps.Keys(before))
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []ManifestChange
+	for _, name := range names {
+		old, inBefore := before[name]
+		cur, inAfter := after[name]
+		switch {
+		case !inAfter:
+			changes = append(changes, ManifestChange{Kind: ManifestToolRemoved, Tool: name, Fields: nil, Schema: nil})
+		case !inBefore:
+			changes = append(changes, ManifestChange{Kind: ManifestToolAdded, Tool: name, Fields: nil, Schema: nil})
+		default:
+			if change, changed := compareManifestEntries(old, cur); changed {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+func parseManifest(data []byte) (map[string]manifestEntry, error) {
+	var doc manifestDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	out := make(map[string]manifestEntry, len(doc.Tools))
+	for _, e := range doc.Tools {
+		out[e.Name] = e
+	}
+	return out, nil
+}
+
+func compareManifestEntries(a, b manifestEntry) (ManifestChange, bool) {
+	change := ManifestChange{Kind: ManifestToolChanged, Tool: a.Name, Fields: nil, Schema: nil}
+	if a.Description != b.Description {
+		change.Fields = append(change.Fields, "description")
+	}
+	if !bytes.Equal(a.Parameters, b.Parameters) {
+		change.Schema = diffSchemas(decodeSchema(a.Parameters), decodeSchema(b.Parameters))
+		if len(change.Schema) > 0 {
+			change.Fields = append(change.Fields, "parameters")
+		}
+	}
+	if !slices.Equal(a.Tags, b.Tags) {
+		change.Fields = append(change.Fields, "tags")
+	}
+	if a.Version != b.Version {
+		change.Fields = append(change.Fields, "version")
+	}
+	if a.Dangerous != b.Dangerous {
+		change.Fields = append(change.Fields, "dangerous")
+	}
+	return change, len(change.Fields) > 0
+}
+
+func decodeSchema(raw json.RawMessage) map[string]any {
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// diffSchemas summarizes top-level property, required, and keyword differences between two schemas.
+func diffSchemas(a, b map[string]any) []string {
+	var out []string
+	propsA, _ := a["properties"].(map[string]any)
+	propsB, _ := b["properties"].(map[string]any)
+	for _, name := range sortedUnion(propsA, propsB) {
+		pa, inA := propsA[name]
+		pb, inB := propsB[name]
+		switch {
+		case !inB:
+			out = append(out, fmt.Sprintf("removed property %q", name))
+		case !inA:
+			out = append(out, fmt.Sprintf("added property %q", name))
+		case !reflect.DeepEqual(pa, pb):
+			out = append(out, fmt.Sprintf("changed property %q", name))
+		}
+	}
+	reqA := requiredSet(a["required"])
+	reqB := requiredSet(b["required"])
+	for _, name := range sortedUnion(reqA, reqB) {
+		_, inA := reqA[name]
+		_, inB := reqB[name]
+		switch {
+		case inB && !inA:
+			out = append(out, fmt.Sprintf("required %q", name))
+		case inA && !inB:
+			out = append(out, fmt.Sprintf("no longer required %q", name))
+		}
+	}
+	for _, key := range sortedUnion(a, b) {
+		if key == "properties" || key == "required" {
+			continue
+		}
+		if !reflect.DeepEqual(a[key], b[key]) {
+			out = append(out, fmt.Sprintf("changed keyword %q", key))
+		}
+	}
+	return out
+}
+
+func requiredSet(v any) map[string]any {
+	list, _ := v.([]any)
+	out := make(map[string]any, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out[s] = true
+		}
+	}
+	return out
+}
+
+func sortedUnion(a, b map[string]any) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
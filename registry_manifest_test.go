@@ -0,0 +1,103 @@
+package toolsy_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/testutil"
+)
+
+func manifestTool(name string, params map[string]any, tags ...string) *testutil.MockTool {
+	return &testutil.MockTool{
+		ManifestVal: toolsy.ToolManifest{Name: name, Description: name + " tool", Parameters: params, Tags: tags},
+	}
+}
+
+func manifestRegistry(t *testing.T, tools ...toolsy.Tool) *toolsy.Registry {
+	t.Helper()
+	reg, err := toolsy.NewRegistryBuilder().Add(tools...).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func searchSchema() map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"required":   []any{"q"},
+		"properties": map[string]any{"q": map[string]any{"type": "string"}, "limit": map[string]any{"type": "integer"}},
+	}
+}
+
+func TestRegistryManifest_Canonical(t *testing.T) {
+	t.Parallel()
+	hidden := manifestTool("internal", nil)
+	hidden.ManifestVal.Hidden = true
+	a := manifestRegistry(t, manifestTool("search", searchSchema(), "web", "read"), manifestTool("a<b", nil), hidden)
+	b := manifestRegistry(t, hidden, manifestTool("a<b", nil), manifestTool("search", searchSchema(), "read", "web"))
+
+	docA, err := a.Manifest()
+	require.NoError(t, err)
+	docB, err := b.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, string(docA), string(docB), "registration and tag order do not matter")
+	assert.Equal(t, a.ManifestDigest(), b.ManifestDigest())
+	assert.True(t, strings.HasPrefix(a.ManifestDigest(), toolsy.ManifestDigestPrefix))
+	assert.NotContains(t, string(docA), "internal", "hidden tools are not part of the manifest")
+	assert.Contains(t, string(docA), `"name":"a<b"`, "no HTML escaping")
+	assert.Contains(t, string(docA),
+		`"parameters":{"properties":{"limit":{"type":"integer"},"q":{"type":"string"}},"required":["q"],"type":"object"}`)
+	assert.Contains(t, string(docA), `"tags":["read","web"]`)
+
+	var doc struct {
+		Digest string `json:"digest"`
+	}
+	require.NoError(t, json.Unmarshal(docA, &doc))
+	assert.Equal(t, a.ManifestDigest(), doc.Digest)
+
+	c := manifestRegistry(t, manifestTool("search", searchSchema()))
+	assert.NotEqual(t, a.ManifestDigest(), c.ManifestDigest())
+}
+
+func TestCompareManifests(t *testing.T) {
+	t.Parallel()
+	changed := searchSchema()
+	changed["required"] = []any{"q", "limit"}
+	changed["properties"] = map[string]any{"limit": map[string]any{"type": "number"}, "page": map[string]any{}}
+	changed["additionalProperties"] = false
+	before := manifestRegistry(t, manifestTool("search", searchSchema()), manifestTool("old", nil),
+		manifestTool("same", nil))
+	renamed := manifestTool("search", changed, "web")
+	renamed.ManifestVal.Dangerous = true
+	after := manifestRegistry(t, renamed, manifestTool("new", nil), manifestTool("same", nil))
+
+	docBefore, err := before.Manifest()
+	require.NoError(t, err)
+	docAfter, err := after.Manifest()
+	require.NoError(t, err)
+	changes, err := toolsy.CompareManifests(docBefore, docAfter)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, toolsy.ManifestChange{Kind: toolsy.ManifestToolAdded, Tool: "new"}, changes[0])
+	assert.Equal(t, toolsy.ManifestChange{Kind: toolsy.ManifestToolRemoved, Tool: "old"}, changes[1])
+	assert.Equal(t, toolsy.ManifestToolChanged, changes[2].Kind)
+	assert.Equal(t, []string{"parameters", "tags", "dangerous"}, changes[2].Fields)
+	assert.Equal(t, []string{
+		`changed property "limit"`,
+		`added property "page"`,
+		`removed property "q"`,
+		`required "limit"`,
+		`changed keyword "additionalProperties"`,
+	}, changes[2].Schema)
+
+	same, err := toolsy.CompareManifests(docBefore, docBefore)
+	require.NoError(t, err)
+	assert.Empty(t, same)
+
+	_, err = toolsy.CompareManifests([]byte("{"), docAfter)
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package toolsy
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultMountSeparator joins the prefix and tool name in [RegistryBuilder.Mount].
+const DefaultMountSeparator = "."
+
+// MountOption configures [RegistryBuilder.Mount].
+type MountOption func(*mountOptions)
+
+type mountOptions struct {
+	separator string
+}
+
+// WithMountSeparator replaces [DefaultMountSeparator]. OpenAI and Anthropic tool names allow only
+// letters, digits, "_" and "-", so registries exported to them should mount with "_" or "-".
+func WithMountSeparator(sep string) MountOption {
+	return func(o *mountOptions) {
+		o.separator = sep
+	}
+}
+
+// Mount adds every tool of other under the name prefix + separator + original name, so tool sets
+// from different packages cannot collide. Description, parameters and metadata are unchanged, and
+// chunks carry the prefixed [Chunk.ToolName]. Middlewares added with [RegistryBuilder.Use] wrap the
+// mounted tools like any other; middlewares of other stay applied inside them, while its registry
+// options (hooks, limits, policies) do not carry over.
+//
+// Versions added with [RegistryBuilder.AddVersioned] are mounted as versions of the prefixed name.
+// Registries are immutable, so Mount snapshots the tools of other when it is called. An empty
+// prefix or a nil registry makes [RegistryBuilder.Build] fail.
+func (b *RegistryBuilder) Mount(prefix string, other *Registry, opts ...MountOption) *RegistryBuilder {
+	o := mountOptions{separator: DefaultMountSeparator}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if strings.TrimSpace(prefix) == "" {
+		b.errs = append(b.errs, errors.New("toolsy: mount prefix is required"))
+		return b
+	}
+	if other == nil {
+		b.errs = append(b.errs, errors.New("toolsy: cannot mount a nil registry"))
+		return b
+	}
+	for _, name := range other.sortedToolNames() {
+		if list, versioned := other.versions[name]; versioned {
+			for _, t := range list {
+				b.versioned = append(b.versioned, mountedTool(prefix+o.separator+name, t))
+			}
+			continue
+		}
+		b.tools = append(b.tools, mountedTool(prefix+o.separator+name, other.tools[name]))
+	}
+	return b
+}
+
+// mountedTool renames t, keeping an [AsAsyncTool] layer outermost so the parent can re-wrap it.
+func mountedTool(name string, t Tool) Tool {
+	if aw, ok := t.(*asyncTool); ok {
+		return &asyncTool{
+			toolBase: toolBase{next: OverrideTool(aw.next, WithNewName(name))},
+			opts:     aw.opts,
+		}
+	}
+	return OverrideTool(t, WithNewName(name))
+}
@@ -0,0 +1,71 @@
+package toolsy
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mountChild(t *testing.T, mws ...Middleware) *Registry {
+	t.Helper()
+	handler := func(_ context.Context, _ *RunEnv, a collectArgs) (collectOut, error) {
+		return collectOut{Greeting: "found " + a.Name}, nil
+	}
+	lookup, err := NewTool("lookup", "Look up a record", handler, WithTags("search"))
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Use(mws...).Add(lookup).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func TestRegistryBuilder_Mount(t *testing.T) {
+	t.Parallel()
+	var parentCalls, childCalls atomic.Int32
+	parentMW := func(next Tool) Tool { return &countingTool{toolBase: toolBase{next: next}, calls: &parentCalls} }
+	childMW := func(next Tool) Tool { return &countingTool{toolBase: toolBase{next: next}, calls: &childCalls} }
+
+	reg, err := NewRegistryBuilder().Use(parentMW).
+		Mount("crm", mountChild(t, childMW)).
+		Mount("calendar", mountChild(t)).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"calendar.lookup", "crm.lookup"}, reg.ToolNames())
+
+	tool, ok := reg.GetTool("crm.lookup")
+	require.True(t, ok)
+	m := tool.Manifest()
+	assert.Equal(t, "Look up a record", m.Description)
+	assert.Equal(t, []string{"search"}, m.Tags)
+	assert.Contains(t, m.Parameters["properties"], "name")
+
+	var chunks []Chunk
+	err = reg.Execute(context.Background(),
+		ToolCall{ToolName: "crm.lookup", Input: ToolInput{ArgsJSON: []byte(`{"name":"acme"}`)}},
+		func(c Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "crm.lookup", chunks[0].ToolName)
+	assert.JSONEq(t, `{"greeting":"found acme"}`, string(chunks[0].Data))
+	assert.Equal(t, int32(1), parentCalls.Load())
+	assert.Equal(t, int32(1), childCalls.Load())
+}
+
+func TestRegistryBuilder_MountSeparatorAndErrors(t *testing.T) {
+	t.Parallel()
+	reg, err := NewRegistryBuilder().Mount("crm", mountChild(t), WithMountSeparator("_")).Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"crm_lookup"}, reg.ToolNames())
+
+	_, err = NewRegistryBuilder().Mount(" ", mountChild(t)).Build()
+	require.ErrorContains(t, err, "mount prefix is required")
+	_, err = NewRegistryBuilder().Mount("crm", nil).Build()
+	require.ErrorContains(t, err, "nil registry")
+	_, err = NewRegistryBuilder().Mount("crm", mountChild(t)).Mount("crm", mountChild(t)).Build()
+	require.ErrorContains(t, err, `duplicate tool name "crm.lookup"`)
+}
@@ -0,0 +1,192 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RegistryScopeSpec describes conversation-local tools and hooks layered over a parent registry.
+type RegistryScopeSpec struct {
+	// Tools are scope-local; a local tool shadows a parent tool with the same name.
+	// Parent middlewares from [RegistryBuilder.Use] are applied to them.
+	Tools []Tool
+	// OnBeforeExecute, OnAfterExecute, OnError, and OnChunk run after the parent hooks of the same kind.
+	OnBeforeExecute func(context.Context, ToolCall)
+	OnAfterExecute  func(context.Context, ToolCall, ExecutionSummary, time.Duration)
+	OnError         func(context.Context, ToolCall, error)
+	OnChunk         func(context.Context, Chunk)
+}
+
+// RegistryScope is a short-lived registry layered over a parent (for example one per conversation).
+// Lookups check scope-local tools first and fall back to the parent. The scope shares runtime state
+// with the parent: [Registry.Shutdown] on the parent invalidates every scope. [RegistryScope.Close]
+// drops the local tools without touching the parent.
+type RegistryScope struct {
+	reg atomic.Pointer[Registry]
+}
+
+// NewScope creates a [RegistryScope] over r. Local tools are validated like [RegistryBuilder.Build]
+// (nil, empty name, duplicate local names, nested [AsAsyncTool], [WithToolNameValidation]), and
+// [WithMaxTools] caps the tools the scope exposes, parent tools included. Scope-local tools are not
+// part of a view's manifest digest; keep durable capabilities in the parent.
+func (r *Registry) NewScope(spec RegistryScopeSpec) (*RegistryScope, error) {
+	if _, err := r.requireRuntimeState(); err != nil {
+		return nil, fmt.Errorf("toolsy: scope: %w", err)
+	}
+	tools := maps.Clone(r.tools)
+	if tools == nil {
+		tools = make(map[string]Tool, len(spec.Tools))
+	}
+	versions := maps.Clone(r.versions)
+	local := make(map[string]Tool, len(spec.Tools))
+	for _, raw := range spec.Tools {
+		t, err := wrapRegistryTool(raw, r.middlewares)
+		if err != nil {
+			return nil, fmt.Errorf("toolsy: scope: %w", err)
+		}
+		name := t.Manifest().Name
+		if _, dup := local[name]; dup {
+			return nil, fmt.Errorf("toolsy: scope: duplicate tool name %q", name)
+		}
+		local[name] = t
+		tools[name] = t
+		delete(versions, name)
+	}
+	if err := checkRegistryToolNames(local, r.opts.namePattern); err != nil {
+		return nil, fmt.Errorf("toolsy: scope: %w", err)
+	}
+	if n := scopeToolCount(tools, versions); r.opts.maxTools > 0 && n > r.opts.maxTools {
+		return nil, fmt.Errorf("toolsy: scope: %w: %d tools, limit %d", ErrTooManyTools, n, r.opts.maxTools)
+	}
+	opts := r.opts
+	opts.onBefore = appendHook(opts.onBefore, spec.OnBeforeExecute)
+	opts.onAfter = appendHook(opts.onAfter, spec.OnAfterExecute)
+	opts.onError = appendHook(opts.onError, spec.OnError)
+	opts.onChunk = appendHook(opts.onChunk, spec.OnChunk)
+
+	scope := &RegistryScope{reg: atomic.Pointer[Registry]{}}
+	scope.reg.Store(&Registry{
+		tools:       tools,
+		versions:    versions,
+		middlewares: r.middlewares,
+		opts:        opts,
+		state:       r.state,
+		footprints:  &sync.Map{},
+		descriptors: &sync.Map{},
+	})
+	return scope, nil
+}
+
+// scopeToolCount counts tools the way [WithMaxTools] does at Build: every registered version of a
+// versioned tool counts, while tools holds only its latest.
+func scopeToolCount(tools map[string]Tool, versions map[string][]Tool) int {
+	n := len(tools)
+	for _, vs := range versions {
+		n += max(len(vs)-1, 0)
+	}
+	return n
+}
+
+func (s *RegistryScope) registry() (*Registry, error) {
+	if s == nil {
+		return nil, NewRegistryStateError()
+	}
+	reg := s.reg.Load()
+	if reg == nil {
+		return nil, NewShutdownError()
+	}
+	return reg, nil
+}
+
+// Execute runs one call against scope-local tools, then parent tools. See [Registry.Execute].
+// After [RegistryScope.Close] it returns [ErrShutdown].
+func (s *RegistryScope) Execute(ctx context.Context, call ToolCall, yield func(Chunk) error) error {
+	reg, err := s.registry()
+	if err != nil {
+		return err
+	}
+	return reg.Execute(ctx, call, yield)
+}
+
+// ExecuteIter is the iterator form of [RegistryScope.Execute]. See [Registry.ExecuteIter].
+func (s *RegistryScope) ExecuteIter(ctx context.Context, call ToolCall) iter.Seq2[Chunk, error] {
+	reg, err := s.registry()
+	if err != nil {
+		return func(yield func(Chunk, error) bool) {
+			yield(Chunk{}, err)
+		}
+	}
+	return reg.ExecuteIter(ctx, call)
+}
+
+// ExecuteBatchStream runs calls in parallel against the scope. See [Registry.ExecuteBatchStream].
+// After [RegistryScope.Close] it returns [ErrShutdown].
+func (s *RegistryScope) ExecuteBatchStream(ctx context.Context, calls []ToolCall, yield func(Chunk) error) error {
+	reg, err := s.registry()
+	if err != nil {
+		return err
+	}
+	return reg.ExecuteBatchStream(ctx, calls, yield)
+}
+
+// ExecuteBatchIter is the iterator form of [RegistryScope.ExecuteBatchStream].
+// See [Registry.ExecuteBatchIter].
+func (s *RegistryScope) ExecuteBatchIter(ctx context.Context, calls []ToolCall) iter.Seq2[Chunk, error] {
+	reg, err := s.registry()
+	if err != nil {
+		return func(yield func(Chunk, error) bool) {
+			yield(Chunk{}, err)
+		}
+	}
+	return reg.ExecuteBatchIter(ctx, calls)
+}
+
+// GetAllTools returns local and inherited tools sorted by name; nil after Close.
+func (s *RegistryScope) GetAllTools() []Tool {
+	reg, err := s.registry()
+	if err != nil {
+		return nil
+	}
+	return reg.GetAllTools()
+}
+
+// GetVisibleTools returns local and inherited tools not marked hidden, sorted by name; nil after Close.
+func (s *RegistryScope) GetVisibleTools() []Tool {
+	reg, err := s.registry()
+	if err != nil {
+		return nil
+	}
+	return reg.GetVisibleTools()
+}
+
+// GetTool resolves name against local tools first, then the parent.
+func (s *RegistryScope) GetTool(name string) (Tool, bool) {
+	reg, err := s.registry()
+	if err != nil {
+		return nil, false
+	}
+	return reg.GetTool(name)
+}
+
+// ToolNames returns local and inherited tool names, sorted; nil after Close.
+func (s *RegistryScope) ToolNames() []string {
+	reg, err := s.registry()
+	if err != nil {
+		return nil
+	}
+	return reg.ToolNames()
+}
+
+// Close releases scope-local tools. Calls already in flight finish normally; new calls fail with
+// [ErrShutdown]. Close never shuts down the parent and is idempotent.
+func (s *RegistryScope) Close() {
+	if s == nil {
+		return
+	}
+	s.reg.Store(nil)
+}
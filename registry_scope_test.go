@@ -0,0 +1,164 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constTool(t *testing.T, name, value string) Tool {
+	t.Helper()
+	tool, err := NewTool(name, name, func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		return value, nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func executeString(t *testing.T, exec func(context.Context, ToolCall, func(Chunk) error) error, name string) string {
+	t.Helper()
+	var out string
+	call := ToolCall{ToolName: name, Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	err := exec(context.Background(), call, func(c Chunk) error {
+		return json.Unmarshal(c.Data, &out)
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestRegistryScope_LocalShadowsParent(t *testing.T) {
+	parent := mustBuildRegistry(t, []Tool{constTool(t, "shared", "parent"), constTool(t, "global", "global")})
+	scope, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{
+		constTool(t, "shared", "local"),
+		constTool(t, "upload_handle", "file-1"),
+	}})
+	require.NoError(t, err)
+	defer scope.Close()
+
+	assert.Equal(t, "local", executeString(t, scope.Execute, "shared"))
+	assert.Equal(t, "global", executeString(t, scope.Execute, "global"))
+	assert.Equal(t, "file-1", executeString(t, scope.Execute, "upload_handle"))
+	assert.Equal(t, []string{"global", "shared", "upload_handle"}, scope.ToolNames())
+
+	assert.Equal(t, "parent", executeString(t, parent.Execute, "shared"))
+	assert.False(t, parent.Has("upload_handle"))
+}
+
+func TestRegistryScope_DuplicateLocalName(t *testing.T) {
+	parent := mustBuildRegistry(t, nil)
+	_, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{constTool(t, "x", "1"), constTool(t, "x", "2")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate tool name")
+}
+
+type countingTool struct {
+	toolBase
+
+	calls *atomic.Int32
+}
+
+func (c *countingTool) Execute(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	c.calls.Add(1)
+	return c.next.Execute(ctx, env, input, yield)
+}
+
+func TestRegistryScope_InheritsMiddlewareAndChainsHooks(t *testing.T) {
+	var wrapped atomic.Int32
+	mw := func(next Tool) Tool { return &countingTool{toolBase: toolBase{next: next}, calls: &wrapped} }
+	var order []string
+	var mu sync.Mutex
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, s)
+	}
+	parent, err := NewRegistryBuilder(
+		WithOnAfterExecute(func(context.Context, ToolCall, ExecutionSummary, time.Duration) { record("parent") }),
+	).Use(mw).Build()
+	require.NoError(t, err)
+	scope, err := parent.NewScope(RegistryScopeSpec{
+		Tools:          []Tool{constTool(t, "local", "v")},
+		OnAfterExecute: func(context.Context, ToolCall, ExecutionSummary, time.Duration) { record("scope") },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "v", executeString(t, scope.Execute, "local"))
+	assert.Equal(t, int32(1), wrapped.Load())
+	assert.Equal(t, []string{"parent", "scope"}, order)
+}
+
+func TestRegistryScope_CloseAndParentShutdown(t *testing.T) {
+	parent := mustBuildRegistry(t, []Tool{constTool(t, "global", "g")})
+	closed, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{constTool(t, "local", "l")}})
+	require.NoError(t, err)
+	closed.Close()
+	closed.Close()
+
+	err = closed.Execute(context.Background(), ToolCall{ToolName: "local"}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrShutdown)
+	assert.Nil(t, closed.GetAllTools())
+	assert.Equal(t, "g", executeString(t, parent.Execute, "global"))
+
+	live, err := parent.NewScope(RegistryScopeSpec{})
+	require.NoError(t, err)
+	require.NoError(t, parent.Shutdown(context.Background()))
+	err = live.Execute(context.Background(), ToolCall{ToolName: "global"}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestRegistryScope_ConcurrentScopesOverSharedParent(t *testing.T) {
+	parent := mustBuildRegistry(t, []Tool{constTool(t, "global", "g")})
+	locals := make([]Tool, 32)
+	for i := range locals {
+		name := fmt.Sprintf("local_%d", i)
+		locals[i] = constTool(t, name, name)
+	}
+	// Runs on worker goroutines, so it reports with assert instead of stopping the test.
+	execute := func(scope *RegistryScope, name string) string {
+		var out string
+		err := scope.Execute(context.Background(), ToolCall{ToolName: name, Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+			func(c Chunk) error { return json.Unmarshal(c.Data, &out) })
+		assert.NoError(t, err)
+		return out
+	}
+	var wg sync.WaitGroup
+	for i, local := range locals {
+		wg.Go(func() {
+			name := fmt.Sprintf("local_%d", i)
+			scope, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{local}})
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer scope.Close()
+			for range 10 {
+				assert.Equal(t, name, execute(scope, name))
+				assert.Equal(t, "g", execute(scope, "global"))
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"global"}, parent.ToolNames())
+}
+
+func TestRegistryScope_AppliesNameValidationAndMaxTools(t *testing.T) {
+	parent := mustBuildRegistry(t, []Tool{constTool(t, "global", "g")}, WithMaxTools(2), WithToolNameValidation(nil))
+	bad := &minTool{manifest: ToolManifest{Name: "has space", Description: "d"}}
+	_, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{bad}})
+	require.ErrorIs(t, err, ErrInvalidToolName)
+
+	scope, err := parent.NewScope(RegistryScopeSpec{Tools: []Tool{constTool(t, "a", "a")}})
+	require.NoError(t, err)
+	scope.Close()
+	scope, err = parent.NewScope(RegistryScopeSpec{Tools: []Tool{constTool(t, "global", "shadow")}})
+	require.NoError(t, err, "shadowing a parent tool does not add to the count")
+	scope.Close()
+	_, err = parent.NewScope(RegistryScopeSpec{Tools: []Tool{constTool(t, "a", "a"), constTool(t, "b", "b")}})
+	require.ErrorIs(t, err, ErrTooManyTools)
+}
@@ -0,0 +1,94 @@
+package toolsy
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const maxToolSuggestions = 3
+
+// toolNotFoundError builds the not-found error for name, with suggestions unless disabled.
+func (r *Registry) toolNotFoundError(name string) *ToolError {
+	if r.opts.noSuggestions {
+		return NewToolNotFoundError()
+	}
+	return NewUnknownToolError(name, suggestToolNames(name, r.tools)...)
+}
+
+type toolSuggestion struct {
+	name string
+	dist int
+}
+
+// suggestToolNames returns up to three registered names within an edit distance that grows with the
+// length of name, closest first. Names sharing a prefix of at least three characters with name (in
+// either direction) rank as distance 1. Tools marked [WithHidden] are never suggested, since the
+// suggestions reach the model. It scans the map once, so it stays linear in the tool count.
+func suggestToolNames(name string, tools map[string]Tool) []string {
+	want := strings.ToLower(name)
+	if want == "" {
+		return nil
+	}
+	limit := max(2, len(want)/3+1)
+	var found []toolSuggestion
+	for candidate, t := range tools {
+		if toolManifest(t).Hidden {
+			continue
+		}
+		have := strings.ToLower(candidate)
+		dist := boundedLevenshtein(want, have, limit)
+		if isNamePrefix(want, have) {
+			dist = min(dist, 1)
+		}
+		if dist <= limit {
+			found = append(found, toolSuggestion{name: candidate, dist: dist})
+		}
+	}
+	slices.SortFunc(found, func(a, b toolSuggestion) int {
+		return cmp.Or(cmp.Compare(a.dist, b.dist), cmp.Compare(a.name, b.name))
+	})
+	out := make([]string, 0, min(len(found), maxToolSuggestions))
+	for _, s := range found[:min(len(found), maxToolSuggestions)] {
+		out = append(out, s.name)
+	}
+	return out
+}
+
+func isNamePrefix(a, b string) bool {
+	short, long := a, b
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	return len(short) >= 3 && short != long && strings.HasPrefix(long, short)
+}
+
+// boundedLevenshtein returns the byte edit distance between a and b, or limit+1 once it is certain to
+// exceed limit.
+func boundedLevenshtein(a, b string, limit int) int {
+	if d := len(a) - len(b); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
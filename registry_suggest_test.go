@@ -0,0 +1,87 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func suggestRegistry(t *testing.T, opts ...RegistryOption) *Registry {
+	t.Helper()
+	b := NewRegistryBuilder(opts...)
+	for _, name := range []string{"get_weather", "get_webcam", "send_email", "search"} {
+		tool, err := NewTool(name, "Test tool", func(context.Context, *RunEnv, struct{}) (string, error) {
+			return "ok", nil
+		})
+		require.NoError(t, err)
+		b.Add(tool)
+	}
+	reg, err := b.Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func TestExecute_UnknownToolSuggestsNames(t *testing.T) {
+	t.Parallel()
+	reg := suggestRegistry(t)
+	err := reg.Execute(context.Background(),
+		ToolCall{ToolName: "get_wether", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeToolNotFound, te.Code)
+	assert.Equal(t, `unknown tool "get_wether"; did you mean: get_weather, get_webcam?`, te.Reason)
+
+	err = reg.ValidateCall(ToolCall{ToolName: "search_web", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	require.ErrorIs(t, err, ErrToolNotFound)
+	assert.Contains(t, err.Error(), "did you mean: search?")
+}
+
+func TestExecute_UnknownToolWithoutMatchOrSuggestions(t *testing.T) {
+	t.Parallel()
+	call := ToolCall{ToolName: "get_wether", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+	reg := suggestRegistry(t, WithToolSuggestions(false))
+	err := reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+	assert.NotContains(t, err.Error(), "did you mean")
+
+	call.ToolName = "translate_document"
+	err = suggestRegistry(t).Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+	assert.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestExecute_UnknownToolNeverSuggestsHiddenTools(t *testing.T) {
+	t.Parallel()
+	fn := func(context.Context, *RunEnv, struct{}) (string, error) { return "ok", nil }
+	hidden, err := NewTool("admin_delete_all", "Internal", fn, WithHidden())
+	require.NoError(t, err)
+	visible, err := NewTool("admin_deletes", "Deletion log", fn)
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Add(hidden, visible).Build()
+	require.NoError(t, err)
+
+	err = reg.Execute(context.Background(),
+		ToolCall{ToolName: "admin_delete", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, `unknown tool "admin_delete"; did you mean: admin_deletes?`, te.Reason)
+	assert.NotContains(t, string(ErrorToLLMJSON(err)), "admin_delete_all")
+}
+
+func TestSuggestToolNames_LimitsAndOrders(t *testing.T) {
+	t.Parallel()
+	tools := make(map[string]Tool)
+	var placeholder minTool
+	for i := range 2000 {
+		tools[fmt.Sprintf("tool_%04d", i)] = placeholder
+	}
+	tools["Lookup"] = placeholder
+	assert.Equal(t, []string{"Lookup"}, suggestToolNames("lookup", tools))
+	assert.Len(t, suggestToolNames("tool_0001", tools), maxToolSuggestions)
+	assert.Empty(t, suggestToolNames("", tools))
+}
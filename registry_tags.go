@@ -0,0 +1,53 @@
+package toolsy
+
+import "slices"
+
+// MatchMode selects how [Registry.GetToolsByTags] combines several tags.
+type MatchMode int
+
+const (
+	// MatchAny selects tools carrying at least one of the tags.
+	MatchAny MatchMode = iota
+	// MatchAll selects tools carrying every one of the tags.
+	MatchAll
+)
+
+// GetToolsByTag returns the tools whose [ToolManifest.Tags] contain tag, sorted by manifest name.
+// Tools without tags never match. A nil receiver returns nil.
+func (r *Registry) GetToolsByTag(tag string) []Tool {
+	return r.GetToolsByTags(MatchAny, tag)
+}
+
+// GetToolsByTags returns the tools matching tags under mode, sorted by manifest name like
+// [Registry.GetAllTools]. Tools without tags never match, and no tags select no tools.
+// A nil receiver returns nil.
+func (r *Registry) GetToolsByTags(mode MatchMode, tags ...string) []Tool {
+	if r == nil || len(tags) == 0 {
+		return nil
+	}
+	var out []Tool
+	for _, name := range r.sortedToolNames() {
+		t := r.tools[name]
+		if manifestMatchesTags(toolManifest(t).Tags, mode, tags) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func manifestMatchesTags(have []string, mode MatchMode, want []string) bool {
+	if mode == MatchAll {
+		for _, tag := range want {
+			if !slices.Contains(have, tag) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, tag := range want {
+		if slices.Contains(have, tag) {
+			return true
+		}
+	}
+	return false
+}
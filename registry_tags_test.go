@@ -0,0 +1,65 @@
+package toolsy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/testutil"
+)
+
+func tagRegistry(t *testing.T) *toolsy.Registry {
+	t.Helper()
+	handler := func(context.Context, *toolsy.RunEnv, struct{}) (string, error) { return "ok", nil }
+	search, err := toolsy.NewTool("web_search", "Search the web", handler, toolsy.WithTags("search", "read"))
+	require.NoError(t, err)
+	docs, err := toolsy.NewTool("docs_search", "Search docs", handler, toolsy.WithTags("search"))
+	require.NoError(t, err)
+	write, err := toolsy.NewTool("file_write", "Write a file", handler, toolsy.WithTags("write"))
+	require.NoError(t, err)
+	plain := &testutil.MockTool{ManifestVal: toolsy.ToolManifest{
+		Name:        "plain",
+		Description: "No tags",
+		Parameters:  map[string]any{"type": "object"},
+	}}
+	reg, err := toolsy.NewRegistryBuilder().Add(write, plain, search, docs).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func toolNames(tools []toolsy.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Manifest().Name)
+	}
+	return names
+}
+
+func TestRegistry_GetToolsByTag(t *testing.T) {
+	t.Parallel()
+	reg := tagRegistry(t)
+	assert.Equal(t, []string{"docs_search", "web_search"}, toolNames(reg.GetToolsByTag("search")))
+	assert.Equal(t, []string{"file_write"}, toolNames(reg.GetToolsByTag("write")))
+	assert.Empty(t, reg.GetToolsByTag("missing"))
+	assert.Empty(t, reg.GetToolsByTag(""))
+}
+
+func TestRegistry_GetToolsByTags(t *testing.T) {
+	t.Parallel()
+	reg := tagRegistry(t)
+	assert.Equal(t, []string{"docs_search", "file_write", "web_search"},
+		toolNames(reg.GetToolsByTags(toolsy.MatchAny, "search", "write")))
+	assert.Equal(t, []string{"web_search"}, toolNames(reg.GetToolsByTags(toolsy.MatchAll, "search", "read")))
+	assert.Empty(t, reg.GetToolsByTags(toolsy.MatchAll, "search", "write"))
+	assert.Empty(t, reg.GetToolsByTags(toolsy.MatchAny))
+}
+
+func TestRegistry_GetToolsByTag_NilRegistry(t *testing.T) {
+	t.Parallel()
+	var reg *toolsy.Registry
+	assert.Nil(t, reg.GetToolsByTag("search"))
+	assert.Nil(t, reg.GetToolsByTags(toolsy.MatchAll, "search"))
+}
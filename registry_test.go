@@ -68,6 +68,66 @@ func TestRegistryBuilder_DuplicateToolName(t *testing.T) {
 	assert.Contains(t, err.Error(), "duplicate tool name")
 }
 
+func TestRegistryBuilder_AddOrReplaceMatrix(t *testing.T) {
+	first := minTool{manifest: ToolManifest{Name: "search", Description: "first"}}   //nolint:exhaustruct // test
+	second := minTool{manifest: ToolManifest{Name: "search", Description: "second"}} //nolint:exhaustruct // test
+	other := minTool{manifest: ToolManifest{Name: "other"}}                          //nolint:exhaustruct // test
+	describe := func(r *Registry) string {
+		got, ok := r.GetTool("search")
+		require.True(t, ok)
+		return got.Manifest().Description
+	}
+
+	_, err := NewRegistryBuilder().Add(first, other, second).Build()
+	require.ErrorIs(t, err, ErrDuplicateTool)
+	assert.ErrorContains(t, err, `duplicate tool name "search"`)
+
+	reg, err := NewRegistryBuilder().Add(first, other).AddOrReplace(second).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "second", describe(reg))
+	assert.Equal(t, []string{"other", "search"}, reg.ToolNames())
+
+	reg, err = NewRegistryBuilder().AddOrReplace(first).AddOrReplace(second).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "second", describe(reg))
+
+	_, err = NewRegistryBuilder().AddOrReplace(first).Add(second).Build()
+	require.ErrorIs(t, err, ErrDuplicateTool, "Add after AddOrReplace still refuses to overwrite")
+
+	var calls int
+	mw := func(next Tool) Tool { calls++; return next }
+	reg, err = NewRegistryBuilder().Use(mw).Add(first).AddOrReplace(second).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "middlewares wrap only the surviving tool")
+	assert.Equal(t, "second", describe(reg))
+	assert.Len(t, reg.GetAllTools(), 1)
+
+	v1 := minTool{manifest: ToolManifest{Name: "search", Version: "1.0.0"}} //nolint:exhaustruct // test
+	v2 := minTool{manifest: ToolManifest{Name: "search", Version: "2.0.0"}} //nolint:exhaustruct // test
+	reg, err = NewRegistryBuilder().AddVersioned(v1, v2).AddOrReplace(second).Build()
+	require.NoError(t, err, "replacing a versioned tool drops all of its versions")
+	assert.Equal(t, "second", describe(reg))
+	_, ok := reg.GetToolVersion("search", "1.0.0")
+	assert.False(t, ok)
+	assert.Len(t, reg.ToolVersions("search"), 1)
+
+	_, err = NewRegistryBuilder().AddOrReplace(nil).Build()
+	require.ErrorContains(t, err, "nil tool in registry builder")
+	_, err = NewRegistryBuilder().Add(nil).Build()
+	require.ErrorContains(t, err, "nil tool in registry builder")
+	_, err = NewRegistryBuilder().Add(minTool{}).Build() //nolint:exhaustruct // test
+	require.ErrorContains(t, err, "tool manifest name is required")
+}
+
+func TestRegistryBuilder_MustBuild(t *testing.T) {
+	first := minTool{manifest: ToolManifest{Name: "search"}} //nolint:exhaustruct // test
+	reg := NewRegistryBuilder().Add(first).MustBuild()
+	assert.True(t, reg.Has("search"))
+	assert.PanicsWithError(t, `toolsy: duplicate tool name "search"`, func() {
+		NewRegistryBuilder().Add(first, first).MustBuild()
+	})
+}
+
 func TestRegistryBuilder_NestedAsyncTool_FailsBuild(t *testing.T) {
 	base := mustNamedTool(t, "nested_base")
 	nested := AsAsyncTool(AsAsyncTool(base))
@@ -374,6 +434,32 @@ func TestRegistry_Execute_PanicRecovery_OnAfterSummary(t *testing.T) {
 	assert.Equal(t, "1", lastSummary.CallID)
 	assert.Equal(t, "panic", lastSummary.ToolName)
 	require.Error(t, lastSummary.Error)
+
+	stack := PanicStack(err)
+	assert.Contains(t, string(stack), "registry_test.go", "stack holds the panicking frame")
+	assert.Equal(t, stack, lastSummary.PanicStack)
+	assert.NotContains(t, err.Error(), "goroutine")
+	assert.NotContains(t, panicTE.Err.Error(), "goroutine")
+}
+
+func TestRegistry_Execute_WithPanicStacksDisabled(t *testing.T) {
+	tool, err := NewTool("panic", "Panics", func(context.Context, *RunEnv, struct{}) (struct{}, error) {
+		panic("oops")
+	})
+	require.NoError(t, err)
+	var lastSummary ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{tool}, WithPanicStacks(false),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, summary ExecutionSummary, _ time.Duration) {
+			lastSummary = summary
+		}))
+
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "panic", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error { return nil })
+	require.Error(t, err)
+	assert.Equal(t, FinishPanic, lastSummary.FinishReason)
+	assert.Nil(t, PanicStack(err))
+	assert.Nil(t, lastSummary.PanicStack)
+	assert.Nil(t, PanicStack(errors.New("plain")))
 }
 
 func TestRegistry_Execute_OnAfterSummaryTracksSoftErrorChunk(t *testing.T) {
@@ -418,6 +504,76 @@ func TestRegistry_Execute_OnAfterSummaryTracksSoftErrorChunk(t *testing.T) {
 	assert.Equal(t, int64(0), lastSummary.TotalBytes)
 }
 
+func TestRegistry_Execute_StampsCallMetaOnEveryChunk(t *testing.T) {
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	single, err := NewTool("single", "Single-shot", func(_ context.Context, _ *RunEnv, a echoArgs) (string, error) {
+		return a.Text, nil
+	})
+	require.NoError(t, err)
+	stream, err := NewStreamTool(
+		"stream",
+		"Streaming",
+		func(_ context.Context, _ *RunEnv, a echoArgs, yield func(Chunk) error) error {
+			if err := yield(Chunk{Event: EventProgress, Data: []byte("working"), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+			return yield(Chunk{Event: EventResult, Data: []byte(a.Text), MimeType: MimeTypeText})
+		},
+	)
+	require.NoError(t, err)
+	dynamic, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:        "dynamic",
+		Description: "Dynamic",
+		Schema: MapSchemaProvider{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+		},
+		Handler: func(_ context.Context, _ *RunEnv, decoded map[string]any, yield func(Chunk) error) error {
+			text, _ := decoded["text"].(string)
+			return yield(Chunk{Event: EventResult, Data: []byte(text), MimeType: MimeTypeText})
+		},
+	})
+	require.NoError(t, err)
+
+	var observed []Chunk
+	reg := mustBuildRegistry(t, []Tool{single, stream, dynamic}, WithOnChunk(func(_ context.Context, c Chunk) {
+		observed = append(observed, c)
+	}))
+
+	tests := []struct {
+		name   string
+		events []EventType
+	}{
+		{name: "single", events: []EventType{EventResult}},
+		{name: "stream", events: []EventType{EventProgress, EventResult}},
+		{name: "dynamic", events: []EventType{EventResult}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observed = nil
+			callID := "call-" + tt.name
+			var got []Chunk
+			err := reg.Execute(context.Background(), ToolCall{
+				ToolName: tt.name,
+				Input:    ToolInput{CallID: callID, ArgsJSON: []byte(`{"text":"hi"}`)},
+			}, func(c Chunk) error {
+				got = append(got, c)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.events))
+			for i, c := range got {
+				assert.Equal(t, callID, c.CallID)
+				assert.Equal(t, tt.name, c.ToolName)
+				assert.Equal(t, tt.events[i], c.Event)
+			}
+			assert.Equal(t, got, observed)
+		})
+	}
+}
+
 func TestPrepareChunk_EmptyToolErrorJSON_Rejects(t *testing.T) {
 	t.Parallel()
 	invalid := Chunk{Event: EventResult, IsError: true, MimeType: MimeTypeToolErrorJSON}
@@ -740,6 +896,90 @@ func TestRegistry_Execute_ContextDeadlineMapsToErrTimeout(t *testing.T) {
 	requireToolErrorCode(t, err, CodeTimeout, ErrTimeout)
 }
 
+func TestRegistry_Execute_InterruptClassification(t *testing.T) {
+	type A struct{}
+	type R struct{}
+	waitDone := func(ctx context.Context, _ *RunEnv, _ A) (R, error) {
+		<-ctx.Done()
+		return R{}, ctx.Err()
+	}
+	tests := []struct {
+		name    string
+		handler func(context.Context, *RunEnv, A) (R, error)
+		ctx     func() (context.Context, context.CancelFunc)
+		timeout bool
+	}{
+		{
+			name:    "wrapper timeout shorter than caller deadline",
+			handler: waitDone,
+			ctx: func() (context.Context, context.CancelFunc) {
+				caller, cancelCaller := context.WithTimeout(context.Background(), time.Hour)
+				wrapped, cancelWrapped := context.WithTimeout(caller, 20*time.Millisecond)
+				return wrapped, func() { cancelWrapped(); cancelCaller() }
+			},
+			timeout: true,
+		},
+		{
+			name: "handler reports cancellation of a context derived from the deadline",
+			handler: func(ctx context.Context, _ *RunEnv, _ A) (R, error) {
+				<-ctx.Done()
+				return R{}, fmt.Errorf("query aborted: %w", context.Canceled)
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			timeout: true,
+		},
+		{
+			name:    "caller cancels first",
+			handler: waitDone,
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			timeout: false,
+		},
+		{
+			name: "tool ignores ctx and returns late",
+			handler: func(_ context.Context, _ *RunEnv, _ A) (R, error) {
+				time.Sleep(60 * time.Millisecond)
+				return R{}, nil
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			timeout: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tool, err := NewTool("slow", "Slow", tc.handler)
+			require.NoError(t, err)
+			var summaryErr error
+			reg := mustBuildRegistry(t, []Tool{tool},
+				WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+					summaryErr = s.Error
+				}))
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			err = reg.Execute(
+				ctx,
+				ToolCall{ToolName: "slow", Input: ToolInput{CallID: "1", ArgsJSON: []byte(`{}`)}},
+				func(Chunk) error { return nil },
+			)
+			require.Error(t, err)
+			assert.Equal(t, err, summaryErr)
+			if tc.timeout {
+				requireToolErrorCode(t, err, CodeTimeout, ErrTimeout)
+				return
+			}
+			require.ErrorIs(t, err, context.Canceled)
+			assert.NotErrorIs(t, err, ErrTimeout)
+		})
+	}
+}
+
 func TestRegistry_ExecuteIter(t *testing.T) {
 	type A struct {
 		N int `json:"n"`
@@ -845,6 +1085,229 @@ func TestRegistry_ExecuteIter_WrappedCancel_SuppressedLikeDeadline(t *testing.T)
 	require.Equal(t, 0, errorYields)
 }
 
+func TestRegistry_ExecuteIter_BreakAbortsToolYield(t *testing.T) {
+	var toolYieldErr error
+	tool := newMiddlewareMinTool(
+		"iter_endless",
+		func(ctx context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			for {
+				if err := yield(Chunk{Event: EventProgress, Data: []byte("x"), MimeType: MimeTypeText}); err != nil {
+					toolYieldErr = err
+					return err
+				}
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+			}
+		},
+	)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	seen := 0
+	for _, iterErr := range reg.ExecuteIter(context.Background(), ToolCall{
+		ToolName: "iter_endless",
+		Input:    ToolInput{CallID: "e1", ArgsJSON: []byte(`{}`)},
+	}) {
+		require.NoError(t, iterErr)
+		seen++
+		if seen == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, seen)
+	require.ErrorIs(t, toolYieldErr, ErrStreamAborted)
+	assert.Zero(t, reg.InFlight())
+}
+
+func TestRegistry_ExecuteIter_TerminalErrorIsLastPair(t *testing.T) {
+	boom := errors.New("boom")
+	tool := newMiddlewareMinTool(
+		"iter_fail",
+		func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			if err := yield(Chunk{Event: EventProgress, Data: []byte("1"), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+			return boom
+		},
+	)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	var chunks []Chunk
+	var errs []error
+	for chunk, iterErr := range reg.ExecuteIter(context.Background(), ToolCall{
+		ToolName: "iter_fail",
+		Input:    ToolInput{CallID: "f1", ArgsJSON: []byte(`{}`)},
+	}) {
+		chunks = append(chunks, chunk)
+		errs = append(errs, iterErr)
+	}
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], boom)
+	assert.Equal(t, Chunk{}, chunks[1])
+}
+
+func TestRegistry_ExecuteChan(t *testing.T) {
+	type A struct {
+		N int `json:"n"`
+	}
+	empty := errors.New("nothing to stream")
+	stream := func(_ context.Context, _ *RunEnv, a A, yield func(Chunk) error) error {
+		for i := range a.N {
+			if err := yield(Chunk{Event: EventProgress, Data: []byte{byte('0' + i)}, MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+		}
+		if a.N == 0 {
+			return empty
+		}
+		return nil
+	}
+	tool, err := NewStreamTool("chan_stream", "Stream", stream)
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	chunks, errc := reg.ExecuteChan(context.Background(), ToolCall{
+		ToolName: "chan_stream",
+		Input:    ToolInput{CallID: "ch1", ArgsJSON: []byte(`{"n":3}`)},
+	}, 1)
+	var data []byte
+	for c := range chunks {
+		data = append(data, c.Data...)
+	}
+	assert.Equal(t, "012", string(data))
+	require.NoError(t, <-errc)
+	_, open := <-errc
+	assert.False(t, open, "exactly one value is sent on the error channel")
+
+	chunks, errc = reg.ExecuteChan(context.Background(), ToolCall{
+		ToolName: "chan_stream",
+		Input:    ToolInput{CallID: "ch2", ArgsJSON: []byte(`{"n":0}`)},
+	}, 0)
+	for range chunks {
+		t.Fatal("no chunks expected")
+	}
+	require.ErrorIs(t, <-errc, empty)
+}
+
+func TestRegistry_ExecuteChan_CancelUnblocksAbandonedConsumer(t *testing.T) {
+	var toolYieldErr error
+	tool := newMiddlewareMinTool(
+		"chan_endless",
+		func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			for {
+				if err := yield(Chunk{Event: EventProgress, Data: []byte("x"), MimeType: MimeTypeText}); err != nil {
+					toolYieldErr = err
+					return err
+				}
+			}
+		},
+	)
+	reg := mustBuildRegistry(t, []Tool{tool})
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errc := reg.ExecuteChan(ctx, ToolCall{
+		ToolName: "chan_endless",
+		Input:    ToolInput{CallID: "e1", ArgsJSON: []byte(`{}`)},
+	}, 0)
+	<-chunks
+	cancel()
+
+	select {
+	case err := <-errc:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("ExecuteChan goroutine did not exit after cancel")
+	}
+	require.ErrorIs(t, toolYieldErr, context.Canceled)
+	assert.Zero(t, reg.InFlight())
+}
+
+func TestRegistry_ExecuteBatchIter(t *testing.T) {
+	type A struct {
+		X int `json:"x"`
+	}
+	tool, err := NewTool("iter_double", "Double", func(_ context.Context, _ *RunEnv, a A) (int, error) {
+		return a.X * 2, nil
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	calls := []ToolCall{
+		{ToolName: "iter_double", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{"x":1}`)}},
+		{ToolName: "iter_double", Input: ToolInput{CallID: "b", ArgsJSON: []byte(`{"x":2}`)}},
+		{ToolName: "missing", Input: ToolInput{CallID: "c", ArgsJSON: []byte(`{}`)}},
+	}
+	byCall := map[string]Chunk{}
+	for chunk, iterErr := range reg.ExecuteBatchIter(context.Background(), calls) {
+		require.NoError(t, iterErr)
+		byCall[chunk.CallID] = chunk
+	}
+	require.Len(t, byCall, 3)
+	assert.Equal(t, "2", string(byCall["a"].Data))
+	assert.Equal(t, "4", string(byCall["b"].Data))
+	assert.True(t, byCall["c"].IsError, "per-call failures stay soft chunks")
+}
+
+func TestRegistry_ExecuteBatchIter_BreakStopsSiblings(t *testing.T) {
+	var mu sync.Mutex
+	var toolErrs []error
+	tool := newMiddlewareMinTool(
+		"batch_endless",
+		func(ctx context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			for ctx.Err() == nil {
+				if err := yield(Chunk{Event: EventProgress, Data: []byte("x"), MimeType: MimeTypeText}); err != nil {
+					mu.Lock()
+					toolErrs = append(toolErrs, err)
+					mu.Unlock()
+					return err
+				}
+			}
+			return ctx.Err()
+		},
+	)
+	reg := mustBuildRegistry(t, []Tool{tool})
+	calls := []ToolCall{
+		{ToolName: "batch_endless", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "batch_endless", Input: ToolInput{CallID: "b", ArgsJSON: []byte(`{}`)}},
+	}
+
+	seen := 0
+	for _, iterErr := range reg.ExecuteBatchIter(context.Background(), calls) {
+		require.NoError(t, iterErr)
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, seen)
+	assert.Zero(t, reg.InFlight(), "every call has finished when the loop exits")
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, toolErrs)
+	for _, toolErr := range toolErrs {
+		require.ErrorIs(t, toolErr, ErrStreamAborted)
+	}
+}
+
+func TestRegistry_ExecuteBatchIter_FailFastErrorIsLastPair(t *testing.T) {
+	boom := errors.New("boom")
+	tool := newMiddlewareMinTool(
+		"batch_fail",
+		func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error { return boom },
+	)
+	reg := mustBuildRegistry(t, []Tool{tool}, WithBatchErrorsAsChunks(false))
+
+	var errs []error
+	for chunk, iterErr := range reg.ExecuteBatchIter(context.Background(), []ToolCall{
+		{ToolName: "batch_fail", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{}`)}},
+	}) {
+		assert.Equal(t, Chunk{}, chunk)
+		errs = append(errs, iterErr)
+	}
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], boom)
+}
+
 func TestRegistry_ExecuteBatchStream_ChunkTagsAndErrors(t *testing.T) {
 	type A struct {
 		X int `json:"x"`
@@ -888,6 +1351,70 @@ func TestRegistry_ExecuteBatchStream_ChunkTagsAndErrors(t *testing.T) {
 	require.Equal(t, 2, okCount)
 }
 
+func TestRegistry_ExecuteBatchStream_PartialSuccessAcrossErrorKinds(t *testing.T) {
+	type A struct {
+		X int `json:"x"`
+	}
+	double, err := NewTool("double", "Double", func(_ context.Context, _ *RunEnv, a A) (int, error) {
+		return a.X * 2, nil
+	})
+	require.NoError(t, err)
+	broken, err := NewTool("broken", "Broken", func(_ context.Context, _ *RunEnv, _ A) (int, error) {
+		return 0, errors.New("backend down")
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{double, broken})
+
+	calls := []ToolCall{
+		{ToolName: "double", Input: ToolInput{CallID: "ok", ArgsJSON: []byte(`{"x": 2}`)}},
+		{ToolName: "missing", Input: ToolInput{CallID: "not-found", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "double", Input: ToolInput{CallID: "invalid", ArgsJSON: []byte(`{"x": "two"}`)}},
+		{ToolName: "broken", Input: ToolInput{CallID: "system", ArgsJSON: []byte(`{"x": 1}`)}},
+	}
+	byCall := make(map[string]Chunk)
+	err = reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		byCall[c.CallID] = c
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, byCall, len(calls))
+	assert.False(t, byCall["ok"].IsError)
+	assert.JSONEq(t, `4`, string(byCall["ok"].Data))
+	for callID, code := range map[string]ErrorCode{
+		"not-found": CodeToolNotFound,
+		"invalid":   CodeValidationFailed,
+		"system":    CodeInternal,
+	} {
+		c := byCall[callID]
+		require.True(t, c.IsError, callID)
+		te, err := unmarshalToolErrorWire(c.Data)
+		require.NoError(t, err, callID)
+		assert.Equal(t, code, te.Code, callID)
+	}
+	assert.Equal(t, "missing", byCall["not-found"].ToolName)
+}
+
+func TestRegistry_ExecuteBatchStream_FailFastReturnsFirstCallError(t *testing.T) {
+	slow, err := NewTool("slow", "Slow", func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{slow}, WithBatchErrorsAsChunks(false))
+
+	calls := []ToolCall{
+		{ToolName: "slow", Input: ToolInput{CallID: "1", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "missing", Input: ToolInput{CallID: "2", ArgsJSON: []byte(`{}`)}},
+	}
+	var chunks []Chunk
+	err = reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	require.ErrorIs(t, err, ErrToolNotFound)
+	assert.Empty(t, chunks)
+}
+
 func TestRegistry_ExecuteBatchStream_MiddlewareErrorAsChunk(t *testing.T) {
 	type A struct{}
 	type R struct {
@@ -1114,6 +1641,64 @@ func TestRegistry_ExecuteBatchStream_StreamAbortCancelsSiblings(t *testing.T) {
 	assert.True(t, secondCanceled.Load())
 }
 
+func TestRegistry_ExecuteBatchStream_StreamAbortStopsSlowStreamingSibling(t *testing.T) {
+	const (
+		tick          = 10 * time.Second
+		cancelLatency = 2 * time.Second
+	)
+	type slowArgs struct {
+		Fast bool `json:"fast"`
+	}
+	siblingStarted := make(chan struct{})
+	var siblingStopped atomic.Int64
+	tool, err := NewStreamTool(
+		"slow_stream",
+		"Slow stream",
+		func(ctx context.Context, _ *RunEnv, a slowArgs, yield func(Chunk) error) error {
+			if a.Fast {
+				<-siblingStarted
+				return yield(Chunk{Event: EventResult, Data: []byte("fast"), MimeType: MimeTypeText})
+			}
+			close(siblingStarted)
+			ticker := time.NewTicker(tick)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					siblingStopped.Store(time.Now().UnixNano())
+					return ctx.Err()
+				case <-ticker.C:
+					if err := yield(Chunk{Event: EventProgress, Data: []byte("tick"), MimeType: MimeTypeText}); err != nil {
+						return err
+					}
+				}
+			}
+		},
+	)
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	var abortedAt time.Time
+	start := time.Now()
+	err = reg.ExecuteBatchStream(
+		context.Background(),
+		[]ToolCall{
+			{ToolName: "slow_stream", Input: ToolInput{CallID: "slow", ArgsJSON: []byte(`{"fast":false}`)}},
+			{ToolName: "slow_stream", Input: ToolInput{CallID: "fast", ArgsJSON: []byte(`{"fast":true}`)}},
+		},
+		func(Chunk) error {
+			abortedAt = time.Now()
+			return errors.New("client disconnected")
+		},
+	)
+	require.ErrorIs(t, err, ErrStreamAborted)
+	require.False(t, abortedAt.IsZero())
+	stopped := siblingStopped.Load()
+	require.NotZero(t, stopped, "slow sibling must observe ctx cancellation")
+	assert.Less(t, time.Unix(0, stopped).Sub(abortedAt), cancelLatency)
+	assert.Less(t, time.Since(start), tick)
+}
+
 func TestRegistry_ExecuteBatchStream_StreamAbortPreventsExtraCallbackOnValidatorFailure(t *testing.T) {
 	firstYieldReturned := make(chan struct{})
 	allowFirstReturn := make(chan struct{})
@@ -1476,3 +2061,334 @@ func TestRegistry_Execute_DeadlineNormalizedToTimeout(t *testing.T) {
 	require.Equal(t, CodeTimeout, te.Code)
 	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
+
+func TestRegistryBuilder_WithMaxTools(t *testing.T) {
+	tools := manySmallDynamicTools(t, 4)
+
+	_, err := NewRegistryBuilder(WithMaxTools(3)).Add(tools...).Build()
+	require.ErrorIs(t, err, ErrTooManyTools)
+
+	b := NewRegistryBuilder(WithMaxTools(4))
+	assert.Equal(t, 4, b.Remaining())
+	b.Add(tools[:3]...)
+	assert.Equal(t, 1, b.Remaining())
+	b.Add(tools[3])
+	assert.Zero(t, b.Remaining())
+	reg, err := b.Build()
+	require.NoError(t, err)
+	assert.Len(t, reg.ToolNames(), 4)
+
+	assert.Equal(t, -1, NewRegistryBuilder().Remaining())
+}
+
+// The registry has no concurrency queue: calls either start immediately or are rejected.
+// Once Shutdown begins, new calls fail fast while in-flight calls drain.
+func TestRegistry_Shutdown_RejectsLateCallsWhileInFlightDrain(t *testing.T) {
+	const inFlight, late = 3, 5
+	var started sync.WaitGroup
+	started.Add(inFlight)
+	release := make(chan struct{})
+	tool, err := NewTool("slow", "slow", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		started.Done()
+		<-release
+		return "done", nil
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+	call := ToolCall{ToolName: "slow", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	inFlightErrs := make(chan error, inFlight)
+	for range inFlight {
+		go func() { inFlightErrs <- reg.Execute(context.Background(), call, func(Chunk) error { return nil }) }()
+	}
+	started.Wait()
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- reg.Shutdown(context.Background()) }()
+	require.Eventually(t, func() bool {
+		return errors.Is(reg.Execute(context.Background(), call, func(Chunk) error { return nil }), ErrShutdown)
+	}, time.Second, time.Millisecond)
+
+	lateErrs := make(chan error, late)
+	begin := time.Now()
+	for range late {
+		go func() { lateErrs <- reg.Execute(context.Background(), call, func(Chunk) error { return nil }) }()
+	}
+	for range late {
+		require.ErrorIs(t, <-lateErrs, ErrShutdown)
+	}
+	assert.Less(t, time.Since(begin), 500*time.Millisecond, "late calls must not wait for in-flight work")
+
+	select {
+	case err := <-shutdownDone:
+		t.Fatalf("shutdown returned before in-flight calls finished: %v", err)
+	default:
+	}
+	close(release)
+	for range inFlight {
+		require.NoError(t, <-inFlightErrs)
+	}
+	require.NoError(t, <-shutdownDone)
+}
+
+func TestRegistry_OnChunkProgress_PerCallRunningTotals(t *testing.T) {
+	type A struct {
+		N int `json:"n"`
+	}
+	tool, err := NewStreamTool(
+		"stream",
+		"stream",
+		func(_ context.Context, _ *RunEnv, a A, yield func(Chunk) error) error {
+			for i := range a.N {
+				if err := yield(Chunk{Event: EventResult, Data: []byte(fmt.Sprintf("%03d", i)), MimeType: MimeTypeText}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	progress := make(map[string][]ChunkProgress)
+	reg := mustBuildRegistry(t, []Tool{tool}, WithOnChunkProgress(func(_ context.Context, c Chunk, p ChunkProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress[c.CallID] = append(progress[c.CallID], p)
+	}))
+
+	err = reg.ExecuteBatchStream(context.Background(), []ToolCall{
+		{ToolName: "stream", Input: ToolInput{CallID: "a", ArgsJSON: []byte(`{"n":4}`)}},
+		{ToolName: "stream", Input: ToolInput{CallID: "b", ArgsJSON: []byte(`{"n":2}`)}},
+	}, func(Chunk) error { return nil })
+	require.NoError(t, err)
+
+	for callID, want := range map[string]int{"a": 4, "b": 2} {
+		got := progress[callID]
+		require.Len(t, got, want, callID)
+		for i, p := range got {
+			assert.Equal(t, i, p.Index, callID)
+			assert.Equal(t, int64(3*(i+1)), p.BytesSoFar, callID)
+			assert.Equal(t, i == 0, p.FirstChunk, callID)
+			if i > 0 {
+				assert.GreaterOrEqual(t, p.Elapsed, got[i-1].Elapsed, callID)
+			}
+		}
+	}
+}
+
+// The registry applies no deadline of its own (see README "Zero-resiliency core"), so a tool only
+// ever observes the caller's deadline, whatever middleware stack or execution path is used.
+func TestRegistry_DoesNotDeriveDeadlines(t *testing.T) {
+	type observed struct {
+		deadline time.Time
+		ok       bool
+	}
+	seen := make(chan observed, 4)
+	tool, err := NewTool("deadline", "deadline", func(ctx context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		d, ok := ctx.Deadline()
+		seen <- observed{deadline: d, ok: ok}
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Use(WithLogging(nil), WithErrorFormatter()).Add(tool).Build()
+	require.NoError(t, err)
+	call := ToolCall{ToolName: "deadline", Input: ToolInput{ArgsJSON: []byte(`{}`)}}
+
+	require.NoError(t, reg.Execute(context.Background(), call, func(Chunk) error { return nil }))
+	assert.False(t, (<-seen).ok, "no caller deadline means no deadline")
+
+	want := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+	require.NoError(t, reg.Execute(ctx, call, func(Chunk) error { return nil }))
+	got := <-seen
+	require.True(t, got.ok)
+	assert.True(t, want.Equal(got.deadline))
+
+	require.NoError(t, reg.ExecuteBatchStream(ctx, []ToolCall{call}, func(Chunk) error { return nil }))
+	got = <-seen
+	require.True(t, got.ok)
+	assert.True(t, want.Equal(got.deadline))
+}
+
+func TestRegistry_Execute_CallMetadataReachesHooksAndChunks(t *testing.T) {
+	type A struct{}
+	tool, err := NewStreamTool(
+		"stream",
+		"stream",
+		func(_ context.Context, _ *RunEnv, _ A, yield func(Chunk) error) error {
+			if err := yield(Chunk{Event: EventProgress, Data: []byte("step"), MimeType: MimeTypeText}); err != nil {
+				return err
+			}
+			return yield(Chunk{
+				Event:    EventResult,
+				Data:     []byte(`{"ok":true}`),
+				MimeType: MimeTypeJSON,
+				Metadata: map[string]any{"trace_id": "tool-trace", "rows": 3},
+			})
+		},
+	)
+	require.NoError(t, err)
+
+	var before, after map[string]any
+	var hooked []map[string]any
+	reg := mustBuildRegistry(
+		t,
+		[]Tool{tool},
+		WithOnBeforeExecute(func(_ context.Context, call ToolCall) { before = call.Metadata }),
+		WithOnChunk(func(_ context.Context, c Chunk) { hooked = append(hooked, c.Metadata) }),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, summary ExecutionSummary, _ time.Duration) {
+			after = summary.Metadata
+		}),
+	)
+	meta := map[string]any{"conversation_id": "c-1", "trace_id": "call-trace"}
+	var delivered []map[string]any
+	err = reg.Execute(
+		context.Background(),
+		ToolCall{ToolName: "stream", Input: ToolInput{CallID: "1", ArgsJSON: []byte(`{}`)}, Metadata: meta},
+		func(c Chunk) error {
+			delivered = append(delivered, c.Metadata)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, meta, before)
+	assert.Equal(t, meta, after)
+	require.Len(t, delivered, 2)
+	assert.Equal(t, meta, delivered[0])
+	assert.Equal(t, map[string]any{"conversation_id": "c-1", "trace_id": "tool-trace", "rows": 3}, delivered[1])
+	assert.Equal(t, delivered, hooked)
+	assert.Equal(t, map[string]any{"conversation_id": "c-1", "trace_id": "call-trace"}, meta,
+		"the caller's map must not be modified")
+}
+
+func TestRegistry_Execute_SummaryTimingBreakdown(t *testing.T) {
+	tool, err := NewTool("slow", "Slow", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		time.Sleep(30 * time.Millisecond)
+		return "done", nil
+	})
+	require.NoError(t, err)
+	var summary ExecutionSummary
+	var total time.Duration
+	reg := mustBuildRegistry(t, []Tool{tool},
+		WithOnBeforeExecute(func(context.Context, ToolCall) { time.Sleep(20 * time.Millisecond) }),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, d time.Duration) {
+			summary, total = s, d
+		}))
+	before := time.Now()
+	err = reg.Execute(context.Background(), ToolCall{ToolName: "slow", Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+		func(Chunk) error { return nil })
+	require.NoError(t, err)
+
+	assert.False(t, summary.StartedAt.Before(before))
+	assert.GreaterOrEqual(t, summary.ExecDuration, 30*time.Millisecond)
+	assert.GreaterOrEqual(t, total-summary.ExecDuration, 20*time.Millisecond, "hook time is not execution time")
+}
+
+func TestRegistry_WithOnError(t *testing.T) {
+	okTool, err := NewTool("ok", "OK", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		return "fine", nil
+	})
+	require.NoError(t, err)
+	failTool, err := NewTool("fail", "Fail", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		return "", errors.New("db down")
+	})
+	require.NoError(t, err)
+	panicTool, err := NewTool("panic", "Panic", func(_ context.Context, _ *RunEnv, _ struct{}) (string, error) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+
+	type observed struct {
+		tool string
+		err  error
+	}
+	var mu sync.Mutex
+	var seen []observed
+	var afterErr error
+	reg := mustBuildRegistry(t, []Tool{okTool, failTool, panicTool}, WithRecoverPanics(true),
+		WithOnError(func(_ context.Context, call ToolCall, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, observed{tool: call.ToolName, err: err})
+		}),
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			afterErr = s.Error
+		}))
+	run := func(name string) error {
+		return reg.Execute(context.Background(), ToolCall{ToolName: name, Input: ToolInput{ArgsJSON: []byte(`{}`)}},
+			func(Chunk) error { return nil })
+	}
+
+	require.NoError(t, run("ok"))
+	assert.Empty(t, seen)
+
+	failErr := run("fail")
+	require.Error(t, failErr)
+	panicErr := run("panic")
+	requireToolErrorCode(t, panicErr, CodeInternal)
+	mu.Lock()
+	assert.Equal(t, panicErr, afterErr, "the hook sees the final error")
+	mu.Unlock()
+	require.ErrorIs(t, run("missing"), ErrToolNotFound)
+	require.Len(t, seen, 3)
+	assert.Equal(t, observed{tool: "fail", err: failErr}, seen[0])
+	assert.Equal(t, observed{tool: "panic", err: panicErr}, seen[1])
+	assert.Equal(t, "missing", seen[2].tool)
+
+	seen = nil
+	err = reg.ExecuteBatchStream(context.Background(), []ToolCall{
+		{ToolName: "ok", Input: ToolInput{CallID: "1", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "fail", Input: ToolInput{CallID: "2", ArgsJSON: []byte(`{}`)}},
+	}, func(Chunk) error { return nil })
+	require.NoError(t, err)
+	require.Len(t, seen, 1, "soft error chunks still count as one failed call")
+	assert.Equal(t, "fail", seen[0].tool)
+
+	seen = nil
+	require.NoError(t, reg.Shutdown(context.Background()))
+	require.ErrorIs(t, run("ok"), ErrShutdown)
+	require.Len(t, seen, 1)
+	require.ErrorIs(t, seen[0].err, ErrShutdown)
+}
+
+func TestRegistry_OnAfterExecute_ObservesRejectedCalls(t *testing.T) {
+	var mu sync.Mutex
+	var summaries []ExecutionSummary
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "known")},
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			summaries = append(summaries, s)
+		}))
+	call := ToolCall{ToolName: "missing", Input: ToolInput{CallID: "c1", ArgsJSON: []byte(`{}`)}}
+	err := reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrToolNotFound)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "c1", summaries[0].CallID)
+	assert.Equal(t, "missing", summaries[0].ToolName)
+	assert.Equal(t, err, summaries[0].Error)
+	assert.Equal(t, FinishNotFound, summaries[0].FinishReason)
+	assert.Zero(t, summaries[0].ChunksDelivered)
+	assert.Zero(t, reg.InFlight())
+
+	require.NoError(t, reg.Shutdown(context.Background()))
+	summaries = nil
+	call = ToolCall{ToolName: "known", Input: ToolInput{CallID: "c2", ArgsJSON: []byte(`{}`)}}
+	err = reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.ErrorIs(t, err, ErrShutdown)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "c2", summaries[0].CallID)
+	assert.Equal(t, err, summaries[0].Error)
+	assert.Equal(t, FinishShutdown, summaries[0].FinishReason)
+
+	summaries = nil
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), []ToolCall{call}, func(Chunk) error { return nil }))
+	require.Len(t, summaries, 1, "batch calls rejected after shutdown reach the hook too")
+	assert.Equal(t, 1, summaries[0].ErrorChunks)
+	assert.Equal(t, FinishShutdown, summaries[0].FinishReason)
+}
@@ -0,0 +1,52 @@
+package toolsy
+
+// argsValidator is implemented by tools that can check arguments without running the handler.
+type argsValidator interface {
+	validateArgs(argsJSON []byte) error
+}
+
+// validateToolArgs walks the middleware chain down to the first layer that validates arguments;
+// tools with no such layer pass.
+func validateToolArgs(t Tool, argsJSON []byte) error {
+	for t != nil {
+		if v, ok := t.(argsValidator); ok {
+			return v.validateArgs(argsJSON)
+		}
+		u, ok := t.(ChainUnwrapper)
+		if !ok {
+			return nil
+		}
+		t = u.UnwrapNext()
+	}
+	return nil
+}
+
+// ValidateCall checks call's arguments the way [Registry.Execute] would, without running the tool:
+// the tool is resolved, [ToolCall.ArgsEncoding] is decoded, and the arguments are validated against
+// the tool's JSON Schema and, for typed tools, [Validatable]. The returned error is the same client
+// error Execute would return (for example [ErrValidation] or [ErrToolNotFound]); nil means Execute
+// would get past argument validation. Policies, authorizers, hooks, rate limits and the in-flight
+// limit are not involved, so ValidateCall is cheap and safe to call concurrently with executions.
+// Tools whose arguments can only be bound with the run context (an [ArgsBinder]) always pass.
+func (r *Registry) ValidateCall(call ToolCall) (err error) {
+	if _, stateErr := r.requireRuntimeState(); stateErr != nil {
+		return stateErr
+	}
+	tool, ok := r.lookupTool(call.ToolName)
+	if !ok {
+		if r.opts.view.ID != "" {
+			return NewCapabilityDeniedError(call.ToolName, r.opts.view)
+		}
+		return r.toolNotFoundError(call.ToolName)
+	}
+	call.Input = call.Input.Clone()
+	if decErr := r.decodeCallArgs(&call, tool); decErr != nil {
+		return decErr
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			err = NewInternalError(newPanicError(p, !r.opts.noPanicStacks))
+		}
+	}()
+	return validateToolArgs(tool, call.Input.ArgsJSON)
+}
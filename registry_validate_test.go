@@ -0,0 +1,96 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateCallArgs struct {
+	Site  string `json:"site"`
+	Query string `json:"query"`
+	Limit int    `json:"limit" maximum:"10"`
+}
+
+func (a validateCallArgs) Validate() error {
+	if a.Query == "forbidden" {
+		return NewValidationError("query is not allowed", "query")
+	}
+	return nil
+}
+
+func newValidateCallRegistry(t *testing.T, ran *int, hooked *int) *Registry {
+	t.Helper()
+	search, err := NewTool("search", "Search", func(context.Context, *RunEnv, validateCallArgs) (string, error) {
+		*ran++
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	docs, err := NewBoundTool("docs_search", search, map[string]any{"site": "docs"})
+	require.NoError(t, err)
+	proxy, err := NewProxyTool("proxy", "Proxy", []byte(`{"type":"object","required":["id"]}`),
+		func(context.Context, *RunEnv, []byte, func(Chunk) error) error {
+			*ran++
+			return nil
+		})
+	require.NoError(t, err)
+	dynamic, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:        "dynamic",
+		Description: "Dynamic",
+		Schema:      MapSchemaProvider{"type": "object"},
+		ValidateArgs: func(_ context.Context, decoded map[string]any) error {
+			if _, ok := decoded["id"]; !ok {
+				return errors.New("id is required")
+			}
+			return nil
+		},
+		Handler: func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+			*ran++
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	count := func(context.Context, ToolCall) { *hooked++ }
+	return mustBuildRegistry(t, []Tool{search, docs, proxy, dynamic},
+		WithOnBeforeExecute(count), WithOnError(func(context.Context, ToolCall, error) { *hooked++ }))
+}
+
+func TestRegistry_ValidateCall(t *testing.T) {
+	var ran, hooked int
+	reg := newValidateCallRegistry(t, &ran, &hooked)
+	validate := func(tool, args string) error {
+		return reg.ValidateCall(ToolCall{ToolName: tool, Input: ToolInput{ArgsJSON: []byte(args)}})
+	}
+
+	require.NoError(t, validate("search", `{"site":"a","query":"q","limit":3}`))
+	require.ErrorIs(t, validate("search", `{"site":"a","query":"q","limit":30}`), ErrValidation)
+	require.ErrorIs(t, validate("search", `{"site":"a","query":"forbidden","limit":1}`), ErrValidation)
+	te, ok := AsToolError(validate("search", `{"site":`))
+	require.True(t, ok)
+	assert.Equal(t, CodeSchemaInvalid, te.Code)
+	require.NoError(t, validate("docs_search", `{"query":"q","limit":1}`), "bound arguments are merged first")
+	require.ErrorIs(t, validate("docs_search", `{"query":"q","limit":11}`), ErrValidation)
+	require.NoError(t, validate("proxy", `{"id":1}`))
+	require.ErrorIs(t, validate("proxy", `{}`), ErrValidation)
+	require.NoError(t, validate("dynamic", `{"id":1}`))
+	require.Error(t, validate("dynamic", `{}`))
+	require.ErrorIs(t, validate("missing", `{}`), ErrToolNotFound)
+
+	assert.Zero(t, ran, "tools never run")
+	assert.Zero(t, hooked, "hooks never fire")
+	assert.Zero(t, reg.InFlight())
+}
+
+func TestRegistry_ValidateCall_MatchesExecute(t *testing.T) {
+	var ran, hooked int
+	reg := newValidateCallRegistry(t, &ran, &hooked)
+	call := ToolCall{ToolName: "search", Input: ToolInput{ArgsJSON: []byte(`{"site":"a","query":"q","limit":99}`)}}
+	validateErr := reg.ValidateCall(call)
+	execErr := reg.Execute(context.Background(), call, func(Chunk) error { return nil })
+	require.Error(t, validateErr)
+	assert.Equal(t, formatExecutionError(execErr), formatExecutionError(validateErr))
+	assert.Equal(t, FinishReasonOf(execErr), FinishReasonOf(validateErr))
+}
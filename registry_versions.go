@@ -0,0 +1,185 @@
+package toolsy
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ToolVersionSeparator separates a tool name from a version in [ToolCall.ToolName], as in "search@2".
+const ToolVersionSeparator = "@"
+
+// AddVersioned appends tools registered by (name, [ToolManifest.Version]). Several versions may share
+// a name: the registry advertises the highest one under the plain name, and "name@version" selects a
+// specific one (see [Registry.GetToolVersion]). Build fails when a versioned tool has no version, when
+// a (name, version) pair repeats, or when a name is registered both here and with [RegistryBuilder.Add].
+func (b *RegistryBuilder) AddVersioned(tools ...Tool) *RegistryBuilder {
+	b.versioned = append(b.versioned, tools...)
+	return b
+}
+
+// buildVersions wraps the versioned tools, stores the latest version of each name in tools, and returns
+// every version per name ordered oldest first.
+func (b *RegistryBuilder) buildVersions(tools map[string]Tool) (map[string][]Tool, error) {
+	if len(b.versioned) == 0 {
+		return nil, nil
+	}
+	versions := make(map[string][]Tool)
+	for _, raw := range b.versioned {
+		t, err := wrapRegistryTool(raw, b.middlewares)
+		if err != nil {
+			return nil, err
+		}
+		m := t.Manifest()
+		if m.Version == "" {
+			return nil, fmt.Errorf("toolsy: versioned tool %q has no version (use WithVersion)", m.Name)
+		}
+		if _, exists := tools[m.Name]; exists {
+			return nil, fmt.Errorf("toolsy: tool %q is registered both with and without a version", m.Name)
+		}
+		if _, dup := findToolVersion(versions[m.Name], m.Version); dup {
+			return nil, fmt.Errorf("toolsy: duplicate tool version %q@%s", m.Name, m.Version)
+		}
+		versions[m.Name] = append(versions[m.Name], t)
+	}
+	for name, list := range versions {
+		slices.SortStableFunc(list, func(a, b Tool) int {
+			return CompareToolVersions(toolManifest(a).Version, toolManifest(b).Version)
+		})
+		tools[name] = list[len(list)-1]
+	}
+	return versions, nil
+}
+
+// GetToolVersion returns the tool registered as name with the given version. Versions compare as in
+// [CompareToolVersions], so "2", "v2" and "2.0.0" are the same version. A tool added without
+// [RegistryBuilder.AddVersioned] matches when its [ToolManifest.Version] does. A nil receiver returns
+// (nil, false).
+func (r *Registry) GetToolVersion(name, version string) (Tool, bool) {
+	if r == nil {
+		return nil, false
+	}
+	if list, ok := r.versions[name]; ok {
+		return findToolVersion(list, version)
+	}
+	t, ok := r.tools[name]
+	if !ok || CompareToolVersions(toolManifest(t).Version, version) != 0 || toolManifest(t).Version == "" {
+		return nil, false
+	}
+	return t, true
+}
+
+// ToolVersions returns every registered version of name, oldest first. Tools added without
+// [RegistryBuilder.AddVersioned] have one entry. A nil receiver returns nil.
+func (r *Registry) ToolVersions(name string) []Tool {
+	if r == nil {
+		return nil
+	}
+	if list, ok := r.versions[name]; ok {
+		return slices.Clone(list)
+	}
+	if t, ok := r.tools[name]; ok {
+		return []Tool{t}
+	}
+	return nil
+}
+
+// GetAllToolVersions returns every registered tool including superseded versions, sorted by name and
+// then version. [Registry.GetAllTools] lists only the latest version of each name.
+// A nil receiver returns nil.
+func (r *Registry) GetAllToolVersions() []Tool {
+	if r == nil {
+		return nil
+	}
+	var out []Tool
+	for _, name := range r.sortedToolNames() {
+		out = append(out, r.ToolVersions(name)...)
+	}
+	return out
+}
+
+// lookupTool resolves a plain name, a tool cached by [WithToolLoader], or a "name@version" reference.
+func (r *Registry) lookupTool(name string) (Tool, bool) {
+	if t, ok := r.tools[name]; ok {
+		return t, true
+	}
+	if t, ok := r.loadedTool(name); ok {
+		return t, true
+	}
+	base, version, ok := strings.Cut(name, ToolVersionSeparator)
+	if !ok || base == "" || version == "" {
+		return nil, false
+	}
+	return r.GetToolVersion(base, version)
+}
+
+func findToolVersion(list []Tool, version string) (Tool, bool) {
+	for _, t := range list {
+		if CompareToolVersions(toolManifest(t).Version, version) == 0 {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// replaceToolVersion returns a copy of list with the entry of t's version replaced by t.
+func replaceToolVersion(list []Tool, t Tool) ([]Tool, error) {
+	version := toolManifest(t).Version
+	for i, old := range list {
+		if CompareToolVersions(toolManifest(old).Version, version) == 0 {
+			out := slices.Clone(list)
+			out[i] = t
+			return out, nil
+		}
+	}
+	return nil, errors.New("replacement tool version is not registered")
+}
+
+// CompareToolVersions orders semver-like versions: an optional "v" prefix is ignored, dot-separated
+// numeric components compare numerically with missing components treated as 0, non-numeric components
+// compare as strings, and a "-suffix" pre-release sorts before the same release.
+// It returns -1, 0, or +1.
+func CompareToolVersions(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	aCore, aPre, aHasPre := strings.Cut(a, "-")
+	bCore, bPre, bHasPre := strings.Cut(b, "-")
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for i := range max(len(aParts), len(bParts)) {
+		if c := compareVersionPart(versionPart(aParts, i), versionPart(bParts, i)); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case aHasPre && !bHasPre:
+		return -1
+	case !aHasPre && bHasPre:
+		return 1
+	default:
+		return cmp.Compare(aPre, bPre)
+	}
+}
+
+func versionPart(parts []string, i int) string {
+	if i < len(parts) && parts[i] != "" {
+		return parts[i]
+	}
+	return "0"
+}
+
+func compareVersionPart(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return cmp.Compare(a, b)
+	}
+}
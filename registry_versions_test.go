@@ -0,0 +1,123 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchV1Args struct {
+	Q string `json:"q"`
+}
+
+type searchV2Args struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+func versionedRegistry(t *testing.T) *Registry {
+	t.Helper()
+	v1, err := NewTool("search", "Search (old)", func(_ context.Context, _ *RunEnv, a searchV1Args) (string, error) {
+		return "v1:" + a.Q, nil
+	}, WithVersion("1.0.0"))
+	require.NoError(t, err)
+	v2, err := NewTool("search", "Search", func(_ context.Context, _ *RunEnv, a searchV2Args) (string, error) {
+		return "v2:" + a.Query, nil
+	}, WithVersion("2.0.0"))
+	require.NoError(t, err)
+	v10, err := NewTool("search", "Search (beta)", func(_ context.Context, _ *RunEnv, a searchV2Args) (string, error) {
+		return "v10:" + a.Query, nil
+	}, WithVersion("10.0.0-beta"))
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().AddVersioned(v2, v10, v1).Build()
+	require.NoError(t, err)
+	return reg
+}
+
+func TestAddVersioned_ResolvesLatestAndExplicit(t *testing.T) {
+	t.Parallel()
+	reg := versionedRegistry(t)
+	ctx := context.Background()
+	call := func(name, args string) ToolCall {
+		return ToolCall{ToolName: name, Input: ToolInput{ArgsJSON: []byte(args)}}
+	}
+
+	out, err := reg.ExecuteCollect(ctx, call("search", `{"query":"go"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"v10:go"`, string(out))
+
+	out, err = reg.ExecuteCollect(ctx, call("search@1", `{"q":"go"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"v1:go"`, string(out))
+
+	out, err = reg.ExecuteCollect(ctx, call("search@v2.0", `{"query":"go"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"v2:go"`, string(out))
+
+	_, err = reg.ExecuteCollect(ctx, call("search@3", `{}`))
+	require.ErrorIs(t, err, ErrToolNotFound)
+}
+
+func TestAddVersioned_Listing(t *testing.T) {
+	t.Parallel()
+	reg := versionedRegistry(t)
+	assert.Equal(t, []string{"search"}, reg.ToolNames())
+	require.Len(t, reg.GetAllTools(), 1)
+	assert.Equal(t, "10.0.0-beta", reg.GetAllTools()[0].Manifest().Version)
+
+	var versions []string
+	for _, tool := range reg.GetAllToolVersions() {
+		versions = append(versions, tool.Manifest().Version)
+	}
+	assert.Equal(t, []string{"1.0.0", "2.0.0", "10.0.0-beta"}, versions)
+
+	tool, ok := reg.GetToolVersion("search", "2")
+	require.True(t, ok)
+	assert.Equal(t, "2.0.0", tool.Manifest().Version)
+	_, ok = reg.GetTool("search@1.0.0")
+	assert.True(t, ok)
+
+	without, removed := reg.Without("search")
+	assert.True(t, removed)
+	assert.Empty(t, without.GetAllToolVersions())
+	_, ok = without.GetToolVersion("search", "1")
+	assert.False(t, ok)
+}
+
+func TestAddVersioned_BuildErrors(t *testing.T) {
+	t.Parallel()
+	handler := func(context.Context, *RunEnv, struct{}) (string, error) { return "", nil }
+	plain, err := NewTool("search", "Search", handler)
+	require.NoError(t, err)
+	v1, err := NewTool("search", "Search", handler, WithVersion("1"))
+	require.NoError(t, err)
+	v1Again, err := NewTool("search", "Search", handler, WithVersion("v1.0"))
+	require.NoError(t, err)
+
+	_, err = NewRegistryBuilder().Add(plain).AddVersioned(v1).Build()
+	require.ErrorContains(t, err, "both with and without a version")
+	_, err = NewRegistryBuilder().AddVersioned(plain).Build()
+	require.ErrorContains(t, err, "has no version")
+	_, err = NewRegistryBuilder().AddVersioned(v1, v1Again).Build()
+	require.ErrorContains(t, err, "duplicate tool version")
+}
+
+func TestCompareToolVersions(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1.0.0", 0},
+		{"v2", "2", 0},
+		{"1.9", "1.10", -1},
+		{"2.0.0-rc1", "2.0.0", -1},
+		{"2.0.0-rc2", "2.0.0-rc1", 1},
+		{"1.x", "1.2", 1},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, CompareToolVersions(tc.a, tc.b), "%s vs %s", tc.a, tc.b)
+	}
+}
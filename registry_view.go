@@ -150,6 +150,15 @@ func (v *RegistryView) ManifestSet() (ManifestSet, error) {
 	return v.reg.ManifestSet()
 }
 
+// EffectiveDescriptors returns the effective descriptors visible through the view, with view policies applied
+// when [DescribeForCall] is set. See [Registry.EffectiveDescriptors].
+func (v *RegistryView) EffectiveDescriptors(ctx context.Context, opts ...DescribeOption) []EffectiveDescriptor {
+	if v == nil || v.reg == nil {
+		return nil
+	}
+	return v.reg.EffectiveDescriptors(ctx, opts...)
+}
+
 // ValidateManifestContract validates required tools against this view.
 func (v *RegistryView) ValidateManifestContract(requiredNames []string) error {
 	if len(requiredNames) == 0 {
@@ -179,12 +188,12 @@ func (v *RegistryView) ExecuteIter(ctx context.Context, call ToolCall) iter.Seq2
 
 		err := v.Execute(ctxChild, call, func(c Chunk) error {
 			if consumerStopped {
-				return context.Canceled
+				return wrapYieldError(context.Canceled)
 			}
 			if !yield(c, nil) {
 				consumerStopped = true
 				cancel()
-				return context.Canceled
+				return wrapYieldError(context.Canceled)
 			}
 			return nil
 		})
@@ -351,6 +360,7 @@ func newRegistryViewSnapshotMismatchError(reason string, fixableArgs ...string)
 		Retryable:   false,
 		FixableArgs: append([]string(nil), fixableArgs...),
 		SafeMessage: "",
+		Violations:  nil,
 		Err:         errors.New("registry view snapshot " + reason),
 	}
 }
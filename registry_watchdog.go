@@ -0,0 +1,141 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AbandonedExecution describes a tool execution whose context is done while its handler still runs.
+// Abandoned is the time elapsed since the context was cancelled or hit its deadline.
+type AbandonedExecution struct {
+	ToolName  string
+	CallID    string
+	Abandoned time.Duration
+}
+
+// WithExecutionWatchdog tracks in-flight executions and calls onAbandoned once for every execution
+// whose handler has not returned a full interval after its context was done. Handlers run on the
+// caller's goroutine, so this is the point where an outer timeout wrapper gives up and a handler that
+// ignores ctx starts leaking. The watchdog goroutine runs only while executions are in flight.
+// interval <= 0 disables the watchdog; onAbandoned may be nil when only
+// [Registry.AbandonedExecutions] is needed (for example in tests).
+func WithExecutionWatchdog(interval time.Duration, onAbandoned func(AbandonedExecution)) RegistryOption {
+	return func(o *registryOptions) {
+		o.watchdogInterval = interval
+		o.onAbandoned = onAbandoned
+	}
+}
+
+// AbandonedExecutions returns executions whose context is done but whose handler has not returned yet.
+// It returns nil when [WithExecutionWatchdog] is not configured. Views share the tracked set.
+func (r *Registry) AbandonedExecutions() []AbandonedExecution {
+	if r == nil || r.state == nil || r.state.watchdog == nil {
+		return nil
+	}
+	return r.state.watchdog.abandoned(time.Now(), 0)
+}
+
+type watchedExecution struct {
+	toolName string
+	callID   string
+	doneAt   time.Time
+	reported bool
+	stop     func() bool
+}
+
+type executionWatchdog struct {
+	interval time.Duration
+	report   func(AbandonedExecution)
+
+	mu     sync.Mutex
+	nextID uint64
+	active map[uint64]*watchedExecution
+	wake   chan struct{} // closed to stop the scan goroutine once active is empty
+}
+
+func newExecutionWatchdog(interval time.Duration, report func(AbandonedExecution)) *executionWatchdog {
+	return &executionWatchdog{
+		interval: interval,
+		report:   report,
+		mu:       sync.Mutex{},
+		nextID:   0,
+		active:   make(map[uint64]*watchedExecution),
+		wake:     nil,
+	}
+}
+
+// track registers one execution bound to ctx and returns the function that ends tracking.
+func (w *executionWatchdog) track(ctx context.Context, toolName, callID string) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.nextID++
+	id := w.nextID
+	exec := &watchedExecution{toolName: toolName, callID: callID, doneAt: time.Time{}, reported: false, stop: nil}
+	exec.stop = context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		exec.doneAt = time.Now()
+	})
+	w.active[id] = exec
+	if w.wake == nil {
+		w.wake = make(chan struct{})
+		go w.scan(w.wake)
+	}
+	var once sync.Once
+	return func() { once.Do(func() { w.untrack(id) }) }
+}
+
+func (w *executionWatchdog) untrack(id uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	exec, ok := w.active[id]
+	if !ok {
+		return
+	}
+	exec.stop()
+	delete(w.active, id)
+	if len(w.active) == 0 && w.wake != nil {
+		close(w.wake)
+		w.wake = nil
+	}
+}
+
+func (w *executionWatchdog) scan(stop <-chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			for _, a := range w.abandoned(now, w.interval) {
+				if w.report != nil {
+					w.report(a)
+				}
+			}
+		}
+	}
+}
+
+// abandoned lists executions abandoned for at least minAge. A positive minAge marks them reported,
+// so the periodic scan reports each execution once while snapshots with minAge 0 list all of them.
+func (w *executionWatchdog) abandoned(now time.Time, minAge time.Duration) []AbandonedExecution {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []AbandonedExecution
+	for _, exec := range w.active {
+		if exec.doneAt.IsZero() {
+			continue
+		}
+		age := now.Sub(exec.doneAt)
+		if minAge > 0 {
+			if exec.reported || age < minAge {
+				continue
+			}
+			exec.reported = true
+		}
+		out = append(out, AbandonedExecution{ToolName: exec.toolName, CallID: exec.callID, Abandoned: age})
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package toolsy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExecutionWatchdog_ReportsHandlerIgnoringCancellation(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stubborn := newMiddlewareMinTool("stubborn", func(context.Context, *RunEnv, ToolInput, func(Chunk) error) error {
+		close(started)
+		<-release
+		return nil
+	})
+	var mu sync.Mutex
+	var reports []AbandonedExecution
+	reported := make(chan struct{}, 1)
+	reg := mustBuildRegistry(t, []Tool{stubborn}, WithExecutionWatchdog(interval, func(a AbandonedExecution) {
+		mu.Lock()
+		reports = append(reports, a)
+		mu.Unlock()
+		select {
+		case reported <- struct{}{}:
+		default:
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.Execute(ctx, ToolCall{
+			ToolName: "stubborn",
+			Input:    ToolInput{CallID: "c-1", ArgsJSON: []byte(`{}`)},
+		}, func(Chunk) error { return nil })
+	}()
+	<-started
+	assert.Empty(t, reg.AbandonedExecutions(), "running execution with a live context is not abandoned")
+	cancel()
+
+	select {
+	case <-reported:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchdog did not report the abandoned execution")
+	}
+	snapshot := reg.AbandonedExecutions()
+	require.Len(t, snapshot, 1)
+	assert.Equal(t, "stubborn", snapshot[0].ToolName)
+	assert.Equal(t, "c-1", snapshot[0].CallID)
+	assert.GreaterOrEqual(t, snapshot[0].Abandoned, interval)
+
+	time.Sleep(3 * interval)
+	close(release)
+	require.NoError(t, <-done)
+	assert.Empty(t, reg.AbandonedExecutions())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reports, 1, "each abandoned execution is reported once")
+	assert.Equal(t, "c-1", reports[0].CallID)
+}
+
+func TestWithExecutionWatchdog_CooperativeHandlerNotReported(t *testing.T) {
+	started := make(chan struct{})
+	tool := newMiddlewareMinTool("polite", func(ctx context.Context, _ *RunEnv, _ ToolInput, _ func(Chunk) error) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var reports atomic.Int32
+	reg := mustBuildRegistry(t, []Tool{tool}, WithExecutionWatchdog(time.Millisecond, func(AbandonedExecution) {
+		reports.Add(1)
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := reg.Execute(ctx, ToolCall{ToolName: "polite", Input: ToolInput{ArgsJSON: []byte(`{}`)}}, func(Chunk) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, reg.AbandonedExecutions())
+	assert.Zero(t, reports.Load())
+}
+
+func TestRegistry_AbandonedExecutions_NilWithoutWatchdog(t *testing.T) {
+	reg := mustBuildRegistry(t, []Tool{mustNamedTool(t, "a")})
+	assert.Nil(t, reg.AbandonedExecutions())
+	var nilReg *Registry
+	assert.Nil(t, nilReg.AbandonedExecutions())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"maps"
 	"reflect"
+	"slices"
 )
 
 // MemoryAccess describes how a tool uses session in-memory state.
@@ -97,6 +98,8 @@ func cloneManifestForPolicy(m ToolManifest) ToolManifest {
 	out.OutputSchema = deepCloneMap(m.OutputSchema)
 	out.Tags = append([]string(nil), m.Tags...)
 	out.Requirements = cloneRequirements(m.Requirements)
+	out.SensitiveArgs = slices.Clone(m.SensitiveArgs)
+	out.Examples = cloneExamples(m.Examples)
 	return out
 }
 
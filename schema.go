@@ -3,25 +3,42 @@ package toolsy
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"math"
 	"reflect"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 )
 
 // SchemaRegistry stores custom type to JSON Schema mappings for typed builders/extractors.
+// There is no package-global registry: each tool or extractor uses the registry passed with
+// [WithSchemaRegistry] or [SchemaConfig.Registry], or a fresh one, so libraries and parallel tests can
+// map the same type differently. A registry is safe for concurrent use.
 type SchemaRegistry struct {
-	mu    sync.RWMutex
-	types map[reflect.Type]*jsonschema.Schema
+	mu     sync.RWMutex
+	types  map[reflect.Type]*jsonschema.Schema
+	codecs map[reflect.Type]SchemaStringCodec
+
+	// generation counts changes to types; schemas caches what was generated from the current one.
+	generation uint64
+	schemas    map[schemaCacheKey]schemaCacheEntry
 }
 
 // NewSchemaRegistry creates an empty schema registry.
 func NewSchemaRegistry() *SchemaRegistry {
 	return &SchemaRegistry{
-		mu:    sync.RWMutex{},
-		types: make(map[reflect.Type]*jsonschema.Schema),
+		mu:         sync.RWMutex{},
+		types:      make(map[reflect.Type]*jsonschema.Schema),
+		codecs:     make(map[reflect.Type]SchemaStringCodec),
+		generation: 0,
+		schemas:    nil,
 	}
 }
 
@@ -37,53 +54,159 @@ func (r *SchemaRegistry) RegisterType(emptyInstance any, jsonType, format string
 	if jsonType == "" {
 		panic("toolsy: RegisterType jsonType must not be empty")
 	}
-	t := reflect.TypeOf(emptyInstance)
-	s := &jsonschema.Schema{Type: jsonType, Format: format}
+	r.setTypeSchema(reflect.TypeOf(emptyInstance), &jsonschema.Schema{Type: jsonType, Format: format})
+}
+
+// setTypeSchema records a type mapping and drops the schemas generated from the previous mappings,
+// so tools built afterwards see the change (tools built earlier keep their schema).
+func (r *SchemaRegistry) setTypeSchema(t reflect.Type, s *jsonschema.Schema) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.types == nil {
 		r.types = make(map[reflect.Type]*jsonschema.Schema)
 	}
 	r.types[t] = s
+	r.generation++
+	r.schemas = nil
+}
+
+// customSchemaMarker tags sub-schemas registered with [SchemaRegistry.RegisterTypeSchema] during
+// generation, so strict mode and tag enrichment leave them as registered. It never reaches callers.
+const customSchemaMarker = "x-toolsy-custom-schema"
+
+// optionalFieldMarker flags the property of a field tagged `optional:"true"` until strict mode
+// has run; [finalizeGeneratedSchema] removes it.
+const optionalFieldMarker = "x-toolsy-optional"
+
+// RegisterTypeSchema registers a complete JSON Schema for a Go type, replacing the generated subschema
+// wherever the type appears (for example {"type":"string","pattern":"^-?\\d+(\\.\\d+)?$"} for a decimal).
+// Like [SchemaRegistry.RegisterType], registration is by [reflect.TypeOf](emptyInstance) and pointer
+// fields use the same schema. [WithStrict] does not rewrite additionalProperties or required inside it.
+// schema is copied. It panics when emptyInstance is nil or schema is empty or does not compile.
+func (r *SchemaRegistry) RegisterTypeSchema(emptyInstance any, schema map[string]any) {
+	if emptyInstance == nil {
+		panic("toolsy: RegisterTypeSchema emptyInstance must not be nil")
+	}
+	if len(schema) == 0 {
+		panic("toolsy: RegisterTypeSchema schema must not be empty")
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic(fmt.Sprintf("toolsy: RegisterTypeSchema schema must be JSON: %v", err))
+	}
+	s := new(jsonschema.Schema)
+	if err := json.Unmarshal(data, s); err != nil {
+		panic(fmt.Sprintf("toolsy: RegisterTypeSchema invalid schema: %v", err))
+	}
+	if _, err := s.Resolve(nil); err != nil {
+		panic(fmt.Sprintf("toolsy: RegisterTypeSchema invalid schema: %v", err))
+	}
+	if s.Extra == nil {
+		s.Extra = make(map[string]any, 1)
+	}
+	s.Extra[customSchemaMarker] = true
+	r.setTypeSchema(reflect.TypeOf(emptyInstance), s)
 }
 
 func ensureSchemaConfig(cfg SchemaConfig) SchemaConfig {
 	if cfg.Registry == nil {
 		cfg.Registry = NewSchemaRegistry()
 	}
-	// json.RawMessage is []byte; default jsonschema maps it to "array". Tool args use it for JSON objects.
-	cfg.Registry.RegisterType(json.RawMessage(nil), "object", "")
 	return cfg
 }
 
-func (r *SchemaRegistry) buildTypeSchemas() map[reflect.Type]*jsonschema.Schema {
+// builtinTypeSchemas returns the mappings every generated schema starts from. Registrations on the
+// [SchemaRegistry] in use take precedence, and the registry itself is never modified.
+func builtinTypeSchemas() map[reflect.Type]*jsonschema.Schema {
+	return map[reflect.Type]*jsonschema.Schema{
+		// json.RawMessage is []byte; default jsonschema maps it to "array". Tool args use it for JSON objects.
+		reflect.TypeFor[json.RawMessage](): {Type: "object"},
+		reflect.TypeFor[time.Time]():       {Type: "string", Format: "date-time"},
+		// Decoded by the built-in duration [SchemaStringCodec]; encoding/json alone reads only nanoseconds.
+		reflect.TypeFor[time.Duration](): {Type: "string", Description: `Go duration, e.g. "30s"`},
+		// encoding/json reads and writes []byte as a base64 string; default jsonschema maps it to "array".
+		reflect.TypeFor[[]byte](): base64Schema(),
+	}
+}
+
+// buildTypeSchemas returns the type mappings for schema generation and the cache for schemas
+// generated from them.
+func (r *SchemaRegistry) buildTypeSchemas() (map[reflect.Type]*jsonschema.Schema, schemaCache) {
+	out := builtinTypeSchemas()
+	cache := schemaCache{registry: nil, generation: 0}
 	if r == nil {
-		return nil
+		return out, cache
 	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	out := make(map[reflect.Type]*jsonschema.Schema, len(r.types))
 	for t, s := range r.types {
 		if s != nil {
 			out[t] = s.CloneSchemas()
 		}
 	}
-	return out
+	if len(r.types) > 0 {
+		cache = schemaCache{registry: r, generation: r.generation}
+	}
+	return out, cache
 }
 
 // generateSchema produces a JSON Schema map and a resolved validator for type T.
 // It is called once when building a Tool. cfg.Strict sets additionalProperties: false
 // for all objects (OpenAI Structured Outputs). cfg.Registry controls custom type mappings.
+// Results are cached per type, strict flag and mappings; the returned map is always a private copy.
 func generateSchema[T any](cfg SchemaConfig) (map[string]any, *jsonschema.Resolved, error) {
+	return generateSchemaType(reflect.TypeFor[T](), cfg)
+}
+
+// generateSchemaType is [generateSchema] for a type known only at run time (see [NewToolset]).
+func generateSchemaType(typ reflect.Type, cfg SchemaConfig) (map[string]any, *jsonschema.Resolved, error) {
 	cfg = ensureSchemaConfig(cfg)
-	opts := &jsonschema.ForOptions{TypeSchemas: cfg.Registry.buildTypeSchemas()}
-	schema, err := jsonschema.For[T](opts)
+	typeSchemas, cache := cfg.Registry.buildTypeSchemas()
+	key := schemaCacheKey{
+		typ:              typ,
+		strict:           cfg.Strict,
+		optionalNullable: cfg.OptionalAsNullable,
+		closeFreeform:    cfg.CloseFreeform,
+	}
+	if schemaMap, resolved, ok := cache.load(key); ok {
+		return schemaMap, resolved, nil
+	}
+	schemaMap, resolved, err := buildSchema(typ, cfg, typeSchemas)
 	if err != nil {
 		return nil, nil, err
 	}
+	cache.store(key, schemaMap, resolved)
+	return schemaMap, resolved, nil
+}
+
+func buildSchema(
+	typ reflect.Type,
+	cfg SchemaConfig,
+	typeSchemas map[reflect.Type]*jsonschema.Schema,
+) (map[string]any, *jsonschema.Resolved, error) {
+	if err := addDerivedTypeSchemas(typ, typeSchemas); err != nil {
+		return nil, nil, err
+	}
+	opts := &jsonschema.ForOptions{TypeSchemas: typeSchemas}
+	schema, err := jsonschema.ForType(typ, opts)
+	if err != nil {
+		return nil, nil, diagnoseSchemaError(typ, opts, err)
+	}
 	if schema == nil {
 		return nil, nil, errNilSchema
 	}
+	if err := enrichSchemaFromStructTags(schema, typ); err != nil {
+		return nil, nil, err
+	}
+	if cfg.Strict {
+		applySchemaStrictMode(schema, cfg)
+	}
+	allowNullInNullableEnums(schema)
+	finalizeGeneratedSchema(schema)
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, nil, err
+	}
 	data, err := json.Marshal(schema)
 	if err != nil {
 		return nil, nil, err
@@ -92,58 +215,107 @@ func generateSchema[T any](cfg SchemaConfig) (map[string]any, *jsonschema.Resolv
 	if unmarshalErr := json.Unmarshal(data, &schemaMap); unmarshalErr != nil {
 		return nil, nil, unmarshalErr
 	}
-	enrichSchemaFromStructTags(schemaMap, reflect.TypeOf(*new(T)))
-	if cfg.Strict {
-		applyStrictMode(schemaMap)
-	}
-	stripSchemaIDs(schemaMap)
-	resolved, err := compileRawSchema(schemaMap)
-	if err != nil {
-		return nil, nil, err
-	}
 	return schemaMap, resolved, nil
 }
 
-// enrichSchemaFromStructTags adds description and enum from struct tags to root-level properties.
-// typ may be a pointer; json tag (first part before comma) is used to match property keys.
-func enrichSchemaFromStructTags(schemaMap map[string]any, typ reflect.Type) {
-	if schemaMap == nil || typ == nil {
-		return
+// constraintTags are the struct tags copied into the schema as JSON Schema keywords of the same name.
+// Numeric values are emitted as JSON numbers; on slice and array fields they constrain the elements.
+var constraintTags = []string{
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "pattern",
+}
+
+// arrayTags are the struct tags of slice and array fields that constrain the array itself: item
+// counts and, with uniqueItems:"true", distinct items.
+var arrayTags = []string{"minItems", "maxItems", "uniqueItems"}
+
+// enrichSchemaFromStructTags applies description, enum, and [constraintTags] struct tags to the
+// matching properties, recursing into nested structs, pointers, slices, and maps, whether their
+// sub-schemas are inline (as jsonschema-go generates them) or behind a local $defs/definitions $ref.
+// typ may be a pointer; property keys are matched by JSON name and promoted fields of embedded structs
+// are included. Invalid tag values fail with the field path.
+func enrichSchemaFromStructTags(schema *jsonschema.Schema, typ reflect.Type) error {
+	e := tagEnricher{root: schema, active: make(map[reflect.Type]bool)}
+	return e.enrich(schema, typ, "")
+}
+
+// tagEnricher walks a Go type and its schema in lockstep; active guards recursive types behind $ref.
+type tagEnricher struct {
+	root   *jsonschema.Schema
+	active map[reflect.Type]bool
+}
+
+func (e *tagEnricher) enrich(node *jsonschema.Schema, typ reflect.Type, prefix string) error {
+	if node == nil || typ == nil || isCustomSchema(node) {
+		return nil
 	}
-	if typ.Kind() == reflect.Pointer {
-		typ = typ.Elem()
+	if node.Ref != "" {
+		node = resolveLocalSchemaRef(e.root, node.Ref)
+		if node == nil {
+			return nil
+		}
 	}
-	if typ.Kind() != reflect.Struct {
-		return
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
 	}
-	props, ok := schemaMap["properties"].(map[string]any)
-	if !ok || len(props) == 0 {
-		return
+	switch typ.Kind() { //nolint:exhaustive // only container and struct kinds carry nested fields
+	case reflect.Slice, reflect.Array:
+		return e.enrich(node.Items, typ.Elem(), prefix+"[]")
+	case reflect.Map:
+		return e.enrich(node.AdditionalProperties, typ.Elem(), prefix+"{}")
+	case reflect.Struct:
+	default:
+		return nil
 	}
-	jsonToField := make(map[string]reflect.StructField)
-	for field := range typ.Fields() {
-		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
-		if jsonTag == "" || jsonTag == "-" {
-			continue
-		}
-		jsonToField[jsonTag] = field
+	if len(node.Properties) == 0 || e.active[typ] {
+		return nil
 	}
-	for key, val := range props {
-		prop, ok := val.(map[string]any)
+	e.active[typ] = true
+	defer delete(e.active, typ)
+	for _, field := range reflect.VisibleFields(typ) {
+		name, ok := schemaFieldName(field)
 		if !ok {
 			continue
 		}
-		field, ok := jsonToField[key]
-		if !ok {
+		prop := node.Properties[name]
+		if prop == nil {
 			continue
 		}
-		enrichPropertyFromStructField(prop, field)
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if err := enrichPropertyFromStructField(prop, field); err != nil {
+			return &fieldPathError{Path: path, Type: field.Type, Err: err}
+		}
+		if optional, err := optionalField(field); err != nil {
+			return &fieldPathError{Path: path, Type: field.Type, Err: err}
+		} else if optional {
+			node.Required = slices.DeleteFunc(node.Required, func(req string) bool { return req == name })
+			prop.Extra = maps.Clone(prop.Extra)
+			if prop.Extra == nil {
+				prop.Extra = make(map[string]any, 1)
+			}
+			prop.Extra[optionalFieldMarker] = true
+		}
+		if err := e.enrich(prop, field.Type, path); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func enrichPropertyFromStructField(prop map[string]any, field reflect.StructField) {
+func enrichPropertyFromStructField(prop *jsonschema.Schema, field reflect.StructField) error {
+	if raw, ok := field.Tag.Lookup("freeform"); ok {
+		if err := applyFreeformTag(prop, field.Type, strings.TrimSpace(raw)); err != nil {
+			return err
+		}
+	}
 	if desc := field.Tag.Get("description"); desc != "" {
-		prop["description"] = desc
+		prop.Description = desc
+	}
+	target := prop
+	if prop.Items != nil && constrainsElements(field.Type) {
+		target = prop.Items
 	}
 	if enumStr := field.Tag.Get("enum"); enumStr != "" {
 		parts := strings.Split(enumStr, ",")
@@ -151,56 +323,337 @@ func enrichPropertyFromStructField(prop map[string]any, field reflect.StructFiel
 		for i, p := range parts {
 			enum[i] = strings.TrimSpace(p)
 		}
-		prop["enum"] = enum
+		target.Enum = enum
 	}
+	if raw, ok := field.Tag.Lookup("maxBytes"); ok {
+		if err := applyMaxBytesTag(prop, field, raw); err != nil {
+			return err
+		}
+	}
+	for _, tag := range arrayTags {
+		raw, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		if target == prop {
+			return fmt.Errorf("%s tag on a non-array field", tag)
+		}
+		if err := applyArrayTag(prop, tag, strings.TrimSpace(raw)); err != nil {
+			return err
+		}
+	}
+	for _, tag := range constraintTags {
+		raw, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		if err := applyConstraintTag(target, tag, strings.TrimSpace(raw)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// walkSchema recursively visits every map node in the schema tree (including $defs and definitions).
-func walkSchema(schemaMap map[string]any, visit func(map[string]any)) {
-	if schemaMap == nil {
+// optionalField reports whether field is tagged `optional:"true"`, which keeps it out of "required"
+// with or without [WithStrict].
+func optionalField(field reflect.StructField) (bool, error) {
+	raw, ok := field.Tag.Lookup("optional")
+	if !ok {
+		return false, nil
+	}
+	optional, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		return false, fmt.Errorf("invalid optional tag %q: must be true or false", raw)
+	}
+	return optional, nil
+}
+
+// applyFreeformTag replaces the generated schema of a field tagged `freeform:"object"` (or "true")
+// with {"type": "object"}, which accepts any object, and of one tagged `freeform:"any"` with the
+// empty schema, which accepts any value. Pointer fields also accept null; "false" keeps the schema.
+func applyFreeformTag(prop *jsonschema.Schema, typ reflect.Type, raw string) error {
+	description := prop.Description
+	switch raw {
+	case "false":
+		return nil
+	case "object", "true":
+		*prop = jsonschema.Schema{Type: "object"} //nolint:exhaustruct // free-form object
+		if typ.Kind() == reflect.Pointer {
+			*prop = jsonschema.Schema{Types: []string{"null", "object"}} //nolint:exhaustruct // nullable free-form object
+		}
+	case "any":
+		*prop = jsonschema.Schema{} //nolint:exhaustruct // the empty schema accepts any value
+	default:
+		return fmt.Errorf(`invalid freeform tag %q: must be "object", "any", or "false"`, raw)
+	}
+	prop.Description = description
+	return nil
+}
+
+// constrainsElements reports whether constraint tags on a field of typ apply to its array items.
+// []byte is encoded as a base64 string, so its constraints stay on the property.
+func constrainsElements(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() != reflect.Uint8
+}
+
+// applyArrayTag sets the JSON Schema keyword of one [arrayTags] tag on the array schema target.
+func applyArrayTag(target *jsonschema.Schema, tag, raw string) error {
+	if tag == "uniqueItems" {
+		unique, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid uniqueItems tag %q: must be true or false", raw)
+		}
+		target.UniqueItems = unique
+		return nil
+	}
+	n, err := strconv.ParseUint(raw, 10, 31)
+	if err != nil {
+		return fmt.Errorf("invalid %s tag %q: must be a non-negative integer", tag, raw)
+	}
+	if tag == "minItems" {
+		target.MinItems = jsonschema.Ptr(int(n))
+	} else {
+		target.MaxItems = jsonschema.Ptr(int(n))
+	}
+	return nil
+}
+
+// applyConstraintTag sets the JSON Schema keyword of one constraint tag on target.
+func applyConstraintTag(target *jsonschema.Schema, tag, raw string) error {
+	switch tag {
+	case "pattern":
+		if _, err := regexp.Compile(raw); err != nil {
+			return fmt.Errorf("invalid pattern tag %q: %w", raw, err)
+		}
+		target.Pattern = raw
+	case "minLength", "maxLength":
+		n, err := strconv.ParseUint(raw, 10, 31)
+		if err != nil {
+			return fmt.Errorf("invalid %s tag %q: must be a non-negative integer", tag, raw)
+		}
+		if tag == "minLength" {
+			target.MinLength = jsonschema.Ptr(int(n))
+		} else {
+			target.MaxLength = jsonschema.Ptr(int(n))
+		}
+	default:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("invalid %s tag %q: must be a finite number", tag, raw)
+		}
+		switch tag {
+		case "minimum":
+			target.Minimum = &f
+		case "maximum":
+			target.Maximum = &f
+		case "exclusiveMinimum":
+			target.ExclusiveMinimum = &f
+		case "exclusiveMaximum":
+			target.ExclusiveMaximum = &f
+		}
+	}
+	return nil
+}
+
+// isCustomSchema reports whether s was registered with [SchemaRegistry.RegisterTypeSchema].
+func isCustomSchema(s *jsonschema.Schema) bool {
+	return s.Extra[customSchemaMarker] == true
+}
+
+// resolveLocalSchemaRef resolves "#", "#/$defs/<name>" and "#/definitions/<name>" against root.
+func resolveLocalSchemaRef(root *jsonschema.Schema, ref string) *jsonschema.Schema {
+	if ref == "#" {
+		return root
+	}
+	if name, ok := strings.CutPrefix(ref, "#/$defs/"); ok {
+		return root.Defs[unescapePointerToken(name)]
+	}
+	if name, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+		return root.Definitions[unescapePointerToken(name)]
+	}
+	return nil
+}
+
+// walkSchemas calls visit for s and, while visit returns true, every sub-schema below it.
+func walkSchemas(s *jsonschema.Schema, visit func(*jsonschema.Schema) bool) {
+	if s == nil || !visit(s) {
 		return
 	}
-	visit(schemaMap)
-	for _, val := range schemaMap {
+	for _, c := range []*jsonschema.Schema{
+		s.Items, s.AdditionalItems, s.Contains, s.UnevaluatedItems, s.AdditionalProperties, s.PropertyNames,
+		s.UnevaluatedProperties, s.Not, s.If, s.Then, s.Else, s.ContentSchema,
+	} {
+		walkSchemas(c, visit)
+	}
+	for _, list := range [][]*jsonschema.Schema{s.PrefixItems, s.ItemsArray, s.AllOf, s.AnyOf, s.OneOf} {
+		for _, c := range list {
+			walkSchemas(c, visit)
+		}
+	}
+	for _, named := range []map[string]*jsonschema.Schema{
+		s.Defs, s.Definitions, s.DependencySchemas, s.Properties, s.PatternProperties, s.DependentSchemas,
+	} {
+		for _, c := range named {
+			walkSchemas(c, visit)
+		}
+	}
+}
+
+// applySchemaStrictMode is [applyStrictMode] for a generated schema. Fields tagged `optional:"true"`
+// stay out of "required", or with [SchemaConfig.OptionalAsNullable] are required but accept null.
+// Free-form objects (no properties) stay open unless [SchemaConfig.CloseFreeform] is set.
+func applySchemaStrictMode(schema *jsonschema.Schema, cfg SchemaConfig) {
+	walkSchemas(schema, func(n *jsonschema.Schema) bool {
+		if isCustomSchema(n) {
+			return false
+		}
+		if n.Properties != nil {
+			if n.AdditionalProperties == nil || isTrueSchema(n.AdditionalProperties) {
+				n.AdditionalProperties = &jsonschema.Schema{Not: &jsonschema.Schema{}}
+			}
+			var required []string
+			for _, name := range slices.Sorted(maps.Keys(n.Properties)) {
+				prop := n.Properties[name]
+				switch {
+				case prop == nil || prop.Extra[optionalFieldMarker] != true:
+				case cfg.OptionalAsNullable:
+					n.Properties[name] = nullableSchema(prop)
+				default:
+					continue
+				}
+				required = append(required, name)
+			}
+			n.Required = required
+		} else if cfg.CloseFreeform && (n.Type == "object" || slices.Contains(n.Types, "object")) &&
+			(n.AdditionalProperties == nil || isTrueSchema(n.AdditionalProperties)) {
+			n.AdditionalProperties = &jsonschema.Schema{Not: &jsonschema.Schema{}}
+		}
+		return true
+	})
+}
+
+// isTrueSchema reports whether s is the empty schema, which accepts any value.
+func isTrueSchema(s *jsonschema.Schema) bool {
+	data, err := json.Marshal(s)
+	return err == nil && string(data) == "true"
+}
+
+// nullableSchema returns s widened to also accept null, adding "null" to its type when it has one
+// and wrapping it in anyOf otherwise.
+func nullableSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	switch {
+	case s.Type == "null" || slices.Contains(s.Types, "null"):
+		return s
+	case s.Type != "":
+		s.Types = []string{"null", s.Type}
+		s.Type = ""
+		return s
+	case len(s.Types) > 0:
+		s.Types = append([]string{"null"}, s.Types...)
+		return s
+	}
+	//nolint:exhaustruct // a bare union of s and null
+	return &jsonschema.Schema{AnyOf: []*jsonschema.Schema{s, {Type: "null"}}}
+}
+
+// finalizeGeneratedSchema drops the custom-schema marker and every id and $id, as [stripSchemaIDs] does
+// for raw schemas. Extra maps can be shared with the [SchemaRegistry], so they are replaced, not edited.
+func finalizeGeneratedSchema(schema *jsonschema.Schema) {
+	walkSchemas(schema, func(n *jsonschema.Schema) bool {
+		n.ID = ""
+		_, marked := n.Extra[customSchemaMarker]
+		_, optional := n.Extra[optionalFieldMarker]
+		_, hasID := n.Extra["id"]
+		if marked || optional || hasID {
+			extra := maps.Clone(n.Extra)
+			delete(extra, customSchemaMarker)
+			delete(extra, optionalFieldMarker)
+			delete(extra, "id")
+			if len(extra) == 0 {
+				extra = nil
+			}
+			n.Extra = extra
+		}
+		return true
+	})
+}
+
+// applyStrictMode sets additionalProperties: false and requires every property for every object in
+// the schema, except inside sub-schemas registered with [SchemaRegistry.RegisterTypeSchema]. An
+// additionalProperties schema (a typed map) is kept. Objects inside anyOf/oneOf branches keep their
+// own "required", since forcing it would change which branch matches; with [SchemaConfig.PreserveUnions]
+// those branches are not modified at all. [SchemaConfig.CloseFreeform] also closes objects without
+// properties.
+func applyStrictMode(schemaMap map[string]any, cfg SchemaConfig) {
+	applyStrictModeNode(schemaMap, false, cfg)
+}
+
+func applyStrictModeNode(schemaMap map[string]any, inUnion bool, cfg SchemaConfig) {
+	if schemaMap == nil || schemaMap[customSchemaMarker] == true {
+		return
+	}
+	if _, isObj := schemaMap["properties"]; isObj {
+		if _, typedMap := schemaMap["additionalProperties"].(map[string]any); !typedMap {
+			schemaMap["additionalProperties"] = false
+		}
+		if props, ok := schemaMap["properties"].(map[string]any); ok && !inUnion {
+			keys := make([]string, 0, len(props))
+			for k := range props {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			required := make([]any, len(keys))
+			for i, k := range keys {
+				required[i] = k
+			}
+			if len(required) > 0 {
+				schemaMap["required"] = required
+			}
+		}
+	} else if cfg.CloseFreeform && isObjectSchema(schemaMap) {
+		if ap, has := schemaMap["additionalProperties"]; !has || ap == true {
+			schemaMap["additionalProperties"] = false
+		}
+	}
+	for key, val := range schemaMap {
+		union := key == "anyOf" || key == "oneOf"
+		if union && cfg.PreserveUnions {
+			continue
+		}
 		switch v := val.(type) {
 		case map[string]any:
-			walkSchema(v, visit)
+			applyStrictModeNode(v, inUnion || union, cfg)
 		case []any:
 			for _, item := range v {
-				if m2, ok := item.(map[string]any); ok {
-					walkSchema(m2, visit)
+				if m, ok := item.(map[string]any); ok {
+					applyStrictModeNode(m, inUnion || union, cfg)
 				}
 			}
 		}
 	}
 }
 
-// applyStrictMode sets additionalProperties: false for every object in the schema.
-func applyStrictMode(schemaMap map[string]any) {
-	walkSchema(schemaMap, func(n map[string]any) {
-		if _, isObj := n["properties"]; isObj {
-			n["additionalProperties"] = false
-			if props, ok := n["properties"].(map[string]any); ok {
-				keys := make([]string, 0, len(props))
-				for k := range props {
-					keys = append(keys, k)
-				}
-				slices.Sort(keys)
-				required := make([]any, len(keys))
-				for i, k := range keys {
-					required[i] = k
-				}
-				if len(required) > 0 {
-					n["required"] = required
-				}
-			}
-		}
-	})
+// isObjectSchema reports whether a raw schema node is typed "object", alone or in a type union.
+func isObjectSchema(schemaMap map[string]any) bool {
+	switch t := schemaMap["type"].(type) {
+	case string:
+		return t == "object"
+	case []any:
+		return slices.Contains(t, any("object"))
+	}
+	return false
 }
 
-var errNilSchema = errors.New("schema reflection returned nil")
+var (
+	errNilSchema          = errors.New("schema reflection returned nil")
+	errEmptyJSONSchemaTag = errors.New("empty jsonschema tag")
+)
 
-// compileRawSchema compiles a raw JSON Schema map into a resolved validator. The map is not mutated.
+// compileRawSchema compiles a raw JSON Schema map into a resolved validator. The map is not mutated;
+// "nullable": true (see [FlattenNullableTypes]) admits null as in OpenAPI.
 // Callers must ensure the schema is valid (e.g. no conflicting $id that would break resolution).
 func compileRawSchema(schemaMap map[string]any) (*jsonschema.Resolved, error) {
 	data, err := json.Marshal(schemaMap)
@@ -211,13 +664,54 @@ func compileRawSchema(schemaMap map[string]any) (*jsonschema.Resolved, error) {
 	if err := json.Unmarshal(data, &s); err != nil {
 		return nil, err
 	}
+	expandNullable(&s)
 	return s.Resolve(nil)
 }
 
-// stripSchemaIDs removes id and $id from schema so resolution does not depend on them.
+// stripSchemaIDs removes id and $id from every schema node so resolution does not depend on them.
+// Maps keyed by property or definition name and instance data (enum, const, default, examples) are not
+// schema nodes, so a property named "id" survives.
 func stripSchemaIDs(schemaMap map[string]any) {
-	walkSchema(schemaMap, func(n map[string]any) {
+	walkSchemaNodes(schemaMap, func(n map[string]any) {
 		delete(n, "id")
 		delete(n, "$id")
 	})
 }
+
+// walkSchemaNodes visits schemaMap and every sub-schema below it, skipping name-keyed maps and instance data.
+func walkSchemaNodes(schemaMap map[string]any, visit func(map[string]any)) {
+	if schemaMap == nil {
+		return
+	}
+	visit(schemaMap)
+	for key, val := range schemaMap {
+		switch key {
+		case "enum", "const", "default", "examples":
+			continue
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			named, _ := val.(map[string]any)
+			for _, sub := range named {
+				if m, ok := sub.(map[string]any); ok {
+					walkSchemaNodes(m, visit)
+				}
+			}
+			continue
+		}
+		switch v := val.(type) {
+		case map[string]any:
+			walkSchemaNodes(v, visit)
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					walkSchemaNodes(m, visit)
+				}
+			}
+		}
+	}
+}
+
+// CloneSchema returns a deep copy of a JSON Schema map, for example [ToolManifest.Parameters] before
+// handing it to a provider SDK that may mutate it. It returns nil for an empty map.
+func CloneSchema(schema map[string]any) map[string]any {
+	return deepCloneMap(schema)
+}
@@ -0,0 +1,183 @@
+package toolsy
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// base64Schema is the schema of a []byte value, which encoding/json writes as a base64 string.
+func base64Schema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", ContentEncoding: "base64"} //nolint:exhaustruct // string with encoding
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// isBase64Bytes reports whether encoding/json encodes values of typ as base64 strings: slices of a
+// byte kind without a custom marshaler. Byte arrays are encoded as JSON arrays.
+func isBase64Bytes(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	for _, t := range []reflect.Type{typ, reflect.PointerTo(typ), typ.Elem(), reflect.PointerTo(typ.Elem())} {
+		if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyMaxBytesTag handles `maxBytes:"N"` on a []byte field: the schema caps the base64 length and
+// mentions the decoded limit, which [checkByteLimits] enforces exactly.
+func applyMaxBytesTag(prop *jsonschema.Schema, field reflect.StructField, raw string) error {
+	typ := field.Type
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if !isBase64Bytes(typ) {
+		return fmt.Errorf("maxBytes tag on a field that is not []byte")
+	}
+	n, err := parseMaxBytes(raw)
+	if err != nil {
+		return err
+	}
+	prop.MaxLength = jsonschema.Ptr((n + 2) / 3 * 4)
+	note := fmt.Sprintf("Base64-encoded, at most %d bytes decoded.", n)
+	if prop.Description == "" {
+		prop.Description = note
+	} else {
+		prop.Description = strings.TrimSuffix(prop.Description, ".") + ". " + note
+	}
+	return nil
+}
+
+func parseMaxBytes(raw string) (int, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxBytes tag %q: must be a non-negative integer", raw)
+	}
+	return int(n), nil
+}
+
+// byteLimitNode mirrors the argument type down to the []byte fields tagged `maxBytes`.
+type byteLimitNode struct {
+	limit  int // -1 when this node only leads to limited fields
+	fields map[string]*byteLimitNode
+	elem   *byteLimitNode
+}
+
+// buildByteLimitPlan returns the limit tree for typ, or nil when no field of typ has a maxBytes tag.
+// Tag values were validated during schema generation.
+func buildByteLimitPlan(typ reflect.Type, active map[reflect.Type]bool) *byteLimitNode {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if active[typ] || isBase64Bytes(typ) {
+		return nil
+	}
+	active[typ] = true
+	defer delete(active, typ)
+	switch typ.Kind() { //nolint:exhaustive // only container and struct kinds can contain byte fields
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if elem := buildByteLimitPlan(typ.Elem(), active); elem != nil {
+			return &byteLimitNode{limit: -1, fields: nil, elem: elem}
+		}
+	case reflect.Struct:
+		fields := make(map[string]*byteLimitNode)
+		for _, field := range reflect.VisibleFields(typ) {
+			name, ok := schemaFieldName(field)
+			if !ok {
+				continue
+			}
+			if raw, tagged := field.Tag.Lookup("maxBytes"); tagged {
+				if n, err := parseMaxBytes(raw); err == nil {
+					fields[name] = &byteLimitNode{limit: n, fields: nil, elem: nil}
+				}
+				continue
+			}
+			if sub := buildByteLimitPlan(field.Type, active); sub != nil {
+				fields[name] = sub
+			}
+		}
+		if len(fields) > 0 {
+			return &byteLimitNode{limit: -1, fields: fields, elem: nil}
+		}
+	}
+	return nil
+}
+
+// checkByteLimits reports base64 strings in tree that decode to more bytes than their maxBytes tag
+// allows, as a validation [ToolError] with the first violation or, with allErrors, all of them.
+// Strings that are not valid base64 are left to the decoder.
+func checkByteLimits(plan *byteLimitNode, tree any, allErrors bool) error {
+	if plan == nil {
+		return nil
+	}
+	limit := 1
+	if allErrors {
+		limit = 0
+	}
+	var out []FieldViolation
+	var walk func(n *byteLimitNode, v any, path string)
+	walk = func(n *byteLimitNode, v any, path string) {
+		if limit > 0 && len(out) >= limit {
+			return
+		}
+		switch {
+		case n.limit >= 0:
+			s, ok := v.(string)
+			if !ok {
+				return
+			}
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err == nil && len(data) > n.limit {
+				out = append(out, FieldViolation{
+					Path:    path,
+					Keyword: "maxBytes",
+					Message: fmt.Sprintf("maxBytes: decoded size %d exceeds %d bytes", len(data), n.limit),
+					Value:   nil,
+				})
+			}
+		case n.fields != nil:
+			obj, _ := v.(map[string]any)
+			for _, name := range slices.Sorted(maps.Keys(n.fields)) {
+				if fv, ok := obj[name]; ok {
+					walk(n.fields[name], fv, path+"/"+escapePointerToken(name))
+				}
+			}
+		case n.elem != nil:
+			switch c := v.(type) {
+			case []any:
+				for i, item := range c {
+					walk(n.elem, item, path+"/"+strconv.Itoa(i))
+				}
+			case map[string]any:
+				for _, key := range slices.Sorted(maps.Keys(c)) {
+					walk(n.elem, c[key], path+"/"+escapePointerToken(key))
+				}
+			}
+		}
+	}
+	walk(plan, tree, "")
+	if len(out) == 0 {
+		return nil
+	}
+	te := NewValidationError(out[0].Message)
+	te.Violations = out
+	if len(out) > 1 {
+		te.Reason = violationsReason(out)
+	}
+	te.FixableArgs = violationFixableArgs(out)
+	return te
+}
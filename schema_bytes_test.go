@@ -0,0 +1,126 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type blob []byte
+
+type bytesArgs struct {
+	Data   []byte            `json:"data"   maxBytes:"4" description:"Raw payload"`
+	Blob   blob              `json:"blob"`
+	Thumb  *[]byte           `json:"thumb"`
+	Chunks [][]byte          `json:"chunks"`
+	Files  map[string]bytesF `json:"files"`
+}
+
+type bytesF struct {
+	Body []byte `json:"body" maxBytes:"2"`
+}
+
+func b64(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+func TestGenerateSchema_BytesAsBase64(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		schemaMap, _, err := generateSchema[bytesArgs](testSchemaConfig(strict))
+		require.NoError(t, err)
+		props := schemaMap["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{
+			"type": "string", "contentEncoding": "base64", "maxLength": float64(8),
+			"description": "Raw payload. Base64-encoded, at most 4 bytes decoded.",
+		}, props["data"])
+		assert.Equal(t, map[string]any{"type": "string", "contentEncoding": "base64"}, props["blob"])
+		thumb := props["thumb"].(map[string]any)
+		assert.Equal(t, []any{"null", "string"}, thumb["type"])
+		assert.Equal(t, "base64", thumb["contentEncoding"])
+		items := props["chunks"].(map[string]any)["items"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "string", "contentEncoding": "base64"}, items)
+		assert.NotContains(t, props["data"], "additionalProperties", "strict mode must not treat bytes as objects")
+	}
+}
+
+func TestExtractor_BytesRoundTrip(t *testing.T) {
+	ext, err := NewExtractor[bytesArgs](true)
+	require.NoError(t, err)
+	call := `{"data":"` + b64("abcd") + `","blob":"` + b64("xyz") + `","thumb":null,"chunks":["` + b64("q") +
+		`"],"files":{"a":{"body":"` + b64("hi") + `"}}}`
+	args, err := ext.ParseAndValidate([]byte(call))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abcd"), args.Data)
+	assert.Equal(t, blob("xyz"), args.Blob)
+	assert.Equal(t, [][]byte{[]byte("q")}, args.Chunks)
+	assert.Equal(t, []byte("hi"), args.Files["a"].Body)
+}
+
+func TestExtractor_MaxBytes(t *testing.T) {
+	call := func(data, body string) []byte {
+		return []byte(`{"data":"` + b64(data) + `","blob":"","thumb":null,"chunks":[],` +
+			`"files":{"a":{"body":"` + b64(body) + `"}}}`)
+	}
+	ext, err := NewExtractor[bytesArgs](false)
+	require.NoError(t, err)
+
+	_, err = ext.ParseAndValidate(call("abcde", "hi"))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+	assert.True(t, clientCorrectable(err))
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "/data", te.Violations[0].Path)
+	assert.Equal(t, "maxBytes", te.Violations[0].Keyword)
+	assert.Contains(t, te.Error(), "decoded size 5 exceeds 4 bytes")
+	require.Error(t, ext.Validate(call("abcd", "hi!")), "Validate runs the same check")
+
+	all, err := NewExtractorWithConfig[bytesArgs](SchemaConfig{AllValidationErrors: true}) //nolint:exhaustruct // test
+	require.NoError(t, err)
+	_, err = all.ParseAndValidate(call("abcde", "hi!"))
+	te, ok = AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 2)
+	assert.Equal(t, "/data", te.Violations[0].Path)
+	assert.Equal(t, "/files/a/body", te.Violations[1].Path)
+}
+
+func TestGenerateSchema_MaxBytesOnNonBytes(t *testing.T) {
+	type bad struct {
+		Name string `json:"name" maxBytes:"4"`
+	}
+	_, _, err := generateSchema[bad](testSchemaConfig(false))
+	require.ErrorContains(t, err, "maxBytes tag on a field that is not []byte")
+	type badValue struct {
+		Data []byte `json:"data" maxBytes:"lots"`
+	}
+	_, _, err = generateSchema[badValue](testSchemaConfig(false))
+	require.ErrorContains(t, err, "invalid maxBytes tag")
+}
+
+func TestNewTool_BytesResult(t *testing.T) {
+	type out struct {
+		Image []byte `json:"image"`
+	}
+	tool, err := NewTool("snap", "Returns bytes", func(context.Context, *RunEnv, struct{}) (out, error) {
+		return out{Image: []byte{0, 0xff, 'a'}}, nil
+	})
+	require.NoError(t, err)
+	image := tool.Manifest().OutputSchema["properties"].(map[string]any)["image"].(map[string]any)
+	assert.Equal(t, "base64", image["contentEncoding"])
+
+	var data []byte
+	err = tool.Execute(context.Background(), nil, ToolInput{ArgsJSON: []byte(`{}`)}, func(c Chunk) error {
+		data = append(data, c.Data...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, json.Valid(data))
+	var got out
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, []byte{0, 0xff, 'a'}, got.Image)
+	assert.True(t, strings.Contains(string(data), `"AP9h"`))
+}
@@ -0,0 +1,79 @@
+package toolsy
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// schemaCacheKey identifies a schema produced by [generateSchema]: for a fixed set of type mappings,
+// the Go type and the strict flags fully determine the result.
+type schemaCacheKey struct {
+	typ              reflect.Type
+	strict           bool
+	optionalNullable bool
+	closeFreeform    bool
+}
+
+// schemaCacheEntry is never handed out directly; readers get a deep copy of schemaMap. resolved is
+// read-only after compilation and shared.
+type schemaCacheEntry struct {
+	schemaMap map[string]any
+	resolved  *jsonschema.Resolved
+}
+
+// defaultSchemaCache holds schemas generated without custom type mappings, the common case of a
+// fresh [SchemaRegistry] per tool. Registries with mappings cache on themselves (see [schemaCache]).
+var defaultSchemaCache sync.Map //nolint:gochecknoglobals // process-wide cache of immutable entries
+
+// schemaCache is the cache for one generation of a registry's type mappings.
+type schemaCache struct {
+	registry   *SchemaRegistry // nil selects defaultSchemaCache
+	generation uint64
+}
+
+func (c schemaCache) load(key schemaCacheKey) (map[string]any, *jsonschema.Resolved, bool) {
+	var entry schemaCacheEntry
+	if c.registry == nil {
+		v, ok := defaultSchemaCache.Load(key)
+		if !ok {
+			return nil, nil, false
+		}
+		entry, ok = v.(schemaCacheEntry)
+		if !ok {
+			return nil, nil, false
+		}
+	} else {
+		r := c.registry
+		r.mu.RLock()
+		var ok bool
+		entry, ok = r.schemas[key]
+		stale := r.generation != c.generation
+		r.mu.RUnlock()
+		if !ok || stale {
+			return nil, nil, false
+		}
+	}
+	return deepCopySchema(entry.schemaMap), entry.resolved, true
+}
+
+// store caches a copy of schemaMap unless the registry's mappings changed since the snapshot the
+// schema was generated from.
+func (c schemaCache) store(key schemaCacheKey, schemaMap map[string]any, resolved *jsonschema.Resolved) {
+	entry := schemaCacheEntry{schemaMap: deepCopySchema(schemaMap), resolved: resolved}
+	if c.registry == nil {
+		defaultSchemaCache.Store(key, entry)
+		return
+	}
+	r := c.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != c.generation {
+		return
+	}
+	if r.schemas == nil {
+		r.schemas = make(map[schemaCacheKey]schemaCacheEntry)
+	}
+	r.schemas[key] = entry
+}
@@ -0,0 +1,54 @@
+package toolsy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaCacheArgs struct {
+	ID    schemaCacheID `json:"id"`
+	Query string        `json:"query"`
+}
+
+type schemaCacheID struct {
+	Value string `json:"value"`
+}
+
+func TestGenerateSchema_CachedCopiesAreIndependent(t *testing.T) {
+	first, resolved, err := generateSchema[schemaCacheArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	first["properties"].(map[string]any)["query"].(map[string]any)["type"] = "integer"
+	delete(first, "properties")
+
+	second, cachedResolved, err := generateSchema[schemaCacheArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	assert.Same(t, resolved, cachedResolved, "compiled schema is shared")
+	assert.Equal(t, "string", second["properties"].(map[string]any)["query"].(map[string]any)["type"])
+
+	strict, _, err := generateSchema[schemaCacheArgs](testSchemaConfig(true))
+	require.NoError(t, err)
+	assert.Equal(t, false, findSchemaObject(strict)["additionalProperties"], "strict mode is part of the key")
+}
+
+func TestGenerateSchema_RegisterTypeInvalidatesRegistryCache(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterType(schemaCacheID{}, "string", "uuid")
+	cfg := SchemaConfig{Registry: registry}
+	idSchema := func() map[string]any {
+		m, _, err := generateSchema[schemaCacheArgs](cfg)
+		require.NoError(t, err)
+		return m["properties"].(map[string]any)["id"].(map[string]any)
+	}
+	assert.Equal(t, "uuid", idSchema()["format"])
+	assert.Equal(t, "uuid", idSchema()["format"])
+
+	registry.RegisterType(schemaCacheID{}, "string", "ulid")
+	assert.Equal(t, "ulid", idSchema()["format"], "later registrations apply to new tools")
+
+	plain, _, err := generateSchema[schemaCacheArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	assert.Equal(t, "object", plain["properties"].(map[string]any)["id"].(map[string]any)["type"],
+		"registry mappings never leak into the default cache")
+}
@@ -0,0 +1,208 @@
+package toolsy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// SchemaStringCodec decodes the string JSON form of a Go type whose own JSON decoding does not accept
+// strings, for example [time.Duration], which encoding/json reads only as integer nanoseconds.
+// DecodeString returns a value of the registered type; its JSON encoding must decode back into that type.
+type SchemaStringCodec interface {
+	DecodeString(s string) (any, error)
+}
+
+// SchemaStringCodecFunc adapts a function to [SchemaStringCodec].
+type SchemaStringCodecFunc func(s string) (any, error)
+
+// DecodeString calls f(s).
+func (f SchemaStringCodecFunc) DecodeString(s string) (any, error) {
+	return f(s)
+}
+
+// RegisterStringCodec makes typed tools and [Extractor.ParseAndValidate] decode string arguments of the
+// type of emptyInstance through codec, wherever that type appears in the args (pointer fields included).
+// Pair it with a "string" schema from [SchemaRegistry.RegisterType] or [SchemaRegistry.RegisterTypeSchema].
+// Non-string JSON values are left to encoding/json. A registered codec replaces a built-in one;
+// time.Duration has a built-in codec using [time.ParseDuration].
+func (r *SchemaRegistry) RegisterStringCodec(emptyInstance any, codec SchemaStringCodec) {
+	if emptyInstance == nil {
+		panic("toolsy: RegisterStringCodec emptyInstance must not be nil")
+	}
+	if codec == nil {
+		panic("toolsy: RegisterStringCodec codec must not be nil")
+	}
+	t := reflect.TypeOf(emptyInstance)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.codecs == nil {
+		r.codecs = make(map[reflect.Type]SchemaStringCodec)
+	}
+	r.codecs[t] = codec
+}
+
+func builtinStringCodecs() map[reflect.Type]SchemaStringCodec {
+	return map[reflect.Type]SchemaStringCodec{
+		reflect.TypeFor[time.Duration](): SchemaStringCodecFunc(func(s string) (any, error) {
+			return time.ParseDuration(s)
+		}),
+	}
+}
+
+func (r *SchemaRegistry) buildStringCodecs() map[reflect.Type]SchemaStringCodec {
+	out := builtinStringCodecs()
+	if r == nil {
+		return out
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for t, c := range r.codecs {
+		out[t] = c
+	}
+	return out
+}
+
+// stringDecodeNode mirrors the parts of an args type that lead to a codec-decoded type.
+// Struct nodes have fields (by JSON name); slice, array, and map nodes have elem.
+type stringDecodeNode struct {
+	typ    reflect.Type
+	codec  SchemaStringCodec
+	fields map[string]*stringDecodeNode
+	elem   *stringDecodeNode
+}
+
+var jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// buildStringDecodePlan returns the decode tree for typ, or nil when no value of typ needs a codec,
+// in which case arguments are unmarshaled without the extra pass.
+func buildStringDecodePlan(
+	typ reflect.Type,
+	codecs map[reflect.Type]SchemaStringCodec,
+	active map[reflect.Type]bool,
+) *stringDecodeNode {
+	if typ == nil {
+		return nil
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if codec, ok := codecs[typ]; ok {
+		return &stringDecodeNode{typ: typ, codec: codec, fields: nil, elem: nil}
+	}
+	if active[typ] || reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	active[typ] = true
+	defer delete(active, typ)
+	switch typ.Kind() { //nolint:exhaustive // only container and struct kinds can contain codec types
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if elem := buildStringDecodePlan(typ.Elem(), codecs, active); elem != nil {
+			return &stringDecodeNode{typ: typ, codec: nil, fields: nil, elem: elem}
+		}
+	case reflect.Struct:
+		fields := make(map[string]*stringDecodeNode)
+		for _, field := range reflect.VisibleFields(typ) {
+			name, ok := schemaFieldName(field)
+			if !ok {
+				continue
+			}
+			if child := buildStringDecodePlan(field.Type, codecs, active); child != nil {
+				fields[name] = child
+			}
+		}
+		if len(fields) > 0 {
+			return &stringDecodeNode{typ: typ, codec: nil, fields: fields, elem: nil}
+		}
+	}
+	return nil
+}
+
+// decodeCodecStrings rewrites argsJSON with codec-decoded values. Numbers are kept as [json.Number]
+// so re-encoding never loses integer precision.
+func decodeCodecStrings(plan *stringDecodeNode, argsJSON []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(argsJSON))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, wrapJSONParseError(err)
+	}
+	decoded, err := plan.apply(tree, "")
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, NewInternalError(fmt.Errorf("toolsy: re-encode decoded args: %w", err))
+	}
+	return out, nil
+}
+
+// apply replaces string values at codec positions of v (a decoded JSON value) with the JSON encoding of
+// the codec's result, in place where possible. It fails with a validation error naming the JSON pointer path.
+func (n *stringDecodeNode) apply(v any, path string) (any, error) {
+	switch {
+	case n.codec != nil:
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+		return n.decode(s, path)
+	case n.fields != nil:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for key, child := range n.fields {
+			val, present := obj[key]
+			if !present {
+				continue
+			}
+			out, err := child.apply(val, path+"/"+escapePointerToken(key))
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = out
+		}
+	case n.elem != nil:
+		switch container := v.(type) {
+		case []any:
+			for i, item := range container {
+				out, err := n.elem.apply(item, fmt.Sprintf("%s/%d", path, i))
+				if err != nil {
+					return nil, err
+				}
+				container[i] = out
+			}
+		case map[string]any:
+			for key, item := range container {
+				out, err := n.elem.apply(item, path+"/"+escapePointerToken(key))
+				if err != nil {
+					return nil, err
+				}
+				container[key] = out
+			}
+		}
+	}
+	return v, nil
+}
+
+func (n *stringDecodeNode) decode(s, path string) (any, error) {
+	decoded, err := n.codec.DecodeString(s)
+	if err == nil && (decoded == nil || reflect.TypeOf(decoded) != n.typ) {
+		err = fmt.Errorf("codec returned %T, want %s", decoded, n.typ)
+	}
+	var data []byte
+	if err == nil {
+		data, err = json.Marshal(decoded)
+	}
+	if err != nil {
+		te := NewValidationError(fmt.Sprintf("invalid %s at %s: %v", n.typ, path, err))
+		te.Violations = []FieldViolation{{Path: path, Keyword: "format", Message: err.Error(), Value: smallValue(s)}}
+		te.FixableArgs = violationFixableArgs(te.Violations)
+		return nil, te
+	}
+	return json.RawMessage(data), nil
+}
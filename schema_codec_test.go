@@ -0,0 +1,150 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scheduleArgs struct {
+	At       time.Time                `json:"at"`
+	Every    time.Duration            `json:"every"`
+	Timeout  *time.Duration           `json:"timeout,omitempty"`
+	Backoffs []time.Duration          `json:"backoffs,omitempty"`
+	Windows  map[string]time.Duration `json:"windows,omitempty"`
+}
+
+func TestGenerateSchema_BuiltinTimeMappings(t *testing.T) {
+	schemaMap, _, err := generateSchema[scheduleArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, props["at"])
+	assert.Equal(t, map[string]any{"type": "string", "description": `Go duration, e.g. "30s"`}, props["every"])
+}
+
+func TestExtractor_DecodesDurationStrings(t *testing.T) {
+	ext, err := NewExtractor[scheduleArgs](false)
+	require.NoError(t, err)
+	args, err := ext.ParseAndValidate([]byte(`{
+		"at": "2026-01-02T03:04:05Z",
+		"every": "1m30s",
+		"timeout": "250ms",
+		"backoffs": ["1s", "2s"],
+		"windows": {"peak": "2h"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), args.At)
+	assert.Equal(t, 90*time.Second, args.Every)
+	require.NotNil(t, args.Timeout)
+	assert.Equal(t, 250*time.Millisecond, *args.Timeout)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, args.Backoffs)
+	assert.Equal(t, map[string]time.Duration{"peak": 2 * time.Hour}, args.Windows)
+
+	_, err = ext.ParseAndValidate([]byte(`{"at":"2026-01-02T03:04:05Z","every":"1m","backoffs":["soon"]}`))
+	require.ErrorIs(t, err, ErrValidation)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "/backoffs/0", te.Violations[0].Path)
+	assert.Equal(t, "format", te.Violations[0].Keyword)
+	assert.Equal(t, "soon", te.Violations[0].Value)
+	assert.Equal(t, []string{"backoffs"}, te.FixableArgs)
+}
+
+func TestSchemaRegistry_UserMappingsBeatTimeBuiltins(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterType(time.Duration(0), "integer", "")
+	registry.RegisterType(time.Time{}, "string", "date")
+	type args struct {
+		TTL time.Duration `json:"ttl"`
+		Day time.Time     `json:"day"`
+	}
+	ext, err := NewExtractorWithConfig[args](SchemaConfig{Registry: registry})
+	require.NoError(t, err)
+	props := ext.Schema()["properties"].(map[string]any)
+	assert.Equal(t, "integer", props["ttl"].(map[string]any)["type"])
+	assert.Equal(t, "date", props["day"].(map[string]any)["format"])
+
+	got, err := ext.ParseAndValidate([]byte(`{"ttl":1000,"day":"2026-01-02T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, time.Microsecond, got.TTL)
+}
+
+type ticketID struct{ n int }
+
+func (id ticketID) MarshalJSON() ([]byte, error) { return json.Marshal(id.n) }
+
+func (id *ticketID) UnmarshalJSON(data []byte) error { return json.Unmarshal(data, &id.n) }
+
+func TestSchemaRegistry_RegisterStringCodec(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterType(time.Duration(0), "string", "")
+	registry.RegisterStringCodec(time.Duration(0), SchemaStringCodecFunc(func(s string) (any, error) {
+		if s == "forever" {
+			return time.Duration(1<<63 - 1), nil
+		}
+		return time.ParseDuration(s)
+	}))
+	registry.RegisterTypeSchema(ticketID{}, map[string]any{"type": "string", "pattern": "^T-[0-9]+$"})
+	registry.RegisterStringCodec(ticketID{}, SchemaStringCodecFunc(func(s string) (any, error) {
+		var n int
+		if _, err := fmt.Sscanf(s, "T-%d", &n); err != nil {
+			return nil, errors.New("want T-<number>")
+		}
+		return ticketID{n: n}, nil
+	}))
+	type args struct {
+		TTL     time.Duration `json:"ttl"`
+		Tickets []ticketID    `json:"tickets"`
+	}
+	tool, err := NewTool("close", "Close tickets", func(_ context.Context, _ *RunEnv, a args) (string, error) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d", a.TTL)
+		for _, id := range a.Tickets {
+			fmt.Fprintf(&b, ",%d", id.n)
+		}
+		return b.String(), nil
+	}, WithSchemaRegistry(registry))
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+
+	var out string
+	err = reg.Execute(context.Background(), ToolCall{
+		ToolName: "close",
+		Input:    ToolInput{ArgsJSON: []byte(`{"ttl":"forever","tickets":["T-7","T-42"]}`)},
+	}, func(c Chunk) error {
+		return json.Unmarshal(c.Data, &out)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "9223372036854775807,7,42", out)
+
+	assert.Panics(t, func() { registry.RegisterStringCodec(nil, SchemaStringCodecFunc(nil)) })
+	assert.Panics(t, func() { registry.RegisterStringCodec(ticketID{}, nil) })
+}
+
+func TestStringDecodePlan_RejectsWrongCodecType(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterStringCodec(time.Duration(0), SchemaStringCodecFunc(func(string) (any, error) {
+		return "not a duration", nil
+	}))
+	type args struct {
+		TTL time.Duration `json:"ttl"`
+	}
+	ext, err := NewExtractorWithConfig[args](SchemaConfig{Registry: registry})
+	require.NoError(t, err)
+	_, err = ext.ParseAndValidate([]byte(`{"ttl":"1s"}`))
+	require.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "codec returned string, want time.Duration")
+
+	assert.Nil(t, buildStringDecodePlan(reflect.TypeFor[struct {
+		Name string `json:"name"`
+	}](), builtinStringCodecs(), map[reflect.Type]bool{}))
+}
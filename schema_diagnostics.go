@@ -0,0 +1,106 @@
+package toolsy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// fieldPathError localizes a schema construction failure to one field of the args type.
+// Path is dot-separated JSON names; "[]" marks slice/array elements and "{}" map values.
+type fieldPathError struct {
+	Path string
+	Type reflect.Type
+	Err  error
+}
+
+func (e *fieldPathError) Error() string {
+	return fmt.Sprintf("args field %q (type %s): %v", e.Path, e.Type, e.Err)
+}
+
+func (e *fieldPathError) Unwrap() error { return e.Err }
+
+// diagnoseSchemaError wraps err from schema reflection of typ with the path of the deepest field
+// whose own schema fails. It runs only after reflection failed, so successful builds pay nothing.
+// err is returned unchanged when no single field can be blamed.
+func diagnoseSchemaError(typ reflect.Type, opts *jsonschema.ForOptions, err error) error {
+	if typ == nil {
+		return err
+	}
+	if located := locateSchemaField(typ, opts, "", make(map[reflect.Type]bool)); located != nil {
+		return located
+	}
+	return err
+}
+
+func locateSchemaField(
+	typ reflect.Type,
+	opts *jsonschema.ForOptions,
+	prefix string,
+	seen map[reflect.Type]bool,
+) *fieldPathError {
+	typ, suffix := schemaElemType(typ)
+	prefix += suffix
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+	defer delete(seen, typ)
+	for _, field := range reflect.VisibleFields(typ) {
+		name, ok := schemaFieldName(field)
+		if !ok {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if tag, has := field.Tag.Lookup("jsonschema"); has && tag == "" {
+			return &fieldPathError{Path: path, Type: field.Type, Err: errEmptyJSONSchemaTag}
+		}
+		if _, fieldErr := jsonschema.ForType(field.Type, opts); fieldErr != nil {
+			if deeper := locateSchemaField(field.Type, opts, path, seen); deeper != nil {
+				return deeper
+			}
+			return &fieldPathError{Path: path, Type: field.Type, Err: fieldErr}
+		}
+	}
+	return nil
+}
+
+// schemaElemType unwraps pointers and containers down to the type whose fields carry the schema.
+func schemaElemType(typ reflect.Type) (reflect.Type, string) {
+	var suffix strings.Builder
+	for {
+		switch typ.Kind() { //nolint:exhaustive // only wrapper kinds are unwrapped
+		case reflect.Pointer:
+			typ = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			suffix.WriteString("[]")
+			typ = typ.Elem()
+		case reflect.Map:
+			suffix.WriteString("{}")
+			typ = typ.Elem()
+		default:
+			return typ, suffix.String()
+		}
+	}
+}
+
+// schemaFieldName returns the JSON property name of a field that contributes to the schema.
+// Embedded structs are skipped because [reflect.VisibleFields] already promotes their fields.
+func schemaFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() || field.Anonymous {
+		return "", false
+	}
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
@@ -0,0 +1,111 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diagDateRange struct {
+	Start chan int `json:"start"`
+	End   string   `json:"end"`
+}
+
+type diagFilters struct {
+	Query     string        `json:"query"`
+	DateRange diagDateRange `json:"dateRange"`
+}
+
+type diagNestedArgs struct {
+	Limit   int          `json:"limit"`
+	Filters *diagFilters `json:"filters"`
+}
+
+type diagItem struct {
+	Callback func() `json:"callback"`
+}
+
+type diagListArgs struct {
+	Items []diagItem `json:"items"`
+}
+
+type diagMapArgs struct {
+	Weights map[complex64]int `json:"weights"`
+}
+
+type diagTagArgs struct {
+	Inner struct {
+		Name string `json:"name" jsonschema:""`
+	} `json:"inner"`
+}
+
+type diagBase struct {
+	Hook func() `json:"hook"`
+}
+
+type diagEmbeddedArgs struct {
+	diagBase
+	Name string `json:"name"`
+}
+
+func TestNewTool_SchemaErrorNamesNestedFieldPath(t *testing.T) {
+	_, err := NewTool("diag", "Diag", func(_ context.Context, _ *RunEnv, _ diagNestedArgs) (string, error) {
+		return "", nil
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `args field "filters.dateRange.start" (type chan int)`)
+	var fpe *fieldPathError
+	require.ErrorAs(t, err, &fpe)
+	assert.Equal(t, "filters.dateRange.start", fpe.Path)
+	assert.NotNil(t, errors.Unwrap(fpe))
+}
+
+func TestGenerateSchema_ErrorPathCoversContainersTagsAndEmbedding(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  func() error
+		path string
+	}{
+		{
+			name: "slice element",
+			gen:  func() error { _, _, err := generateSchema[diagListArgs](SchemaConfig{}); return err },
+			path: "items[].callback",
+		},
+		{
+			name: "map key",
+			gen:  func() error { _, _, err := generateSchema[diagMapArgs](SchemaConfig{}); return err },
+			path: "weights",
+		},
+		{
+			name: "empty jsonschema tag",
+			gen:  func() error { _, _, err := generateSchema[diagTagArgs](SchemaConfig{}); return err },
+			path: "inner.name",
+		},
+		{
+			name: "promoted embedded field",
+			gen:  func() error { _, _, err := generateSchema[diagEmbeddedArgs](SchemaConfig{}); return err },
+			path: "hook",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.gen()
+			require.Error(t, err)
+			var fpe *fieldPathError
+			require.ErrorAs(t, err, &fpe)
+			assert.Equal(t, tt.path, fpe.Path)
+			assert.Contains(t, err.Error(), `args field "`+tt.path+`"`)
+		})
+	}
+}
+
+func TestGenerateSchema_ValidArgsUnaffectedByDiagnostics(t *testing.T) {
+	type ok struct {
+		Name string `json:"name"`
+	}
+	_, _, err := generateSchema[ok](SchemaConfig{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,149 @@
+package toolsy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// SchemaEnumer is implemented by argument types whose allowed values are known in Go, typically a
+// named type with a set of constants. Generated schemas list the values as the "enum" of every
+// field of that type (including pointer, slice, and map element positions), so the compiled
+// validator enforces them; arguments still decode through the type's own UnmarshalJSON. The
+// values are what the type marshals to, so an int-backed type encoded as names returns the names.
+// SchemaEnum is called on a pointer to the zero value when the schema is generated.
+type SchemaEnumer interface {
+	SchemaEnum() []string
+}
+
+// SchemaEnumValuer is [SchemaEnumer] for non-string values, such as numeric codes.
+type SchemaEnumValuer interface {
+	SchemaEnum() []any
+}
+
+// addDerivedTypeSchemas adds a schema with "enum" to typeSchemas for every [SchemaEnumer] or
+// [SchemaEnumValuer] type reachable from root. A mapping already in typeSchemas (from the
+// [SchemaRegistry]) is kept and gains the enum; otherwise the type is derived from the values.
+// Named byte slice types without a mapping get the base64 string schema of []byte.
+func addDerivedTypeSchemas(root reflect.Type, typeSchemas map[reflect.Type]*jsonschema.Schema) error {
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type) error
+	walk = func(t reflect.Type) error {
+		if t == nil || seen[t] {
+			return nil
+		}
+		seen[t] = true
+		if values, ok := schemaEnumValues(t); ok {
+			s, err := enumTypeSchema(t, values, typeSchemas[t])
+			if err != nil {
+				return err
+			}
+			typeSchemas[t] = s
+			return nil
+		}
+		if typeSchemas[t] != nil {
+			return nil
+		}
+		if isBase64Bytes(t) {
+			typeSchemas[t] = base64Schema()
+			return nil
+		}
+		switch t.Kind() { //nolint:exhaustive // only container and struct kinds reach other types
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			return walk(t.Elem())
+		case reflect.Map:
+			return walk(t.Elem())
+		case reflect.Struct:
+			for _, field := range reflect.VisibleFields(t) {
+				if _, ok := schemaFieldName(field); ok {
+					if err := walk(field.Type); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+func schemaEnumValues(t reflect.Type) ([]any, bool) {
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface {
+		return nil, false
+	}
+	switch e := reflect.New(t).Interface().(type) {
+	case SchemaEnumer:
+		values := e.SchemaEnum()
+		out := make([]any, len(values))
+		for i, v := range values {
+			out[i] = v
+		}
+		return out, true
+	case SchemaEnumValuer:
+		return slices.Clone(e.SchemaEnum()), true
+	}
+	return nil, false
+}
+
+var errEmptySchemaEnum = errors.New("SchemaEnum returned no values")
+
+func enumTypeSchema(t reflect.Type, values []any, base *jsonschema.Schema) (*jsonschema.Schema, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("toolsy: enum type %s: %w", t, errEmptySchemaEnum)
+	}
+	var s *jsonschema.Schema
+	if base != nil {
+		s = base.CloneSchemas()
+	} else {
+		s = &jsonschema.Schema{Type: enumValuesType(values)} //nolint:exhaustruct // type and enum only
+	}
+	s.Enum = values
+	return s, nil
+}
+
+// enumValuesType is the JSON type shared by all values, or "" when they differ.
+func enumValuesType(values []any) string {
+	typ := ""
+	for _, v := range values {
+		var vt string
+		switch n := v.(type) {
+		case string:
+			vt = "string"
+		case bool:
+			vt = "boolean"
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			vt = "integer"
+		case float32, float64:
+			vt = "number"
+			if f := reflect.ValueOf(n).Float(); f == float64(int64(f)) {
+				vt = "integer"
+			}
+		default:
+			return ""
+		}
+		switch {
+		case typ == "":
+			typ = vt
+		case typ == vt:
+		case typ == "integer" && vt == "number", typ == "number" && vt == "integer":
+			typ = "number"
+		default:
+			return ""
+		}
+	}
+	return typ
+}
+
+// allowNullInNullableEnums adds null to the enum of schemas whose type admits null (pointer fields),
+// which would otherwise reject the null the type union allows.
+func allowNullInNullableEnums(schema *jsonschema.Schema) {
+	walkSchemas(schema, func(n *jsonschema.Schema) bool {
+		if n.Enum != nil && slices.Contains(n.Types, "null") && !slices.Contains(n.Enum, nil) {
+			n.Enum = append(slices.Clip(n.Enum), nil)
+		}
+		return true
+	})
+}
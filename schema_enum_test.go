@@ -0,0 +1,76 @@
+package toolsy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type enumColor string
+
+func (enumColor) SchemaEnum() []string { return []string{"red", "green"} }
+
+type enumCode int
+
+func (*enumCode) SchemaEnum() []any { return []any{200, 404} }
+
+type enumRegion string
+
+func (enumRegion) SchemaEnum() []string { return []string{"eu", "us"} }
+
+type enumEmpty string
+
+func (enumEmpty) SchemaEnum() []string { return nil }
+
+type enumArgs struct {
+	Color    enumColor            `json:"color"`
+	Fallback *enumColor           `json:"fallback"`
+	Palette  []enumColor          `json:"palette"`
+	ByName   map[string]enumColor `json:"by_name"`
+	Code     enumCode             `json:"code"`
+	Priority *Priority            `json:"priority"`
+	Region   enumRegion           `json:"region"`
+}
+
+func TestGenerateSchema_SchemaEnumer(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.RegisterType(enumRegion(""), "string", "region-code")
+	schemaMap, _, err := generateSchema[enumArgs](SchemaConfig{Registry: registry})
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	colors := []any{"red", "green"}
+	assert.Equal(t, map[string]any{"type": "string", "enum": colors}, props["color"])
+	assert.Equal(t, []any{"red", "green", nil}, props["fallback"].(map[string]any)["enum"], "pointer fields accept null")
+	assert.Equal(t, colors, props["palette"].(map[string]any)["items"].(map[string]any)["enum"])
+	assert.Equal(t, colors, props["by_name"].(map[string]any)["additionalProperties"].(map[string]any)["enum"])
+	assert.Equal(t, map[string]any{"type": "integer", "enum": []any{float64(200), float64(404)}}, props["code"])
+	assert.Equal(t, []any{"null", "string"}, props["priority"].(map[string]any)["type"], "int-backed, encoded as names")
+	assert.Equal(t, map[string]any{"type": "string", "format": "region-code", "enum": []any{"eu", "us"}},
+		props["region"], "the enum augments the registered mapping")
+
+	ext, err := NewExtractorWithConfig[enumArgs](SchemaConfig{Registry: registry})
+	require.NoError(t, err)
+	valid := `{"color":"red","fallback":null,"palette":["green"],"by_name":{"a":"red"},"code":404,` +
+		`"priority":"urgent","region":"eu"}`
+	args, err := ext.ParseAndValidate([]byte(valid))
+	require.NoError(t, err)
+	require.NotNil(t, args.Priority)
+	assert.Equal(t, PriorityUrgent, *args.Priority, "decoded through UnmarshalJSON")
+
+	invalid := `{"color":"red","fallback":null,"palette":["blue"],"by_name":{},"code":404,` +
+		`"priority":null,"region":"eu"}`
+	_, err = ext.ParseAndValidate([]byte(invalid))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, "/palette/0", te.Violations[0].Path)
+	assert.Equal(t, "enum", te.Violations[0].Keyword)
+}
+
+func TestGenerateSchema_SchemaEnumerEmpty(t *testing.T) {
+	type args struct {
+		V enumEmpty `json:"v"`
+	}
+	_, err := NewExtractor[args](false)
+	require.ErrorIs(t, err, errEmptySchemaEnum)
+}
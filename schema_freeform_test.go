@@ -0,0 +1,78 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type freeformInner struct {
+	Patch json.RawMessage `json:"patch"`
+	Meta  map[string]any  `json:"meta"`
+}
+
+type freeformArgs struct {
+	Target string          `json:"target"`
+	Patch  json.RawMessage `json:"patch"`
+	Body   json.RawMessage `json:"body"   freeform:"any" description:"Any JSON value"`
+	Nested freeformInner   `json:"nested"`
+	Opts   *freeformInner  `json:"opts"   freeform:"object"`
+}
+
+const freeformCall = `{"target":"t","patch":{"b": 1.50, "a":[1, {"deep":true}]},"body":[1, "two"],` +
+	`"nested":{"patch":{"x":{"y":null}},"meta":{"k":1}},"opts":null}`
+
+func TestGenerateSchema_FreeformUnderStrict(t *testing.T) {
+	schemaMap, _, err := generateSchema[freeformArgs](testSchemaConfig(true))
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object"}, props["patch"])
+	assert.Equal(t, map[string]any{"description": "Any JSON value"}, props["body"])
+	assert.Equal(t, map[string]any{"type": []any{"null", "object"}}, props["opts"])
+	nested := props["nested"].(map[string]any)
+	assert.Equal(t, false, nested["additionalProperties"], "ordinary structs are still closed")
+	assert.Equal(t, true, nested["properties"].(map[string]any)["meta"].(map[string]any)["additionalProperties"])
+
+	ext, err := NewExtractor[freeformArgs](true)
+	require.NoError(t, err)
+	args, err := ext.ParseAndValidate([]byte(freeformCall))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"b": 1.50, "a":[1, {"deep":true}]}`, string(args.Patch))
+	assert.Equal(t, `{"b": 1.50, "a":[1, {"deep":true}]}`, string(args.Patch), "raw bytes are kept as sent")
+	assert.Equal(t, `[1, "two"]`, string(args.Body))
+	assert.Equal(t, `{"x":{"y":null}}`, string(args.Nested.Patch))
+
+	notObject := `{"target":"t","patch":[1],"body":1,"nested":{"patch":{},"meta":{}},"opts":null}`
+	_, err = ext.ParseAndValidate([]byte(notObject))
+	assert.True(t, clientCorrectable(err), "json.RawMessage without freeform:\"any\" must be an object")
+}
+
+func TestGenerateSchema_StrictCloseFreeform(t *testing.T) {
+	cfg := SchemaConfig{Strict: true, CloseFreeform: true}
+	schemaMap, _, err := generateSchema[freeformArgs](cfg)
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	assert.Equal(t, false, props["patch"].(map[string]any)["additionalProperties"])
+	assert.NotContains(t, props["body"], "additionalProperties", "any-value fields are not objects")
+
+	ext, err := NewExtractorWithConfig[freeformArgs](cfg)
+	require.NoError(t, err)
+	_, err = ext.ParseAndValidate([]byte(freeformCall))
+	assert.True(t, clientCorrectable(err))
+	_, err = ext.ParseAndValidate([]byte(`{"target":"t","patch":{},"body":1,"nested":{"patch":{},"meta":{}},"opts":{}}`))
+	require.NoError(t, err)
+
+	raw := map[string]any{"type": "object", "properties": map[string]any{"blob": map[string]any{"type": "object"}}}
+	applyStrictMode(raw, cfg)
+	assert.Equal(t, false, raw["properties"].(map[string]any)["blob"].(map[string]any)["additionalProperties"])
+}
+
+func TestGenerateSchema_FreeformTagInvalid(t *testing.T) {
+	type args struct {
+		V json.RawMessage `json:"v" freeform:"maybe"`
+	}
+	_, err := NewExtractor[args](false)
+	require.ErrorContains(t, err, `invalid freeform tag "maybe"`)
+}
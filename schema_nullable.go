@@ -0,0 +1,100 @@
+package toolsy
+
+import (
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// NullableStyle selects how parameter schemas spell a value that may be null, such as a pointer field.
+type NullableStyle int
+
+const (
+	// NullableTypeArray keeps the generated form, "type": ["null", "string"].
+	NullableTypeArray NullableStyle = iota
+	// NullableFlag writes "type": "string" plus "nullable": true (OpenAPI 3.0 style).
+	NullableFlag
+	// NullableAnyOf writes "anyOf": [{"type": "string", ...}, {"type": "null"}], keeping annotations
+	// such as description on the outer schema.
+	NullableAnyOf
+)
+
+// WithNullableStyle rewrites every type union of "null" and one other type in the parameters
+// schema into style, after [WithStrict] and before any [WithSchemaTransform]. Validation accepts
+// explicit nulls in every style, because validators honor "nullable": true. Unions with several
+// non-null types are left as they are.
+func WithNullableStyle(style NullableStyle) ToolOption {
+	return func(c *ToolConfig) {
+		c.Schema.Nullable = style
+	}
+}
+
+// schemaTransforms returns the transforms cfg applies to a parameters schema, in order.
+func schemaTransforms(cfg SchemaConfig) []SchemaTransform {
+	var style SchemaTransform
+	switch cfg.Nullable {
+	case NullableFlag:
+		style = FlattenNullableTypes
+	case NullableAnyOf:
+		style = nullableAnyOf
+	case NullableTypeArray:
+		return cfg.Transforms
+	}
+	if style == nil {
+		return cfg.Transforms
+	}
+	return append([]SchemaTransform{style}, cfg.Transforms...)
+}
+
+// nullableType returns the non-null type of a "type": ["null", T] union.
+func nullableType(n map[string]any) (string, bool) {
+	types, ok := n["type"].([]any)
+	if !ok || len(types) != 2 {
+		return "", false
+	}
+	nullAt := slices.Index(types, any("null"))
+	if nullAt < 0 {
+		return "", false
+	}
+	other, ok := types[1-nullAt].(string)
+	if !ok || other == "null" {
+		return "", false
+	}
+	return other, true
+}
+
+// schemaAnnotations stay on the outer schema when [NullableAnyOf] moves a node into an anyOf branch.
+var schemaAnnotations = []string{"title", "description", "default", "examples", "deprecated"}
+
+func nullableAnyOf(schema map[string]any) map[string]any {
+	walkSchemaNodes(schema, func(n map[string]any) {
+		other, ok := nullableType(n)
+		if !ok {
+			return
+		}
+		if _, has := n["anyOf"]; has {
+			return
+		}
+		branch := make(map[string]any, len(n))
+		for k, v := range n {
+			if !slices.Contains(schemaAnnotations, k) {
+				branch[k] = v
+				delete(n, k)
+			}
+		}
+		branch["type"] = other
+		n["anyOf"] = []any{branch, map[string]any{"type": "null"}}
+	})
+	return schema
+}
+
+// expandNullable turns "nullable": true back into a type union so the compiled validator accepts null.
+func expandNullable(s *jsonschema.Schema) {
+	walkSchemas(s, func(n *jsonschema.Schema) bool {
+		if nullable, _ := n.Extra["nullable"].(bool); nullable && n.Type != "" && n.Type != "null" {
+			n.Types = []string{n.Type, "null"}
+			n.Type = ""
+		}
+		return true
+	})
+}
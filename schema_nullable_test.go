@@ -0,0 +1,109 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullableMoney struct{}
+
+type nullableInner struct {
+	A int `json:"a"`
+}
+
+type nullableArgs struct {
+	S     *string        `json:"s"                description:"optional text"`
+	I     *int           `json:"i,omitempty"`
+	P     *nullableInner `json:"p"`
+	Money *nullableMoney `json:"money,omitempty"`
+}
+
+func newNullableTool(t *testing.T, opts ...ToolOption) Tool {
+	t.Helper()
+	registry := NewSchemaRegistry()
+	registry.RegisterType(nullableMoney{}, "number", "decimal")
+	opts = append(opts, WithSchemaRegistry(registry))
+	tool, err := NewTool("pay", "Pays", func(context.Context, *RunEnv, nullableArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, opts...)
+	require.NoError(t, err)
+	return tool
+}
+
+func nullableProps(t *testing.T, tool Tool) map[string]map[string]any {
+	t.Helper()
+	out := make(map[string]map[string]any)
+	for name, prop := range tool.Manifest().Parameters["properties"].(map[string]any) {
+		out[name] = prop.(map[string]any)
+	}
+	return out
+}
+
+func runNullable(tool Tool, args string) error {
+	return tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(args)},
+		func(Chunk) error { return nil })
+}
+
+func TestWithNullableStyle_Flag(t *testing.T) {
+	t.Parallel()
+	for _, strict := range []bool{false, true} {
+		opts := []ToolOption{WithNullableStyle(NullableFlag)}
+		if strict {
+			opts = append(opts, WithStrict())
+		}
+		tool := newNullableTool(t, opts...)
+		props := nullableProps(t, tool)
+		assert.Equal(t, map[string]any{"type": "string", "nullable": true, "description": "optional text"}, props["s"])
+		assert.Equal(t, map[string]any{"type": "integer", "nullable": true}, props["i"])
+		assert.Equal(t, "object", props["p"]["type"])
+		assert.Equal(t, true, props["p"]["nullable"])
+		assert.Equal(t, map[string]any{"type": "number", "format": "decimal", "nullable": true}, props["money"])
+
+		require.NoError(t, runNullable(tool, `{"s":null,"i":null,"p":null,"money":null}`), "strict=%v", strict)
+		require.NoError(t, runNullable(tool, `{"s":"x","i":1,"p":{"a":2},"money":null}`), "strict=%v", strict)
+		te, ok := AsToolError(runNullable(tool, `{"s":1,"i":null,"p":null,"money":null}`))
+		require.True(t, ok, "strict=%v", strict)
+		assert.Equal(t, CodeValidationFailed, te.Code)
+	}
+}
+
+func TestWithNullableStyle_AnyOf(t *testing.T) {
+	t.Parallel()
+	for _, strict := range []bool{false, true} {
+		opts := []ToolOption{WithNullableStyle(NullableAnyOf)}
+		if strict {
+			opts = append(opts, WithStrict())
+		}
+		tool := newNullableTool(t, opts...)
+		props := nullableProps(t, tool)
+		assert.Equal(t, map[string]any{
+			"description": "optional text",
+			"anyOf":       []any{map[string]any{"type": "string"}, map[string]any{"type": "null"}},
+		}, props["s"])
+		assert.Equal(t, map[string]any{"anyOf": []any{
+			map[string]any{"type": "number", "format": "decimal"}, map[string]any{"type": "null"},
+		}}, props["money"])
+		inner := props["p"]["anyOf"].([]any)[0].(map[string]any)
+		assert.Equal(t, "object", inner["type"])
+		assert.Contains(t, inner["properties"], "a")
+		if strict {
+			assert.Equal(t, false, inner["additionalProperties"])
+		}
+
+		require.NoError(t, runNullable(tool, `{"s":null,"i":null,"p":null,"money":null}`), "strict=%v", strict)
+		require.NoError(t, runNullable(tool, `{"s":"x","i":1,"p":{"a":2},"money":null}`), "strict=%v", strict)
+		te, ok := AsToolError(runNullable(tool, `{"s":null,"i":null,"p":{"a":"x"},"money":null}`))
+		require.True(t, ok, "strict=%v", strict)
+		assert.Equal(t, CodeValidationFailed, te.Code)
+	}
+}
+
+func TestWithNullableStyle_DefaultKeepsTypeArray(t *testing.T) {
+	t.Parallel()
+	props := nullableProps(t, newNullableTool(t))
+	assert.Equal(t, []any{"null", "string"}, props["s"]["type"])
+	assert.NotContains(t, props["s"], "nullable")
+}
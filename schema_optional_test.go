@@ -0,0 +1,92 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type optionalArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"           optional:"true"`
+	Sort  string `json:"sort,omitempty"`
+	Page  *int   `json:"page,omitempty"  optional:"true"`
+}
+
+func newOptionalTool(t *testing.T, opts ...ToolOption) Tool {
+	t.Helper()
+	tool, err := NewTool("search", "Searches", func(context.Context, *RunEnv, optionalArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, opts...)
+	require.NoError(t, err)
+	return tool
+}
+
+func optionalCall(tool Tool, args string) error {
+	return tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(args)},
+		func(Chunk) error { return nil })
+}
+
+func TestOptionalTag_StrictMatrix(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		opts     []ToolOption
+		required []any
+	}{
+		// Without strict mode, omitempty and the optional tag both keep a field out of required.
+		{name: "default", opts: nil, required: []any{"query"}},
+		// Strict mode requires every field except those tagged optional.
+		{name: "strict", opts: []ToolOption{WithStrict()}, required: []any{"query", "sort"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tool := newOptionalTool(t, tc.opts...)
+			params := tool.Manifest().Parameters
+			assert.ElementsMatch(t, tc.required, params["required"])
+			props := params["properties"].(map[string]any)
+			assert.NotContains(t, props["limit"], optionalFieldMarker)
+			if tool.Manifest().Strict {
+				assert.Equal(t, false, params["additionalProperties"])
+			}
+			require.NoError(t, optionalCall(tool, `{"query":"q","sort":"asc"}`), "tagged fields may be omitted")
+			err := optionalCall(tool, `{"sort":"asc","limit":1}`)
+			te, ok := AsToolError(err)
+			require.True(t, ok, "untagged required fields are still enforced")
+			assert.Equal(t, CodeValidationFailed, te.Code)
+		})
+	}
+}
+
+func TestOptionalTag_StrictAsNullable(t *testing.T) {
+	t.Parallel()
+	tool := newOptionalTool(t, WithStrict(), WithStrictOptionalAsNullable())
+	params := tool.Manifest().Parameters
+	assert.Equal(t, []any{"limit", "page", "query", "sort"}, params["required"])
+	props := params["properties"].(map[string]any)
+	assert.Equal(t, []any{"null", "integer"}, props["limit"].(map[string]any)["type"])
+	assert.Equal(t, []any{"null", "integer"}, props["page"].(map[string]any)["type"], "already nullable")
+	assert.Equal(t, "string", props["sort"].(map[string]any)["type"])
+
+	require.NoError(t, optionalCall(tool, `{"query":"q","sort":"","limit":null,"page":null}`))
+	require.NoError(t, optionalCall(tool, `{"query":"q","sort":"","limit":5,"page":2}`))
+	_, ok := AsToolError(optionalCall(tool, `{"query":"q","sort":""}`))
+	assert.True(t, ok, "required-with-nullable fields must be sent")
+
+	plain := newOptionalTool(t, WithStrictOptionalAsNullable())
+	assert.ElementsMatch(t, []any{"query"}, plain.Manifest().Parameters["required"], "no effect without strict")
+}
+
+func TestOptionalTag_Invalid(t *testing.T) {
+	t.Parallel()
+	type badArgs struct {
+		Q string `json:"q" optional:"maybe"`
+	}
+	_, err := NewTool("bad", "Bad", func(context.Context, *RunEnv, badArgs) (collectOut, error) {
+		return collectOut{}, nil
+	})
+	require.ErrorContains(t, err, `invalid optional tag "maybe"`)
+}
@@ -0,0 +1,107 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictMapArgs struct {
+	Labels map[string]string `json:"labels"`
+}
+
+func strictCall(tool Tool, args string) error {
+	return tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(args)},
+		func(Chunk) error { return nil })
+}
+
+func TestStrictMode_TypedMapField(t *testing.T) {
+	t.Parallel()
+	tool, err := NewTool("label", "Labels", func(context.Context, *RunEnv, strictMapArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, WithStrict())
+	require.NoError(t, err)
+	labels := tool.Manifest().Parameters["properties"].(map[string]any)["labels"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+	require.NoError(t, strictCall(tool, `{"labels":{"env":"prod"}}`))
+	_, ok := AsToolError(strictCall(tool, `{"labels":{"env":1}}`))
+	assert.True(t, ok)
+}
+
+func strictDynamicTool(t *testing.T, schema map[string]any, opts ...ToolOption) Tool {
+	t.Helper()
+	tool, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:         "dyn",
+		Description:  "Dynamic",
+		Schema:       MapSchemaProvider(schema),
+		OutputSchema: nil,
+		ValidateArgs: nil,
+		Handler: func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+			return nil
+		},
+		Options: append([]ToolOption{WithStrict()}, opts...),
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestStrictMode_RawTypedMapKeepsValueSchema(t *testing.T) {
+	t.Parallel()
+	tool := strictDynamicTool(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{"counts": map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{},
+			"additionalProperties": map[string]any{"type": "integer"},
+		}},
+	})
+	require.NoError(t, strictCall(tool, `{"counts":{"a":1,"b":2}}`))
+	_, ok := AsToolError(strictCall(tool, `{"counts":{"a":"x"}}`))
+	assert.True(t, ok)
+}
+
+func unionSchema() map[string]any {
+	branch := func(kind string, field string) map[string]any {
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"kind": map[string]any{"const": kind},
+				field:  map[string]any{"type": "string"},
+			},
+			"required": []any{"kind"},
+		}
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{"target": map[string]any{
+			"oneOf": []any{branch("email", "address"), branch("sms", "phone")},
+		}},
+		"required": []any{"target"},
+	}
+}
+
+func TestStrictMode_OneOfBranchesKeepRequired(t *testing.T) {
+	t.Parallel()
+	tool := strictDynamicTool(t, unionSchema())
+	target := tool.Manifest().Parameters["properties"].(map[string]any)["target"].(map[string]any)
+	first := target["oneOf"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{"kind"}, first["required"])
+	assert.Equal(t, false, first["additionalProperties"])
+
+	require.NoError(t, strictCall(tool, `{"target":{"kind":"sms"}}`), "validated before strict mode, still must")
+	require.NoError(t, strictCall(tool, `{"target":{"kind":"email","address":"a@b.c"}}`))
+	_, ok := AsToolError(strictCall(tool, `{"target":{"kind":"email","phone":"1"}}`))
+	assert.True(t, ok, "branches are closed")
+}
+
+func TestWithStrictPreserveUnions(t *testing.T) {
+	t.Parallel()
+	tool := strictDynamicTool(t, unionSchema(), WithStrictPreserveUnions())
+	params := tool.Manifest().Parameters
+	assert.Equal(t, false, params["additionalProperties"])
+	target := params["properties"].(map[string]any)["target"].(map[string]any)
+	assert.Equal(t, unionSchema()["properties"].(map[string]any)["target"], target)
+	require.NoError(t, strictCall(tool, `{"target":{"kind":"email","phone":"1"}}`))
+}
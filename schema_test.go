@@ -3,9 +3,11 @@ package toolsy
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"slices"
 	"testing"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -140,7 +142,7 @@ func TestApplyStrictMode(t *testing.T) {
 			},
 		},
 	}
-	applyStrictMode(m)
+	applyStrictMode(m, SchemaConfig{})
 	assert.Equal(t, false, m["additionalProperties"])
 	props := m["properties"].(map[string]any)
 	assert.Equal(t, false, props["b"].(map[string]any)["additionalProperties"])
@@ -334,3 +336,397 @@ func TestGenerateSchema_JSONRawMessage(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, "object", bodyProp["type"])
 }
+
+type constraintFilter struct {
+	Query string `json:"query" minLength:"2" maxLength:"20" pattern:"^[a-z ]+$"`
+	Limit int    `json:"limit" minimum:"1" maximum:"100"`
+}
+
+type constraintArgs struct {
+	Score  float64          `json:"score" exclusiveMinimum:"0" exclusiveMaximum:"1"`
+	Codes  []string         `json:"codes" maxLength:"3"`
+	Filter constraintFilter `json:"filter"`
+}
+
+func TestGenerateSchema_ConstraintTags(t *testing.T) {
+	schemaMap, _, err := generateSchema[constraintArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+
+	score := props["score"].(map[string]any)
+	assert.Equal(t, float64(0), score["exclusiveMinimum"])
+	assert.Equal(t, float64(1), score["exclusiveMaximum"])
+	codes := props["codes"].(map[string]any)
+	assert.NotContains(t, codes, "maxLength")
+	assert.Equal(t, float64(3), codes["items"].(map[string]any)["maxLength"])
+
+	filter := props["filter"].(map[string]any)["properties"].(map[string]any)
+	limit := filter["limit"].(map[string]any)
+	assert.Equal(t, float64(1), limit["minimum"])
+	assert.Equal(t, float64(100), limit["maximum"])
+	query := filter["query"].(map[string]any)
+	assert.Equal(t, "^[a-z ]+$", query["pattern"])
+
+	data, err := json.Marshal(limit)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"integer","minimum":1,"maximum":100}`, string(data))
+	assert.Contains(t, string(data), `"minimum":1`, "numeric constraints must be JSON numbers")
+}
+
+func TestExtractor_ParseAndValidate_EnforcesConstraintTags(t *testing.T) {
+	ext, err := NewExtractor[constraintArgs](false)
+	require.NoError(t, err)
+
+	valid := `{"score":0.5,"codes":["ab"],"filter":{"query":"red shoes","limit":10}}`
+	_, err = ext.ParseAndValidate([]byte(valid))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		args    string
+		path    string
+		keyword string
+	}{
+		{"below minimum", `{"score":0.5,"codes":[],"filter":{"query":"ab","limit":0}}`, "/filter/limit", "minimum"},
+		{"above maximum", `{"score":0.5,"codes":[],"filter":{"query":"ab","limit":101}}`, "/filter/limit", "maximum"},
+		{"exclusive", `{"score":0,"codes":[],"filter":{"query":"ab","limit":1}}`, "/score", "exclusiveMinimum"},
+		{"too short", `{"score":0.5,"codes":[],"filter":{"query":"a","limit":1}}`, "/filter/query", "minLength"},
+		{"pattern", `{"score":0.5,"codes":[],"filter":{"query":"AB","limit":1}}`, "/filter/query", "pattern"},
+		{"element", `{"score":0.5,"codes":["abcd"],"filter":{"query":"ab","limit":1}}`, "/codes/0", "maxLength"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ext.ParseAndValidate([]byte(tt.args))
+			require.ErrorIs(t, err, ErrValidation)
+			te, ok := AsToolError(err)
+			require.True(t, ok)
+			require.Len(t, te.Violations, 1)
+			assert.Equal(t, tt.path, te.Violations[0].Path)
+			assert.Equal(t, tt.keyword, te.Violations[0].Keyword)
+		})
+	}
+}
+
+func TestGenerateSchema_InvalidConstraintTagNamesField(t *testing.T) {
+	type badMin struct {
+		N int `json:"n" minimum:"one"`
+	}
+	type badPattern struct {
+		S string `json:"s" pattern:"("`
+	}
+	type nested struct {
+		Inner []badMin `json:"inner"`
+	}
+
+	_, err := NewExtractor[nested](false)
+	require.Error(t, err)
+	var pathErr *fieldPathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "inner[].n", pathErr.Path)
+	assert.Equal(t, `args field "inner[].n" (type int): invalid minimum tag "one": must be a finite number`, err.Error())
+
+	_, err = NewExtractor[badPattern](false)
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "s", pathErr.Path)
+	assert.Contains(t, err.Error(), `invalid pattern tag "("`)
+}
+
+type nestedRange struct {
+	Unit string `json:"unit" description:"Range unit" enum:"day,week"`
+}
+
+type nestedAudit struct {
+	Actor string `json:"actor" description:"Who changed it"`
+}
+
+type nestedFilter struct {
+	nestedAudit
+
+	ID    string       `json:"id" description:"Filter id"`
+	Range *nestedRange `json:"range"`
+}
+
+type nestedSearchArgs struct {
+	Filter nestedFilter  `json:"filter"`
+	Sorts  []nestedRange `json:"sorts"`
+}
+
+func TestGenerateSchema_NestedStructTags(t *testing.T) {
+	schemaMap, _, err := generateSchema[nestedSearchArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	filter := props["filter"].(map[string]any)["properties"].(map[string]any)
+
+	assert.Equal(t, "Filter id", filter["id"].(map[string]any)["description"])
+	assert.Equal(t, "Who changed it", filter["actor"].(map[string]any)["description"])
+	unit := filter["range"].(map[string]any)["properties"].(map[string]any)["unit"].(map[string]any)
+	assert.Equal(t, "Range unit", unit["description"])
+	assert.Equal(t, []any{"day", "week"}, unit["enum"])
+	sortUnit := props["sorts"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)["unit"]
+	assert.Equal(t, []any{"day", "week"}, sortUnit.(map[string]any)["enum"])
+}
+
+func TestExtractor_RejectsInvalidNestedEnum(t *testing.T) {
+	ext, err := NewExtractor[nestedSearchArgs](false)
+	require.NoError(t, err)
+
+	args := `{"filter":{"actor":"a","id":"f1","range":{"unit":"year"}},"sorts":[]}`
+	_, err = ext.ParseAndValidate([]byte(args))
+	require.ErrorIs(t, err, ErrValidation)
+	assert.True(t, clientCorrectable(err))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "/filter/range/unit", te.Violations[0].Path)
+	assert.Equal(t, "enum", te.Violations[0].Keyword)
+
+	_, err = ext.ParseAndValidate([]byte(`{"filter":{"actor":"a","id":"f1","range":null},"sorts":[{"unit":"x"}]}`))
+	te, ok = AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, "/sorts/0/unit", te.Violations[0].Path)
+}
+
+func TestEnrichSchemaFromStructTags_FollowsDefsRefs(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{"range": {Ref: "#/$defs/Range"}},
+		Defs: map[string]*jsonschema.Schema{
+			"Range": {
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"unit": {Type: "string"}},
+			},
+		},
+	}
+	type args struct {
+		Range nestedRange `json:"range"`
+	}
+	require.NoError(t, enrichSchemaFromStructTags(schema, reflect.TypeFor[args]()))
+	assert.Equal(t, []any{"day", "week"}, schema.Defs["Range"].Properties["unit"].Enum)
+}
+
+func TestStripSchemaIDs_KeepsPropertiesNamedID(t *testing.T) {
+	schemaMap := map[string]any{
+		"$id":  "urn:root",
+		"type": "object",
+		"properties": map[string]any{
+			"id":  map[string]any{"type": "string", "$id": "urn:prop"},
+			"tag": map[string]any{"type": "object", "default": map[string]any{"id": "keep"}},
+		},
+	}
+	stripSchemaIDs(schemaMap)
+	assert.NotContains(t, schemaMap, "$id")
+	props := schemaMap["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, props["id"])
+	assert.Equal(t, map[string]any{"id": "keep"}, props["tag"].(map[string]any)["default"])
+}
+
+type customDecimal struct{ v string }
+
+func (d *customDecimal) UnmarshalJSON(data []byte) error { return json.Unmarshal(data, &d.v) }
+
+type customLabels struct{ m map[string]string }
+
+func (l *customLabels) UnmarshalJSON(data []byte) error { return json.Unmarshal(data, &l.m) }
+
+type customLine struct {
+	Price  customDecimal  `json:"price"`
+	Fee    *customDecimal `json:"fee,omitempty"`
+	Labels customLabels   `json:"labels"`
+}
+
+type customOrder struct {
+	Lines []customLine `json:"lines"`
+}
+
+type customOrderArgs struct {
+	Order customOrder `json:"order"`
+}
+
+func customSchemaRegistry() *SchemaRegistry {
+	registry := NewSchemaRegistry()
+	registry.RegisterTypeSchema(customDecimal{}, map[string]any{
+		"type":        "string",
+		"pattern":     `^-?\d+(\.\d+)?$`,
+		"description": "decimal as string",
+	})
+	registry.RegisterTypeSchema(customLabels{}, map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{"env": map[string]any{"type": "string"}},
+		"additionalProperties": map[string]any{"type": "string"},
+	})
+	return registry
+}
+
+func customLineSchema(schemaMap map[string]any) map[string]any {
+	order := schemaMap["properties"].(map[string]any)["order"].(map[string]any)
+	lines := order["properties"].(map[string]any)["lines"].(map[string]any)
+	return lines["items"].(map[string]any)
+}
+
+func TestSchemaRegistryRegisterTypeSchema_NestedTwoLevels(t *testing.T) {
+	ext, err := NewExtractorWithConfig[customOrderArgs](SchemaConfig{Registry: customSchemaRegistry()})
+	require.NoError(t, err)
+	schemaMap := ext.Schema()
+	line := customLineSchema(schemaMap)
+	lineProps := line["properties"].(map[string]any)
+	price := lineProps["price"].(map[string]any)
+	assert.Equal(t, "string", price["type"])
+	assert.Equal(t, `^-?\d+(\.\d+)?$`, price["pattern"])
+	assert.Equal(t, "decimal as string", price["description"])
+	assert.Equal(t, `^-?\d+(\.\d+)?$`, lineProps["fee"].(map[string]any)["pattern"])
+
+	data, err := json.Marshal(schemaMap)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), customSchemaMarker)
+
+	_, err = ext.ParseAndValidate([]byte(`{"order":{"lines":[{"price":"12.50","labels":{}}]}}`))
+	require.NoError(t, err)
+	_, err = ext.ParseAndValidate([]byte(`{"order":{"lines":[{"price":"12.50","fee":"abc","labels":{}}]}}`))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "/order/lines/0/fee", te.Violations[0].Path)
+	assert.Equal(t, "pattern", te.Violations[0].Keyword)
+}
+
+func TestSchemaRegistryRegisterTypeSchema_StrictKeepsCustomObject(t *testing.T) {
+	ext, err := NewExtractorWithConfig[customOrderArgs](SchemaConfig{Strict: true, Registry: customSchemaRegistry()})
+	require.NoError(t, err)
+	schemaMap := ext.Schema()
+	assert.Equal(t, false, schemaMap["additionalProperties"])
+	line := customLineSchema(schemaMap)
+	assert.Equal(t, false, line["additionalProperties"])
+	assert.ElementsMatch(t, []any{"fee", "labels", "price"}, line["required"])
+
+	labels := line["properties"].(map[string]any)["labels"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, labels["additionalProperties"])
+	assert.NotContains(t, labels, "required")
+
+	_, err = ext.ParseAndValidate([]byte(`{"order":{"lines":[{"price":"1","fee":"2","labels":{"team":"core"}}]}}`))
+	require.NoError(t, err)
+}
+
+func TestSchemaRegistryRegisterTypeSchema_InvalidPanics(t *testing.T) {
+	registry := NewSchemaRegistry()
+	assert.Panics(t, func() { registry.RegisterTypeSchema(nil, map[string]any{"type": "string"}) })
+	assert.Panics(t, func() { registry.RegisterTypeSchema(customDecimal{}, nil) })
+	assert.Panics(t, func() { registry.RegisterTypeSchema(customDecimal{}, map[string]any{"type": 5}) })
+	assert.Panics(t, func() { registry.RegisterTypeSchema(customDecimal{}, map[string]any{"pattern": "("}) })
+}
+
+func TestSchemaRegistry_ConflictingMappingsInParallel(t *testing.T) {
+	type Money struct{}
+	type Args struct {
+		Amount Money `json:"amount"`
+	}
+	for _, format := range []string{"decimal", "cents", "micros"} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+			registry := NewSchemaRegistry()
+			registry.RegisterType(Money{}, "string", format)
+
+			typed, err := NewTool("typed", "d", func(_ context.Context, _ *RunEnv, _ Args) (string, error) {
+				return "", nil
+			}, WithSchemaRegistry(registry))
+			require.NoError(t, err)
+			stream, err := NewStreamTool("stream", "d", func(_ context.Context, _ *RunEnv, _ Args, _ func(Chunk) error) error {
+				return nil
+			}, WithSchemaRegistry(registry))
+			require.NoError(t, err)
+			ext, err := NewExtractorWithConfig[Args](SchemaConfig{Registry: registry})
+			require.NoError(t, err)
+
+			for _, params := range []map[string]any{
+				typed.Manifest().Parameters, stream.Manifest().Parameters, ext.Schema(),
+			} {
+				amount := params["properties"].(map[string]any)["amount"].(map[string]any)
+				assert.Equal(t, format, amount["format"])
+			}
+		})
+	}
+}
+
+func TestSchemaRegistry_OverridesBuiltinMappingWithoutMutation(t *testing.T) {
+	type Args struct {
+		Raw json.RawMessage `json:"raw"`
+	}
+	registry := NewSchemaRegistry()
+	registry.RegisterType(json.RawMessage(nil), "string", "json")
+
+	schemaMap, _, err := generateSchema[Args](SchemaConfig{Registry: registry})
+	require.NoError(t, err)
+	raw := schemaMap["properties"].(map[string]any)["raw"].(map[string]any)
+	assert.Equal(t, "json", raw["format"])
+
+	def, _, err := generateSchema[Args](testSchemaConfig(false))
+	require.NoError(t, err)
+	assert.Equal(t, "object", def["properties"].(map[string]any)["raw"].(map[string]any)["type"])
+
+	empty := NewSchemaRegistry()
+	_, _, err = generateSchema[Args](SchemaConfig{Registry: empty})
+	require.NoError(t, err)
+	assert.Empty(t, empty.types, "generation must not register built-in mappings on the caller's registry")
+}
+
+type arrayTagBatch struct {
+	Codes []string `json:"codes" enum:"a,b" uniqueItems:"true"`
+}
+
+type arrayTagArgs struct {
+	IDs     []int           `json:"ids" minItems:"1" maxItems:"50" minimum:"1"`
+	Batches []arrayTagBatch `json:"batches" maxItems:"2"`
+}
+
+func TestGenerateSchema_ArrayTags(t *testing.T) {
+	schemaMap, _, err := generateSchema[arrayTagArgs](testSchemaConfig(false))
+	require.NoError(t, err)
+	props := schemaMap["properties"].(map[string]any)
+	ids := props["ids"].(map[string]any)
+	assert.InDelta(t, 1, ids["minItems"], 0)
+	assert.InDelta(t, 50, ids["maxItems"], 0)
+	assert.InDelta(t, 1, ids["items"].(map[string]any)["minimum"], 0, "element constraints stay on items")
+	codes := props["batches"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)["codes"]
+	assert.Equal(t, true, codes.(map[string]any)["uniqueItems"])
+	assert.Equal(t, []any{"a", "b"}, codes.(map[string]any)["items"].(map[string]any)["enum"])
+	assert.NotContains(t, codes, "enum")
+
+	ext, err := NewExtractor[arrayTagArgs](false)
+	require.NoError(t, err)
+	many := make([]int, 51)
+	for i := range many {
+		many[i] = i + 1
+	}
+	data, err := json.Marshal(map[string]any{"ids": many, "batches": []any{}})
+	require.NoError(t, err)
+	_, err = ext.ParseAndValidate(data)
+	require.True(t, clientCorrectable(err))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "maxItems", te.Violations[0].Keyword)
+	assert.Equal(t, "/ids", te.Violations[0].Path)
+
+	for _, bad := range []string{
+		`{"ids":[],"batches":[]}`,
+		`{"ids":[1],"batches":[{"codes":["a","a"]}]}`,
+		`{"ids":[1],"batches":[{"codes":["c"]}]}`,
+	} {
+		_, err = ext.ParseAndValidate([]byte(bad))
+		assert.True(t, clientCorrectable(err), bad)
+	}
+	_, err = ext.ParseAndValidate([]byte(`{"ids":[1,2],"batches":[{"codes":["a","b"]}]}`))
+	require.NoError(t, err)
+}
+
+func TestGenerateSchema_ArrayTagsRejectInvalid(t *testing.T) {
+	type notArray struct {
+		N int `json:"n" maxItems:"3"`
+	}
+	type badCount struct {
+		L []string `json:"l" minItems:"-1"`
+	}
+	_, err := NewExtractor[notArray](false)
+	require.ErrorContains(t, err, "maxItems tag on a non-array field")
+	_, err = NewExtractor[badCount](false)
+	require.ErrorContains(t, err, `invalid minItems tag "-1"`)
+}
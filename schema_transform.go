@@ -0,0 +1,86 @@
+package toolsy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// SchemaTransform rewrites a parameters schema; see [WithSchemaTransform].
+type SchemaTransform func(schema map[string]any) map[string]any
+
+var errNilTransformedSchema = errors.New("toolsy: schema transform returned nil")
+
+// WithSchemaTransform post-processes the tool's parameters schema after generation, struct-tag
+// enrichment, and [WithStrict]. The transformed schema is both [ToolManifest.Parameters] and the
+// schema arguments are validated against, so a transform that drops a constraint also stops
+// enforcing it. fn receives a deep copy it may mutate and return; returning nil is an error.
+// Repeated options run in order. Typed, dynamic, and proxy tools all accept it; use
+// [FlattenNullableTypes] and [StripFormats] for common provider quirks.
+func WithSchemaTransform(fn SchemaTransform) ToolOption {
+	return func(c *ToolConfig) {
+		if fn != nil {
+			c.Schema.Transforms = append(slices.Clip(c.Schema.Transforms), fn)
+		}
+	}
+}
+
+// transformSchema runs transforms on a private copy of schemaMap and compiles the result, or returns
+// schemaMap unchanged when there are none.
+func transformSchema(schemaMap map[string]any, transforms []SchemaTransform) (map[string]any, bool, error) {
+	if len(transforms) == 0 {
+		return schemaMap, false, nil
+	}
+	out := schemaMap
+	for _, fn := range transforms {
+		out = fn(deepCloneMap(out))
+		if out == nil {
+			return nil, false, errNilTransformedSchema
+		}
+	}
+	return out, true, nil
+}
+
+// transformCompiledSchema is [transformSchema] for a schema that already has a resolved validator,
+// which is recompiled only when a transform ran.
+func transformCompiledSchema(
+	schemaMap map[string]any,
+	resolved *jsonschema.Resolved,
+	transforms []SchemaTransform,
+) (map[string]any, *jsonschema.Resolved, error) {
+	out, changed, err := transformSchema(schemaMap, transforms)
+	if err != nil || !changed {
+		return out, resolved, err
+	}
+	stripSchemaIDs(out)
+	compiled, err := compileRawSchema(out)
+	if err != nil {
+		return nil, nil, fmt.Errorf("toolsy: compile transformed schema: %w", err)
+	}
+	return out, compiled, nil
+}
+
+// FlattenNullableTypes rewrites type unions with "null" and one other type, such as
+// "type": ["null", "string"], into "type": "string" plus "nullable": true, for providers that accept
+// only a single type (OpenAPI 3.0 style). It is the transform behind [NullableFlag]; validation
+// still accepts null for those fields.
+func FlattenNullableTypes(schema map[string]any) map[string]any {
+	walkSchemaNodes(schema, func(n map[string]any) {
+		if other, ok := nullableType(n); ok {
+			n["type"] = other
+			n["nullable"] = true
+		}
+	})
+	return schema
+}
+
+// StripFormats removes the "format" keyword (date-time, uri, email, ...) from every schema node,
+// for providers that reject formats they do not know. Properties named "format" are kept.
+func StripFormats(schema map[string]any) map[string]any {
+	walkSchemaNodes(schema, func(n map[string]any) {
+		delete(n, "format")
+	})
+	return schema
+}
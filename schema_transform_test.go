@@ -0,0 +1,112 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transformTag struct {
+	Format string `json:"format"`
+}
+
+type transformArgs struct {
+	Name  string         `json:"name"`
+	Note  *string        `json:"note,omitempty"`
+	Start time.Time      `json:"start"`
+	Tags  []transformTag `json:"tags,omitempty"`
+}
+
+func newTransformTool(t *testing.T, opts ...ToolOption) Tool {
+	t.Helper()
+	tool, err := NewTool("plan", "Plans", func(context.Context, *RunEnv, transformArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, opts...)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestWithSchemaTransform_BuiltinTransforms(t *testing.T) {
+	t.Parallel()
+	tool := newTransformTool(t, WithSchemaTransform(StripFormats), WithSchemaTransform(FlattenNullableTypes))
+	props := tool.Manifest().Parameters["properties"].(map[string]any)
+	assert.NotContains(t, props["start"], "format")
+	assert.Equal(t, map[string]any{"type": "string", "nullable": true}, props["note"])
+	items := props["tags"].(map[string]any)["items"].(map[string]any)
+	assert.Contains(t, items["properties"], "format", "a property named format is not a keyword")
+
+	plain := newTransformTool(t)
+	plainProps := plain.Manifest().Parameters["properties"].(map[string]any)
+	assert.Equal(t, "date-time", plainProps["start"].(map[string]any)["format"], "generated schemas are not shared")
+
+	err := tool.Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{"name":"a","start":"2026-01-02T03:04:05Z","note":null}`)},
+		func(Chunk) error { return nil })
+	require.NoError(t, err, "nullable: true still admits null")
+}
+
+func TestWithSchemaTransform_EnforcedByValidation(t *testing.T) {
+	t.Parallel()
+	limit := func(schema map[string]any) map[string]any {
+		props := schema["properties"].(map[string]any)
+		props["name"].(map[string]any)["maxLength"] = 3
+		return schema
+	}
+	tool := newTransformTool(t, WithSchemaTransform(limit))
+	yield := func(Chunk) error { return nil }
+	require.NoError(t, tool.Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{"name":"abc","start":"2026-01-02T03:04:05Z"}`)}, yield))
+	err := tool.Execute(context.Background(), NewRunEnv(nil),
+		ToolInput{ArgsJSON: []byte(`{"name":"abcd","start":"2026-01-02T03:04:05Z"}`)}, yield)
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+}
+
+func TestWithSchemaTransform_DynamicAndProxy(t *testing.T) {
+	t.Parallel()
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"at": map[string]any{"type": []any{"string", "null"}, "format": "date-time"}},
+	}
+	dyn, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:         "dyn",
+		Description:  "Dynamic",
+		Schema:       MapSchemaProvider(schema),
+		OutputSchema: nil,
+		ValidateArgs: nil,
+		Handler: func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+			return nil
+		},
+		Options: []ToolOption{WithSchemaTransform(StripFormats), WithSchemaTransform(FlattenNullableTypes)},
+	})
+	require.NoError(t, err)
+	at := dyn.Manifest().Parameters["properties"].(map[string]any)["at"]
+	assert.Equal(t, map[string]any{"type": "string", "nullable": true}, at)
+	assert.Contains(t, schema["properties"].(map[string]any)["at"], "format", "the caller's schema is untouched")
+
+	proxySchema := []byte(`{"type":"object","properties":{"u":{"type":"string","format":"uri"}}}`)
+	proxy, err := NewProxyTool("proxy", "Proxy", proxySchema,
+		func(context.Context, *RunEnv, []byte, func(Chunk) error) error { return nil },
+		WithSchemaTransform(StripFormats))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "string"}, proxy.Manifest().Parameters["properties"].(map[string]any)["u"])
+}
+
+func TestWithSchemaTransform_Errors(t *testing.T) {
+	t.Parallel()
+	_, err := NewTool("plan", "Plans", func(context.Context, *RunEnv, transformArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, WithSchemaTransform(func(map[string]any) map[string]any { return nil }))
+	require.ErrorIs(t, err, errNilTransformedSchema)
+
+	ext, err := NewExtractor[transformArgs](false)
+	require.NoError(t, err)
+	_, err = ExtractorTool(ext, "plan", "Plans", func(context.Context, *RunEnv, transformArgs) (collectOut, error) {
+		return collectOut{}, nil
+	}, WithSchemaTransform(StripFormats))
+	require.ErrorContains(t, err, "WithSchemaTransform")
+}
@@ -0,0 +1,420 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxViolationValueBytes bounds the JSON size of [FieldViolation.Value]; larger values are omitted.
+const maxViolationValueBytes = 64
+
+// FieldViolation is one schema violation in tool arguments.
+// Path is a JSON Pointer (RFC 6901) into the arguments, for example "/items/2/unit"; "" is the root.
+// Keyword is the JSON Schema keyword that failed ("type", "enum", "required", ...).
+// Value holds the offending value when it is small; missing properties have no value.
+type FieldViolation struct {
+	Path    string `json:"path"`
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+	Value   any    `json:"value,omitempty"`
+}
+
+// ViolationsJSON returns e.Violations as a JSON array (an empty array when there are none), ready
+// to embed in the tool-error message fed back to the model.
+func (e *ToolError) ViolationsJSON() ([]byte, error) {
+	if e == nil || len(e.Violations) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e.Violations)
+}
+
+// newSchemaValidationError converts a failed schema validation of instance into a validation
+// [ToolError] carrying up to limit structured violations (limit <= 0 means all). Reason keeps the
+// validator's original message for a single violation and lists every violation otherwise.
+func newSchemaValidationError(schema map[string]any, instance any, validateErr error, limit int) *ToolError {
+	te := NewValidationError(validateErr.Error())
+	te.Violations = schemaViolations(schema, instance, limit)
+	if len(te.Violations) == 0 {
+		te.Violations = []FieldViolation{fallbackViolation(validateErr)}
+	}
+	if len(te.Violations) > 1 {
+		te.Reason = violationsReason(te.Violations)
+	}
+	te.FixableArgs = violationFixableArgs(te.Violations)
+	return te
+}
+
+func violationsReason(vs []FieldViolation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d schema violations:", len(vs))
+	for i, v := range vs {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		path := v.Path
+		if path == "" {
+			path = "/"
+		}
+		fmt.Fprintf(&b, " %s: %s", path, v.Message)
+	}
+	return b.String()
+}
+
+// fallbackViolation keeps the validator's innermost message when the walker cannot localize it.
+func fallbackViolation(err error) FieldViolation {
+	msg := err.Error()
+	if i := strings.LastIndex(msg, "validating "); i >= 0 {
+		if j := strings.Index(msg[i:], ": "); j >= 0 {
+			msg = msg[i+j+2:]
+		}
+	}
+	keyword, _, found := strings.Cut(msg, ":")
+	if !found || strings.ContainsAny(keyword, " \"") {
+		keyword = ""
+	}
+	return FieldViolation{Path: "", Keyword: keyword, Message: msg, Value: nil}
+}
+
+// violationFixableArgs lists the top-level argument names touched by violations.
+func violationFixableArgs(vs []FieldViolation) []string {
+	var out []string
+	for _, v := range vs {
+		top, _, _ := strings.Cut(strings.TrimPrefix(v.Path, "/"), "/")
+		if top == "" {
+			continue
+		}
+		top = unescapePointerToken(top)
+		if !slices.Contains(out, top) {
+			out = append(out, top)
+		}
+	}
+	return out
+}
+
+// schemaViolations walks instance against schema and returns up to limit violations
+// (limit <= 0 means all). It covers the keywords generated schemas use; anything else is
+// reported by the caller from the validator's own message.
+func schemaViolations(schema map[string]any, instance any, limit int) []FieldViolation {
+	w := violationWalker{root: schema, limit: limit, out: nil, depth: 0}
+	w.walk(schema, instance, "")
+	return w.out
+}
+
+// maxViolationDepth stops $ref cycles from recursing forever on malformed schemas.
+const maxViolationDepth = 64
+
+type violationWalker struct {
+	root  map[string]any
+	limit int
+	out   []FieldViolation
+	depth int
+}
+
+func (w *violationWalker) full() bool {
+	return w.limit > 0 && len(w.out) >= w.limit
+}
+
+func (w *violationWalker) add(path, keyword, message string, value any) {
+	if w.full() {
+		return
+	}
+	w.out = append(w.out, FieldViolation{Path: path, Keyword: keyword, Message: message, Value: smallValue(value)})
+}
+
+func (w *violationWalker) walk(node map[string]any, inst any, path string) {
+	if node == nil || w.full() || w.depth > maxViolationDepth {
+		return
+	}
+	w.depth++
+	defer func() { w.depth-- }()
+	if ref, ok := node["$ref"].(string); ok {
+		if target := resolveLocalRef(w.root, ref); target != nil {
+			w.walk(target, inst, path)
+		}
+	}
+	if !w.checkType(node, inst, path) {
+		return
+	}
+	w.checkValue(node, inst, path)
+	switch v := inst.(type) {
+	case map[string]any:
+		w.walkObject(node, v, path)
+	case []any:
+		w.walkArray(node, v, path)
+	case string:
+		w.checkString(node, v, path)
+	case float64:
+		w.checkNumber(node, v, path)
+	}
+	w.walkCombinators(node, inst, path)
+}
+
+// checkType reports a type mismatch and returns false when descending further makes no sense.
+func (w *violationWalker) checkType(node map[string]any, inst any, path string) bool {
+	var allowed []string
+	switch t := node["type"].(type) {
+	case string:
+		allowed = []string{t}
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				allowed = append(allowed, s)
+			}
+		}
+	default:
+		return true
+	}
+	got := jsonTypeOf(inst)
+	for _, want := range allowed {
+		if want == got || (want == "number" && got == "integer") {
+			return true
+		}
+	}
+	w.add(path, "type", fmt.Sprintf("expected %s, got %s", strings.Join(allowed, " or "), got), inst)
+	return false
+}
+
+func (w *violationWalker) checkValue(node map[string]any, inst any, path string) {
+	if enum, ok := node["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, inst) }) {
+		w.add(path, "enum", fmt.Sprintf("must be one of %s", compactJSON(enum)), inst)
+	}
+	if c, ok := node["const"]; ok && !jsonEqual(c, inst) {
+		w.add(path, "const", "must equal "+compactJSON(c), inst)
+	}
+}
+
+func (w *violationWalker) walkObject(node map[string]any, obj map[string]any, path string) {
+	if required, ok := node["required"].([]any); ok {
+		for _, r := range required {
+			name, isString := r.(string)
+			if !isString {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				w.add(path+"/"+escapePointerToken(name), "required", "missing required property", nil)
+			}
+		}
+	}
+	props, _ := node["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		childPath := path + "/" + escapePointerToken(k)
+		if sub, ok := props[k].(map[string]any); ok {
+			w.walk(sub, obj[k], childPath)
+			continue
+		}
+		switch extra := node["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				w.add(childPath, "additionalProperties", "unexpected property", nil)
+			}
+		case map[string]any:
+			w.walk(extra, obj[k], childPath)
+		}
+	}
+}
+
+func (w *violationWalker) walkArray(node map[string]any, arr []any, path string) {
+	if n, ok := schemaNumber(node, "minItems"); ok && float64(len(arr)) < n {
+		w.add(path, "minItems", fmt.Sprintf("must have at least %v items", n), nil)
+	}
+	if n, ok := schemaNumber(node, "maxItems"); ok && float64(len(arr)) > n {
+		w.add(path, "maxItems", fmt.Sprintf("must have at most %v items", n), nil)
+	}
+	items, ok := node["items"].(map[string]any)
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		w.walk(items, item, fmt.Sprintf("%s/%d", path, i))
+	}
+}
+
+func (w *violationWalker) checkString(node map[string]any, s, path string) {
+	n := float64(utf8.RuneCountInString(s))
+	if m, ok := schemaNumber(node, "minLength"); ok && n < m {
+		w.add(path, "minLength", fmt.Sprintf("must be at least %v characters", m), s)
+	}
+	if m, ok := schemaNumber(node, "maxLength"); ok && n > m {
+		w.add(path, "maxLength", fmt.Sprintf("must be at most %v characters", m), s)
+	}
+	if pattern, ok := node["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			w.add(path, "pattern", fmt.Sprintf("must match pattern %q", pattern), s)
+		}
+	}
+}
+
+func (w *violationWalker) checkNumber(node map[string]any, n float64, path string) {
+	if m, ok := schemaNumber(node, "minimum"); ok && n < m {
+		w.add(path, "minimum", fmt.Sprintf("must be >= %v", m), n)
+	}
+	if m, ok := schemaNumber(node, "maximum"); ok && n > m {
+		w.add(path, "maximum", fmt.Sprintf("must be <= %v", m), n)
+	}
+	if m, ok := schemaNumber(node, "exclusiveMinimum"); ok && n <= m {
+		w.add(path, "exclusiveMinimum", fmt.Sprintf("must be > %v", m), n)
+	}
+	if m, ok := schemaNumber(node, "exclusiveMaximum"); ok && n >= m {
+		w.add(path, "exclusiveMaximum", fmt.Sprintf("must be < %v", m), n)
+	}
+}
+
+func (w *violationWalker) walkCombinators(node map[string]any, inst any, path string) {
+	if all, ok := node["allOf"].([]any); ok {
+		for _, sub := range all {
+			if m, isMap := sub.(map[string]any); isMap {
+				w.walk(m, inst, path)
+			}
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		branches, ok := node[keyword].([]any)
+		if !ok {
+			continue
+		}
+		matched := 0
+		for _, sub := range branches {
+			if m, isMap := sub.(map[string]any); isMap && len(schemaViolations(w.rootFor(m), inst, 1)) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			w.add(path, keyword, "does not match any allowed schema", inst)
+		case keyword == "oneOf" && matched > 1:
+			w.add(path, keyword, "matches more than one schema", inst)
+		}
+	}
+}
+
+// rootFor makes sub validate with $ref resolution against the walker's root schema.
+func (w *violationWalker) rootFor(sub map[string]any) map[string]any {
+	out := make(map[string]any, len(sub)+2)
+	for k, v := range sub {
+		out[k] = v
+	}
+	for _, defs := range []string{"$defs", "definitions"} {
+		if d, ok := w.root[defs]; ok {
+			if _, own := out[defs]; !own {
+				out[defs] = d
+			}
+		}
+	}
+	return out
+}
+
+// resolveLocalRef resolves "#/..." JSON Pointer references against root.
+func resolveLocalRef(root map[string]any, ref string) map[string]any {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil
+	}
+	if pointer == "" {
+		return root
+	}
+	var cur any = root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		m, isMap := cur.(map[string]any)
+		if !isMap {
+			return nil
+		}
+		cur = m[unescapePointerToken(token)]
+	}
+	target, _ := cur.(map[string]any)
+	return target
+}
+
+func jsonTypeOf(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if x == math.Trunc(x) && !math.IsInf(x, 0) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	return aNum && bNum && af == bf
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func schemaNumber(node map[string]any, key string) (float64, bool) {
+	return toFloat(node[key])
+}
+
+func smallValue(v any) any {
+	switch v.(type) {
+	case nil, map[string]any, []any:
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || len(data) > maxViolationValueBytes {
+		return nil
+	}
+	return v
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func escapePointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
@@ -0,0 +1,250 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type violationItem struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+}
+
+type violationOrder struct {
+	Count int             `json:"count"`
+	Mode  string          `json:"mode,omitempty" enum:"fast,slow"`
+	Items []violationItem `json:"items"`
+}
+
+func TestExtractor_ParseAndValidate_ViolationHasJSONPointer(t *testing.T) {
+	ext, err := NewExtractor[violationOrder](false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		args string
+		want FieldViolation
+	}{
+		{
+			name: "type",
+			args: `{"count":"three","items":[]}`,
+			want: FieldViolation{Path: "/count", Keyword: "type", Message: "expected integer, got string", Value: "three"},
+		},
+		{
+			name: "enum",
+			args: `{"count":1,"mode":"eager","items":[]}`,
+			want: FieldViolation{Path: "/mode", Keyword: "enum", Message: `must be one of ["fast","slow"]`, Value: "eager"},
+		},
+		{
+			name: "nested required",
+			args: `{"count":1,"items":[{"name":"a","unit":"kg"},{"unit":"kg"}]}`,
+			want: FieldViolation{Path: "/items/1/name", Keyword: "required", Message: "missing required property"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ext.ParseAndValidate([]byte(tt.args))
+			require.ErrorIs(t, err, ErrValidation)
+			te, ok := AsToolError(err)
+			require.True(t, ok)
+			assert.Equal(t, []FieldViolation{tt.want}, te.Violations)
+		})
+	}
+}
+
+func TestNewTool_ViolationInArrayItem(t *testing.T) {
+	type args struct {
+		Items []violationItem `json:"items"`
+	}
+	tool, err := NewTool("weigh", "Weigh", func(_ context.Context, _ *RunEnv, _ args) (string, error) {
+		return "", nil
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{tool})
+	err = reg.Execute(context.Background(), ToolCall{
+		ToolName: "weigh",
+		Input: ToolInput{ArgsJSON: []byte(
+			`{"items":[{"name":"a","unit":"kg"},{"name":"b","unit":"lb"},{"name":"c","unit":5}]}`,
+		)},
+	}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, FieldViolation{
+		Path:    "/items/2/unit",
+		Keyword: "type",
+		Message: "expected string, got integer",
+		Value:   float64(5),
+	}, te.Violations[0])
+	assert.Equal(t, []string{"items"}, te.FixableArgs)
+
+	data, err := te.ViolationsJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`[{"path":"/items/2/unit","keyword":"type","message":"expected string, got integer","value":5}]`,
+		string(data))
+}
+
+func TestRawSchemaTools_PopulateViolations(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"limit": map[string]any{"type": "integer", "minimum": 1},
+		},
+		"required": []any{"limit"},
+	}
+	raw, err := json.Marshal(schema)
+	require.NoError(t, err)
+	proxy, err := NewProxyTool("proxy", "Proxy", raw,
+		func(context.Context, *RunEnv, []byte, func(Chunk) error) error { return nil })
+	require.NoError(t, err)
+	dynamic, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:        "dynamic",
+		Description: "Dynamic",
+		Schema:      MapSchemaProvider(schema),
+		Handler: func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{proxy, dynamic})
+
+	for _, name := range []string{"proxy", "dynamic"} {
+		t.Run(name, func(t *testing.T) {
+			err := reg.Execute(context.Background(), ToolCall{
+				ToolName: name,
+				Input:    ToolInput{ArgsJSON: []byte(`{"limit":0}`)},
+			}, func(Chunk) error { return nil })
+			require.ErrorIs(t, err, ErrValidation)
+			te, ok := AsToolError(err)
+			require.True(t, ok)
+			assert.Equal(t, []FieldViolation{
+				{Path: "/limit", Keyword: "minimum", Message: "must be >= 1", Value: float64(0)},
+			}, te.Violations)
+		})
+	}
+}
+
+func TestToolErrorWire_RoundTripsViolations(t *testing.T) {
+	te := NewValidationError("bad args")
+	te.Violations = []FieldViolation{{Path: "/a", Keyword: "type", Message: "expected string, got number", Value: 1.5}}
+	data, err := marshalToolErrorWire(te, "")
+	require.NoError(t, err)
+	back, err := unmarshalToolErrorWire(data)
+	require.NoError(t, err)
+	assert.Equal(t, te.Violations, back.Violations)
+	assert.ErrorIs(t, back, ErrValidation)
+
+	empty, err := NewValidationError("x").ViolationsJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(empty))
+}
+
+func TestNewSchemaValidationError_FallsBackToValidatorMessage(t *testing.T) {
+	schema := map[string]any{"type": "object", "not": map[string]any{"required": []any{"a"}}}
+	te := newSchemaValidationError(schema, map[string]any{"a": 1.0},
+		errors.New("validating root: not: validated against {...}"), 1)
+	require.Len(t, te.Violations, 1)
+	assert.Equal(t, "not", te.Violations[0].Keyword)
+	assert.Empty(t, te.Violations[0].Path)
+	assert.Equal(t, "not: validated against {...}", te.Violations[0].Message)
+}
+
+func TestSchemaViolations_ResolvesRefsAndEscapesPointers(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"a/b": map[string]any{"$ref": "#/$defs/Name"},
+		},
+		"additionalProperties": false,
+		"$defs": map[string]any{
+			"Name": map[string]any{"type": "string", "maxLength": 3},
+		},
+	}
+	got := schemaViolations(schema, map[string]any{"a/b": "long", "x~y": true}, 0)
+	assert.Equal(t, []FieldViolation{
+		{Path: "/a~1b", Keyword: "maxLength", Message: "must be at most 3 characters", Value: "long"},
+		{Path: "/x~0y", Keyword: "additionalProperties", Message: "unexpected property"},
+	}, got)
+	assert.Equal(t, []string{"a/b", "x~y"}, violationFixableArgs(got))
+}
+
+type allErrorsArgs struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Tags  []int  `json:"tags"`
+}
+
+const allErrorsInput = `{"name":7,"tags":["x"]}`
+
+var allErrorsWant = []FieldViolation{
+	{Path: "/count", Keyword: "required", Message: "missing required property"},
+	{Path: "/name", Keyword: "type", Message: "expected string, got integer", Value: float64(7)},
+	{Path: "/tags/0", Keyword: "type", Message: "expected integer, got string", Value: "x"},
+}
+
+func TestWithAllValidationErrors_ReportsEveryViolation(t *testing.T) {
+	noop := func(_ context.Context, _ *RunEnv, _ allErrorsArgs) (string, error) { return "", nil }
+	typed, err := NewTool("typed", "Typed", noop, WithAllValidationErrors())
+	require.NoError(t, err)
+	stream, err := NewStreamTool("stream", "Stream",
+		func(_ context.Context, _ *RunEnv, _ allErrorsArgs, _ func(Chunk) error) error { return nil },
+		WithAllValidationErrors())
+	require.NoError(t, err)
+	dynamic, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:        "dynamic",
+		Description: "Dynamic",
+		Schema:      MapSchemaProvider(typed.Manifest().Parameters),
+		Handler: func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+			return nil
+		},
+		Options: []ToolOption{WithAllValidationErrors()},
+	})
+	require.NoError(t, err)
+	firstOnly, err := NewTool("first", "First", noop)
+	require.NoError(t, err)
+	reg := mustBuildRegistry(t, []Tool{typed, stream, dynamic, firstOnly})
+
+	for _, name := range []string{"typed", "stream", "dynamic"} {
+		t.Run(name, func(t *testing.T) {
+			err := reg.Execute(context.Background(), ToolCall{
+				ToolName: name,
+				Input:    ToolInput{ArgsJSON: []byte(allErrorsInput)},
+			}, func(Chunk) error { return nil })
+			require.ErrorIs(t, err, ErrValidation)
+			te, ok := AsToolError(err)
+			require.True(t, ok)
+			assert.Equal(t, allErrorsWant, te.Violations)
+			assert.Equal(t, []string{"count", "name", "tags"}, te.FixableArgs)
+			assert.Equal(t, "3 schema violations: /count: missing required property; "+
+				"/name: expected string, got integer; /tags/0: expected integer, got string", te.Reason)
+		})
+	}
+
+	err = reg.Execute(context.Background(), ToolCall{
+		ToolName: "first",
+		Input:    ToolInput{ArgsJSON: []byte(allErrorsInput)},
+	}, func(Chunk) error { return nil })
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Len(t, te.Violations, 1)
+}
+
+func TestExtractor_AllValidationErrors(t *testing.T) {
+	ext, err := NewExtractorWithConfig[allErrorsArgs](SchemaConfig{AllValidationErrors: true})
+	require.NoError(t, err)
+	_, err = ext.ParseAndValidate([]byte(allErrorsInput))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, allErrorsWant, te.Violations)
+
+	_, err = ExtractorTool(ext, "x", "X",
+		func(_ context.Context, _ *RunEnv, _ allErrorsArgs) (string, error) { return "", nil },
+		WithAllValidationErrors())
+	require.Error(t, err)
+}
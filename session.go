@@ -140,12 +140,12 @@ func (s *Session) ExecuteIter(ctx context.Context, call ToolCall) iter.Seq2[Chun
 
 		err := s.Execute(ctxChild, call, func(c Chunk) error {
 			if consumerStopped {
-				return context.Canceled
+				return wrapYieldError(context.Canceled)
 			}
 			if !yield(c, nil) {
 				consumerStopped = true
 				cancel()
-				return context.Canceled
+				return wrapYieldError(context.Canceled)
 			}
 			return nil
 		})
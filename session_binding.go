@@ -142,6 +142,7 @@ func newSessionBindingMismatchError(reason string, fixableArgs ...string) *ToolE
 		Retryable:   false,
 		FixableArgs: append([]string(nil), fixableArgs...),
 		SafeMessage: "",
+		Violations:  nil,
 		Err:         fmt.Errorf("toolsy: session binding %w", errors.New(reason)),
 	}
 }
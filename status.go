@@ -0,0 +1,65 @@
+package toolsy
+
+import "time"
+
+// StatusPhase names a lifecycle phase reported by a tool while a call is still running.
+// UIs can map phases to icons; adapters map them to their own progress message formats.
+type StatusPhase string
+
+// Documented status phases. Tools may use other values, but consumers are only expected to render these.
+const (
+	// StatusQueued means the work is waiting in a downstream queue.
+	StatusQueued StatusPhase = "queued"
+	// StatusRunning means the downstream system has started the work.
+	StatusRunning StatusPhase = "running"
+	// StatusRetrying means a previous attempt failed and the tool is trying again.
+	StatusRetrying StatusPhase = "retrying"
+	// StatusRateLimited means an upstream rate limit was hit; RetryAfter says how long the tool waits.
+	StatusRateLimited StatusPhase = "rate_limited"
+	// StatusCircuitOpen means a circuit breaker is rejecting calls to the dependency.
+	StatusCircuitOpen StatusPhase = "circuit_open"
+	// StatusWaiting means the tool is blocked on an external event (approval, webhook, job completion).
+	StatusWaiting StatusPhase = "waiting"
+	// StatusConfirmationRequired means the registry is waiting for the user to approve the call
+	// ([WithConfirmationHandler]); Detail names the tool.
+	StatusConfirmationRequired StatusPhase = "confirmation_required"
+)
+
+// StatusProgressLabel is the reserved [ProgressInfo.Label] that marks a progress chunk built by [StatusChunk].
+const StatusProgressLabel = "toolsy.status"
+
+// Status is a machine-readable intermediate status emitted as an [EventProgress] chunk.
+type Status struct {
+	Phase StatusPhase
+	// Detail is a short human-readable explanation (for example "upstream returned 429").
+	Detail string
+	// RetryAfter is the wait before the next attempt; zero when unknown or not applicable.
+	RetryAfter time.Duration
+}
+
+// StatusChunk encodes s as an [EventProgress] chunk. The phase is stored in [ProgressInfo.Status],
+// the detail in [ProgressInfo.Message], and the label is set to [StatusProgressLabel].
+// Status chunks never count as results in [ExecutionSummary] or [ToolOutcome].
+func StatusChunk(s Status) Chunk {
+	return Chunk{ //nolint:exhaustruct // CallID/ToolName are filled by the registry; status chunks carry no data
+		Event: EventProgress,
+		Progress: &ProgressInfo{ //nolint:exhaustruct // percent/total/token do not apply to lifecycle statuses
+			Label:      StatusProgressLabel,
+			Status:     string(s.Phase),
+			Message:    s.Detail,
+			RetryAfter: s.RetryAfter,
+		},
+	}
+}
+
+// StatusFromChunk decodes a chunk built by [StatusChunk]. It reports false for any other chunk.
+func StatusFromChunk(c Chunk) (Status, bool) {
+	if c.Event != EventProgress || c.Progress == nil || c.Progress.Label != StatusProgressLabel {
+		return Status{}, false
+	}
+	return Status{
+		Phase:      StatusPhase(c.Progress.Status),
+		Detail:     c.Progress.Message,
+		RetryAfter: c.Progress.RetryAfter,
+	}, true
+}
@@ -0,0 +1,79 @@
+package toolsy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusChunk_ReservedProgressFields(t *testing.T) {
+	t.Parallel()
+	c := StatusChunk(Status{Phase: StatusRateLimited, Detail: "upstream returned 429", RetryAfter: 4 * time.Second})
+
+	assert.Equal(t, EventProgress, c.Event)
+	require.NotNil(t, c.Progress)
+	assert.Equal(t, StatusProgressLabel, c.Progress.Label)
+	assert.Equal(t, "rate_limited", c.Progress.Status)
+	assert.Equal(t, "upstream returned 429", c.Progress.Message)
+	assert.Equal(t, 4*time.Second, c.Progress.RetryAfter)
+	assert.Empty(t, c.Data)
+	require.NoError(t, validateChunk(c))
+
+	got, ok := StatusFromChunk(c)
+	require.True(t, ok)
+	assert.Equal(t, Status{Phase: StatusRateLimited, Detail: "upstream returned 429", RetryAfter: 4 * time.Second}, got)
+}
+
+func TestStatusFromChunk_IgnoresOtherChunks(t *testing.T) {
+	t.Parallel()
+	for _, c := range []Chunk{
+		{Event: EventProgress, Progress: &ProgressInfo{Label: "custom", Status: "running"}},
+		{Event: EventProgress},
+		{Event: EventResult, Progress: &ProgressInfo{Label: StatusProgressLabel}},
+	} {
+		_, ok := StatusFromChunk(c)
+		assert.False(t, ok)
+	}
+}
+
+func TestRegistry_StatusChunksCountAsProgress(t *testing.T) {
+	t.Parallel()
+	tool := &minTool{
+		manifest: ToolManifest{Name: "slow", Description: "d", Parameters: map[string]any{"type": "object"}},
+		execute: func(_ context.Context, _ *RunEnv, _ ToolInput, yield func(Chunk) error) error {
+			if err := yield(StatusChunk(Status{Phase: StatusQueued})); err != nil {
+				return err
+			}
+			if err := yield(StatusChunk(Status{Phase: StatusRetrying, Detail: "attempt 2"})); err != nil {
+				return err
+			}
+			return yield(Chunk{Event: EventResult, Data: []byte("done"), MimeType: MimeTypeText})
+		},
+	}
+	var summary ExecutionSummary
+	var progressCalls int
+	reg := mustBuildRegistry(t, []Tool{tool},
+		WithOnAfterExecute(func(_ context.Context, _ ToolCall, s ExecutionSummary, _ time.Duration) {
+			summary = s
+		}),
+		WithOnChunkProgress(func(context.Context, Chunk, ChunkProgress) { progressCalls++ }),
+	)
+
+	var statuses []Status
+	require.NoError(t, reg.Execute(context.Background(), ToolCall{ToolName: "slow", Input: ToolInput{CallID: "c1"}},
+		func(c Chunk) error {
+			if s, ok := StatusFromChunk(c); ok {
+				statuses = append(statuses, s)
+			}
+			return nil
+		}))
+
+	assert.Equal(t, []Status{{Phase: StatusQueued}, {Phase: StatusRetrying, Detail: "attempt 2"}}, statuses)
+	assert.Equal(t, 2, summary.ProgressChunks)
+	assert.Equal(t, 1, summary.ChunksDelivered)
+	assert.Equal(t, int64(len("done")), summary.TotalBytes)
+	assert.Equal(t, 1, progressCalls)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -263,3 +264,60 @@ func TestRegistry_ExecuteBatchStream_YieldError(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistry_StampsChunkSeqAndTimestamp(t *testing.T) {
+	t.Parallel()
+	stream := func(_ context.Context, _ *RunEnv, _ struct{}, yield func(Chunk) error) error {
+		if err := yield(ProgressChunk(10, "start")); err != nil {
+			return err
+		}
+		for _, s := range []string{"a", "b"} {
+			c := Chunk{Event: EventResult, Data: []byte(s), MimeType: MimeTypeText, Seq: 99}
+			if err := yield(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tool, err := NewStreamTool("seq", "Seq", stream)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	hooked := map[string][]int{}
+	reg, err := NewRegistryBuilder(WithOnChunk(func(_ context.Context, c Chunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		hooked[c.CallID] = append(hooked[c.CallID], c.Seq)
+	})).Add(tool).Build()
+	require.NoError(t, err)
+
+	before := time.Now()
+	got := map[string][]Chunk{}
+	calls := []ToolCall{
+		{ToolName: "seq", Input: ToolInput{CallID: "c1", ArgsJSON: []byte(`{}`)}},
+		{ToolName: "seq", Input: ToolInput{CallID: "c2", ArgsJSON: []byte(`{}`)}},
+	}
+	require.NoError(t, reg.ExecuteBatchStream(context.Background(), calls, func(c Chunk) error {
+		got[c.CallID] = append(got[c.CallID], c)
+		return nil
+	}))
+	for _, id := range []string{"c1", "c2"} {
+		require.Len(t, got[id], 3)
+		for i, c := range got[id] {
+			assert.Equal(t, i, c.Seq, "per-call seq overrides the tool's value")
+			assert.False(t, c.Timestamp.Before(before))
+			if i > 0 {
+				assert.False(t, c.Timestamp.Before(got[id][i-1].Timestamp))
+			}
+		}
+		assert.Equal(t, []int{0, 1, 2}, hooked[id])
+	}
+
+	var direct []Chunk
+	require.NoError(t, tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)},
+		func(c Chunk) error {
+			direct = append(direct, c)
+			return nil
+		}))
+	assert.True(t, direct[0].Timestamp.IsZero(), "direct Execute leaves stamps unset")
+}
@@ -0,0 +1,30 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skosovsky/toolsy"
+)
+
+const (
+	abandonedGrace = 100 * time.Millisecond
+	abandonedPoll  = 5 * time.Millisecond
+)
+
+// VerifyNoAbandonedExecutions fails t when reg still tracks executions whose context is done but whose
+// handler has not returned. Handlers get a short grace period to observe cancellation first.
+// reg must be built with [toolsy.WithExecutionWatchdog]; use it next to goleak.VerifyNone, which catches
+// the goroutines but not the tool or call that leaked them.
+func VerifyNoAbandonedExecutions(t testing.TB, reg *toolsy.Registry) {
+	t.Helper()
+	deadline := time.Now().Add(abandonedGrace)
+	abandoned := reg.AbandonedExecutions()
+	for len(abandoned) > 0 && time.Now().Before(deadline) {
+		time.Sleep(abandonedPoll)
+		abandoned = reg.AbandonedExecutions()
+	}
+	for _, a := range abandoned {
+		t.Errorf("toolsy: tool %q call %q still running %s after its context was done", a.ToolName, a.CallID, a.Abandoned)
+	}
+}
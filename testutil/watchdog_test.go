@@ -0,0 +1,68 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type recordingTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestVerifyNoAbandonedExecutions(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	tool := &MockTool{
+		ManifestVal: toolsy.ToolManifest{
+			Name:       "stubborn",
+			Parameters: map[string]any{"type": "object"},
+		},
+		ExecuteFn: func(context.Context, *toolsy.RunEnv, toolsy.ToolInput, func(toolsy.Chunk) error) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}
+	reg, err := toolsy.NewRegistryBuilder(toolsy.WithExecutionWatchdog(time.Hour, nil)).Add(tool).Build()
+	require.NoError(t, err)
+
+	clean := &recordingTB{TB: t}
+	VerifyNoAbandonedExecutions(clean, reg)
+	assert.Empty(t, clean.errors)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.Execute(ctx, toolsy.ToolCall{
+			ToolName: "stubborn",
+			Input:    toolsy.ToolInput{CallID: "leaky", ArgsJSON: []byte(`{}`)},
+		}, func(toolsy.Chunk) error { return nil })
+	}()
+	<-started
+	cancel()
+	require.Eventually(t, func() bool { return len(reg.AbandonedExecutions()) == 1 }, time.Second, time.Millisecond)
+
+	leaky := &recordingTB{TB: t}
+	VerifyNoAbandonedExecutions(leaky, reg)
+	require.Len(t, leaky.errors, 1)
+	assert.Contains(t, leaky.errors[0], `tool "stubborn" call "leaky"`)
+
+	close(release)
+	require.NoError(t, <-done)
+	VerifyNoAbandonedExecutions(t, reg)
+}
@@ -0,0 +1,272 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToolDefinition is one config-defined tool read by [LoadToolDefinitions] and built by [BuildTools].
+type ToolDefinition struct {
+	Name        string
+	Description string
+	// Parameters is the JSON Schema of the arguments; an empty schema accepts any object.
+	Parameters map[string]any
+	Tags       []string
+	// Timeout bounds one execution; zero means no limit beyond the call's context.
+	Timeout   time.Duration
+	Dangerous bool
+	// Handler selects the [DynamicHandler] that implements the tool.
+	Handler string
+	// Config holds handler-specific settings (for example the URL of an HTTP-backed tool).
+	Config map[string]any
+
+	// Source and Line locate the definition for error messages; they are not part of its identity.
+	Source string
+	Line   int
+}
+
+// DynamicHandler is a handler factory for config-defined tools: it receives the definition and
+// returns the function that runs each call with the schema-validated arguments. Factories report
+// bad Config values as errors, which [BuildTools] attributes to the definition.
+type DynamicHandler func(def ToolDefinition) (
+	func(ctx context.Context, env *RunEnv, args map[string]any, yield func(Chunk) error) error,
+	error,
+)
+
+type toolDefinitionFile struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description"`
+	Parameters  map[string]any `yaml:"parameters"`
+	Tags        []string       `yaml:"tags"`
+	Timeout     string         `yaml:"timeout"`
+	Dangerous   bool           `yaml:"dangerous"`
+	Handler     string         `yaml:"handler"`
+	Config      map[string]any `yaml:"config"`
+}
+
+// LoadToolDefinitions reads tool definitions from JSON or YAML (JSON is read as YAML). The document is
+// either a list of definitions or an object with a "tools" list; each definition has name,
+// description, parameters, tags, timeout (a Go duration such as "30s"), dangerous, handler, and
+// config. Every problem found (missing fields, bad timeouts or schemas, duplicate names) is reported
+// in one joined error, each prefixed with "source:line"; the source is the name of r when it has one
+// (such as an [os.File]).
+func LoadToolDefinitions(r io.Reader) ([]ToolDefinition, error) {
+	source := "definitions"
+	if named, ok := r.(interface{ Name() string }); ok {
+		source = named.Name()
+	}
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("toolsy: %s: %w", source, err)
+	}
+	list, err := toolDefinitionList(&doc, source)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]ToolDefinition, 0, len(list.Content))
+	var errs []error
+	seen := make(map[string]int, len(list.Content))
+	for _, node := range list.Content {
+		def, defErrs := decodeToolDefinition(node, source)
+		errs = append(errs, defErrs...)
+		if def.Name != "" {
+			if line, dup := seen[def.Name]; dup {
+				errs = append(errs, definitionError(def, "duplicate tool name (first defined on line %d)", line))
+				continue
+			}
+			seen[def.Name] = def.Line
+		}
+		if len(defErrs) == 0 {
+			defs = append(defs, def)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func toolDefinitionList(doc *yaml.Node, source string) (*yaml.Node, error) {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
+	}
+	if root.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == "tools" {
+				root = root.Content[i+1]
+				break
+			}
+		}
+	}
+	if root.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("toolsy: %s:%d: expected a list of tool definitions or a \"tools\" list",
+			source, root.Line)
+	}
+	return root, nil
+}
+
+func decodeToolDefinition(node *yaml.Node, source string) (ToolDefinition, []error) {
+	def := ToolDefinition{Source: source, Line: node.Line} //nolint:exhaustruct // filled below
+	var raw toolDefinitionFile
+	if err := node.Decode(&raw); err != nil {
+		return def, []error{definitionError(def, "%v", err)}
+	}
+	def.Name = raw.Name
+	def.Description = raw.Description
+	def.Parameters = raw.Parameters
+	def.Tags = raw.Tags
+	def.Dangerous = raw.Dangerous
+	def.Handler = raw.Handler
+	def.Config = raw.Config
+
+	var errs []error
+	if def.Name == "" {
+		errs = append(errs, definitionError(def, "name is required"))
+	}
+	if def.Handler == "" {
+		errs = append(errs, definitionError(def, "handler is required"))
+	}
+	if raw.Timeout != "" {
+		d, err := time.ParseDuration(raw.Timeout)
+		if err != nil || d < 0 {
+			errs = append(errs, definitionError(def, "invalid timeout %q", raw.Timeout))
+		}
+		def.Timeout = d
+	}
+	def.Parameters = definitionParameters(def.Parameters)
+	if _, err := compileRawSchema(def.Parameters); err != nil {
+		errs = append(errs, definitionError(def, "invalid parameters schema: %v", err))
+	}
+	return def, errs
+}
+
+func definitionParameters(params map[string]any) map[string]any {
+	if params == nil {
+		return map[string]any{"type": "object"}
+	}
+	return params
+}
+
+func definitionError(def ToolDefinition, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if def.Name != "" {
+		msg = fmt.Sprintf("tool %q: %s", def.Name, msg)
+	}
+	if def.Source == "" && def.Line == 0 {
+		return fmt.Errorf("toolsy: %s", msg)
+	}
+	return fmt.Errorf("toolsy: %s:%d: %s", def.Source, def.Line, msg)
+}
+
+// BuildTools builds one dynamic tool per definition with the handler factory registered under its
+// Handler key. A definition's tags and dangerous flag become [WithTags] and [WithDangerous], and its
+// timeout cancels the call with a retryable [CodeTimeout] error. Unknown handler keys and factory
+// errors are reported together, each located by the definition's source and line.
+func BuildTools(defs []ToolDefinition, handlers map[string]DynamicHandler) ([]Tool, error) {
+	tools := make([]Tool, 0, len(defs))
+	var errs []error
+	for _, def := range defs {
+		t, err := buildDefinedTool(def, handlers)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tools = append(tools, t)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+func buildDefinedTool(def ToolDefinition, handlers map[string]DynamicHandler) (Tool, error) {
+	factory, ok := handlers[def.Handler]
+	if !ok || factory == nil {
+		return nil, definitionError(def, "unknown handler %q", def.Handler)
+	}
+	handler, err := factory(def)
+	if err != nil {
+		return nil, definitionError(def, "%v", err)
+	}
+	if handler == nil {
+		return nil, definitionError(def, "handler %q returned no function", def.Handler)
+	}
+	opts := []ToolOption{WithTags(def.Tags...)}
+	if def.Dangerous {
+		opts = append(opts, WithDangerous())
+	}
+	t, err := NewDynamicToolFromSpec(DynamicToolSpec{
+		Name:         def.Name,
+		Description:  def.Description,
+		Schema:       MapSchemaProvider(definitionParameters(def.Parameters)),
+		OutputSchema: nil,
+		ValidateArgs: nil,
+		Handler:      withDefinitionTimeout(def.Timeout, handler),
+		Options:      opts,
+	})
+	if err != nil {
+		return nil, definitionError(def, "%v", err)
+	}
+	return &definedTool{toolBase: toolBase{next: t}, def: cloneToolDefinition(def)}, nil
+}
+
+func withDefinitionTimeout(
+	timeout time.Duration,
+	handler func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error,
+) func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error {
+	if timeout <= 0 {
+		return handler
+	}
+	return func(ctx context.Context, env *RunEnv, args map[string]any, yield func(Chunk) error) error {
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		err := handler(runCtx, env, args, yield)
+		if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return NewTimeoutErrorFrom(err, true)
+		}
+		return err
+	}
+}
+
+// definedTool marks a tool built from a [ToolDefinition] so [Registry.Reload] can diff against it.
+type definedTool struct {
+	toolBase
+
+	def ToolDefinition
+}
+
+func (t *definedTool) Execute(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
+	return t.next.Execute(ctx, env, input, yield)
+}
+
+// toolDefinitionOf finds the definition a registered tool was built from, looking through middlewares.
+func toolDefinitionOf(t Tool) (ToolDefinition, bool) {
+	for t != nil {
+		if d, ok := t.(*definedTool); ok {
+			return d.def, true
+		}
+		u, ok := t.(ChainUnwrapper)
+		if !ok {
+			break
+		}
+		t = u.UnwrapNext()
+	}
+	return ToolDefinition{}, false //nolint:exhaustruct // not found
+}
+
+func cloneToolDefinition(def ToolDefinition) ToolDefinition {
+	def.Parameters = deepCloneMap(def.Parameters)
+	def.Config = deepCloneMap(def.Config)
+	def.Tags = append([]string(nil), def.Tags...)
+	return def
+}
@@ -0,0 +1,204 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const definitionsYAML = `tools:
+  - name: echo
+    description: Echoes the text
+    handler: echo
+    tags: [text]
+    timeout: 2s
+    parameters:
+      type: object
+      properties:
+        text: {type: string}
+      required: [text]
+  - name: wipe
+    description: Deletes everything
+    handler: echo
+    dangerous: true
+    config:
+      prefix: "wiped "
+`
+
+func echoHandlers() map[string]DynamicHandler {
+	return map[string]DynamicHandler{
+		"echo": func(def ToolDefinition) (
+			func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error, error,
+		) {
+			prefix, _ := def.Config["prefix"].(string)
+			return func(_ context.Context, _ *RunEnv, args map[string]any, yield func(Chunk) error) error {
+				text, _ := args["text"].(string)
+				return yield(Chunk{Event: EventResult, Data: []byte(prefix + text), MimeType: MimeTypeText})
+			}, nil
+		},
+	}
+}
+
+func TestLoadToolDefinitions_YAML(t *testing.T) {
+	t.Parallel()
+	defs, err := LoadToolDefinitions(strings.NewReader(definitionsYAML))
+	require.NoError(t, err)
+	require.Len(t, defs, 2)
+	assert.Equal(t, "echo", defs[0].Name)
+	assert.Equal(t, []string{"text"}, defs[0].Tags)
+	assert.Equal(t, 2*time.Second, defs[0].Timeout)
+	assert.Equal(t, 2, defs[0].Line)
+	assert.True(t, defs[1].Dangerous)
+	assert.Equal(t, map[string]any{"type": "object"}, defs[1].Parameters)
+
+	tools, err := BuildTools(defs, echoHandlers())
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Add(tools...).Build()
+	require.NoError(t, err)
+	assert.Len(t, reg.GetToolsByTag("text"), 1)
+	wipe, ok := reg.GetTool("wipe")
+	require.True(t, ok)
+	assert.True(t, wipe.Manifest().Dangerous)
+
+	out, err := reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "echo", Input: ToolInput{ArgsJSON: []byte(`{"text":"hi"}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", string(out))
+
+	_, err = reg.ExecuteCollect(context.Background(),
+		ToolCall{ToolName: "echo", Input: ToolInput{ArgsJSON: []byte(`{}`)}})
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeValidationFailed, te.Code)
+}
+
+func TestLoadToolDefinitions_AggregatesErrors(t *testing.T) {
+	t.Parallel()
+	src := `[
+  {"name": "a", "handler": "h", "timeout": "soon"},
+  {"description": "nameless"},
+  {"name": "a", "handler": "h"},
+  {"name": "b", "handler": "h", "parameters": {"type": 42}}
+]`
+	_, err := LoadToolDefinitions(strings.NewReader(src))
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, `definitions:2: tool "a": invalid timeout "soon"`)
+	assert.Contains(t, msg, "definitions:3: name is required")
+	assert.Contains(t, msg, "definitions:3: handler is required")
+	assert.Contains(t, msg, `definitions:4: tool "a": duplicate tool name (first defined on line 2)`)
+	assert.Contains(t, msg, `definitions:5: tool "b": invalid parameters schema`)
+
+	_, err = LoadToolDefinitions(strings.NewReader(`name: x`))
+	require.ErrorContains(t, err, "definitions:1: expected a list")
+}
+
+func TestBuildTools_Errors(t *testing.T) {
+	t.Parallel()
+	failing := map[string]DynamicHandler{
+		"bad": func(ToolDefinition) (func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error, error) {
+			return nil, errors.New("config.url is required")
+		},
+	}
+	defs := []ToolDefinition{
+		{Name: "x", Handler: "missing", Source: "tools.yaml", Line: 3},
+		{Name: "y", Handler: "bad", Source: "tools.yaml", Line: 7},
+	}
+	_, err := BuildTools(defs, failing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `tools.yaml:3: tool "x": unknown handler "missing"`)
+	assert.Contains(t, err.Error(), `tools.yaml:7: tool "y": config.url is required`)
+}
+
+func TestBuildTools_Timeout(t *testing.T) {
+	t.Parallel()
+	handlers := map[string]DynamicHandler{
+		"slow": func(ToolDefinition) (func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error, error) {
+			return func(ctx context.Context, _ *RunEnv, _ map[string]any, _ func(Chunk) error) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, nil
+		},
+	}
+	tools, err := BuildTools([]ToolDefinition{{Name: "slow", Handler: "slow", Timeout: 10 * time.Millisecond}}, handlers)
+	require.NoError(t, err)
+	_, err = CollectTool(context.Background(), tools[0], []byte(`{}`))
+	te, ok := AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, CodeTimeout, te.Code)
+	assert.True(t, te.Retryable)
+}
+
+func TestRegistryReload_Diff(t *testing.T) {
+	t.Parallel()
+	defs, err := LoadToolDefinitions(strings.NewReader(definitionsYAML))
+	require.NoError(t, err)
+	tools, err := BuildTools(defs, echoHandlers())
+	require.NoError(t, err)
+	code, err := NewTool("code", "Registered in code", func(context.Context, *RunEnv, struct{}) (collectOut, error) {
+		return collectOut{}, nil
+	})
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder().Add(code).Add(tools...).Build()
+	require.NoError(t, err)
+	echoBefore, _ := reg.GetTool("echo")
+
+	next := []ToolDefinition{
+		defs[0],
+		{Name: "fresh", Description: "New", Handler: "echo", Line: 40},
+	}
+	next[0].Line = 99 // moving a definition within the file is not a change
+	reloaded, report, err := reg.Reload(next, echoHandlers())
+	require.NoError(t, err)
+	assert.Equal(t, ReloadReport{Added: []string{"fresh"}, Replaced: nil, Removed: []string{"wipe"}}, report)
+	assert.ElementsMatch(t, []string{"code", "echo", "fresh"}, reloaded.ToolNames())
+	echoAfter, _ := reloaded.GetTool("echo")
+	assert.Same(t, echoBefore, echoAfter)
+	_, stillThere := reg.GetTool("wipe")
+	assert.True(t, stillThere, "the original registry is not modified")
+
+	next[0].Description = "Echoes the text back"
+	_, report, err = reloaded.Reload(next, echoHandlers())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"echo"}, report.Replaced)
+	assert.Empty(t, report.Added)
+	assert.Empty(t, report.Removed)
+
+	_, _, err = reg.Reload([]ToolDefinition{{Name: "code", Handler: "echo", Source: "tools.yaml", Line: 1}},
+		echoHandlers())
+	require.ErrorContains(t, err, `tools.yaml:1: tool "code": name is already used by a tool registered in code`)
+}
+
+func TestRegistryReload_AppliesNameValidationAndMaxTools(t *testing.T) {
+	t.Parallel()
+	defs := func(names ...string) []ToolDefinition {
+		out := make([]ToolDefinition, 0, len(names))
+		for _, name := range names {
+			out = append(out, ToolDefinition{Name: name, Description: "Echo", Handler: "echo"})
+		}
+		return out
+	}
+	tools, err := BuildTools(defs("a"), echoHandlers())
+	require.NoError(t, err)
+	reg, err := NewRegistryBuilder(WithMaxTools(1), WithToolNameValidation(regexp.MustCompile(`^[a-z]+$`))).
+		Add(tools...).Build()
+	require.NoError(t, err)
+
+	_, _, err = reg.Reload(defs("b_1"), echoHandlers())
+	require.ErrorIs(t, err, ErrInvalidToolName)
+	assert.ErrorContains(t, err, `"b_1"`)
+
+	_, _, err = reg.Reload(defs("a", "c"), echoHandlers())
+	require.ErrorIs(t, err, ErrTooManyTools)
+
+	reloaded, report, err := reg.Reload(defs("b"), echoHandlers())
+	require.NoError(t, err, "swapping one tool for another stays within the limit")
+	assert.Equal(t, []string{"b"}, report.Added)
+	assert.Equal(t, []string{"b"}, reloaded.ToolNames())
+}
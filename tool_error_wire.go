@@ -9,12 +9,13 @@ import (
 const MimeTypeToolErrorJSON = "application/vnd.toolsy.tool-error+json"
 
 type toolErrorWire struct {
-	Code        ErrorCode `json:"code"`
-	Retryable   bool      `json:"retryable"`
-	Reason      string    `json:"reason,omitempty"`
-	FixableArgs []string  `json:"fixable_args,omitempty"`
-	SafeMessage string    `json:"safe_message,omitempty"`
-	Message     string    `json:"message,omitempty"`
+	Code        ErrorCode        `json:"code"`
+	Retryable   bool             `json:"retryable"`
+	Reason      string           `json:"reason,omitempty"`
+	FixableArgs []string         `json:"fixable_args,omitempty"`
+	SafeMessage string           `json:"safe_message,omitempty"`
+	Violations  []FieldViolation `json:"violations,omitempty"`
+	Message     string           `json:"message,omitempty"`
 }
 
 func marshalToolErrorWire(te *ToolError, llmMessage string) ([]byte, error) {
@@ -27,6 +28,7 @@ func marshalToolErrorWire(te *ToolError, llmMessage string) ([]byte, error) {
 		Reason:      te.Reason,
 		FixableArgs: append([]string(nil), te.FixableArgs...),
 		SafeMessage: te.SafeMessage,
+		Violations:  append([]FieldViolation(nil), te.Violations...),
 		Message:     llmMessage,
 	}
 	return json.Marshal(wire)
@@ -46,6 +48,7 @@ func unmarshalToolErrorWire(data []byte) (*ToolError, error) {
 		Reason:      wire.Reason,
 		FixableArgs: append([]string(nil), wire.FixableArgs...),
 		SafeMessage: wire.SafeMessage,
+		Violations:  append([]FieldViolation(nil), wire.Violations...),
 	}
 	te.Err = sentinelForErrorCode(wire.Code)
 	if te.Err == nil {
@@ -75,6 +78,14 @@ func sentinelForErrorCode(code ErrorCode) error {
 		return ErrRegistryState
 	case CodeBudgetExceeded:
 		return ErrBudgetExceeded
+	case CodeOverloaded:
+		return ErrOverloaded
+	case CodeLeaseHeld:
+		return ErrLeaseHeld
+	case CodeRateLimited:
+		return ErrRateLimited
+	case CodeConfirmationDenied:
+		return ErrConfirmationDenied
 	case CodeSchemaInvalid:
 		return ErrValidation
 	case CodeDependencyMissing, CodeToolsContractMissing:
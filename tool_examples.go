@@ -0,0 +1,79 @@
+package toolsy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithExamples attaches example argument payloads that show the model what a valid call looks like.
+// Each value is marshaled with encoding/json (pass a [json.RawMessage] for JSON text) and added to
+// the root "examples" keyword of [ToolManifest.Parameters], so provider exporters carry them; they
+// are also available as [ToolManifest.Examples] and through [ToolExamples] for orchestrators that
+// place examples in the system prompt instead. Typed tools fail to build when an example does not
+// match the generated schema; [NewDynamicTool] and [NewProxyTool] take examples as given.
+func WithExamples(examples ...any) ToolOption {
+	return func(c *ToolConfig) {
+		c.Examples = append(c.Examples, examples...)
+	}
+}
+
+// ToolExamples is implemented by tools that carry example arguments ([WithExamples]).
+// Tools built by this package implement it; Examples returns a copy of [ToolManifest.Examples].
+type ToolExamples interface {
+	Examples() []json.RawMessage
+}
+
+// Examples returns a copy of the tool's example arguments.
+func (t *tool) Examples() []json.RawMessage {
+	return cloneExamples(t.manifest.Examples)
+}
+
+// resolveExamples marshals cfg.Examples into cfg.Manifest.Examples, checking each one with validate
+// when it is not nil.
+func resolveExamples(cfg *ToolConfig, validate func(argsJSON []byte) error) error {
+	if len(cfg.Examples) == 0 {
+		return nil
+	}
+	out := make([]json.RawMessage, 0, len(cfg.Examples))
+	for i, example := range cfg.Examples {
+		raw, err := json.Marshal(example)
+		if err != nil {
+			return fmt.Errorf("toolsy: example %d: %w", i, err)
+		}
+		if validate != nil {
+			if err := validate(raw); err != nil {
+				return fmt.Errorf("toolsy: example %d does not match the parameters schema: %w", i, err)
+			}
+		}
+		out = append(out, raw)
+	}
+	cfg.Manifest.Examples = out
+	return nil
+}
+
+// withSchemaExamples sets the root "examples" keyword of schema (already a private copy) from examples.
+func withSchemaExamples(schema map[string]any, examples []json.RawMessage) map[string]any {
+	if len(examples) == 0 || schema == nil {
+		return schema
+	}
+	values := make([]any, 0, len(examples))
+	for _, raw := range examples {
+		var v any
+		if err := json.Unmarshal(raw, &v); err == nil {
+			values = append(values, v)
+		}
+	}
+	schema["examples"] = values
+	return schema
+}
+
+func cloneExamples(in []json.RawMessage) []json.RawMessage {
+	if in == nil {
+		return nil
+	}
+	out := make([]json.RawMessage, len(in))
+	for i, raw := range in {
+		out[i] = append(json.RawMessage(nil), raw...)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exampleArgs struct {
+	City  string `json:"city"`
+	Units string `json:"units,omitempty" enum:"metric,imperial"`
+}
+
+func exampleToolFn(context.Context, *RunEnv, exampleArgs) (string, error) { return "ok", nil }
+
+func TestWithExamples_TypedTool(t *testing.T) {
+	tool, err := NewTool("weather", "Weather", exampleToolFn,
+		WithExamples(exampleArgs{City: "Oslo", Units: "metric"}, json.RawMessage(`{"city": "Paris"}`)))
+	require.NoError(t, err)
+
+	m := tool.Manifest()
+	assert.Equal(t, []any{
+		map[string]any{"city": "Oslo", "units": "metric"},
+		map[string]any{"city": "Paris"},
+	}, m.Parameters["examples"])
+	require.Len(t, m.Examples, 2)
+	assert.JSONEq(t, `{"city":"Paris"}`, string(m.Examples[1]))
+
+	ex, ok := tool.(ToolExamples)
+	require.True(t, ok)
+	got := ex.Examples()
+	got[0][0] = 'x'
+	assert.JSONEq(t, `{"city":"Oslo","units":"metric"}`, string(ex.Examples()[0]), "Examples returns a copy")
+
+	plain, err := NewTool("weather", "Weather", exampleToolFn)
+	require.NoError(t, err)
+	assert.NotContains(t, plain.Manifest().Parameters, "examples")
+	assert.Nil(t, plain.Manifest().Examples)
+}
+
+func TestWithExamples_InvalidExampleFailsBuild(t *testing.T) {
+	_, err := NewTool("weather", "Weather", exampleToolFn,
+		WithExamples(map[string]any{"city": "Oslo"}, map[string]any{"city": "Oslo", "units": "kelvin"}))
+	require.ErrorContains(t, err, "example 1 does not match the parameters schema")
+
+	_, err = NewTool("weather", "Weather", exampleToolFn, WithExamples(func() {}))
+	require.ErrorContains(t, err, "example 0")
+}
+
+func TestWithExamples_DynamicToolTakesRawJSON(t *testing.T) {
+	handler := func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error { return nil }
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"q": map[string]any{"type": "string"}},
+		"required":   []any{"q"},
+	}
+	tool, err := NewDynamicToolFromSpec(DynamicToolSpec{ //nolint:exhaustruct // test
+		Name:        "search",
+		Description: "Search",
+		Schema:      MapSchemaProvider(schema),
+		Handler:     handler,
+		Options:     []ToolOption{WithExamples(json.RawMessage(`{"q":"go","page":2}`), json.RawMessage(`{}`))},
+	})
+	require.NoError(t, err, "dynamic tools do not validate examples at construction")
+	assert.Equal(t, []any{map[string]any{"q": "go", "page": float64(2)}, map[string]any{}},
+		tool.Manifest().Parameters["examples"])
+}
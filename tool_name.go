@@ -0,0 +1,103 @@
+package toolsy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultToolNamePattern is the pattern tool names must match unless [WithNameValidation] replaces
+// it: 1 to 64 letters, digits, underscores, hyphens, and dots. It is the OpenAI and Anthropic limit
+// plus the dot [RegistryBuilder.Mount] joins names with.
+const DefaultToolNamePattern = `^[A-Za-z0-9_.-]{1,64}$`
+
+var defaultToolNameRegexp = regexp.MustCompile(DefaultToolNamePattern)
+
+// ErrInvalidToolName is wrapped by the errors of [ValidateToolName] and of tool constructors given
+// a name that does not match the name pattern.
+var ErrInvalidToolName = errors.New("toolsy: invalid tool name")
+
+// ValidateToolName checks name against [DefaultToolNamePattern].
+func ValidateToolName(name string) error {
+	return matchToolName(name, defaultToolNameRegexp)
+}
+
+func matchToolName(name string, re *regexp.Regexp) error {
+	if re.MatchString(name) {
+		return nil
+	}
+	if re == defaultToolNameRegexp {
+		switch {
+		case name == "":
+			return fmt.Errorf("%w: name is empty", ErrInvalidToolName)
+		case len(name) > 64:
+			return fmt.Errorf("%w %q: %d characters, the limit is 64", ErrInvalidToolName, name, len(name))
+		default:
+			return fmt.Errorf("%w %q: use only letters, digits, '_', '-' and '.'", ErrInvalidToolName, name)
+		}
+	}
+	return fmt.Errorf("%w %q: must match %s", ErrInvalidToolName, name, re)
+}
+
+// WithNameValidation replaces [DefaultToolNamePattern] for this tool, for teams whose providers
+// allow other names. A nil pattern disables the check.
+func WithNameValidation(re *regexp.Regexp) ToolOption {
+	return func(c *ToolConfig) {
+		c.NamePattern = re
+		c.SkipNameValidation = re == nil
+	}
+}
+
+// WithRequireDescription makes building the tool fail when its description is empty or blank.
+// Models choose tools by their descriptions, so an empty one is usually a mistake.
+func WithRequireDescription() ToolOption {
+	return func(c *ToolConfig) {
+		c.RequireDescription = true
+	}
+}
+
+// checkToolIdentity validates the name and description a tool is built with against cfg.
+func checkToolIdentity(cfg ToolConfig, name, description string) error {
+	if !cfg.SkipNameValidation {
+		re := cfg.NamePattern
+		if re == nil {
+			re = defaultToolNameRegexp
+		}
+		if err := matchToolName(name, re); err != nil {
+			return err
+		}
+	}
+	if cfg.RequireDescription && strings.TrimSpace(description) == "" {
+		return fmt.Errorf("toolsy: tool %q: description is required", name)
+	}
+	return nil
+}
+
+// WithToolNameValidation makes [RegistryBuilder.Build] reject tools whose names do not match re, or
+// [DefaultToolNamePattern] when re is nil. Tools built by this package already check their names;
+// this catches hand-written [Tool] implementations and names changed by middlewares.
+func WithToolNameValidation(re *regexp.Regexp) RegistryOption {
+	return func(o *registryOptions) {
+		if re == nil {
+			re = defaultToolNameRegexp
+		}
+		o.namePattern = re
+	}
+}
+
+// checkRegistryToolNames applies [WithToolNameValidation] to the names of a registry being built.
+func checkRegistryToolNames(tools map[string]Tool, re *regexp.Regexp) error {
+	if re == nil {
+		return nil
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(tools)) {
+		if err := matchToolName(name, re); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
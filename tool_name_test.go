@@ -0,0 +1,84 @@
+package toolsy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nameTestFn(context.Context, *RunEnv, struct{}) (string, error) { return "", nil }
+
+func TestValidateToolName(t *testing.T) {
+	for _, name := range []string{"search", "get-weather_v2", "billing.search", strings.Repeat("a", 64)} {
+		require.NoError(t, ValidateToolName(name), name)
+	}
+	for name, want := range map[string]string{
+		"":                      "name is empty",
+		"get weather":           `"get weather": use only letters`,
+		"ns/search":             `"ns/search": use only letters`,
+		strings.Repeat("a", 65): "65 characters, the limit is 64",
+	} {
+		err := ValidateToolName(name)
+		require.ErrorIs(t, err, ErrInvalidToolName)
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestConstructors_RejectInvalidNames(t *testing.T) {
+	_, err := NewTool("get weather", "Weather", nameTestFn)
+	require.ErrorIs(t, err, ErrInvalidToolName)
+
+	stream := func(context.Context, *RunEnv, struct{}, func(Chunk) error) error { return nil }
+	_, err = NewStreamTool("get weather", "Weather", stream)
+	require.ErrorIs(t, err, ErrInvalidToolName)
+
+	proxy := func(context.Context, *RunEnv, []byte, func(Chunk) error) error { return nil }
+	_, err = NewProxyTool("", "Proxy", []byte(`{"type":"object"}`), proxy)
+	require.ErrorIs(t, err, ErrInvalidToolName)
+
+	dynamic := func(context.Context, *RunEnv, map[string]any, func(Chunk) error) error { return nil }
+	_, err = NewDynamicToolFromSpec(DynamicToolSpec{ //nolint:exhaustruct // test
+		Name:        "a:b",
+		Description: "Dynamic",
+		Schema:      MapSchemaProvider(map[string]any{"type": "object"}),
+		Handler:     dynamic,
+	})
+	require.ErrorIs(t, err, ErrInvalidToolName)
+}
+
+func TestWithNameValidation(t *testing.T) {
+	_, err := NewTool("ns/search", "Search", nameTestFn, WithNameValidation(regexp.MustCompile(`^[a-z/]+$`)))
+	require.NoError(t, err)
+	_, err = NewTool("Search", "Search", nameTestFn, WithNameValidation(regexp.MustCompile(`^[a-z/]+$`)))
+	require.ErrorIs(t, err, ErrInvalidToolName)
+	assert.ErrorContains(t, err, "must match ^[a-z/]+$")
+
+	_, err = NewTool("any name at all", "Search", nameTestFn, WithNameValidation(nil))
+	require.NoError(t, err)
+}
+
+func TestWithRequireDescription(t *testing.T) {
+	_, err := NewTool("search", "", nameTestFn)
+	require.NoError(t, err, "empty descriptions are allowed by default")
+	_, err = NewTool("search", "  ", nameTestFn, WithRequireDescription())
+	require.ErrorContains(t, err, `tool "search": description is required`)
+}
+
+func TestWithToolNameValidation_Registry(t *testing.T) {
+	bad := minTool{manifest: ToolManifest{Name: "bad name"}, execute: nil} //nolint:exhaustruct // test
+	good := minTool{manifest: ToolManifest{Name: "good"}, execute: nil}    //nolint:exhaustruct // test
+
+	_, err := NewRegistryBuilder().Add(bad, good).Build()
+	require.NoError(t, err, "registries accept any name unless the option is set")
+
+	_, err = NewRegistryBuilder(WithToolNameValidation(nil)).Add(bad, good).Build()
+	require.ErrorIs(t, err, ErrInvalidToolName)
+	assert.ErrorContains(t, err, `"bad name"`)
+
+	_, err = NewRegistryBuilder(WithToolNameValidation(regexp.MustCompile(`^[a-z ]+$`))).Add(bad, good).Build()
+	require.NoError(t, err)
+}
@@ -0,0 +1,339 @@
+package httptool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// maxErrorBodyBytes caps how much of a 4xx response body is quoted back to the model.
+const maxErrorBodyBytes = 1024
+
+// HTTPToolConfig describes the single endpoint wrapped by [NewHTTPTool].
+type HTTPToolConfig struct {
+	// Method is the HTTP method (default GET).
+	Method string
+	// URL is the endpoint URL template; {field} placeholders are replaced with the path-escaped value of the
+	// argument whose JSON name is field, e.g. "https://api.example.com/users/{user_id}/orders".
+	URL string
+	// Query lists argument fields sent as query parameters. Every other field that is not a path placeholder
+	// goes to the JSON body for POST, PUT and PATCH, and to the query string for other methods.
+	Query []string
+	// Headers are sent with every request. Authorization headers are rejected; use toolsy.CredentialsProvider.
+	Headers map[string]string
+	// Transform rewrites a 2xx response before it is yielded as a single result chunk. It turns off streaming.
+	Transform func(status int, body []byte) ([]byte, error)
+	// ChunkBytes streams a 2xx response as result chunks of at most this many bytes (0 means one chunk).
+	ChunkBytes int
+	// MaxResponseBytes caps the response body (default 512KB); a larger body fails with CodeValidationFailed.
+	MaxResponseBytes int
+	// HTTPClient overrides the client; only its Timeout is kept on top of the SSRF-safe transport.
+	HTTPClient HTTPClient
+	// AllowPrivateIPs allows requests to private IP ranges. For testing only (e.g. httptest on 127.0.0.1).
+	AllowPrivateIPs bool
+}
+
+// NewHTTPTool wraps one REST endpoint as a typed tool: the schema comes from T as with [toolsy.NewStreamTool],
+// and each call renders cfg.URL from the arguments, sends the request and yields the response body.
+// A 4xx response is a client error quoting the status and the start of the body, so the model can adjust
+// its arguments; 429 is a rate-limit error honoring Retry-After; 5xx and transport failures are system
+// errors. The request is bound to the call context, so cancellation and deadlines abort it.
+// WithDangerous is applied to methods other than GET and HEAD; opts can add more.
+func NewHTTPTool[T any](name, desc string, cfg HTTPToolConfig, opts ...toolsy.ToolOption) (toolsy.Tool, error) {
+	ep, err := newEndpoint(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if ep.method != http.MethodGet && ep.method != http.MethodHead {
+		opts = append([]toolsy.ToolOption{toolsy.WithDangerous()}, opts...)
+	}
+	tool, err := toolsy.NewStreamTool(name, desc,
+		func(ctx context.Context, run *toolsy.RunEnv, args T, yield func(toolsy.Chunk) error) error {
+			return ep.call(ctx, run, name, args, yield)
+		}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("toolkit/httptool: build %s tool: %w", name, err)
+	}
+	return tool, nil
+}
+
+type endpoint struct {
+	cfg        HTTPToolConfig
+	method     string
+	base       *url.URL
+	pathFields []string
+	client     HTTPClient
+	maxBytes   int
+}
+
+func newEndpoint(cfg HTTPToolConfig) (*endpoint, error) {
+	if hasForbiddenHeaders(cfg.Headers) {
+		return nil, errors.New(
+			"toolkit/httptool: static Authorization headers are not allowed; use toolsy.CredentialsProvider",
+		)
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("toolkit/httptool: invalid endpoint URL %q", cfg.URL)
+	}
+	// Dials and redirects are pinned to the configured host.
+	o := options{ //nolint:exhaustruct // only the fields defaultHTTPClient reads
+		httpClient:      cfg.HTTPClient,
+		allowedDomains:  []string{u.Hostname()},
+		allowPrivateIPs: cfg.AllowPrivateIPs,
+	}
+	ep := &endpoint{
+		cfg:        cfg,
+		method:     strings.ToUpper(cfg.Method),
+		base:       u,
+		pathFields: placeholders(cfg.URL),
+		client:     defaultHTTPClient(&o),
+		maxBytes:   cfg.MaxResponseBytes,
+	}
+	if ep.method == "" {
+		ep.method = http.MethodGet
+	}
+	if ep.maxBytes <= 0 {
+		ep.maxBytes = defaultMaxResponseBody
+	}
+	return ep, nil
+}
+
+// placeholders returns the {field} names in template, in order.
+func placeholders(template string) []string {
+	var names []string
+	for {
+		i := strings.Index(template, "{")
+		if i < 0 {
+			return names
+		}
+		j := strings.Index(template[i:], "}")
+		if j < 0 {
+			return names
+		}
+		names = append(names, template[i+1:i+j])
+		template = template[i+j+1:]
+	}
+}
+
+func (e *endpoint) call(
+	ctx context.Context,
+	run *toolsy.RunEnv,
+	toolName string,
+	args any,
+	yield func(toolsy.Chunk) error,
+) error {
+	req, err := e.newRequest(ctx, args)
+	if err != nil {
+		return err
+	}
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if run.Credentials != nil {
+		authHeader, authErr := run.Credentials.GetAuth(ctx, toolName)
+		if authErr != nil {
+			return toolsy.NewInternalError(
+				fmt.Errorf("toolkit/httptool: credentials for %s: %w", toolName, authErr),
+			)
+		}
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+	}
+
+	// G704: the host is fixed by HTTPToolConfig and enforced by the SafeDialTransport whitelist.
+	resp, err := e.client.Do(req) //nolint:bodyclose // closed via CloseResponseBody
+	if err != nil {
+		return toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: do request: %w", err))
+	}
+	defer CloseResponseBody(ctx, resp.Body)
+
+	if !IsSuccessStatus(resp.StatusCode) {
+		return e.statusError(ctx, resp)
+	}
+	if e.cfg.ChunkBytes > 0 && e.cfg.Transform == nil {
+		return e.stream(ctx, resp, yield)
+	}
+	body, err := e.readBody(ctx, resp.Body)
+	if err != nil {
+		return err
+	}
+	if e.cfg.Transform != nil {
+		body, err = e.cfg.Transform(resp.StatusCode, body)
+		if err != nil {
+			return err
+		}
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return yield(toolsy.Chunk{Event: toolsy.EventResult, Data: body, MimeType: bodyMimeType(body, resp)})
+}
+
+// newRequest renders the URL and body from the JSON form of args.
+func (e *endpoint) newRequest(ctx context.Context, args any) (*http.Request, error) {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: encode args: %w", err))
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: args must be a JSON object: %w", err))
+	}
+
+	rendered := e.cfg.URL
+	for _, name := range e.pathFields {
+		v, ok := fields[name]
+		if !ok {
+			return nil, toolsy.NewValidationError("missing path parameter "+name, name)
+		}
+		rendered = strings.ReplaceAll(rendered, "{"+name+"}", url.PathEscape(fieldString(v)))
+	}
+	u, err := url.Parse(rendered)
+	if err != nil {
+		return nil, toolsy.NewValidationError("invalid path parameter: " + err.Error())
+	}
+
+	hasBody := e.method == http.MethodPost || e.method == http.MethodPut || e.method == http.MethodPatch
+	q := u.Query()
+	body := make(map[string]any)
+	for name, v := range fields {
+		switch {
+		case slices.Contains(e.pathFields, name):
+		case slices.Contains(e.cfg.Query, name) || !hasBody:
+			q.Set(name, fieldString(v))
+		default:
+			body[name] = v
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	var reqBody io.Reader
+	if hasBody {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: encode body: %w", err))
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, e.method, u.String(), reqBody)
+	if err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: new request: %w", err))
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// fieldString formats an argument value for a URL: strings as is, everything else as JSON.
+func fieldString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func (e *endpoint) readBody(ctx context.Context, r io.Reader) ([]byte, error) {
+	body, err := ReadBodyLimited(ctx, r, e.maxBytes)
+	if mapped := toolsy.MapToolkitReadError(
+		ctx, err, "toolkit/httptool: read body", e.maxBytes, "response body", "",
+	); mapped != nil {
+		return nil, mapped
+	}
+	if err != nil {
+		return nil, toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: read body: %w", err))
+	}
+	return body, nil
+}
+
+// stream yields the body in chunks of at most ChunkBytes, failing once more than MaxResponseBytes arrive.
+func (e *endpoint) stream(ctx context.Context, resp *http.Response, yield func(toolsy.Chunk) error) error {
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = toolsy.MimeTypeOctetStream
+	}
+	r := LimitStreamReaderWithContext(ctx, resp.Body, e.maxBytes)
+	buf := make([]byte, e.cfg.ChunkBytes)
+	for {
+		n, err := io.ReadFull(r, buf)
+		done := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !done {
+			if mapped := toolsy.MapToolkitReadError(
+				ctx, err, "toolkit/httptool: read body", e.maxBytes, "response body", "",
+			); mapped != nil {
+				return mapped
+			}
+			return toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: read body: %w", err))
+		}
+		if n > 0 {
+			chunk := toolsy.Chunk{Event: toolsy.EventResult, Data: bytes.Clone(buf[:n]), MimeType: mimeType}
+			if yieldErr := yield(chunk); yieldErr != nil {
+				return yieldErr
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// statusError maps a non-2xx response: 429 to a rate-limit error, other 4xx to a validation error the
+// model can act on, and everything else to an internal error.
+func (e *endpoint) statusError(ctx context.Context, resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return toolsy.NewRateLimitedError(retryAfter(resp.Header.Get("Retry-After")))
+	}
+	if resp.StatusCode < http.StatusBadRequest || resp.StatusCode >= http.StatusInternalServerError {
+		return toolsy.NewInternalError(fmt.Errorf("toolkit/httptool: %s %s: status %d",
+			e.method, e.base.Host, resp.StatusCode))
+	}
+	// The body is only quoted, so a short or failed read still produces a useful error.
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if ie := toolsy.ToolkitContextError(ctx, "toolkit/httptool: read error body"); ie != nil {
+		return ie
+	}
+	reason := fmt.Sprintf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if text := strings.TrimSpace(strings.ToValidUTF8(string(snippet), "")); text != "" {
+		reason += ": " + text
+	}
+	return toolsy.NewValidationError(reason)
+}
+
+// retryAfter parses a Retry-After header given in seconds; dates and junk yield zero.
+func retryAfter(v string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// bodyMimeType labels a complete body: JSON when it parses, text when it is UTF-8, else the response type.
+func bodyMimeType(body []byte, resp *http.Response) string {
+	switch {
+	case json.Valid(body):
+		return toolsy.MimeTypeJSON
+	case utf8.Valid(body):
+		return toolsy.MimeTypeText
+	}
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		return mt
+	}
+	return toolsy.MimeTypeOctetStream
+}
@@ -0,0 +1,196 @@
+package httptool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+type orderArgs struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status,omitempty"`
+	Note   string `json:"note,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+func runHTTPTool(t *testing.T, tool toolsy.Tool, args string) ([]toolsy.Chunk, error) {
+	t.Helper()
+	return runHTTPToolCtx(context.Background(), tool, args)
+}
+
+func runHTTPToolCtx(ctx context.Context, tool toolsy.Tool, args string) ([]toolsy.Chunk, error) {
+	var chunks []toolsy.Chunk
+	err := tool.Execute(ctx, toolsy.NewRunEnv(nil), toolsy.ToolInput{ArgsJSON: []byte(args)},
+		func(c toolsy.Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	return chunks, err
+}
+
+func TestNewHTTPTool_RendersRequest(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, `{"id":"o-1"}`)
+	}))
+	defer srv.Close()
+
+	tool, err := NewHTTPTool[orderArgs]("create_order", "Create an order", HTTPToolConfig{
+		Method:          http.MethodPost,
+		URL:             srv.URL + "/users/{user_id}/orders?v=2",
+		Query:           []string{"limit"},
+		Headers:         map[string]string{"X-Api-Version": "2"},
+		AllowPrivateIPs: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, tool.Manifest().Dangerous, "non-GET endpoints are dangerous")
+
+	chunks, err := runHTTPTool(t, tool, `{"user_id":"a b","status":"new","note":"rush","limit":5}`)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.JSONEq(t, `{"id":"o-1"}`, string(chunks[0].Data))
+	assert.Equal(t, toolsy.MimeTypeJSON, chunks[0].MimeType)
+
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/users/a%20b/orders", got.URL.EscapedPath())
+	assert.Equal(t, "5", got.URL.Query().Get("limit"))
+	assert.Equal(t, "2", got.URL.Query().Get("v"), "static query in the template is kept")
+	assert.Equal(t, "2", got.Header.Get("X-Api-Version"))
+	assert.JSONEq(t, `{"status":"new","note":"rush"}`, string(gotBody))
+}
+
+func TestNewHTTPTool_GetSendsFieldsAsQuery(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	tool, err := NewHTTPTool[orderArgs]("list_orders", "List orders", HTTPToolConfig{
+		URL: srv.URL + "/users/{user_id}/orders", AllowPrivateIPs: true,
+	})
+	require.NoError(t, err)
+	assert.False(t, tool.Manifest().Dangerous)
+	chunks, err := runHTTPTool(t, tool, `{"user_id":"u1","status":"open"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "status=open", query)
+	assert.Equal(t, toolsy.MimeTypeText, chunks[0].MimeType)
+}
+
+func TestNewHTTPTool_StatusMapping(t *testing.T) {
+	status := http.StatusUnprocessableEntity
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"error":"status must be one of open, closed"}`)
+	}))
+	defer srv.Close()
+	tool, err := NewHTTPTool[orderArgs]("list_orders", "List orders", HTTPToolConfig{
+		URL: srv.URL + "/users/{user_id}/orders", AllowPrivateIPs: true,
+	})
+	require.NoError(t, err)
+
+	_, err = runHTTPTool(t, tool, `{"user_id":"u1","status":"bogus"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation)
+	assert.Equal(t, toolsy.FinishClientError, toolsy.FinishReasonOf(err))
+	assert.Contains(t, err.Error(), "HTTP 422")
+	assert.Contains(t, err.Error(), "must be one of open, closed")
+
+	status = http.StatusTooManyRequests
+	_, err = runHTTPTool(t, tool, `{"user_id":"u1"}`)
+	te, ok := toolsy.AsToolError(err)
+	require.True(t, ok)
+	assert.Equal(t, toolsy.CodeRateLimited, te.Code)
+
+	status = http.StatusBadGateway
+	_, err = runHTTPTool(t, tool, `{"user_id":"u1"}`)
+	assert.Equal(t, toolsy.FinishSystemError, toolsy.FinishReasonOf(err))
+	assert.NotContains(t, toolsy.NewErrorChunkFromErr(err).Data, []byte("must be one of"))
+}
+
+func TestNewHTTPTool_TransformAndStream(t *testing.T) {
+	body := strings.Repeat("0123456789", 5)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	tool, err := NewHTTPTool[orderArgs]("get_report", "Report", HTTPToolConfig{
+		URL:             srv.URL + "/r/{user_id}",
+		AllowPrivateIPs: true,
+		Transform: func(status int, b []byte) ([]byte, error) {
+			out, err := json.Marshal(map[string]any{"status": status, "bytes": len(b)})
+			return out, err
+		},
+	})
+	require.NoError(t, err)
+	chunks, err := runHTTPTool(t, tool, `{"user_id":"u1"}`)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.JSONEq(t, `{"status":200,"bytes":50}`, string(chunks[0].Data))
+
+	tool, err = NewHTTPTool[orderArgs]("get_report", "Report", HTTPToolConfig{
+		URL: srv.URL + "/r/{user_id}", AllowPrivateIPs: true, ChunkBytes: 16,
+	})
+	require.NoError(t, err)
+	chunks, err = runHTTPTool(t, tool, `{"user_id":"u1"}`)
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+	var joined bytes.Buffer
+	for _, c := range chunks {
+		joined.Write(c.Data)
+	}
+	assert.Equal(t, body, joined.String())
+
+	tool, err = NewHTTPTool[orderArgs]("get_report", "Report", HTTPToolConfig{
+		URL: srv.URL + "/r/{user_id}", AllowPrivateIPs: true, ChunkBytes: 16, MaxResponseBytes: 20,
+	})
+	require.NoError(t, err)
+	_, err = runHTTPTool(t, tool, `{"user_id":"u1"}`)
+	require.ErrorIs(t, err, toolsy.ErrValidation, "oversized streams fail closed")
+}
+
+func TestNewHTTPTool_RespectsDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	tool, err := NewHTTPTool[orderArgs]("slow", "Slow", HTTPToolConfig{
+		URL: srv.URL + "/slow/{user_id}", AllowPrivateIPs: true,
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = runHTTPToolCtx(ctx, tool, `{"user_id":"u1"}`)
+	assert.Equal(t, toolsy.FinishTimeout, toolsy.FinishReasonOf(err))
+}
+
+func TestNewHTTPTool_RejectsBadConfig(t *testing.T) {
+	_, err := NewHTTPTool[orderArgs]("x", "x", HTTPToolConfig{URL: "ftp://example.com/{user_id}"})
+	require.Error(t, err)
+	_, err = NewHTTPTool[orderArgs]("x", "x", HTTPToolConfig{
+		URL: "https://example.com/{user_id}", Headers: map[string]string{"Authorization": "Bearer x"},
+	})
+	require.Error(t, err)
+}
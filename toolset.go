@@ -0,0 +1,389 @@
+package toolsy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ToolDescriber is implemented by [NewToolset] receivers that describe their methods.
+// Keys are Go method names; [WithMethodDescription] takes precedence.
+type ToolDescriber interface {
+	ToolDescriptions() map[string]string
+}
+
+// ToolsetOption configures [NewToolset].
+type ToolsetOption func(*toolsetConfig)
+
+type toolsetConfig struct {
+	prefix        string
+	naming        func(method string) string
+	descriptions  map[string]string
+	exclude       map[string]bool
+	toolOptions   []ToolOption
+	methodOptions map[string][]ToolOption
+}
+
+// WithToolsetPrefix prepends prefix to every generated tool name (e.g. "billing_").
+func WithToolsetPrefix(prefix string) ToolsetOption {
+	return func(c *toolsetConfig) {
+		c.prefix = prefix
+	}
+}
+
+// WithToolsetNaming replaces the default snake_case mapping from method name to tool name.
+// The prefix from [WithToolsetPrefix] is still applied to the result.
+func WithToolsetNaming(fn func(method string) string) ToolsetOption {
+	return func(c *toolsetConfig) {
+		c.naming = fn
+	}
+}
+
+// WithMethodDescription sets the description of the tool generated for method.
+func WithMethodDescription(method, description string) ToolsetOption {
+	return func(c *toolsetConfig) {
+		c.descriptions[method] = description
+	}
+}
+
+// WithExcludedMethods skips the named methods; they are neither exposed nor checked.
+func WithExcludedMethods(methods ...string) ToolsetOption {
+	return func(c *toolsetConfig) {
+		for _, m := range methods {
+			c.exclude[m] = true
+		}
+	}
+}
+
+// WithToolsetToolOptions applies opts to every generated tool.
+func WithToolsetToolOptions(opts ...ToolOption) ToolsetOption {
+	return func(c *toolsetConfig) {
+		c.toolOptions = append(c.toolOptions, opts...)
+	}
+}
+
+// WithMethodToolOptions applies opts to the tool generated for method, after [WithToolsetToolOptions].
+func WithMethodToolOptions(method string, opts ...ToolOption) ToolsetOption {
+	return func(c *toolsetConfig) {
+		c.methodOptions[method] = append(c.methodOptions[method], opts...)
+	}
+}
+
+// NewToolset builds one tool per exported method of receiver. Supported method shapes are
+//
+//	func(ctx context.Context, args A) (R, error)
+//	func(ctx context.Context, args A, yield func(Chunk) error) error
+//
+// optionally with env *RunEnv between ctx and args; A must be a struct. Tool names default to the
+// snake_case method name (GetUserByID becomes get_user_by_id) and descriptions come from
+// [ToolDescriber] or [WithMethodDescription]. Methods with other signatures or without a description
+// are reported together in one error unless excluded with [WithExcludedMethods]; register the result
+// with [RegistryBuilder.Add].
+func NewToolset(receiver any, opts ...ToolsetOption) ([]Tool, error) {
+	if receiver == nil {
+		return nil, errors.New("toolsy: toolset receiver is nil")
+	}
+	cfg := toolsetConfig{
+		prefix:        "",
+		naming:        snakeCase,
+		descriptions:  make(map[string]string),
+		exclude:       map[string]bool{"ToolDescriptions": true},
+		toolOptions:   nil,
+		methodOptions: make(map[string][]ToolOption),
+	}
+	var described map[string]string
+	if d, ok := receiver.(ToolDescriber); ok {
+		described = d.ToolDescriptions()
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rv := reflect.ValueOf(receiver)
+	rt := rv.Type()
+	var tools []Tool
+	var errs []error
+	seen := make(map[string]bool, rt.NumMethod())
+	for i := range rt.NumMethod() {
+		m := rt.Method(i)
+		seen[m.Name] = true
+		if cfg.exclude[m.Name] {
+			continue
+		}
+		desc := cfg.descriptions[m.Name]
+		if desc == "" {
+			desc = described[m.Name]
+		}
+		t, err := newMethodTool(cfg.prefix+cfg.naming(m.Name), desc, rv.Method(i),
+			append(slices.Clone(cfg.toolOptions), cfg.methodOptions[m.Name]...))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("toolsy: toolset method %s: %w", m.Name, err))
+			continue
+		}
+		tools = append(tools, t)
+	}
+	configured := slices.Concat(
+		slices.Collect(maps.Keys(cfg.descriptions)),
+		slices.Collect(maps.Keys(cfg.methodOptions)),
+	)
+	slices.Sort(configured)
+	for _, method := range slices.Compact(configured) {
+		if !seen[method] {
+			errs = append(errs, fmt.Errorf("toolsy: toolset option references unknown method %s", method))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tools, nil
+}
+
+//nolint:gochecknoglobals // reflect types compared against method signatures
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	runEnvType  = reflect.TypeFor[*RunEnv]()
+	errorType   = reflect.TypeFor[error]()
+	yieldType   = reflect.TypeFor[func(Chunk) error]()
+)
+
+// methodShape describes a supported method signature.
+type methodShape struct {
+	withEnv bool
+	stream  bool
+	args    reflect.Type
+	result  reflect.Type
+}
+
+func methodShapeOf(mt reflect.Type) (methodShape, error) {
+	var shape methodShape
+	in := make([]reflect.Type, mt.NumIn())
+	for i := range in {
+		in[i] = mt.In(i)
+	}
+	if len(in) > 0 && in[len(in)-1] == yieldType {
+		shape.stream = true
+		in = in[:len(in)-1]
+	}
+	if len(in) == 3 && in[1] == runEnvType {
+		shape.withEnv = true
+		in = []reflect.Type{in[0], in[2]}
+	}
+	if mt.IsVariadic() || len(in) != 2 || in[0] != contextType || in[1].Kind() != reflect.Struct {
+		return shape, fmt.Errorf("unsupported signature %s: want func(context.Context, [*RunEnv,] Args, "+
+			"[func(Chunk) error]) with a struct Args", mt)
+	}
+	shape.args = in[1]
+	switch {
+	case shape.stream && mt.NumOut() == 1 && mt.Out(0) == errorType:
+	case !shape.stream && mt.NumOut() == 2 && mt.Out(1) == errorType:
+		shape.result = mt.Out(0)
+	default:
+		return shape, fmt.Errorf("unsupported signature %s: want (Result, error), or error when streaming", mt)
+	}
+	return shape, nil
+}
+
+func newMethodTool(name, description string, method reflect.Value, opts []ToolOption) (Tool, error) {
+	shape, err := methodShapeOf(method.Type())
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(description) == "" {
+		return nil, errors.New("description is required (ToolDescriptions or WithMethodDescription)")
+	}
+	var cfg ToolConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.Schema = ensureSchemaConfig(cfg.Schema)
+	args, err := newReflectArgs(shape.args, cfg.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if !shape.stream && len(cfg.Manifest.OutputSchema) == 0 {
+		outSchema, _, genErr := generateSchemaType(shape.result, cfg.Schema)
+		if genErr != nil {
+			return nil, genErr
+		}
+		cfg.Manifest.OutputSchema = outSchema
+	}
+	if err := checkToolIdentity(cfg, name, description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, args.validate); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Manifest.SensitiveArgs = mergeSensitiveArgs(args.sensitiveArgs, cfg.Manifest.SensitiveArgs)
+
+	call := func(ctx context.Context, env *RunEnv, argv reflect.Value, extra ...reflect.Value) []reflect.Value {
+		in := []reflect.Value{reflect.ValueOf(&ctx).Elem()}
+		if shape.withEnv {
+			in = append(in, reflect.ValueOf(env))
+		}
+		return method.Call(append(append(in, argv), extra...))
+	}
+	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
+		yieldWrapped := func(c Chunk) error {
+			prepared, err := prepareChunk(c)
+			if err != nil {
+				return err
+			}
+			if err := output.check(prepared); err != nil {
+				return err
+			}
+			if err := yield(prepared); err != nil {
+				return wrapYieldError(err)
+			}
+			return nil
+		}
+		argv, err := args.parse(input.ArgsJSON)
+		if err != nil {
+			return err
+		}
+		if shape.stream {
+			return streamHandlerError(errorOf(call(ctx, env, argv, reflect.ValueOf(yieldWrapped))[0]))
+		}
+		out := call(ctx, env, argv)
+		if err := errorOf(out[1]); err != nil {
+			return wrapHandlerError(err)
+		}
+		res := out[0].Interface()
+		data, err := marshalToolResult(res)
+		if err != nil {
+			return NewInternalError(fmt.Errorf("toolsy: marshal typed result: %w", err))
+		}
+		return yieldWrapped(Chunk{
+			Event:       EventResult,
+			Data:        data,
+			MimeType:    MimeTypeJSON,
+			TypedResult: res,
+		})
+	}
+	return &tool{
+		manifest: buildToolManifest(name, description, args.schemaMap, cfg.Manifest),
+		execute:  execute,
+		validate: func(argsJSON []byte) error {
+			_, err := args.parse(argsJSON)
+			return err
+		},
+	}, nil
+}
+
+func errorOf(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	err, _ := v.Interface().(error)
+	return err
+}
+
+// streamHandlerError applies the [NewStreamTool] handler error mapping.
+func streamHandlerError(err error) error {
+	if err == nil || clientCorrectable(err) || errors.Is(err, ErrStreamAborted) || IsControlError(err) {
+		return err
+	}
+	return wrapHandlerError(err)
+}
+
+// reflectArgs is the run-time counterpart of [Extractor] for argument types known only via reflection.
+type reflectArgs struct {
+	typ           reflect.Type
+	schemaMap     map[string]any
+	validator     schemaValidator
+	allErrors     bool
+	stringCodecs  *stringDecodeNode
+	byteLimits    *byteLimitNode
+	sensitiveArgs []string
+}
+
+func newReflectArgs(typ reflect.Type, cfg SchemaConfig) (*reflectArgs, error) {
+	schemaMap, resolved, err := generateSchemaType(typ, cfg)
+	if err != nil {
+		return nil, err
+	}
+	schemaMap, resolved, err = transformCompiledSchema(schemaMap, resolved, schemaTransforms(cfg))
+	if err != nil {
+		return nil, err
+	}
+	sensitive, err := sensitiveArgPointers(typ)
+	if err != nil {
+		return nil, err
+	}
+	return &reflectArgs{
+		typ:           typ,
+		schemaMap:     schemaMap,
+		validator:     resolved,
+		allErrors:     cfg.AllValidationErrors,
+		stringCodecs:  buildStringDecodePlan(typ, cfg.Registry.buildStringCodecs(), make(map[reflect.Type]bool)),
+		byteLimits:    buildByteLimitPlan(typ, make(map[reflect.Type]bool)),
+		sensitiveArgs: sensitive,
+	}, nil
+}
+
+// validate mirrors [Extractor.Validate]: Layer 1 only.
+func (a *reflectArgs) validate(argsJSON []byte) error {
+	var tree any
+	if err := json.Unmarshal(argsJSON, &tree); err != nil {
+		return wrapJSONParseError(err)
+	}
+	if err := validateAgainstSchema(a.validator, a.schemaMap, tree, a.allErrors); err != nil {
+		return err
+	}
+	return checkByteLimits(a.byteLimits, tree, a.allErrors)
+}
+
+// parse mirrors [Extractor.ParseAndValidate] and returns the decoded struct value.
+func (a *reflectArgs) parse(argsJSON []byte) (reflect.Value, error) {
+	if err := a.validate(argsJSON); err != nil {
+		return reflect.Value{}, err
+	}
+	if a.stringCodecs != nil {
+		decoded, err := decodeCodecStrings(a.stringCodecs, argsJSON)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		argsJSON = decoded
+	}
+	ptr := reflect.New(a.typ)
+	if err := json.Unmarshal(argsJSON, ptr.Interface()); err != nil {
+		return reflect.Value{}, wrapJSONParseError(err)
+	}
+	err := validateCustom(ptr.Elem().Interface())
+	if _, ok := ptr.Elem().Interface().(Validatable); !ok {
+		err = validateCustom(ptr.Interface())
+	}
+	if err != nil {
+		if clientCorrectable(err) {
+			return reflect.Value{}, err
+		}
+		return reflect.Value{}, NewValidationError(err.Error())
+	}
+	return ptr.Elem(), nil
+}
+
+// snakeCase maps a Go identifier to snake_case, keeping initialisms together (HTTPStatus becomes http_status).
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type toolsetLookupArgs struct {
+	UserID string `json:"user_id" maxLength:"8"`
+}
+
+type toolsetUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type toolsetService struct {
+	prefix string
+}
+
+func (s *toolsetService) GetUserByID(_ context.Context, args toolsetLookupArgs) (toolsetUser, error) {
+	if args.UserID == "missing" {
+		return toolsetUser{}, errors.New("db: no rows")
+	}
+	return toolsetUser{ID: args.UserID, Name: s.prefix + args.UserID}, nil
+}
+
+func (s *toolsetService) StreamEvents(
+	_ context.Context, env *RunEnv, args toolsetLookupArgs, yield func(Chunk) error,
+) error {
+	if env == nil {
+		return errors.New("env is required")
+	}
+	for _, e := range []string{"a", "b"} {
+		if err := yield(Chunk{Event: EventProgress, Data: []byte(args.UserID + e), MimeType: MimeTypeText}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *toolsetService) ToolDescriptions() map[string]string {
+	return map[string]string{
+		"GetUserByID":  "Look up a user",
+		"StreamEvents": "Stream user events",
+	}
+}
+
+type toolsetBroken struct{ toolsetService }
+
+func (toolsetBroken) Close() error                                                   { return nil }
+func (toolsetBroken) Count(context.Context, int) (int, error)                        { return 0, nil }
+func (toolsetBroken) Undescribed(context.Context, toolsetLookupArgs) (string, error) { return "", nil }
+
+func TestNewToolset_BuildsTools(t *testing.T) {
+	t.Parallel()
+	tools, err := NewToolset(&toolsetService{prefix: "user-"}, WithToolsetPrefix("crm_"),
+		WithMethodToolOptions("GetUserByID", WithReadOnly()))
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	lookup, stream := tools[0], tools[1]
+	assert.Equal(t, "crm_get_user_by_id", lookup.Manifest().Name)
+	assert.Equal(t, "Look up a user", lookup.Manifest().Description)
+	assert.True(t, lookup.Manifest().ReadOnly)
+	assert.Contains(t, lookup.Manifest().OutputSchema["properties"], "name")
+	assert.Equal(t, "crm_stream_events", stream.Manifest().Name)
+	assert.False(t, stream.Manifest().ReadOnly)
+
+	reg, err := NewRegistryBuilder().Add(tools...).Build()
+	require.NoError(t, err)
+	var chunks []Chunk
+	collect := func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	}
+	call := ToolCall{ToolName: "crm_get_user_by_id", Input: ToolInput{ArgsJSON: []byte(`{"user_id":"u1"}`)}}
+	require.NoError(t, reg.Execute(context.Background(), call, collect))
+	require.Len(t, chunks, 1)
+	assert.JSONEq(t, `{"id":"u1","name":"user-u1"}`, string(chunks[0].Data))
+
+	call.Input.ArgsJSON = []byte(`{"user_id":"much-too-long"}`)
+	require.ErrorIs(t, reg.Execute(context.Background(), call, collect), ErrValidation)
+
+	call.Input.ArgsJSON = []byte(`{"user_id":"missing"}`)
+	err = reg.Execute(context.Background(), call, collect)
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err))
+
+	chunks = nil
+	call = ToolCall{ToolName: "crm_stream_events", Input: ToolInput{ArgsJSON: []byte(`{"user_id":"u2"}`)}}
+	require.NoError(t, reg.Execute(context.Background(), call, collect))
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "u2b", string(chunks[1].Data))
+}
+
+func TestNewToolset_AggregatesErrors(t *testing.T) {
+	t.Parallel()
+	_, err := NewToolset(&toolsetBroken{}, WithMethodDescription("Missing", "x"))
+	require.Error(t, err)
+	for _, want := range []string{"method Close", "method Count", "method Undescribed", "unknown method Missing"} {
+		assert.ErrorContains(t, err, want)
+	}
+
+	tools, err := NewToolset(&toolsetBroken{}, WithExcludedMethods("Close", "Count"),
+		WithMethodDescription("Undescribed", "Now described"),
+		WithToolsetNaming(func(m string) string { return "x_" + snakeCase(m) }))
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+	assert.Equal(t, "x_undescribed", tools[2].Manifest().Name)
+}
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]string{
+		"GetUserByID": "get_user_by_id",
+		"HTTPStatus":  "http_status",
+		"List2Items":  "list2_items",
+		"Ping":        "ping",
+	} {
+		assert.Equal(t, want, snakeCase(in), in)
+	}
+}
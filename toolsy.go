@@ -2,10 +2,13 @@ package toolsy
 
 import (
 	"context"
+	"maps"
+	"time"
 )
 
 // EventType enumerates chunk event kinds for Chunk: EventProgress for intermediate UI status,
-// EventResult for final data or a stream chunk; EventControl for orchestrator-managed signals.
+// EventResult for final data or a stream chunk; EventControl for orchestrator-managed signals;
+// EventDone for the registry's terminal marker ([WithFinalChunk]).
 type EventType string
 
 const (
@@ -101,18 +104,27 @@ func (in ToolInput) Clone() ToolInput {
 
 // ToolCall is a single execution request (as produced by the LLM).
 type ToolCall struct {
-	ToolName    string
-	Input       ToolInput
-	Env         *RunEnv
-	CallContext CallContext
+	ToolName string
+	Input    ToolInput
+	// ArgsEncoding names the [ArgsCodec] that converts Input.ArgsJSON to JSON (for example "form").
+	// Empty means the payload is already JSON. The registry clears it after decoding.
+	ArgsEncoding string
+	Env          *RunEnv
+	CallContext  CallContext
+	// Metadata carries caller-owned correlation data (conversation, user, or trace IDs). The registry
+	// copies it once per call, passes it to every hook, merges it into each forwarded [Chunk.Metadata],
+	// and reports it in [ExecutionSummary.Metadata]; the caller's map is never modified.
+	Metadata map[string]any
 }
 
 func cloneToolCall(call ToolCall) ToolCall {
 	return ToolCall{
-		ToolName:    call.ToolName,
-		Input:       call.Input.Clone(),
-		Env:         call.Env,
-		CallContext: cloneCallContext(call.CallContext),
+		ToolName:     call.ToolName,
+		Input:        call.Input.Clone(),
+		ArgsEncoding: call.ArgsEncoding,
+		Env:          call.Env,
+		CallContext:  cloneCallContext(call.CallContext),
+		Metadata:     maps.Clone(call.Metadata),
 	}
 }
 
@@ -124,10 +136,17 @@ type ProgressInfo struct {
 	Label   string
 	Status  string
 	Token   string
+	// RetryAfter is set by [StatusChunk] for phases that wait before the next attempt.
+	RetryAfter time.Duration
 }
 
 // Chunk is a single stream event from a tool execution.
 // Data-plane payloads use Data/MimeType. Control-plane signals use EventControl + Control.
+//
+// Ownership: before delivery the registry copies Progress and Envelope (including Envelope.Metadata),
+// so tools may reuse those values between yields. Data, Effects, Controls, and TypedResult are forwarded
+// as-is: after yield returns, consumers own them and the tool must not mutate them. Allocate a fresh
+// buffer per chunk or copy with [CloneMetadata]; [WithStrictChunkOwnership] reports violations in tests.
 type Chunk struct {
 	CallID   string
 	ToolName string
@@ -151,6 +170,15 @@ type Chunk struct {
 	Progress *ProgressInfo
 	// Envelope classifies structured result/error payloads for downstream delivery.
 	Envelope *ToolEnvelope
+	// Seq is the zero-based position of the chunk among the chunks delivered for its call, and
+	// Timestamp is when the registry forwarded it. The registry always assigns both, overwriting
+	// values set by the tool; chunks from direct [Tool.Execute] calls keep a zero Timestamp.
+	Seq       int
+	Timestamp time.Time
+	// Metadata carries per-call correlation data. The registry adds [ToolCall.Metadata] to every
+	// forwarded chunk; on a key conflict the value the tool set wins. Treat it as read-only: chunks
+	// without tool metadata share the call's copy.
+	Metadata map[string]any
 }
 
 // ToolEnvelope returns a typed delivery envelope for this chunk.
@@ -164,16 +192,48 @@ func (c Chunk) ToolEnvelope() ToolEnvelope {
 	return *NewResultEnvelope(c.TypedResult, c.Data, c.MimeType, "", "", nil)
 }
 
+// ChunkProgress carries running totals for one call, passed to [WithOnChunkProgress].
+// Index is zero-based and counts delivered non-error chunks; BytesSoFar includes the current chunk.
+type ChunkProgress struct {
+	Index      int
+	BytesSoFar int64
+	Elapsed    time.Duration
+	FirstChunk bool
+}
+
 // ExecutionSummary is passed to the after-execution hook (WithOnAfterExecute) when a tool
 // execution finishes (success or error). ChunksDelivered and TotalBytes count only chunks
-// with !IsError (successfully delivered result chunks). ErrorChunks and LastErrorText
-// describe delivered soft errors (chunks with IsError=true).
+// with !IsError (successfully delivered result chunks). ProgressChunks counts delivered
+// [EventProgress] chunks such as [StatusChunk]. MetadataBytes is the JSON-encoded size of
+// Envelope.Metadata plus tool-set [Chunk.Metadata] across all delivered chunks. ErrorChunks and LastErrorText
+// describe delivered soft errors (chunks with IsError=true). NoResult reports that a delivered
+// result chunk was a [NoResult] ("nothing found"), for hit-rate analytics of search-style tools.
 type ExecutionSummary struct {
 	CallID          string
 	ToolName        string
 	Error           error
 	ChunksDelivered int
 	TotalBytes      int64
+	ProgressChunks  int
+	MetadataBytes   int64
 	ErrorChunks     int
 	LastErrorText   string
+	NoResult        bool
+	// Deprecated reports that the called tool is marked [WithDeprecated].
+	Deprecated bool
+	// Metadata is the call's copy of [ToolCall.Metadata].
+	Metadata map[string]any
+	// StartedAt is when the registry accepted the call; the duration passed to [WithOnAfterExecute]
+	// is measured from it. ExecDuration is the part spent inside the tool's Execute, after argument
+	// decoding, hooks, and policy checks (zero when the call was rejected before the tool ran).
+	// The registry never queues calls ([WithLoadShedding] rejects them), so there is no queue wait.
+	StartedAt    time.Time
+	ExecDuration time.Duration
+	// FinishReason classifies how the execution ended, derived from Error once panic recovery has
+	// run; see [FinishReason] for the mapping. In [Registry.ExecuteBatchStream] it keeps the
+	// classification of an error that was delivered as a soft error chunk.
+	FinishReason FinishReason
+	// PanicStack is the stack trace of a panic recovered by the registry (see [PanicStack]), for
+	// logs only; it is nil for other outcomes or with [WithPanicStacks] disabled.
+	PanicStack []byte
 }
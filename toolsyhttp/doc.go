@@ -0,0 +1,10 @@
+// Package toolsyhttp exposes a [toolsy.Registry] over HTTP and carries chunk streams between processes.
+//
+// [NewSSEHandler] serves POST /tools/{name}: the request body carries the tool arguments and
+// every chunk the call produces is written as a Server-Sent Event, flushed as soon as it is
+// yielded. Failures that happen before the first chunk map to an HTTP status; later failures
+// arrive as a final "error" event.
+//
+// [NewNDJSONYield] writes chunks as newline-delimited JSON, and [NDJSONDecoder] reads them back so
+// another registry can re-yield them.
+package toolsyhttp
@@ -0,0 +1,217 @@
+package toolsyhttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// MediaTypeNDJSON is the Content-Type of a stream written by [NewNDJSONYield].
+const MediaTypeNDJSON = "application/x-ndjson"
+
+// dataEncodingBase64 marks a line whose data is not valid UTF-8 and was base64-encoded.
+const dataEncodingBase64 = "base64"
+
+type ndjsonChunk struct {
+	Seq          int64            `json:"seq"`
+	CallSeq      int              `json:"call_seq"`
+	Timestamp    string           `json:"ts,omitempty"`
+	CallID       string           `json:"call_id,omitempty"`
+	ToolName     string           `json:"tool_name,omitempty"`
+	Event        toolsy.EventType `json:"event"`
+	Data         string           `json:"data,omitempty"`
+	DataEncoding string           `json:"data_encoding,omitempty"`
+	MimeType     string           `json:"mime_type,omitempty"`
+	IsError      bool             `json:"is_error,omitempty"`
+	Progress     *ndjsonProgress  `json:"progress,omitempty"`
+	Metadata     map[string]any   `json:"metadata,omitempty"`
+}
+
+type ndjsonProgress struct {
+	Percent    *int          `json:"percent,omitempty"`
+	Total      *int          `json:"total,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	Label      string        `json:"label,omitempty"`
+	Status     string        `json:"status,omitempty"`
+	Token      string        `json:"token,omitempty"`
+	RetryAfter time.Duration `json:"retry_after_ns,omitempty"`
+}
+
+// NewNDJSONYield returns a yield callback that writes each chunk to w as one JSON line:
+//
+//	{"seq":1,"call_seq":0,"ts":"2026-01-02T15:04:05.123Z","call_id":"c1","tool_name":"search",
+//	 "event":"result","data":"...","mime_type":"text/plain"}
+//
+// seq counts lines from 1; call_seq and ts are the [toolsy.Chunk] Seq and Timestamp assigned by the
+// registry (ts is omitted when zero). Data is written as a string when it is valid UTF-8 and base64-encoded
+// with "data_encoding":"base64" otherwise. is_error, progress and metadata appear when set; typed
+// results, effects, controls and envelopes stay in process. Each line is a single Write, followed
+// by a flush when w is an [http.Flusher] or has a Flush() error method. A write or flush error is
+// returned, so the registry aborts the call with [toolsy.ErrStreamAborted].
+//
+// The callback is not safe for concurrent use; the registry serializes yields, including in
+// [toolsy.Registry.ExecuteBatchStream].
+func NewNDJSONYield(w io.Writer) func(toolsy.Chunk) error {
+	var seq int64
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	return func(c toolsy.Chunk) error {
+		seq++
+		buf.Reset()
+		if err := enc.Encode(toNDJSONChunk(seq, c)); err != nil {
+			return fmt.Errorf("toolsyhttp: encode chunk: %w", err)
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		return flushWriter(w)
+	}
+}
+
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case http.Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		return f.Flush()
+	}
+	return nil
+}
+
+func toNDJSONChunk(seq int64, c toolsy.Chunk) ndjsonChunk {
+	line := ndjsonChunk{
+		Seq:          seq,
+		CallSeq:      c.Seq,
+		Timestamp:    "",
+		CallID:       c.CallID,
+		ToolName:     c.ToolName,
+		Event:        c.Event,
+		Data:         "",
+		DataEncoding: "",
+		MimeType:     c.MimeType,
+		IsError:      c.IsError,
+		Progress:     nil,
+		Metadata:     c.Metadata,
+	}
+	if !c.Timestamp.IsZero() {
+		line.Timestamp = c.Timestamp.Format(time.RFC3339Nano)
+	}
+	if utf8.Valid(c.Data) {
+		line.Data = string(c.Data)
+	} else {
+		line.Data = base64.StdEncoding.EncodeToString(c.Data)
+		line.DataEncoding = dataEncodingBase64
+	}
+	if p := c.Progress; p != nil {
+		line.Progress = &ndjsonProgress{
+			Percent:    p.Percent,
+			Total:      p.Total,
+			Message:    p.Message,
+			Label:      p.Label,
+			Status:     p.Status,
+			Token:      p.Token,
+			RetryAfter: p.RetryAfter,
+		}
+	}
+	return line
+}
+
+// NDJSONDecoder reads chunks written by [NewNDJSONYield].
+type NDJSONDecoder struct {
+	dec  *json.Decoder
+	last int64
+}
+
+// NewNDJSONDecoder returns a decoder reading from r.
+func NewNDJSONDecoder(r io.Reader) *NDJSONDecoder {
+	return &NDJSONDecoder{dec: json.NewDecoder(r), last: 0}
+}
+
+// Decode returns the next chunk, or [io.EOF] at the end of the stream. Lines must arrive in
+// sequence; a missing or repeated seq is an error. Metadata numbers decode as float64.
+func (d *NDJSONDecoder) Decode() (toolsy.Chunk, error) {
+	var line ndjsonChunk
+	if err := d.dec.Decode(&line); err != nil {
+		if errors.Is(err, io.EOF) {
+			return toolsy.Chunk{}, io.EOF
+		}
+		return toolsy.Chunk{}, fmt.Errorf("toolsyhttp: decode chunk: %w", err)
+	}
+	if line.Seq != d.last+1 {
+		return toolsy.Chunk{}, fmt.Errorf("toolsyhttp: chunk seq %d out of order, want %d", line.Seq, d.last+1)
+	}
+	d.last = line.Seq
+	return fromNDJSONChunk(line)
+}
+
+// ForwardNDJSON decodes every chunk from r and passes it to yield, so a proxy tool can re-yield a
+// remote stream. It returns nil at the end of the stream and stops at the first decode or yield error.
+func ForwardNDJSON(r io.Reader, yield func(toolsy.Chunk) error) error {
+	dec := NewNDJSONDecoder(r)
+	for {
+		c, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := yield(c); err != nil {
+			return err
+		}
+	}
+}
+
+func fromNDJSONChunk(line ndjsonChunk) (toolsy.Chunk, error) {
+	c := toolsy.Chunk{ //nolint:exhaustruct // in-process fields are not part of the wire
+		CallID:   line.CallID,
+		ToolName: line.ToolName,
+		Event:    line.Event,
+		MimeType: line.MimeType,
+		IsError:  line.IsError,
+		Seq:      line.CallSeq,
+		Metadata: line.Metadata,
+	}
+	if line.Timestamp != "" {
+		ts, err := time.Parse(time.RFC3339Nano, line.Timestamp)
+		if err != nil {
+			return toolsy.Chunk{}, fmt.Errorf("toolsyhttp: decode chunk %d timestamp: %w", line.Seq, err)
+		}
+		c.Timestamp = ts
+	}
+	switch line.DataEncoding {
+	case "":
+		if line.Data != "" {
+			c.Data = []byte(line.Data)
+		}
+	case dataEncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(line.Data)
+		if err != nil {
+			return toolsy.Chunk{}, fmt.Errorf("toolsyhttp: decode chunk %d data: %w", line.Seq, err)
+		}
+		c.Data = data
+	default:
+		return toolsy.Chunk{}, fmt.Errorf("toolsyhttp: chunk %d: unknown data encoding %q", line.Seq, line.DataEncoding)
+	}
+	if p := line.Progress; p != nil {
+		c.Progress = &toolsy.ProgressInfo{
+			Percent:    p.Percent,
+			Total:      p.Total,
+			Message:    p.Message,
+			Label:      p.Label,
+			Status:     p.Status,
+			Token:      p.Token,
+			RetryAfter: p.RetryAfter,
+		}
+	}
+	return c, nil
+}
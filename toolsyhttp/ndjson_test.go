@@ -0,0 +1,122 @@
+package toolsyhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy"
+)
+
+func TestNDJSON_RoundTrip(t *testing.T) {
+	percent := 40
+	chunks := []toolsy.Chunk{
+		toolsy.StatusChunk(toolsy.Status{Phase: toolsy.StatusRateLimited, RetryAfter: 2 * time.Second}),
+		{Event: toolsy.EventProgress, Progress: &toolsy.ProgressInfo{Percent: &percent, Message: "indexing"}},
+		{CallID: "c1", ToolName: "img", Event: toolsy.EventResult, Data: []byte{0xff, 0x00, 0x89}, MimeType: "image/png"},
+		{Event: toolsy.EventResult, Data: []byte(`{"a":"<b>"}`), MimeType: toolsy.MimeTypeJSON,
+			Metadata: map[string]any{"trace": "t1", "n": float64(3)},
+			Seq:      3, Timestamp: time.Date(2026, 1, 2, 15, 4, 5, 123, time.UTC)},
+		toolsy.NewErrorChunkFromErr(toolsy.NewValidationError("bad limit", "limit")),
+	}
+	rec := httptest.NewRecorder()
+	yield := NewNDJSONYield(rec)
+	for _, c := range chunks {
+		require.NoError(t, yield(c))
+	}
+	assert.True(t, rec.Flushed)
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	require.Len(t, lines, len(chunks))
+	assert.Contains(t, lines[2], `"data":"/wCJ","data_encoding":"base64"`)
+	assert.Contains(t, lines[3], `"data":"{\"a\":\"<b>\"}"`)
+	assert.Contains(t, lines[4], `"seq":5`)
+	assert.NotContains(t, lines[4], `"ts"`)
+	assert.Contains(t, lines[3], `"call_seq":3,"ts":"2026-01-02T15:04:05.000000123Z"`)
+
+	dec := NewNDJSONDecoder(rec.Body)
+	for i, want := range chunks {
+		got, err := dec.Decode()
+		require.NoError(t, err, "chunk %d", i)
+		assert.Equal(t, want.CallID, got.CallID)
+		assert.Equal(t, want.ToolName, got.ToolName)
+		assert.Equal(t, want.Event, got.Event)
+		assert.Equal(t, want.Data, got.Data)
+		assert.Equal(t, want.MimeType, got.MimeType)
+		assert.Equal(t, want.IsError, got.IsError)
+		assert.Equal(t, want.Progress, got.Progress)
+		assert.Equal(t, want.Metadata, got.Metadata)
+		assert.Equal(t, want.Seq, got.Seq)
+		assert.True(t, want.Timestamp.Equal(got.Timestamp))
+	}
+	_, err := dec.Decode()
+	require.ErrorIs(t, err, io.EOF)
+
+	status, ok := toolsy.StatusFromChunk(chunks[0])
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, status.RetryAfter)
+}
+
+func TestNDJSONDecoder_RejectsOutOfOrder(t *testing.T) {
+	dec := NewNDJSONDecoder(strings.NewReader(`{"seq":1,"event":"result"}` + "\n" + `{"seq":3,"event":"result"}` + "\n"))
+	_, err := dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+	require.ErrorContains(t, err, "out of order")
+
+	_, err = NewNDJSONDecoder(strings.NewReader(`{"seq":1,"data":"x","data_encoding":"hex"}`)).Decode()
+	require.ErrorContains(t, err, "unknown data encoding")
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestNDJSONYield_WriteErrorAbortsStream(t *testing.T) {
+	reg := newTestRegistry(t)
+	broken := errors.New("broken pipe")
+	err := reg.Execute(context.Background(), toolsy.ToolCall{
+		ToolName: "echo",
+		Input:    toolsy.ToolInput{CallID: "c1", ArgsJSON: []byte(`{"text":"hi"}`)},
+	}, NewNDJSONYield(failingWriter{err: broken}))
+	require.ErrorIs(t, err, toolsy.ErrStreamAborted)
+	require.ErrorIs(t, err, broken)
+}
+
+func TestForwardNDJSON_ReyieldsThroughDownstreamRegistry(t *testing.T) {
+	upstream := newTestRegistry(t)
+	var wire bytes.Buffer
+	require.NoError(t, upstream.Execute(context.Background(), toolsy.ToolCall{
+		ToolName: "echo",
+		Input:    toolsy.ToolInput{CallID: "up-1", ArgsJSON: []byte(`{"text":"remote"}`)},
+	}, NewNDJSONYield(&wire)))
+
+	remote, err := toolsy.NewProxyTool("remote_echo", "Remote echo", []byte(`{"type":"object"}`),
+		func(_ context.Context, _ *toolsy.RunEnv, _ []byte, yield func(toolsy.Chunk) error) error {
+			return ForwardNDJSON(bytes.NewReader(wire.Bytes()), yield)
+		})
+	require.NoError(t, err)
+	downstream, err := toolsy.NewRegistryBuilder().Add(remote).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = downstream.Shutdown(context.Background()) })
+
+	var got []toolsy.Chunk
+	require.NoError(t, downstream.Execute(context.Background(), toolsy.ToolCall{
+		ToolName: "remote_echo",
+		Input:    toolsy.ToolInput{CallID: "down-1", ArgsJSON: []byte(`{}`)},
+	}, func(c toolsy.Chunk) error {
+		got = append(got, c)
+		return nil
+	}))
+	require.Len(t, got, 2)
+	assert.Equal(t, toolsy.EventProgress, got[0].Event)
+	assert.Equal(t, "remote", string(got[1].Data))
+	assert.Equal(t, "up-1", got[1].CallID, "chunks keep the call ID they were streamed with")
+}
@@ -0,0 +1,54 @@
+package toolsyhttp
+
+import (
+	"log/slog"
+	"time"
+)
+
+// defaultMaxBodyBytes caps request bodies at 1 MiB unless [WithMaxBodyBytes] says otherwise.
+const defaultMaxBodyBytes = 1 << 20
+
+// Option configures [NewSSEHandler].
+type Option func(*options)
+
+type options struct {
+	timeout      time.Duration
+	maxBodyBytes int64
+	logger       *slog.Logger
+}
+
+func defaultOptions() options {
+	return options{
+		timeout:      0,
+		maxBodyBytes: defaultMaxBodyBytes,
+		logger:       slog.Default(),
+	}
+}
+
+// WithTimeout bounds each request's execution with [context.WithTimeout]. Zero or negative means
+// no limit beyond the request context's own deadline; the registry never adds one.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithMaxBodyBytes sets the largest accepted request body (default 1 MiB); larger bodies get
+// 413 Request Entity Too Large. Zero or negative keeps the default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxBodyBytes = n
+		}
+	}
+}
+
+// WithLogger sets the logger that records the details of system errors hidden from the client
+// (default [slog.Default]).
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
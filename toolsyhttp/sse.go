@@ -0,0 +1,226 @@
+package toolsyhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// MediaTypeToolCall is the request Content-Type for a tool call envelope instead of bare arguments.
+// The envelope is a JSON object with "arguments" (the tool arguments), and optional "call_id" and
+// "metadata" ([toolsy.ToolCall.Metadata]).
+const MediaTypeToolCall = "application/vnd.toolsy.tool-call+json"
+
+type callEnvelope struct {
+	CallID    string          `json:"call_id,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Metadata  map[string]any  `json:"metadata,omitempty"`
+}
+
+// NewSSEHandler returns a handler serving POST /tools/{name}. The body holds the JSON arguments
+// for the named tool, or a [MediaTypeToolCall] envelope; an empty body means {}. The call runs
+// through [toolsy.Registry.Execute] and each chunk is written as one Server-Sent Event and flushed:
+//
+//	id: <call_id>-<seq>
+//	event: progress | result | control | error
+//	data: <Chunk.Data, one data line per line>
+//
+// Error chunks use the "error" event. When the client disconnects, the next yield fails, so the
+// tool's stream aborts with [toolsy.ErrStreamAborted].
+//
+// A call that fails before its first chunk gets a status code and a [toolsy.MimeTypeToolErrorJSON]
+// body: 400 for argument errors, 403 for denied calls, 404 for unknown tools, 429 when rate
+// limited, 503 when overloaded or shutting down, and 504 on timeout, each with the message the
+// model would see. Everything else is a 500 with a generic body; the details go to the logger.
+// A call that fails after streaming started ends with an "error" event carrying the same body.
+func NewSSEHandler(reg *toolsy.Registry, opts ...Option) http.Handler {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	h := &sseHandler{reg: reg, opts: o}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /tools/{name}", h.serveCall)
+	return mux
+}
+
+type sseHandler struct {
+	reg  *toolsy.Registry
+	opts options
+}
+
+func (h *sseHandler) serveCall(w http.ResponseWriter, r *http.Request) {
+	call, err := readToolCall(w, r, h.opts.maxBodyBytes)
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeErrorResponse(w, status, toolsy.NewErrorChunkFromErr(toolsy.NewSchemaError(err.Error())).Data)
+		return
+	}
+	ctx := r.Context()
+	if h.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.opts.timeout)
+		defer cancel()
+	}
+	stream := &sseStream{w: w, rc: http.NewResponseController(w), callID: call.Input.CallID, seq: 0, started: false}
+	execErr := h.reg.Execute(ctx, call, func(c toolsy.Chunk) error {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		return stream.writeChunk(c)
+	})
+	if execErr == nil || r.Context().Err() != nil || errors.Is(execErr, toolsy.ErrStreamAborted) {
+		// Success, or the client is gone and there is nobody left to tell.
+		stream.start()
+		return
+	}
+	status, body := errorResponse(execErr)
+	if status == http.StatusInternalServerError {
+		cause := execErr
+		if te, ok := toolsy.AsToolError(execErr); ok && te.Err != nil {
+			cause = te.Err
+		}
+		h.opts.logger.ErrorContext(ctx, "toolsyhttp: tool call failed",
+			"tool", call.ToolName, "error", execErr, "cause", cause)
+	}
+	if !stream.started {
+		writeErrorResponse(w, status, body)
+		return
+	}
+	_ = stream.writeEvent("error", body)
+}
+
+// readToolCall builds the call for the {name} path value from the request body.
+func readToolCall(w http.ResponseWriter, r *http.Request, maxBodyBytes int64) (toolsy.ToolCall, error) {
+	call := toolsy.ToolCall{ToolName: r.PathValue("name")} //nolint:exhaustruct // filled from the body below
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		return call, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == MediaTypeToolCall {
+		var env callEnvelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			return call, errors.New("invalid tool call envelope")
+		}
+		call.Input.CallID = env.CallID
+		call.Metadata = env.Metadata
+		body = env.Arguments
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte(`{}`)
+	}
+	call.Input.ArgsJSON = body
+	return call, nil
+}
+
+// errorResponse maps an execution error to a status code and a tool error body. System errors
+// are replaced by a generic internal error so no details reach the client.
+func errorResponse(err error) (int, []byte) {
+	status := http.StatusInternalServerError
+	switch toolsy.FinishReasonOf(err) {
+	case toolsy.FinishNotFound:
+		status = http.StatusNotFound
+	case toolsy.FinishClientError:
+		status = clientErrorStatus(err)
+	case toolsy.FinishTimeout:
+		status = http.StatusGatewayTimeout
+	case toolsy.FinishShutdown:
+		status = http.StatusServiceUnavailable
+	default:
+		if errors.Is(err, toolsy.ErrOverloaded) {
+			status = http.StatusServiceUnavailable
+			break
+		}
+		err = toolsy.NewInternalError(err)
+	}
+	return status, toolsy.NewErrorChunkFromErr(err).Data
+}
+
+func clientErrorStatus(err error) int {
+	te, _ := toolsy.AsToolError(err)
+	switch te.Code {
+	case toolsy.CodeRateLimited:
+		return http.StatusTooManyRequests
+	case toolsy.CodePolicyDenied, toolsy.CodeCapabilityDenied, toolsy.CodeConfirmationDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", toolsy.MimeTypeToolErrorJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// sseStream writes chunks of one call as Server-Sent Events. Headers go out with the first event,
+// so a call that fails before yielding can still answer with an error status.
+type sseStream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	callID  string
+	seq     int
+	started bool
+}
+
+func (s *sseStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	h := s.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *sseStream) writeChunk(c toolsy.Chunk) error {
+	event := string(c.Event)
+	switch {
+	case c.IsError:
+		event = "error"
+	case event == "":
+		event = string(toolsy.EventResult)
+	}
+	return s.writeEvent(event, c.Data)
+}
+
+func (s *sseStream) writeEvent(event string, data []byte) error {
+	s.start()
+	s.seq++
+	id := strconv.Itoa(s.seq)
+	if s.callID != "" {
+		id = s.callID + "-" + id
+	}
+	var buf bytes.Buffer
+	buf.WriteString("id: " + id + "\nevent: " + event + "\n")
+	// SSE treats CR, LF and CRLF alike as line ends; each line of data needs its own field.
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	if _, err := s.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package toolsyhttp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/skosovsky/toolsy"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type echoArgs struct {
+	Text  string `json:"text,omitempty"`
+	Limit int    `json:"limit,omitempty" maximum:"10"`
+}
+
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+func newTestRegistry(t *testing.T, tools ...toolsy.Tool) *toolsy.Registry {
+	t.Helper()
+	echo, err := toolsy.NewStreamTool("echo", "Echo", func(
+		_ context.Context, _ *toolsy.RunEnv, a echoArgs, yield func(toolsy.Chunk) error,
+	) error {
+		if err := yield(textChunk(toolsy.EventProgress, "working")); err != nil {
+			return err
+		}
+		return yield(textChunk(toolsy.EventResult, a.Text))
+	})
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistryBuilder().Add(append(tools, echo)...).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Shutdown(context.Background()) })
+	return reg
+}
+
+func textChunk(event toolsy.EventType, data string) toolsy.Chunk {
+	return toolsy.Chunk{Event: event, Data: []byte(data), MimeType: "text/plain"}
+}
+
+func parseSSE(t *testing.T, body io.Reader) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var cur sseEvent
+	var data []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			cur.ID = value
+		case "event":
+			cur.Event = value
+		case "data":
+			data = append(data, value)
+		case "":
+			cur.Data = strings.Join(data, "\n")
+			events = append(events, cur)
+			cur, data = sseEvent{}, nil
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func post(h http.Handler, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSSEHandler_StreamsChunks(t *testing.T) {
+	h := NewSSEHandler(newTestRegistry(t))
+
+	rec := post(h, "/tools/echo", "application/json", `{"text":"line 1\nline 2"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, []sseEvent{
+		{ID: "1", Event: "progress", Data: "working"},
+		{ID: "2", Event: "result", Data: "line 1\nline 2"},
+	}, parseSSE(t, rec.Body))
+
+	rec = post(h, "/tools/echo", MediaTypeToolCall, `{"call_id":"c7","arguments":{"text":"hi"}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	events := parseSSE(t, rec.Body)
+	require.Len(t, events, 2)
+	assert.Equal(t, "c7-2", events[1].ID)
+	assert.Equal(t, "hi", events[1].Data)
+
+	rec = post(h, "/tools/echo", "", "")
+	require.Equal(t, http.StatusOK, rec.Code, "an empty body means no arguments")
+
+	req := httptest.NewRequest(http.MethodGet, "/tools/echo", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestSSEHandler_ErrorStatus(t *testing.T) {
+	secret := errors.New("db password rejected")
+	fail, err := toolsy.NewTool("fail", "Fail", func(context.Context, *toolsy.RunEnv, struct{}) (string, error) {
+		return "", secret
+	})
+	require.NoError(t, err)
+	var logs bytes.Buffer
+	h := NewSSEHandler(newTestRegistry(t, fail), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	rec := post(h, "/tools/echo", "application/json", `{"text":"x","limit":50}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, toolsy.MimeTypeToolErrorJSON, rec.Header().Get("Content-Type"))
+	var wire map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &wire))
+	assert.Equal(t, "VALIDATION_FAILED", wire["code"])
+	assert.Contains(t, wire["message"], "limit")
+
+	rec = post(h, "/tools/echo", "application/json", `{"text":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(h, "/tools/missing", "application/json", `{}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = post(h, "/tools/fail", "application/json", `{}`)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "password")
+	assert.Contains(t, rec.Body.String(), "INTERNAL")
+	assert.Contains(t, logs.String(), "db password rejected", "details are logged, not sent")
+
+	rec = post(h, "/tools/echo", MediaTypeToolCall, `not json`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSSEHandler_ErrorAfterFirstChunkIsEvent(t *testing.T) {
+	tool, err := toolsy.NewStreamTool("half", "Half", func(
+		_ context.Context, _ *toolsy.RunEnv, _ struct{}, yield func(toolsy.Chunk) error,
+	) error {
+		if err := yield(textChunk(toolsy.EventProgress, "started")); err != nil {
+			return err
+		}
+		return errors.New("connection reset by upstream")
+	})
+	require.NoError(t, err)
+	h := NewSSEHandler(newTestRegistry(t, tool), WithLogger(slog.New(slog.DiscardHandler)))
+
+	rec := post(h, "/tools/half", "application/json", `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	events := parseSSE(t, rec.Body)
+	require.Len(t, events, 2)
+	assert.Equal(t, "error", events[1].Event)
+	assert.Contains(t, events[1].Data, "INTERNAL")
+	assert.NotContains(t, events[1].Data, "upstream")
+}
+
+func TestSSEHandler_Limits(t *testing.T) {
+	slow, err := toolsy.NewTool("slow", "Slow", func(ctx context.Context, _ *toolsy.RunEnv, _ struct{}) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	require.NoError(t, err)
+	h := NewSSEHandler(newTestRegistry(t, slow), WithMaxBodyBytes(16), WithTimeout(20*time.Millisecond))
+
+	rec := post(h, "/tools/echo", "application/json", `{"text":"far too long for the limit"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = post(h, "/tools/slow", "application/json", `{}`)
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+}
+
+func TestSSEHandler_ClientDisconnectAbortsTool(t *testing.T) {
+	aborted := make(chan error, 1)
+	endless, err := toolsy.NewStreamTool("endless", "Endless", func(
+		_ context.Context, _ *toolsy.RunEnv, _ struct{}, yield func(toolsy.Chunk) error,
+	) error {
+		for {
+			if err := yield(textChunk(toolsy.EventProgress, "tick")); err != nil {
+				aborted <- err
+				return err
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewSSEHandler(newTestRegistry(t, endless)))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/tools/endless", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "id: 1\n", line)
+	cancel()
+	_ = resp.Body.Close()
+
+	select {
+	case err := <-aborted:
+		require.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool kept streaming after the client disconnected")
+	}
+}
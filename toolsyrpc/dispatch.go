@@ -0,0 +1,162 @@
+package toolsyrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// dispatcher maps JSON-RPC messages onto one registry.
+type dispatcher struct {
+	reg  *toolsy.Registry
+	opts options
+}
+
+// dispatch handles one raw message, passing every outgoing message to send in order. A send error
+// aborts the running tool's stream.
+func (d *dispatcher) dispatch(ctx context.Context, raw []byte, send func(any) error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return
+	}
+	if raw[0] == '[' {
+		_ = send(errorResponse(nil, CodeInvalidRequest, "batch requests are not supported", nil))
+		return
+	}
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		if !json.Valid(raw) {
+			_ = send(errorResponse(nil, CodeParseError, "parse error", nil))
+			return
+		}
+		_ = send(errorResponse(nil, CodeInvalidRequest, "invalid request", nil))
+		return
+	}
+	if req.JSONRPC != Version || req.Method == "" {
+		_ = send(errorResponse(req.ID, CodeInvalidRequest, `invalid request: want jsonrpc "2.0" and a method`, nil))
+		return
+	}
+	params := bytes.TrimSpace(req.Params)
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("null")):
+		params = []byte(`{}`)
+	case params[0] != '{':
+		if len(req.ID) > 0 {
+			_ = send(errorResponse(req.ID, CodeInvalidParams, "params must be an object", nil))
+		}
+		return
+	}
+	if d.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.opts.timeout)
+		defer cancel()
+	}
+	d.execute(ctx, req, params, send)
+}
+
+func (d *dispatcher) execute(ctx context.Context, req Request, params []byte, send func(any) error) {
+	call := toolsy.ToolCall{ //nolint:exhaustruct // a JSON-RPC request carries only name, id and arguments
+		ToolName: req.Method,
+		Input:    toolsy.ToolInput{CallID: callID(req.ID), ArgsJSON: params, Attachments: nil},
+	}
+	isNotification := len(req.ID) == 0
+	seq := 0
+	sendChunk := func(c toolsy.Chunk) error {
+		seq++
+		return send(notification{
+			JSONRPC: Version,
+			Method:  ChunkMethod,
+			Params:  ChunkParams{ID: req.ID, Seq: seq, Event: c.Event, Result: resultFromChunk(c)},
+		})
+	}
+	// The last result chunk is held back until the next chunk or the end of the call shows
+	// whether it belongs in the response.
+	var last *toolsy.Chunk
+	err := d.reg.Execute(ctx, call, func(c toolsy.Chunk) error {
+		if isNotification {
+			return nil
+		}
+		if c.Event != toolsy.EventResult {
+			return sendChunk(c)
+		}
+		if last != nil {
+			if err := sendChunk(*last); err != nil {
+				return err
+			}
+		}
+		last = &c
+		return nil
+	})
+	if isNotification || errors.Is(err, toolsy.ErrStreamAborted) {
+		return
+	}
+	if err != nil {
+		if last != nil && sendChunk(*last) != nil {
+			return
+		}
+		_ = send(d.errorResponse(ctx, call, req.ID, err))
+		return
+	}
+	result := Result{Data: nil, DataEncoding: "", MimeType: "", IsError: false}
+	if last != nil {
+		result = resultFromChunk(*last)
+	}
+	_ = send(Response{JSONRPC: Version, ID: req.ID, Result: &result, Error: nil})
+}
+
+// errorResponse maps an execution error onto a JSON-RPC error: unknown tools to
+// [CodeMethodNotFound], errors the caller can fix to [CodeInvalidParams] with the reason as
+// message, and everything else to a generic [CodeInternalError] whose details are only logged.
+func (d *dispatcher) errorResponse(ctx context.Context, call toolsy.ToolCall, id json.RawMessage, err error) Response {
+	reason := toolsy.FinishReasonOf(err)
+	if reason != toolsy.FinishNotFound && reason != toolsy.FinishClientError {
+		cause := err
+		if te, ok := toolsy.AsToolError(err); ok && te.Err != nil {
+			cause = te.Err
+		}
+		d.opts.logger.ErrorContext(ctx, "toolsyrpc: tool call failed",
+			"tool", call.ToolName, "call_id", call.Input.CallID, "error", err, "cause", cause)
+		return errorResponse(id, CodeInternalError, "internal error", nil)
+	}
+	code := CodeInvalidParams
+	if reason == toolsy.FinishNotFound {
+		code = CodeMethodNotFound
+	}
+	message := err.Error()
+	if te, ok := toolsy.AsToolError(err); ok {
+		switch {
+		case te.Reason != "":
+			message = te.Reason
+		case te.SafeMessage != "":
+			message = te.SafeMessage
+		}
+	}
+	return errorResponse(id, code, message, toolsy.NewErrorChunkFromErr(err).Data)
+}
+
+func errorResponse(id json.RawMessage, code int, message string, data json.RawMessage) Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return Response{
+		JSONRPC: Version,
+		ID:      id,
+		Result:  nil,
+		Error:   &Error{Code: code, Message: message, Data: data},
+	}
+}
+
+// callID turns a request id into [toolsy.ToolInput.CallID]: strings without their quotes, numbers as written.
+func callID(id json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(id, &s); err == nil {
+		return s
+	}
+	if bytes.Equal(id, []byte("null")) {
+		return ""
+	}
+	return string(id)
+}
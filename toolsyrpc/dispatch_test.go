@@ -0,0 +1,169 @@
+package toolsyrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/skosovsky/toolsy"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type searchArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty" maximum:"10"`
+}
+
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params ChunkParams     `json:"params"`
+	Result *Result         `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+func newTestRegistry(t *testing.T) *toolsy.Registry {
+	t.Helper()
+	search, err := toolsy.NewTool("search", "Search",
+		func(_ context.Context, _ *toolsy.RunEnv, a searchArgs) (map[string]any, error) {
+			return map[string]any{"query": a.Query, "hits": 2}, nil
+		})
+	require.NoError(t, err)
+	stream, err := toolsy.NewStreamTool("stream", "Stream", func(
+		_ context.Context, _ *toolsy.RunEnv, _ struct{}, yield func(toolsy.Chunk) error,
+	) error {
+		for _, c := range []toolsy.Chunk{
+			{Event: toolsy.EventProgress, Data: []byte("started"), MimeType: toolsy.MimeTypeText},
+			{Event: toolsy.EventResult, Data: []byte("part 1"), MimeType: toolsy.MimeTypeText},
+			{Event: toolsy.EventResult, Data: []byte{0xff, 0xfe}, MimeType: "application/octet-stream"},
+		} {
+			if err := yield(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	fail, err := toolsy.NewTool("fail", "Fail", func(context.Context, *toolsy.RunEnv, struct{}) (string, error) {
+		return "", errors.New("dsn postgres://admin:hunter2@db rejected")
+	})
+	require.NoError(t, err)
+	reg, err := toolsy.NewRegistryBuilder().Add(search, stream, fail).Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Shutdown(context.Background()) })
+	return reg
+}
+
+// serve runs the stdio loop over the given lines and returns the messages written, in order.
+func serve(t *testing.T, reg *toolsy.Registry, opts []Option, lines ...string) []message {
+	t.Helper()
+	var out bytes.Buffer
+	opts = append([]Option{WithLogger(slog.New(slog.DiscardHandler))}, opts...)
+	require.NoError(t, Serve(context.Background(), reg, strings.NewReader(strings.Join(lines, "\n")), &out, opts...))
+	var msgs []message
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var m message
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m), scanner.Text())
+		msgs = append(msgs, m)
+	}
+	return msgs
+}
+
+func TestServe_SingleResult(t *testing.T) {
+	msgs := serve(t, newTestRegistry(t), nil, `{"jsonrpc":"2.0","id":7,"method":"search","params":{"query":"go"}}`)
+	require.Len(t, msgs, 1)
+	assert.JSONEq(t, `7`, string(msgs[0].ID))
+	require.NotNil(t, msgs[0].Result)
+	assert.JSONEq(t, `{"query":"go","hits":2}`, string(msgs[0].Result.Data))
+	assert.Equal(t, toolsy.MimeTypeJSON, msgs[0].Result.MimeType)
+}
+
+func TestServe_StreamNotificationsThenResponse(t *testing.T) {
+	msgs := serve(t, newTestRegistry(t), nil, `{"jsonrpc":"2.0","id":"s1","method":"stream"}`)
+	require.Len(t, msgs, 3)
+	for i, event := range []toolsy.EventType{toolsy.EventProgress, toolsy.EventResult} {
+		assert.Equal(t, ChunkMethod, msgs[i].Method)
+		assert.JSONEq(t, `"s1"`, string(msgs[i].Params.ID))
+		assert.Equal(t, i+1, msgs[i].Params.Seq)
+		assert.Equal(t, event, msgs[i].Params.Event)
+	}
+	assert.JSONEq(t, `"part 1"`, string(msgs[1].Params.Data))
+	final := msgs[2]
+	require.NotNil(t, final.Result)
+	assert.JSONEq(t, `"//4="`, string(final.Result.Data))
+	assert.Equal(t, DataEncodingBase64, final.Result.DataEncoding)
+}
+
+func TestServe_ErrorMapping(t *testing.T) {
+	reg := newTestRegistry(t)
+	tests := []struct {
+		name    string
+		line    string
+		code    int
+		message string
+		hasData bool
+	}{
+		{
+			name: "invalid params", line: `{"jsonrpc":"2.0","id":1,"method":"search","params":{"query":"go","limit":99}}`,
+			code: CodeInvalidParams, message: "limit", hasData: true,
+		},
+		{
+			name: "unknown tool", line: `{"jsonrpc":"2.0","id":1,"method":"nope","params":{}}`,
+			code: CodeMethodNotFound, message: "tool not found", hasData: true,
+		},
+		{
+			name: "system error", line: `{"jsonrpc":"2.0","id":1,"method":"fail","params":{}}`,
+			code: CodeInternalError, message: "internal error",
+		},
+		{name: "parse error", line: `{"jsonrpc":`, code: CodeParseError, message: "parse error"},
+		{name: "wrong version", line: `{"jsonrpc":"1.0","id":1,"method":"search"}`, code: CodeInvalidRequest, message: "2.0"},
+		{name: "batch", line: `[{"jsonrpc":"2.0","id":1,"method":"search"}]`, code: CodeInvalidRequest, message: "batch"},
+		{
+			name: "positional params", line: `{"jsonrpc":"2.0","id":1,"method":"search","params":["go"]}`,
+			code: CodeInvalidParams, message: "object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := serve(t, reg, nil, tt.line)
+			require.Len(t, msgs, 1)
+			require.NotNil(t, msgs[0].Error)
+			assert.Nil(t, msgs[0].Result)
+			assert.Equal(t, tt.code, msgs[0].Error.Code)
+			assert.Contains(t, msgs[0].Error.Message, tt.message)
+			assert.Equal(t, tt.hasData, len(msgs[0].Error.Data) > 0)
+			assert.NotContains(t, msgs[0].Error.Message+string(msgs[0].Error.Data), "hunter2")
+		})
+	}
+}
+
+func TestServe_NotificationsGetNoReply(t *testing.T) {
+	msgs := serve(t, newTestRegistry(t), nil,
+		`{"jsonrpc":"2.0","method":"stream"}`,
+		`{"jsonrpc":"2.0","method":"fail"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"search","params":{"query":"x"}}`,
+	)
+	require.Len(t, msgs, 1)
+	assert.JSONEq(t, `2`, string(msgs[0].ID))
+}
+
+func TestServe_MessageTooLarge(t *testing.T) {
+	err := Serve(context.Background(), newTestRegistry(t),
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"search","params":{"query":"`+strings.Repeat("x", 200)+`"}}`),
+		&bytes.Buffer{}, WithMaxBodyBytes(64))
+	require.Error(t, err)
+}
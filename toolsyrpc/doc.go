@@ -0,0 +1,8 @@
+// Package toolsyrpc dispatches JSON-RPC 2.0 requests to a [toolsy.Registry].
+//
+// A request's method is the tool name, params the arguments object and id the call ID. A tool
+// that returns one result gets one response; progress chunks and every result chunk but the last
+// are sent first as "toolsy/chunk" notifications, and the last result chunk becomes the response
+// result. [NewHandler] serves the protocol over HTTP and [Serve] over a reader/writer pair such as
+// stdin and stdout.
+package toolsyrpc
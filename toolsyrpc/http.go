@@ -0,0 +1,86 @@
+package toolsyrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/skosovsky/toolsy"
+	"github.com/skosovsky/toolsy/toolsyhttp"
+)
+
+// NewHandler returns a handler that accepts one JSON-RPC request per POST body. A call that
+// produces only its response is answered with a single application/json object. A call that
+// streams starts with its first notification and switches to application/x-ndjson: one message per
+// line, each flushed, with the response last. Notifications (requests without id) get 204 No Content.
+// A body larger than [WithMaxBodyBytes] gets 413; a client disconnect aborts the tool's stream.
+func NewHandler(reg *toolsy.Registry, opts ...Option) http.Handler {
+	d := &dispatcher{reg: reg, opts: applyOptions(opts)}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, d.opts.maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "cannot read request body", http.StatusBadRequest)
+			return
+		}
+		out := &httpSender{w: w, rc: http.NewResponseController(w), streaming: false, wrote: false}
+		d.dispatch(r.Context(), body, func(msg any) error {
+			if err := r.Context().Err(); err != nil {
+				return err
+			}
+			return out.send(msg)
+		})
+		if !out.wrote {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// httpSender picks the response format from the first message: a lone response is plain JSON,
+// a notification starts a newline-delimited stream.
+type httpSender struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	streaming bool
+	wrote     bool
+}
+
+func (s *httpSender) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if !s.wrote {
+		s.wrote = true
+		contentType := "application/json"
+		if _, isNotification := msg.(notification); isNotification {
+			s.streaming = true
+			contentType = toolsyhttp.MediaTypeNDJSON
+		}
+		s.w.Header().Set("Content-Type", contentType)
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if s.streaming {
+		data = append(data, '\n')
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	if !s.streaming {
+		return nil
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package toolsyrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skosovsky/toolsy/toolsyhttp"
+)
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(newTestRegistry(t), WithMaxBodyBytes(256))
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"jsonrpc":"2.0","id":1,"method":"search","params":{"query":"go"}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"data":{"query":"go","hits":2},"mime_type":"application/json"}}`,
+		rec.Body.String())
+
+	rec = post(`{"jsonrpc":"2.0","id":2,"method":"stream"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, toolsyhttp.MediaTypeNDJSON, rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"method":"toolsy/chunk"`)
+	assert.Contains(t, lines[2], `"result"`)
+
+	rec = post(`{"jsonrpc":"2.0","method":"search","params":{"query":"go"}}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = post(`{"jsonrpc":"2.0","id":3,"method":"search","params":{"query":"` + strings.Repeat("x", 300) + `"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rpc", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,62 @@
+package toolsyrpc
+
+import (
+	"log/slog"
+	"time"
+)
+
+// defaultMaxMessageBytes caps HTTP request bodies at 1 MiB unless [WithMaxBodyBytes] says otherwise.
+const defaultMaxMessageBytes = 1 << 20
+
+// Option configures [NewHandler] and [Serve].
+type Option func(*options)
+
+type options struct {
+	timeout      time.Duration
+	maxBodyBytes int64
+	logger       *slog.Logger
+}
+
+func defaultOptions() options {
+	return options{
+		timeout:      0,
+		maxBodyBytes: defaultMaxMessageBytes,
+		logger:       slog.Default(),
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithTimeout bounds each call with [context.WithTimeout]. Zero or negative means no limit beyond
+// the parent context and the registry's own timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithMaxBodyBytes sets the largest request body [NewHandler] accepts (default 1 MiB). Zero or
+// negative keeps the default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxBodyBytes = n
+		}
+	}
+}
+
+// WithLogger sets the logger that records the details of internal errors hidden from the client
+// (default [slog.Default]).
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
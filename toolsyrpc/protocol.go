@@ -0,0 +1,109 @@
+package toolsyrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// Version is the only JSON-RPC version accepted.
+const Version = "2.0"
+
+// ChunkMethod is the notification method carrying intermediate chunks of a call.
+const ChunkMethod = "toolsy/chunk"
+
+// JSON-RPC 2.0 error codes produced by the dispatcher.
+const (
+	// CodeParseError: the message is not valid JSON.
+	CodeParseError = -32700
+	// CodeInvalidRequest: the message is not a valid request object (or is a batch, which is not supported).
+	CodeInvalidRequest = -32600
+	// CodeMethodNotFound: no tool has the method's name ([toolsy.ErrToolNotFound]).
+	CodeMethodNotFound = -32601
+	// CodeInvalidParams: the caller can fix the call (invalid arguments, policy or rate-limit denials).
+	CodeInvalidParams = -32602
+	// CodeInternalError: any other failure; the message is generic.
+	CodeInternalError = -32603
+)
+
+// DataEncodingBase64 marks [Result.Data] holding base64 because the chunk data is not valid UTF-8.
+const DataEncodingBase64 = "base64"
+
+// Request is a JSON-RPC 2.0 request. A request without an id is a notification: the tool runs
+// but nothing is sent back.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response; exactly one of Result and Error is set.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  *Result         `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is the JSON-RPC 2.0 error object. For [CodeInvalidParams] and [CodeMethodNotFound], Data
+// holds the structured tool error ([toolsy.MimeTypeToolErrorJSON] wire).
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Result is one chunk as sent in a response or a [ChunkParams] notification. Data is the chunk's
+// JSON value when MimeType is JSON, a string when it is UTF-8 text, and a base64 string with
+// DataEncoding set otherwise. IsError marks a soft error result the tool yielded.
+type Result struct {
+	Data         json.RawMessage `json:"data,omitempty"`
+	DataEncoding string          `json:"data_encoding,omitempty"`
+	MimeType     string          `json:"mime_type,omitempty"`
+	IsError      bool            `json:"is_error,omitempty"`
+}
+
+// ChunkParams are the params of a [ChunkMethod] notification: the id of the request, the chunk's
+// position in the stream (from 1) and its event.
+type ChunkParams struct {
+	ID    json.RawMessage  `json:"id"`
+	Seq   int              `json:"seq"`
+	Event toolsy.EventType `json:"event"`
+	Result
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  ChunkParams `json:"params"`
+}
+
+// resultFromChunk encodes c.Data as described on [Result].
+func resultFromChunk(c toolsy.Chunk) Result {
+	r := Result{Data: nil, DataEncoding: "", MimeType: c.MimeType, IsError: c.IsError}
+	switch {
+	case len(c.Data) == 0:
+	case isJSONMimeType(c.MimeType) && json.Valid(c.Data):
+		r.Data = bytes.Clone(c.Data)
+	case utf8.Valid(c.Data):
+		r.Data, _ = json.Marshal(string(c.Data))
+	default:
+		r.Data, _ = json.Marshal(base64.StdEncoding.EncodeToString(c.Data))
+		r.DataEncoding = DataEncodingBase64
+	}
+	return r
+}
+
+func isJSONMimeType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
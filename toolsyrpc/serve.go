@@ -0,0 +1,55 @@
+package toolsyrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/skosovsky/toolsy"
+)
+
+// Serve reads newline-delimited JSON-RPC requests from r and writes responses and notifications
+// to w, one message per line, as a stdio tool host does. Requests run concurrently; messages of one
+// call are written in order and never interleave with another message. A message larger than
+// [WithMaxBodyBytes] stops the loop with an error.
+//
+// Serve returns after r reaches EOF (nil) or fails, once every running call has finished.
+// Cancelling ctx cancels running calls, but a blocked read only returns when r is closed; a write
+// error aborts the call that hit it.
+func Serve(ctx context.Context, reg *toolsy.Registry, r io.Reader, w io.Writer, opts ...Option) error {
+	d := &dispatcher{reg: reg, opts: applyOptions(opts)}
+	var writeMu sync.Mutex
+	send := func(msg any) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	scanner := bufio.NewScanner(r)
+	maxLine := int(d.opts.maxBodyBytes)
+	// The scanner's limit is the larger of the buffer's capacity and max, so start no bigger than max.
+	scanner.Buffer(make([]byte, 0, min(bufio.MaxScanTokenSize, maxLine)), maxLine)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := append([]byte(nil), scanner.Bytes()...)
+		wg.Go(func() {
+			d.dispatch(ctx, line, send)
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("toolsyrpc: read request: %w", err)
+	}
+	return ctx.Err()
+}
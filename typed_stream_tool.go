@@ -0,0 +1,52 @@
+package toolsy
+
+import (
+	"context"
+	"fmt"
+)
+
+// NewTypedStreamTool builds a streaming Tool whose handler yields typed values instead of raw chunks.
+// Each value is marshaled into an [EventResult] JSON chunk (with [Chunk.TypedResult] set); a marshal
+// failure is returned from yield as a [CodeInternal] error. Errors from the consumer reach the handler
+// wrapped in [ErrStreamAborted] exactly as with [NewStreamTool], so it can stop early.
+//
+// Unless [WithOutputSchema] is given, [ToolManifest.OutputSchema] is generated from C and describes
+// each chunk, which also makes [WithOutputValidation] usable. Use [NewStreamTool] for raw bytes or
+// mixed event kinds.
+func NewTypedStreamTool[T any, C any](
+	name, description string,
+	fn func(ctx context.Context, args T, yield func(C) error) error,
+	opts ...ToolOption,
+) (Tool, error) {
+	var cfg ToolConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.Schema = ensureSchemaConfig(cfg.Schema)
+	ext, err := NewExtractorWithConfig[T](cfg.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Manifest.OutputSchema) == 0 {
+		outSchema, genErr := generateOutputSchema[C](cfg.Schema)
+		if genErr != nil {
+			return nil, genErr
+		}
+		cfg.Manifest.OutputSchema = outSchema
+	}
+	stream := func(ctx context.Context, _ *RunEnv, args T, yield func(Chunk) error) error {
+		return fn(ctx, args, func(v C) error {
+			data, err := marshalToolResult(v)
+			if err != nil {
+				return NewInternalError(fmt.Errorf("toolsy: marshal stream chunk: %w", err))
+			}
+			return yield(Chunk{
+				Event:       EventResult,
+				Data:        data,
+				MimeType:    MimeTypeJSON,
+				TypedResult: v,
+			})
+		})
+	}
+	return newStreamToolFromExtractor(ext, name, description, stream, cfg)
+}
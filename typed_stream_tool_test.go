@@ -0,0 +1,81 @@
+package toolsy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamSearchArgs struct {
+	Query string `json:"query"`
+}
+
+type streamHit struct {
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+func TestNewTypedStreamTool_MarshalsChunks(t *testing.T) {
+	t.Parallel()
+	tool, err := NewTypedStreamTool("search", "Search documents",
+		func(_ context.Context, args streamSearchArgs, yield func(streamHit) error) error {
+			for i, title := range []string{args.Query + " intro", args.Query + " guide"} {
+				if err := yield(streamHit{Title: title, Score: float64(i)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, WithOutputValidation())
+	require.NoError(t, err)
+	assert.Contains(t, tool.Manifest().OutputSchema["properties"], "score", "chunk schema is generated from C")
+
+	var chunks []Chunk
+	err = tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{"query":"go"}`)},
+		func(c Chunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, EventResult, chunks[1].Event)
+	assert.Equal(t, MimeTypeJSON, chunks[1].MimeType)
+	assert.JSONEq(t, `{"title":"go guide","score":1}`, string(chunks[1].Data))
+	hit, err := DecodeChunkAs[streamHit](chunks[0])
+	require.NoError(t, err)
+	assert.Equal(t, "go intro", hit.Title)
+}
+
+func TestNewTypedStreamTool_AbortAndMarshalErrors(t *testing.T) {
+	t.Parallel()
+	var sawAbort bool
+	tool, err := NewTypedStreamTool("numbers", "Stream numbers",
+		func(_ context.Context, _ struct{}, yield func(float64) error) error {
+			for _, v := range []float64{1, 2, 3} {
+				if err := yield(v); err != nil {
+					sawAbort = errors.Is(err, ErrStreamAborted)
+					return err
+				}
+			}
+			return yield(math.NaN())
+		})
+	require.NoError(t, err)
+
+	stop := errors.New("client gone")
+	err = tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)},
+		func(Chunk) error { return stop })
+	require.ErrorIs(t, err, ErrStreamAborted)
+	assert.True(t, sawAbort, "handler sees ErrStreamAborted from yield")
+
+	var n int
+	err = tool.Execute(context.Background(), NewRunEnv(nil), ToolInput{ArgsJSON: []byte(`{}`)},
+		func(Chunk) error {
+			n++
+			return nil
+		})
+	assert.Equal(t, 3, n)
+	assert.Equal(t, FinishSystemError, FinishReasonOf(err), "unmarshalable values are system errors")
+}
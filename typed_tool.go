@@ -150,7 +150,17 @@ func NewTypedTool[TSubject, TScope, TArgs, TResult, TEffect any](
 		}
 		cfg.Manifest.OutputSchema = outSchema
 	}
-	manifest := buildToolManifest(spec.Name, spec.Description, ext.Schema(), cfg.Manifest)
+	if err := checkToolIdentity(cfg, spec.Name, spec.Description); err != nil {
+		return nil, err
+	}
+	if err := resolveExamples(&cfg, ext.Validate); err != nil {
+		return nil, err
+	}
+	output, err := newOutputValidator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	manifest := ext.toolManifest(spec.Name, spec.Description, cfg.Manifest)
 
 	execute := func(ctx context.Context, env *RunEnv, input ToolInput, yield func(Chunk) error) error {
 		bound, callCtx, err := prepareTypedToolCall[TSubject, TScope, TArgs](
@@ -170,9 +180,13 @@ func NewTypedTool[TSubject, TScope, TArgs, TResult, TEffect any](
 		if err != nil {
 			return wrapHandlerError(err)
 		}
-		return emitTypedToolResult(res, spec.ResultValidator, spec.EffectValidator, spec.Postcondition, yield)
+		return emitTypedToolResult(res, spec.ResultValidator, spec.EffectValidator, spec.Postcondition, output, yield)
+	}
+	t := &tool{manifest: manifest, execute: execute, validate: nil}
+	if spec.ArgsBinder == nil {
+		t.validate = ext.parseOnly
 	}
-	return &tool{manifest: manifest, execute: execute}, nil
+	return t, nil
 }
 
 var errTypedToolNilHandler = errors.New("toolsy: typed tool handler must not be nil")
@@ -285,6 +299,7 @@ func emitTypedToolResult[TResult, TEffect any](
 	resultValidator ResultValidator[TResult],
 	effectValidator EffectValidator[TEffect],
 	postcondition PostconditionValidator[TResult, TEffect],
+	output *outputValidator,
 	yield func(Chunk) error,
 ) error {
 	if resultValidator != nil && !res.Empty && !res.Noop {
@@ -313,6 +328,9 @@ func emitTypedToolResult[TResult, TEffect any](
 	if err != nil {
 		return err
 	}
+	if err := output.check(prepared); err != nil {
+		return err
+	}
 	if err := yield(prepared); err != nil {
 		return wrapYieldError(err)
 	}
@@ -14,9 +14,15 @@ type schemaValidator interface {
 
 // validateAgainstSchema runs Layer 1 validation on already-parsed value v.
 // Caller must unmarshal JSON and pass the result; parse errors are reported by the caller (e.g. Extractor.ParseAndValidate or Tool Execute).
-func validateAgainstSchema(validate schemaValidator, v any) error {
+// schema is the source map of validate; it is only read on failure to build [FieldViolation]s.
+// allErrors reports every violation instead of the first ([WithAllValidationErrors]).
+func validateAgainstSchema(validate schemaValidator, schema map[string]any, v any, allErrors bool) error {
 	if err := validate.Validate(v); err != nil {
-		return NewValidationError(err.Error())
+		limit := 1
+		if allErrors {
+			limit = 0
+		}
+		return newSchemaValidationError(schema, v, err, limit)
 	}
 	return nil
 }